@@ -1,48 +1,92 @@
 package build
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"syscall"
+	"time"
 
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"go.uber.org/zap"
 )
 
+// commandTerminationDelay is the time given to the processes of a cancelled command
+// (e.g. guestfish unmounting the image) to shut down before they are killed.
+const commandTerminationDelay = 30 * time.Second
+
 type imageConfigurator interface {
-	Configure(ctx *image.Context) error
+	Configure(ctx context.Context, buildCtx *image.Context) error
 }
 
 type Builder struct {
 	context           *image.Context
 	imageConfigurator imageConfigurator
+	bootRunner        bootRunner
+	// modifySemaphore limits the number of builds on the host concurrently running
+	// the memory intensive image modification (guestfish and virt-resize).
+	modifySemaphore hostSemaphore
+	// reusingOutputImage indicates that the existing output image is customized again
+	// instead of being rebuilt from the base image.
+	reusingOutputImage bool
 }
 
 func NewBuilder(ctx *image.Context, imageConfigurator imageConfigurator) *Builder {
 	return &Builder{
 		context:           ctx,
 		imageConfigurator: imageConfigurator,
+		bootRunner:        qemuRunner{buildDir: ctx.BuildDir},
+		modifySemaphore: hostSemaphore{
+			dir:   semaphoreDir(ctx),
+			name:  "image-modification",
+			limit: ctx.ImageModificationLimit,
+		},
 	}
 }
 
-func (b *Builder) Build() error {
+// Build configures the image customization components and builds the image.
+// If the given context is cancelled (e.g. on SIGINT), the running commands are terminated
+// and the partial build artifacts are removed.
+func (b *Builder) Build(ctx context.Context) error {
+	err := b.build(ctx)
+	if err != nil && ctx.Err() != nil {
+		b.cleanup()
+	}
+
+	return err
+}
+
+func (b *Builder) build(ctx context.Context) error {
+	if err := os.MkdirAll(b.outputDir(), os.ModePerm); err != nil {
+		log.Auditf("The output directory '%s' could not be created.", b.outputDir())
+		return fmt.Errorf("creating output directory: %w", err)
+	}
+
 	log.Audit("Generating image customization components...")
 
-	if err := b.imageConfigurator.Configure(b.context); err != nil {
+	if err := b.imageConfigurator.Configure(ctx, b.context); err != nil {
 		log.Audit("Error configuring customization components.")
 		return fmt.Errorf("configuring image: %w", err)
 	}
 
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("build cancelled: %w", err)
+	}
+
 	switch b.context.ImageDefinition.Image.ImageType {
 	case image.TypeISO:
 		log.Audit("Building ISO image...")
-		if err := b.buildIsoImage(); err != nil {
+		if err := b.buildIsoImage(ctx); err != nil {
 			log.Audit("Error building ISO image.")
 			return err
 		}
 	case image.TypeRAW:
 		log.Audit("Building RAW image...")
-		if err := b.buildRawImage(); err != nil {
+		if err := b.buildRawImage(ctx); err != nil {
 			log.Audit("Error building RAW image.")
 			return err
 		}
@@ -51,16 +95,120 @@ func (b *Builder) Build() error {
 			image.TypeISO, image.TypeRAW)
 	}
 
+	if err := b.applyOutputPermissions(); err != nil {
+		log.Audit("Error applying the output image permissions.")
+		return err
+	}
+
+	if err := b.writeBuildInfo(); err != nil {
+		log.Audit("Error writing the build information file.")
+		return err
+	}
+
+	if b.context.GenerateSBOM {
+		if err := b.writeSBOM(); err != nil {
+			log.Audit("Error writing the SBOM file.")
+			return err
+		}
+	}
+
+	if b.context.VerifyBoot {
+		log.Audit("Verifying that the image boots...")
+		if err := b.verifyBoot(ctx); err != nil {
+			log.Auditf("Boot verification failed: %s. Please check the %s file under the build directory for more information.",
+				err, bootVerificationLogFile)
+			zap.S().Warnf("Boot verification failed: %s", err)
+		} else {
+			log.Audit("Boot verification succeeded.")
+		}
+	}
+
 	log.Audit("Image build complete!")
 	return nil
 }
 
+// applyOutputPermissions sets the permissions of the output image to the ones requested in the definition.
+func (b *Builder) applyOutputPermissions() error {
+	if b.context.ImageDefinition.Image.OutputPermissions == "" {
+		return nil
+	}
+
+	mode, err := b.context.ImageDefinition.Image.OutputFileMode()
+	if err != nil {
+		return err
+	}
+
+	filename := b.generateOutputImageFilename()
+	if err = os.Chmod(filename, mode); err != nil {
+		return fmt.Errorf("changing permissions of output image %s: %w", filename, err)
+	}
+
+	return nil
+}
+
+// cleanup removes the artifacts of an interrupted build.
+func (b *Builder) cleanup() {
+	log.Audit("Build cancelled. Removing partial build artifacts...")
+
+	if err := b.deleteExistingOutputImage(); err != nil {
+		zap.S().Warnf("Removing partial output image failed: %s", err)
+	}
+
+	RemoveBuildArtifacts(b.context.BuildDir)
+}
+
+// RemoveBuildArtifacts removes the contents of the build directory of an interrupted build.
+// The log files are kept so that the build can still be investigated.
+func RemoveBuildArtifacts(buildDir string) {
+	entries, err := os.ReadDir(buildDir)
+	if err != nil {
+		zap.S().Warnf("Reading build directory '%s' failed: %s", buildDir, err)
+		return
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".log" {
+			continue
+		}
+
+		path := filepath.Join(buildDir, entry.Name())
+		if err = os.RemoveAll(path); err != nil {
+			zap.S().Warnf("Removing build artifact '%s' failed: %s", path, err)
+		}
+	}
+}
+
+// newCommand creates a command which runs in its own process group so that the processes
+// it starts (e.g. guestfish) are terminated along with it once the given context is cancelled.
+func newCommand(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		err := syscall.Kill(-cmd.Process.Pid, syscall.SIGTERM)
+		if errors.Is(err, syscall.ESRCH) {
+			return os.ErrProcessDone
+		}
+		return err
+	}
+	cmd.WaitDelay = commandTerminationDelay
+
+	return cmd
+}
+
 func (b *Builder) generateBuildDirFilename(filename string) string {
 	return filepath.Join(b.context.BuildDir, filename)
 }
 
+func (b *Builder) outputDir() string {
+	if b.context.OutputDir != "" {
+		return b.context.OutputDir
+	}
+
+	return b.context.ImageConfigDir
+}
+
 func (b *Builder) generateOutputImageFilename() string {
-	filename := filepath.Join(b.context.ImageConfigDir, b.context.ImageDefinition.Image.OutputImageName)
+	filename := filepath.Join(b.outputDir(), b.context.ImageDefinition.Image.OutputImageName)
 	return filename
 }
 
@@ -0,0 +1,136 @@
+package build
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/version"
+	"go.uber.org/zap"
+)
+
+const buildInfoSuffix = ".build-info.json"
+
+type buildInfo struct {
+	EIBVersion         string          `json:"eibVersion"`
+	Timestamp          time.Time       `json:"timestamp"`
+	DefinitionChecksum string          `json:"definitionChecksum,omitempty"`
+	ImageType          string          `json:"imageType"`
+	Arch               image.Arch      `json:"arch"`
+	BaseImage          *baseImageInfo  `json:"baseImage,omitempty"`
+	KernelArgs         []string        `json:"kernelArgs,omitempty"`
+	DiskSize           string          `json:"diskSize,omitempty"`
+	KubernetesVersion  string          `json:"kubernetesVersion,omitempty"`
+	HelmCharts         []helmChartInfo `json:"helmCharts"`
+	EmbeddedImages     []string        `json:"embeddedImages"`
+	Requirements       *requirements   `json:"requirements,omitempty"`
+	ValidationWarnings []string        `json:"validationWarnings,omitempty"`
+}
+
+// requirements lists the minimum resources declared in the image definition, in MB.
+type requirements struct {
+	MinMemoryMB int64 `json:"minMemoryMB,omitempty"`
+	MinDiskMB   int64 `json:"minDiskMB,omitempty"`
+}
+
+// baseImageInfo identifies the base image the output image was built from.
+type baseImageInfo struct {
+	Name    string    `json:"name"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"modTime"`
+}
+
+type helmChartInfo struct {
+	Name          string `json:"name"`
+	Version       string `json:"version"`
+	RepositoryURL string `json:"repositoryURL"`
+}
+
+func (b *Builder) generateBuildInfoFilename() string {
+	return b.generateOutputImageFilename() + buildInfoSuffix
+}
+
+// generateBaseImageInfo identifies the base image by its name, size and modification time.
+func (b *Builder) generateBaseImageInfo() (*baseImageInfo, error) {
+	filename := b.generateBaseImageFilename()
+
+	fileInfo, err := os.Stat(filename)
+	if err != nil {
+		return nil, fmt.Errorf("reading base image file info: %w", err)
+	}
+
+	return &baseImageInfo{
+		Name:    b.context.ImageDefinition.Image.BaseImage,
+		Size:    fileInfo.Size(),
+		ModTime: fileInfo.ModTime().UTC(),
+	}, nil
+}
+
+// readBuildInfo reads the build info file written alongside an existing output image.
+func (b *Builder) readBuildInfo() (*buildInfo, error) {
+	filename := b.generateBuildInfoFilename()
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("reading build info file %s: %w", filename, err)
+	}
+
+	var info buildInfo
+	if err = json.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("unmarshalling build info file %s: %w", filename, err)
+	}
+
+	return &info, nil
+}
+
+// writeBuildInfo records the resolved inputs of the build next to the output image.
+func (b *Builder) writeBuildInfo() error {
+	info := buildInfo{
+		EIBVersion:         version.GetVersion(),
+		Timestamp:          time.Now().UTC(),
+		DefinitionChecksum: b.context.BuildInfo.DefinitionChecksum,
+		ImageType:          b.context.ImageDefinition.Image.ImageType,
+		Arch:               b.context.ImageDefinition.Image.Arch,
+		KubernetesVersion:  b.context.ImageDefinition.Kubernetes.Version,
+		KernelArgs:         b.context.ImageDefinition.OperatingSystem.KernelArgs,
+		DiskSize:           string(b.context.ImageDefinition.OperatingSystem.RawConfiguration.DiskSize),
+		HelmCharts:         []helmChartInfo{},
+		EmbeddedImages:     []string{},
+		ValidationWarnings: b.context.BuildInfo.ValidationWarnings,
+	}
+
+	baseImage, err := b.generateBaseImageInfo()
+	if err != nil {
+		zap.S().Warnf("Identifying the base image failed: %s", err)
+	} else {
+		info.BaseImage = baseImage
+	}
+
+	for _, chart := range b.context.BuildInfo.HelmCharts {
+		info.HelmCharts = append(info.HelmCharts, helmChartInfo(chart))
+	}
+
+	info.EmbeddedImages = append(info.EmbeddedImages, b.context.BuildInfo.EmbeddedImages...)
+
+	if r := b.context.ImageDefinition.Requirements; r.MinMemory != "" || r.MinDisk != "" {
+		info.Requirements = &requirements{
+			MinMemoryMB: r.MinMemory.ToMB(),
+			MinDiskMB:   r.MinDisk.ToMB(),
+		}
+	}
+
+	data, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshalling build info: %w", err)
+	}
+
+	filename := b.generateBuildInfoFilename()
+	if err = os.WriteFile(filename, data, fileio.NonExecutablePerms); err != nil {
+		return fmt.Errorf("writing build info file %s: %w", filename, err)
+	}
+
+	return nil
+}
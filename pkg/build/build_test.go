@@ -1,9 +1,13 @@
 package build
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -88,3 +92,320 @@ func TestDeleteExistingImage(t *testing.T) {
 	require.Error(t, err)
 	require.True(t, os.IsNotExist(err))
 }
+
+func TestGenerateOutputImageFilename(t *testing.T) {
+	tests := map[string]struct {
+		outputDir        string
+		expectedFilename string
+	}{
+		"Default": {
+			expectedFilename: filepath.Join("config-dir", "output.iso"),
+		},
+		"Custom output directory": {
+			outputDir:        "output-dir",
+			expectedFilename: filepath.Join("output-dir", "output.iso"),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			builder := Builder{
+				context: &image.Context{
+					ImageConfigDir: "config-dir",
+					OutputDir:      test.outputDir,
+					ImageDefinition: &image.Definition{
+						Image: image.Image{
+							OutputImageName: "output.iso",
+						},
+					},
+				},
+			}
+
+			assert.Equal(t, test.expectedFilename, builder.generateOutputImageFilename())
+		})
+	}
+}
+
+func TestApplyOutputPermissions(t *testing.T) {
+	// Setup
+	outputDir := t.TempDir()
+
+	builder := Builder{
+		context: &image.Context{
+			OutputDir: outputDir,
+			ImageDefinition: &image.Definition{
+				Image: image.Image{
+					OutputImageName:   "eib-image.raw",
+					OutputPermissions: "0640",
+				},
+			},
+		},
+	}
+
+	require.NoError(t, os.WriteFile(builder.generateOutputImageFilename(), nil, 0o600))
+
+	// Test
+	err := builder.applyOutputPermissions()
+
+	// Verify
+	require.NoError(t, err)
+
+	info, err := os.Stat(builder.generateOutputImageFilename())
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o640), info.Mode().Perm())
+}
+
+type mockImageConfigurator struct{}
+
+func (mockImageConfigurator) Configure(context.Context, *image.Context) error {
+	return nil
+}
+
+func TestBuild_CreatesOutputDir(t *testing.T) {
+	// Setup
+	configDir := t.TempDir()
+	outputDir := filepath.Join(t.TempDir(), "images", "output")
+
+	builder := NewBuilder(&image.Context{
+		ImageConfigDir: configDir,
+		OutputDir:      outputDir,
+		ImageDefinition: &image.Definition{
+			Image: image.Image{
+				OutputImageName: "output.raw",
+			},
+		},
+	}, mockImageConfigurator{})
+
+	// Test
+	// The build itself fails due to the missing image type but only after preparing the output directory
+	err := builder.Build(context.Background())
+
+	// Verify
+	require.ErrorContains(t, err, "invalid imageType value specified")
+	assert.DirExists(t, outputDir)
+	assert.Equal(t, filepath.Join(outputDir, "output.raw"), builder.generateOutputImageFilename())
+	assert.NoFileExists(t, filepath.Join(configDir, "output.raw"))
+}
+
+type cancellingImageConfigurator struct {
+	cancel context.CancelFunc
+}
+
+func (c cancellingImageConfigurator) Configure(_ context.Context, ctx *image.Context) error {
+	if err := os.WriteFile(filepath.Join(ctx.BuildDir, "partial"), []byte("partial"), 0o600); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Join(ctx.BuildDir, "combustion"), 0o755); err != nil {
+		return err
+	}
+
+	// Simulate an interrupt midway through the build
+	c.cancel()
+	return nil
+}
+
+func TestBuild_CancelledCleansUp(t *testing.T) {
+	// Setup
+	buildDir := filepath.Join(t.TempDir(), "build-dir")
+	require.NoError(t, os.MkdirAll(buildDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(buildDir, "eib-build.log"), []byte("log"), 0o600))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewBuilder(&image.Context{
+		ImageConfigDir: t.TempDir(),
+		OutputDir:      t.TempDir(),
+		BuildDir:       buildDir,
+		ImageDefinition: &image.Definition{
+			Image: image.Image{
+				ImageType:       image.TypeRAW,
+				OutputImageName: "output.raw",
+			},
+		},
+	}, cancellingImageConfigurator{cancel: cancel})
+
+	// Test
+	err := builder.Build(ctx)
+
+	// Verify
+	require.ErrorIs(t, err, context.Canceled)
+	assert.NoFileExists(t, filepath.Join(buildDir, "partial"))
+	assert.NoDirExists(t, filepath.Join(buildDir, "combustion"))
+	assert.FileExists(t, filepath.Join(buildDir, "eib-build.log"))
+}
+
+func TestBuild_FailureKeepsBuildDir(t *testing.T) {
+	// Setup
+	buildDir := t.TempDir()
+
+	builder := NewBuilder(&image.Context{
+		ImageConfigDir:  t.TempDir(),
+		BuildDir:        buildDir,
+		ImageDefinition: &image.Definition{},
+	}, mockImageConfigurator{})
+
+	// Test
+	err := builder.Build(context.Background())
+
+	// Verify
+	require.ErrorContains(t, err, "invalid imageType value specified")
+	assert.DirExists(t, buildDir)
+}
+
+func TestNewCommand_Cancelled(t *testing.T) {
+	// Setup
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// The child process would outlive the shell and keep the output open
+	// if only the shell was terminated
+	var output bytes.Buffer
+	cmd := newCommand(ctx, "/bin/sh", "-c", "sleep 30 & wait")
+	cmd.Stdout = &output
+	require.NoError(t, cmd.Start())
+
+	// Test
+	start := time.Now()
+	cancel()
+	err := cmd.Wait()
+
+	// Verify
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 10*time.Second)
+}
+
+func TestWriteBuildInfo(t *testing.T) {
+	// Setup
+	outputDir := t.TempDir()
+
+	builder := Builder{
+		context: &image.Context{
+			OutputDir: outputDir,
+			ImageDefinition: &image.Definition{
+				Image: image.Image{
+					ImageType:       image.TypeISO,
+					Arch:            image.ArchTypeX86,
+					OutputImageName: "eib-image.iso",
+				},
+				Kubernetes: image.Kubernetes{
+					Version: "v1.30.3+rke2r1",
+				},
+				Requirements: image.Requirements{
+					MinMemory: "8G",
+					MinDisk:   "64G",
+				},
+			},
+			BuildInfo: image.BuildInfo{
+				DefinitionChecksum: "abc123",
+				HelmCharts: []image.HelmChartInfo{
+					{Name: "apache", Version: "10.7.0", RepositoryURL: "oci://registry-1.docker.io/bitnamicharts"},
+				},
+				EmbeddedImages:     []string{"docker.io/library/nginx:1.25", "registry.suse.com/bci/bci-base:15.5"},
+				ValidationWarnings: []string{"GPG signature checking is disabled for package installation; this is insecure."},
+			},
+		},
+	}
+
+	// Test
+	err := builder.writeBuildInfo()
+
+	// Verify
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(outputDir, "eib-image.iso.build-info.json"))
+	require.NoError(t, err)
+
+	var info buildInfo
+	require.NoError(t, json.Unmarshal(data, &info))
+
+	assert.NotEmpty(t, info.EIBVersion)
+	assert.False(t, info.Timestamp.IsZero())
+	assert.Equal(t, "abc123", info.DefinitionChecksum)
+	assert.Equal(t, image.TypeISO, info.ImageType)
+	assert.Equal(t, image.ArchTypeX86, info.Arch)
+	assert.Equal(t, "v1.30.3+rke2r1", info.KubernetesVersion)
+	assert.Equal(t, []helmChartInfo{
+		{Name: "apache", Version: "10.7.0", RepositoryURL: "oci://registry-1.docker.io/bitnamicharts"},
+	}, info.HelmCharts)
+	assert.Equal(t, []string{"docker.io/library/nginx:1.25", "registry.suse.com/bci/bci-base:15.5"}, info.EmbeddedImages)
+	assert.Equal(t, &requirements{MinMemoryMB: 8192, MinDiskMB: 65536}, info.Requirements)
+	assert.Equal(t, []string{"GPG signature checking is disabled for package installation; this is insecure."}, info.ValidationWarnings)
+}
+
+func TestWriteSBOM(t *testing.T) {
+	// Setup
+	outputDir := t.TempDir()
+
+	builder := Builder{
+		context: &image.Context{
+			OutputDir: outputDir,
+			ImageDefinition: &image.Definition{
+				Image: image.Image{
+					OutputImageName: "eib-image.raw",
+				},
+			},
+			BuildInfo: image.BuildInfo{
+				EmbeddedImages: []string{"docker.io/library/nginx:1.25", "registry.suse.com/bci/bci-base"},
+				RPMs:           []string{"cowsay-3.03-150500.1.1.noarch.rpm"},
+			},
+		},
+	}
+
+	// Test
+	err := builder.writeSBOM()
+
+	// Verify
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(outputDir, "eib-image.raw.spdx.json"))
+	require.NoError(t, err)
+
+	var doc spdxDocument
+	require.NoError(t, json.Unmarshal(data, &doc))
+
+	assert.Equal(t, "SPDX-2.3", doc.SPDXVersion)
+	assert.Equal(t, "SPDXRef-DOCUMENT", doc.SPDXID)
+	assert.Equal(t, "eib-image.raw", doc.Name)
+	assert.Contains(t, doc.DocumentNamespace, "eib-image.raw-")
+
+	expectedPackages := []spdxPackage{
+		{
+			SPDXID:           "SPDXRef-ContainerImage-1",
+			Name:             "docker.io/library/nginx",
+			VersionInfo:      "1.25",
+			DownloadLocation: "NOASSERTION",
+			PrimaryPackage:   "CONTAINER",
+			ExternalRefs: []spdxExternalRef{
+				{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: "pkg:docker/docker.io/library/nginx@1.25"},
+			},
+		},
+		{
+			SPDXID:           "SPDXRef-ContainerImage-2",
+			Name:             "registry.suse.com/bci/bci-base",
+			DownloadLocation: "NOASSERTION",
+			PrimaryPackage:   "CONTAINER",
+			ExternalRefs: []spdxExternalRef{
+				{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: "pkg:docker/registry.suse.com/bci/bci-base"},
+			},
+		},
+		{
+			SPDXID:           "SPDXRef-RPM-1",
+			Name:             "cowsay",
+			VersionInfo:      "3.03-150500.1.1",
+			DownloadLocation: "NOASSERTION",
+			PrimaryPackage:   "OPERATING-SYSTEM",
+			ExternalRefs: []spdxExternalRef{
+				{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: "pkg:rpm/cowsay@3.03-150500.1.1?arch=noarch"},
+			},
+		},
+	}
+	assert.Equal(t, expectedPackages, doc.Packages)
+
+	require.Len(t, doc.Relationships, 3)
+	for i, relationship := range doc.Relationships {
+		assert.Equal(t, "SPDXRef-DOCUMENT", relationship.SPDXElementID)
+		assert.Equal(t, "DESCRIBES", relationship.RelationshipType)
+		assert.Equal(t, expectedPackages[i].SPDXID, relationship.RelatedSPDXElement)
+	}
+}
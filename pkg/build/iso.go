@@ -1,6 +1,7 @@
 package build
 
 import (
+	"context"
 	_ "embed"
 	"fmt"
 	"os"
@@ -9,6 +10,8 @@ import (
 	"strings"
 
 	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
 	"github.com/suse-edge/edge-image-builder/pkg/template"
 	"go.uber.org/zap"
 )
@@ -28,12 +31,16 @@ var extractIsoTemplate string
 //go:embed templates/rebuild-iso.sh.tpl
 var rebuildIsoTemplate string
 
-func (b *Builder) buildIsoImage() error {
+func (b *Builder) buildIsoImage(ctx context.Context) error {
+	if b.context.ReuseOutputImage {
+		log.Audit("Reusing the output image is only supported for RAW images, building from the base image...")
+	}
+
 	if err := b.deleteExistingOutputImage(); err != nil {
 		return fmt.Errorf("deleting existing ISO image: %w", err)
 	}
 
-	if err := b.extractIso(); err != nil {
+	if err := b.extractIso(ctx); err != nil {
 		return fmt.Errorf("extracting the ISO image: %w", err)
 	}
 
@@ -41,23 +48,23 @@ func (b *Builder) buildIsoImage() error {
 	if err != nil {
 		return fmt.Errorf("unable to find extracted raw image: %w", err)
 	}
-	if err = b.modifyRawImage(extractedRawImage, false, false); err != nil {
+	if err = b.modifyRawImage(ctx, extractedRawImage, false, false); err != nil {
 		return fmt.Errorf("modifying the raw image inside of the ISO: %w", err)
 	}
 
-	if err := b.rebuildIso(); err != nil {
+	if err := b.rebuildIso(ctx); err != nil {
 		return fmt.Errorf("building the ISO image: %w", err)
 	}
 
 	return nil
 }
 
-func (b *Builder) extractIso() error {
+func (b *Builder) extractIso(ctx context.Context) error {
 	if err := b.writeIsoScript(extractIsoTemplate, extractIsoScriptName); err != nil {
 		return fmt.Errorf("creating the ISO extraction script: %w", err)
 	}
 
-	cmd, extractLog, err := b.createIsoCommand(extractIsoLogFile, extractIsoScriptName)
+	cmd, extractLog, err := b.createIsoCommand(ctx, extractIsoLogFile, extractIsoScriptName)
 	if err != nil {
 		return fmt.Errorf("preparing to extract the contents of the ISO: %w", err)
 	}
@@ -74,12 +81,12 @@ func (b *Builder) extractIso() error {
 	return nil
 }
 
-func (b *Builder) rebuildIso() error {
+func (b *Builder) rebuildIso(ctx context.Context) error {
 	if err := b.writeIsoScript(rebuildIsoTemplate, rebuildIsoScriptName); err != nil {
 		return fmt.Errorf("creating the ISO rebuild script: %w", err)
 	}
 
-	cmd, rebuildLog, err := b.createIsoCommand(rebuildIsoLogFile, rebuildIsoScriptName)
+	cmd, rebuildLog, err := b.createIsoCommand(ctx, rebuildIsoLogFile, rebuildIsoScriptName)
 	if err != nil {
 		return fmt.Errorf("preparing to build the new ISO: %w", err)
 	}
@@ -100,6 +107,7 @@ func (b *Builder) writeIsoScript(templateContents, outputFilename string) error
 	scriptName := filepath.Join(b.context.BuildDir, outputFilename)
 	isoExtractPath := filepath.Join(b.context.BuildDir, isoExtractDir)
 	rawExtractPath := filepath.Join(b.context.BuildDir, rawExtractDir)
+	isoConfig := b.context.ImageDefinition.OperatingSystem.IsoConfiguration
 	arguments := struct {
 		IsoExtractDir       string
 		RawExtractDir       string
@@ -108,6 +116,8 @@ func (b *Builder) writeIsoScript(templateContents, outputFilename string) error
 		CombustionDir       string
 		ArtefactsDir        string
 		InstallDevice       string
+		AutoInstallDevice   bool
+		RebootAfterInstall  bool
 	}{
 		IsoExtractDir:       isoExtractPath,
 		RawExtractDir:       rawExtractPath,
@@ -115,7 +125,9 @@ func (b *Builder) writeIsoScript(templateContents, outputFilename string) error
 		OutputImageFilename: b.generateOutputImageFilename(),
 		CombustionDir:       b.context.CombustionDir,
 		ArtefactsDir:        b.context.ArtefactsDir,
-		InstallDevice:       b.context.ImageDefinition.OperatingSystem.IsoConfiguration.InstallDevice,
+		InstallDevice:       isoConfig.InstallDevice,
+		AutoInstallDevice:   isoConfig.InstallDevice == image.InstallDeviceAuto,
+		RebootAfterInstall:  isoConfig.RebootAfterInstall,
 	}
 
 	contents, err := template.Parse("iso-script", templateContents, arguments)
@@ -130,7 +142,7 @@ func (b *Builder) writeIsoScript(templateContents, outputFilename string) error
 	return nil
 }
 
-func (b *Builder) createIsoCommand(logFilename, scriptName string) (*exec.Cmd, *os.File, error) {
+func (b *Builder) createIsoCommand(ctx context.Context, logFilename, scriptName string) (*exec.Cmd, *os.File, error) {
 	fullLogFilename := filepath.Join(b.context.BuildDir, logFilename)
 	logFile, err := os.Create(fullLogFilename)
 	if err != nil {
@@ -138,7 +150,7 @@ func (b *Builder) createIsoCommand(logFilename, scriptName string) (*exec.Cmd, *
 	}
 
 	scriptFilename := filepath.Join(b.context.BuildDir, scriptName)
-	cmd := exec.Command(scriptFilename)
+	cmd := newCommand(ctx, scriptFilename)
 	cmd.Stdout = logFile
 	cmd.Stderr = logFile
 
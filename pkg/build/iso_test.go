@@ -1,6 +1,7 @@
 package build
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -117,10 +118,66 @@ func TestWriteIsoScript_Rebuild(t *testing.T) {
 	// Make sure that target device is set as kernel cmdline argument
 	assert.Contains(t, found, "rd.kiwi.oem.installdevice=/dev/vda", "install device target is not configured as kernel cmdline argument")
 
+	// Make sure that the installation doesn't reboot automatically unless requested
+	assert.NotContains(t, found, "rd.kiwi.oem.reboot=1")
+
 	// Make sure that the xorisso command also adds the grub.cfg mapping
 	assert.Contains(t, found, "-map ${ISO_EXTRACT_DIR}/boot/grub2/grub.cfg /boot/grub2/grub.cfg", "xorisso doesn't have grub.cfg mapping")
 }
 
+func TestWriteIsoScript_RebuildAutoInstallDevice(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+	builder := Builder{context: ctx}
+
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			IsoConfiguration: image.IsoConfiguration{
+				InstallDevice:      image.InstallDeviceAuto,
+				RebootAfterInstall: true,
+			},
+		},
+	}
+
+	// Test
+	err := builder.writeIsoScript(rebuildIsoTemplate, rebuildIsoScriptName)
+
+	// Verify
+	require.NoError(t, err)
+
+	foundBytes, err := os.ReadFile(filepath.Join(ctx.BuildDir, rebuildIsoScriptName))
+	require.NoError(t, err)
+	found := string(foundBytes)
+
+	assert.Contains(t, found, "set timeout=", "unattended mode is not configured properly in GRUB menu")
+	assert.Contains(t, found, "rd.kiwi.oem.unattended=1")
+	assert.NotContains(t, found, "rd.kiwi.oem.installdevice=")
+	assert.Contains(t, found, "rd.kiwi.oem.reboot=1")
+	assert.Contains(t, found, "-map ${ISO_EXTRACT_DIR}/boot/grub2/grub.cfg /boot/grub2/grub.cfg")
+}
+
+func TestWriteIsoScript_RebuildNoInstallDevice(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+	builder := Builder{context: ctx}
+
+	// Test
+	err := builder.writeIsoScript(rebuildIsoTemplate, rebuildIsoScriptName)
+
+	// Verify
+	require.NoError(t, err)
+
+	foundBytes, err := os.ReadFile(filepath.Join(ctx.BuildDir, rebuildIsoScriptName))
+	require.NoError(t, err)
+	found := string(foundBytes)
+
+	assert.NotContains(t, found, "set timeout=")
+	assert.NotContains(t, found, "rd.kiwi.oem")
+	assert.NotContains(t, found, "/boot/grub2/grub.cfg /boot/grub2/grub.cfg")
+}
+
 func TestCreateIsoCommand(t *testing.T) {
 	// Setup
 	ctx, teardown := setupContext(t)
@@ -128,7 +185,7 @@ func TestCreateIsoCommand(t *testing.T) {
 	builder := Builder{context: ctx}
 
 	// Test
-	cmd, logFile, err := builder.createIsoCommand("test-log", "test-script")
+	cmd, logFile, err := builder.createIsoCommand(context.Background(), "test-log", "test-script")
 
 	// Verify
 	require.NoError(t, err)
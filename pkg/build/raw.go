@@ -1,14 +1,17 @@
 package build
 
 import (
+	"context"
 	_ "embed"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 
 	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
 	"github.com/suse-edge/edge-image-builder/pkg/template"
 	"go.uber.org/zap"
 )
@@ -23,7 +26,7 @@ const (
 //go:embed templates/modify-raw-image.sh.tpl
 var modifyRawImageTemplate string
 
-func (b *Builder) buildRawImage() error {
+func (b *Builder) buildRawImage(ctx context.Context) error {
 	requiredSpace, err := b.calculateMinimumRequiredSpace()
 	if err != nil {
 		return fmt.Errorf("calculating minimum required space: %w", err)
@@ -42,20 +45,76 @@ func (b *Builder) buildRawImage() error {
 		return fmt.Errorf("insufficient available disk space on the RAW image")
 	}
 
+	if b.context.ReuseOutputImage {
+		if err = b.checkOutputImageReusable(); err == nil {
+			log.Audit("Reusing the existing output image, only the customization components are replaced...")
+			b.reusingOutputImage = true
+			return b.modifyRawImage(ctx, b.generateOutputImageFilename(), true, true)
+		}
+
+		log.Audit("The existing output image cannot be reused, building from the base image...")
+		zap.S().Infof("Not reusing the existing output image: %s", err)
+	}
+
 	if err = b.deleteExistingOutputImage(); err != nil {
 		return fmt.Errorf("deleting existing RAW image: %w", err)
 	}
 
-	cmd := b.createRawImageCopyCommand()
+	cmd := b.createRawImageCopyCommand(ctx)
 	if err = cmd.Run(); err != nil {
 		return fmt.Errorf("copying the base image %s to the output image location %s: %w",
 			b.context.ImageDefinition.Image.BaseImage, b.generateOutputImageFilename(), err)
 	}
 
-	return b.modifyRawImage(b.generateOutputImageFilename(), true, true)
+	return b.modifyRawImage(ctx, b.generateOutputImageFilename(), true, true)
 }
 
-func (b *Builder) modifyRawImage(imagePath string, includeCombustion, renameFilesystem bool) error {
+// checkOutputImageReusable verifies that the existing output image was built from the same base image
+// with the same image type, architecture, kernel arguments and disk size, based on its build info file.
+// In that case, only the customization components must be replaced to bring the image up to date.
+func (b *Builder) checkOutputImageReusable() error {
+	def := b.context.ImageDefinition
+
+	outputFilename := b.generateOutputImageFilename()
+	if _, err := os.Stat(outputFilename); err != nil {
+		return fmt.Errorf("reading output image file info: %w", err)
+	}
+
+	info, err := b.readBuildInfo()
+	if err != nil {
+		return err
+	}
+
+	if info.ImageType != def.Image.ImageType {
+		return fmt.Errorf("existing image type '%s' does not match '%s'", info.ImageType, def.Image.ImageType)
+	}
+
+	if info.Arch != def.Image.Arch {
+		return fmt.Errorf("existing image architecture '%s' does not match '%s'", info.Arch, def.Image.Arch)
+	}
+
+	baseImage, err := b.generateBaseImageInfo()
+	if err != nil {
+		return err
+	}
+
+	if info.BaseImage == nil || info.BaseImage.Name != baseImage.Name ||
+		info.BaseImage.Size != baseImage.Size || !info.BaseImage.ModTime.Equal(baseImage.ModTime) {
+		return fmt.Errorf("base image '%s' changed since the existing image was built", baseImage.Name)
+	}
+
+	if !slices.Equal(info.KernelArgs, def.OperatingSystem.KernelArgs) {
+		return fmt.Errorf("kernel arguments changed since the existing image was built")
+	}
+
+	if info.DiskSize != string(def.OperatingSystem.RawConfiguration.DiskSize) {
+		return fmt.Errorf("disk size changed since the existing image was built")
+	}
+
+	return nil
+}
+
+func (b *Builder) modifyRawImage(ctx context.Context, imagePath string, includeCombustion, renameFilesystem bool) error {
 	if err := b.writeModifyScript(imagePath, includeCombustion, renameFilesystem); err != nil {
 		return fmt.Errorf("writing the image modification script: %w", err)
 	}
@@ -72,7 +131,13 @@ func (b *Builder) modifyRawImage(imagePath string, includeCombustion, renameFile
 		}
 	}()
 
-	cmd := b.createModifyCommand(logFile)
+	release, err := b.modifySemaphore.acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquiring image modification slot: %w", err)
+	}
+	defer release()
+
+	cmd := b.createModifyCommand(ctx, logFile)
 	err = cmd.Run()
 	if err != nil {
 		return fmt.Errorf("running the image modification script: %w", err)
@@ -81,11 +146,11 @@ func (b *Builder) modifyRawImage(imagePath string, includeCombustion, renameFile
 	return nil
 }
 
-func (b *Builder) createRawImageCopyCommand() *exec.Cmd {
+func (b *Builder) createRawImageCopyCommand(ctx context.Context) *exec.Cmd {
 	baseImagePath := b.generateBaseImageFilename()
 	outputImagePath := b.generateOutputImageFilename()
 
-	cmd := exec.Command(copyExec, baseImagePath, outputImagePath)
+	cmd := newCommand(ctx, copyExec, baseImagePath, outputImagePath)
 	return cmd
 }
 
@@ -106,6 +171,7 @@ func (b *Builder) writeModifyScript(imageFilename string, includeCombustion, ren
 		ConfigureCombustion bool
 		RenameFilesystem    bool
 		DiskSize            string
+		StaleDirs           []string
 	}{
 		ImagePath:           imageFilename,
 		CombustionDir:       b.context.CombustionDir,
@@ -116,6 +182,17 @@ func (b *Builder) writeModifyScript(imageFilename string, includeCombustion, ren
 		DiskSize:            string(b.context.ImageDefinition.OperatingSystem.RawConfiguration.DiskSize),
 	}
 
+	// A reused output image has already been resized and had its GRUB configuration
+	// modified, only the customization components must be replaced.
+	if b.reusingOutputImage {
+		values.ConfigureGRUB = ""
+		values.DiskSize = ""
+		values.StaleDirs = []string{
+			"/" + filepath.Base(b.context.CombustionDir),
+			"/" + filepath.Base(b.context.ArtefactsDir),
+		}
+	}
+
 	data, err := template.Parse(modifyScriptName, modifyRawImageTemplate, &values)
 	if err != nil {
 		return fmt.Errorf("parsing %s template: %w", modifyScriptName, err)
@@ -129,16 +206,29 @@ func (b *Builder) writeModifyScript(imageFilename string, includeCombustion, ren
 	return nil
 }
 
-func (b *Builder) createModifyCommand(writer io.Writer) *exec.Cmd {
+func (b *Builder) createModifyCommand(ctx context.Context, writer io.Writer) *exec.Cmd {
 	scriptPath := filepath.Join(b.context.BuildDir, modifyScriptName)
 
-	cmd := exec.Command(scriptPath)
+	cmd := newCommand(ctx, scriptPath)
 	cmd.Stdout = writer
 	cmd.Stderr = writer
 
+	// The temporary files and the appliance cache of guestfish and virt-resize are kept out of
+	// the system temporary directory, which is frequently a small tmpfs.
+	scratchDir := b.scratchDir()
+	cmd.Env = append(os.Environ(), "TMPDIR="+scratchDir, "LIBGUESTFS_CACHEDIR="+scratchDir)
+
 	return cmd
 }
 
+func (b *Builder) scratchDir() string {
+	if b.context.ScratchDir != "" {
+		return b.context.ScratchDir
+	}
+
+	return b.context.BuildDir
+}
+
 // Retrieve the size of the base image in MB.
 func (b *Builder) retrieveImageSize() (int64, error) {
 	imageFile, err := os.Stat(b.generateBaseImageFilename())
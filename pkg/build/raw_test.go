@@ -1,6 +1,7 @@
 package build
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -28,7 +29,7 @@ func TestCreateRawImageCopyCommand(t *testing.T) {
 	}
 
 	// Test
-	cmd := builder.createRawImageCopyCommand()
+	cmd := builder.createRawImageCopyCommand(context.Background())
 
 	// Verify
 	require.NotNil(t, cmd)
@@ -131,7 +132,7 @@ func TestCreateModifyCommand(t *testing.T) {
 		},
 	}
 	// Test
-	cmd := builder.createModifyCommand(io.Discard)
+	cmd := builder.createModifyCommand(context.Background(), io.Discard)
 
 	// Verify
 	require.NotNil(t, cmd)
@@ -140,4 +141,171 @@ func TestCreateModifyCommand(t *testing.T) {
 	assert.Equal(t, expectedPath, cmd.Path)
 	assert.Equal(t, io.Discard, cmd.Stdout)
 	assert.Equal(t, io.Discard, cmd.Stderr)
+	assert.Contains(t, cmd.Env, "TMPDIR=build-dir")
+	assert.Contains(t, cmd.Env, "LIBGUESTFS_CACHEDIR=build-dir")
+}
+
+func TestCreateModifyCommand_ScratchDir(t *testing.T) {
+	// Setup
+	builder := Builder{
+		context: &image.Context{
+			BuildDir:   "build-dir",
+			ScratchDir: "scratch-dir",
+		},
+	}
+	// Test
+	cmd := builder.createModifyCommand(context.Background(), io.Discard)
+
+	// Verify
+	require.NotNil(t, cmd)
+
+	assert.Contains(t, cmd.Env, "TMPDIR=scratch-dir")
+	assert.Contains(t, cmd.Env, "LIBGUESTFS_CACHEDIR=scratch-dir")
+	assert.NotContains(t, cmd.Env, "TMPDIR=build-dir")
+}
+
+func setupReusableOutputImage(t *testing.T) (builder *Builder, teardown func()) {
+	ctx, teardown := setupContext(t)
+	ctx.ArtefactsDir = t.TempDir()
+	ctx.ReuseOutputImage = true
+	ctx.ImageDefinition = &image.Definition{
+		Image: image.Image{
+			ImageType:       image.TypeRAW,
+			Arch:            image.ArchTypeX86,
+			BaseImage:       "base-image.raw",
+			OutputImageName: "output-image.raw",
+		},
+		OperatingSystem: image.OperatingSystem{
+			KernelArgs: []string{"alpha"},
+		},
+	}
+	builder = &Builder{context: ctx}
+
+	require.NoError(t, os.MkdirAll(filepath.Join(ctx.ImageConfigDir, "base-images"), os.ModePerm))
+	require.NoError(t, os.WriteFile(builder.generateBaseImageFilename(), []byte("base"), 0o600))
+	require.NoError(t, os.WriteFile(builder.generateOutputImageFilename(), []byte("customized"), 0o600))
+	require.NoError(t, builder.writeBuildInfo())
+
+	return builder, teardown
+}
+
+func TestCheckOutputImageReusable(t *testing.T) {
+	tests := map[string]struct {
+		modify        func(b *Builder)
+		expectedError string
+	}{
+		`reusable`: {
+			modify: func(*Builder) {},
+		},
+		`missing output image`: {
+			modify: func(b *Builder) {
+				require.NoError(t, os.Remove(b.generateOutputImageFilename()))
+			},
+			expectedError: "reading output image file info",
+		},
+		`missing build info`: {
+			modify: func(b *Builder) {
+				require.NoError(t, os.Remove(b.generateBuildInfoFilename()))
+			},
+			expectedError: "reading build info file",
+		},
+		`different image type`: {
+			modify: func(b *Builder) {
+				b.context.ImageDefinition.Image.ImageType = image.TypeISO
+			},
+			expectedError: "existing image type 'raw' does not match 'iso'",
+		},
+		`different architecture`: {
+			modify: func(b *Builder) {
+				b.context.ImageDefinition.Image.Arch = image.ArchTypeARM
+			},
+			expectedError: "existing image architecture 'x86_64' does not match 'aarch64'",
+		},
+		`changed base image`: {
+			modify: func(b *Builder) {
+				require.NoError(t, os.WriteFile(b.generateBaseImageFilename(), []byte("updated base"), 0o600))
+			},
+			expectedError: "base image 'base-image.raw' changed since the existing image was built",
+		},
+		`changed kernel arguments`: {
+			modify: func(b *Builder) {
+				b.context.ImageDefinition.OperatingSystem.KernelArgs = []string{"alpha", "beta"}
+			},
+			expectedError: "kernel arguments changed since the existing image was built",
+		},
+		`changed disk size`: {
+			modify: func(b *Builder) {
+				b.context.ImageDefinition.OperatingSystem.RawConfiguration.DiskSize = "64G"
+			},
+			expectedError: "disk size changed since the existing image was built",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			builder, teardown := setupReusableOutputImage(t)
+			defer teardown()
+
+			test.modify(builder)
+
+			err := builder.checkOutputImageReusable()
+			if test.expectedError == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.expectedError)
+		})
+	}
+}
+
+func TestBuildRawImage_ReuseOutputImage(t *testing.T) {
+	tests := map[string]struct {
+		modify          func(b *Builder)
+		expectedContent string
+		expectedReused  bool
+	}{
+		`base copy skipped on reuse`: {
+			modify:          func(*Builder) {},
+			expectedContent: "customized",
+			expectedReused:  true,
+		},
+		`base copied when base image changed`: {
+			modify: func(b *Builder) {
+				require.NoError(t, os.WriteFile(b.generateBaseImageFilename(), []byte("updated base"), 0o600))
+			},
+			expectedContent: "updated base",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			builder, teardown := setupReusableOutputImage(t)
+			defer teardown()
+
+			test.modify(builder)
+
+			// The modification script fails without guestfish, which is only
+			// run after the output image has (or has not) been copied.
+			err := builder.buildRawImage(context.Background())
+			require.ErrorContains(t, err, "running the image modification script")
+
+			content, err := os.ReadFile(builder.generateOutputImageFilename())
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedContent, string(content))
+
+			script, err := os.ReadFile(builder.generateBuildDirFilename(modifyScriptName))
+			require.NoError(t, err)
+
+			if test.expectedReused {
+				assert.Contains(t, string(script), "rm-rf /"+filepath.Base(builder.context.CombustionDir))
+				assert.Contains(t, string(script), "rm-rf /"+filepath.Base(builder.context.ArtefactsDir))
+				assert.NotContains(t, string(script), "download /boot/grub2/grub.cfg")
+			} else {
+				assert.NotContains(t, string(script), "rm-rf")
+				assert.Contains(t, string(script), "download /boot/grub2/grub.cfg")
+			}
+		})
+	}
 }
@@ -0,0 +1,169 @@
+package build
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/rpm"
+	"github.com/suse-edge/edge-image-builder/pkg/version"
+)
+
+const (
+	sbomSuffix        = ".spdx.json"
+	spdxVersion       = "SPDX-2.3"
+	spdxDataLicense   = "CC0-1.0"
+	spdxDocumentID    = "SPDXRef-DOCUMENT"
+	spdxNoAssertion   = "NOASSERTION"
+	spdxNamespaceBase = "https://github.com/suse-edge/edge-image-builder/spdx"
+)
+
+type spdxDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	SPDXID           string            `json:"SPDXID"`
+	Name             string            `json:"name"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	DownloadLocation string            `json:"downloadLocation"`
+	FilesAnalyzed    bool              `json:"filesAnalyzed"`
+	PrimaryPackage   string            `json:"primaryPackagePurpose,omitempty"`
+	ExternalRefs     []spdxExternalRef `json:"externalRefs,omitempty"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+func (b *Builder) generateSBOMFilename() string {
+	return b.generateOutputImageFilename() + sbomSuffix
+}
+
+// writeSBOM writes an SPDX software bill of materials enumerating the container images
+// stored in the embedded artifact registry and the RPM packages installed on the image.
+func (b *Builder) writeSBOM() error {
+	imageName := b.context.ImageDefinition.Image.OutputImageName
+
+	doc := spdxDocument{
+		SPDXVersion:       spdxVersion,
+		DataLicense:       spdxDataLicense,
+		SPDXID:            spdxDocumentID,
+		Name:              imageName,
+		DocumentNamespace: fmt.Sprintf("%s/%s-%s", spdxNamespaceBase, imageName, uuid.NewString()),
+		CreationInfo: spdxCreationInfo{
+			Created:  time.Now().UTC().Format(time.RFC3339),
+			Creators: []string{fmt.Sprintf("Tool: edge-image-builder-%s", version.GetVersion())},
+		},
+		Packages:      []spdxPackage{},
+		Relationships: []spdxRelationship{},
+	}
+
+	for i, containerImage := range b.context.BuildInfo.EmbeddedImages {
+		doc.addPackage(containerImagePackage(fmt.Sprintf("SPDXRef-ContainerImage-%d", i+1), containerImage))
+	}
+
+	for i, rpmFile := range b.context.BuildInfo.RPMs {
+		doc.addPackage(rpmPackage(fmt.Sprintf("SPDXRef-RPM-%d", i+1), rpmFile))
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshalling SBOM: %w", err)
+	}
+
+	filename := b.generateSBOMFilename()
+	if err = os.WriteFile(filename, data, fileio.NonExecutablePerms); err != nil {
+		return fmt.Errorf("writing SBOM file %s: %w", filename, err)
+	}
+
+	return nil
+}
+
+func (d *spdxDocument) addPackage(pkg spdxPackage) {
+	d.Packages = append(d.Packages, pkg)
+	d.Relationships = append(d.Relationships, spdxRelationship{
+		SPDXElementID:      spdxDocumentID,
+		RelationshipType:   "DESCRIBES",
+		RelatedSPDXElement: pkg.SPDXID,
+	})
+}
+
+func containerImagePackage(id, containerImage string) spdxPackage {
+	name, tag := containerImage, ""
+	if i := strings.LastIndex(containerImage, ":"); i > strings.LastIndex(containerImage, "/") {
+		name, tag = containerImage[:i], containerImage[i+1:]
+	}
+
+	purl := "pkg:docker/" + name
+	if tag != "" {
+		purl += "@" + tag
+	}
+
+	return spdxPackage{
+		SPDXID:           id,
+		Name:             name,
+		VersionInfo:      tag,
+		DownloadLocation: spdxNoAssertion,
+		PrimaryPackage:   "CONTAINER",
+		ExternalRefs: []spdxExternalRef{
+			{
+				ReferenceCategory: "PACKAGE-MANAGER",
+				ReferenceType:     "purl",
+				ReferenceLocator:  purl,
+			},
+		},
+	}
+}
+
+// rpmPackage describes an RPM based on its file name, following the
+// '<name>-<version>-<release>.<arch>.rpm' naming convention.
+func rpmPackage(id, filename string) spdxPackage {
+	pkg := spdxPackage{
+		SPDXID:           id,
+		Name:             filename,
+		DownloadLocation: spdxNoAssertion,
+		PrimaryPackage:   "OPERATING-SYSTEM",
+	}
+
+	f, err := rpm.ParseFilename(filename)
+	if err != nil {
+		return pkg
+	}
+
+	pkg.Name = f.Name
+	pkg.VersionInfo = fmt.Sprintf("%s-%s", f.Version, f.Release)
+	pkg.ExternalRefs = []spdxExternalRef{
+		{
+			ReferenceCategory: "PACKAGE-MANAGER",
+			ReferenceType:     "purl",
+			ReferenceLocator:  fmt.Sprintf("pkg:rpm/%s@%s?arch=%s", pkg.Name, pkg.VersionInfo, f.Arch),
+		},
+	}
+
+	return pkg
+}
@@ -0,0 +1,104 @@
+package build
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"go.uber.org/zap"
+)
+
+const semaphorePollInterval = time.Second
+
+// defaultSemaphoreDir is shared by all EIB builds running directly on the host.
+// Containerized builds only share it if it is mounted from the host.
+var defaultSemaphoreDir = filepath.Join(os.TempDir(), "edge-image-builder", "locks")
+
+// semaphoreDir returns the directory holding the image modification locks of the build.
+func semaphoreDir(ctx *image.Context) string {
+	if ctx.ImageModificationLockDir != "" {
+		return ctx.ImageModificationLockDir
+	}
+
+	return defaultSemaphoreDir
+}
+
+// hostSemaphore limits the number of processes on the host concurrently running a memory intensive
+// phase of the build (e.g. guestfish and virt-resize). Each slot is represented by a lock file so that
+// slots held by processes which terminate unexpectedly are released by the kernel.
+type hostSemaphore struct {
+	dir   string
+	name  string
+	limit int
+}
+
+// acquire blocks until a slot is available or the context is cancelled.
+// The returned function releases the slot. A non-positive limit disables the semaphore.
+func (s hostSemaphore) acquire(ctx context.Context) (release func(), err error) {
+	if s.limit <= 0 {
+		return func() {}, nil
+	}
+
+	if err = os.MkdirAll(s.dir, os.ModePerm); err != nil {
+		return nil, fmt.Errorf("creating lock directory: %w", err)
+	}
+
+	waiting := false
+	for {
+		for slot := range s.limit {
+			release, err = s.tryAcquire(slot)
+			if err != nil {
+				return nil, err
+			}
+			if release != nil {
+				return release, nil
+			}
+		}
+
+		if !waiting {
+			zap.S().Infof("All %d %s slots are in use, waiting for one to be released...", s.limit, s.name)
+			waiting = true
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("waiting for %s slot: %w", s.name, ctx.Err())
+		case <-time.After(semaphorePollInterval):
+		}
+	}
+}
+
+// tryAcquire attempts to lock the given slot without blocking.
+// A nil release function is returned if the slot is held by another process.
+func (s hostSemaphore) tryAcquire(slot int) (release func(), err error) {
+	lockPath := filepath.Join(s.dir, fmt.Sprintf("%s-%d.lock", s.name, slot))
+
+	file, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("opening lock file %s: %w", lockPath, err)
+	}
+
+	if err = syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		file.Close()
+
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("locking %s: %w", lockPath, err)
+	}
+
+	return func() {
+		if unlockErr := syscall.Flock(int(file.Fd()), syscall.LOCK_UN); unlockErr != nil {
+			zap.S().Warnf("Unlocking %s failed: %s", lockPath, unlockErr)
+		}
+		if closeErr := file.Close(); closeErr != nil {
+			zap.S().Warnf("Closing %s failed: %s", lockPath, closeErr)
+		}
+	}, nil
+}
@@ -0,0 +1,96 @@
+package build
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestHostSemaphore_SerializesBuilders(t *testing.T) {
+	// Setup
+	dir := t.TempDir()
+	semaphore := hostSemaphore{dir: dir, name: "test", limit: 1}
+
+	var running, maxRunning atomic.Int32
+	var wg sync.WaitGroup
+
+	// Test
+	for range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			builder := Builder{modifySemaphore: semaphore}
+			release, err := builder.modifySemaphore.acquire(context.Background())
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer release()
+
+			current := running.Add(1)
+			for {
+				previous := maxRunning.Load()
+				if current <= previous || maxRunning.CompareAndSwap(previous, current) {
+					break
+				}
+			}
+
+			time.Sleep(100 * time.Millisecond)
+			running.Add(-1)
+		}()
+	}
+	wg.Wait()
+
+	// Verify
+	assert.EqualValues(t, 1, maxRunning.Load())
+}
+
+func TestHostSemaphore_Limit(t *testing.T) {
+	// Setup
+	semaphore := hostSemaphore{dir: t.TempDir(), name: "test", limit: 2}
+
+	first, err := semaphore.acquire(context.Background())
+	require.NoError(t, err)
+
+	second, err := semaphore.acquire(context.Background())
+	require.NoError(t, err)
+
+	// Test
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err = semaphore.acquire(ctx)
+
+	// Verify
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	second()
+	third, err := semaphore.acquire(context.Background())
+	require.NoError(t, err)
+
+	first()
+	third()
+}
+
+func TestHostSemaphore_Disabled(t *testing.T) {
+	semaphore := hostSemaphore{dir: t.TempDir(), name: "test"}
+
+	first, err := semaphore.acquire(context.Background())
+	require.NoError(t, err)
+	defer first()
+
+	second, err := semaphore.acquire(context.Background())
+	require.NoError(t, err)
+	defer second()
+}
+
+func TestSemaphoreDir(t *testing.T) {
+	assert.Equal(t, defaultSemaphoreDir, semaphoreDir(&image.Context{}))
+	assert.Equal(t, "/var/lib/eib/locks", semaphoreDir(&image.Context{ImageModificationLockDir: "/var/lib/eib/locks"}))
+}
@@ -0,0 +1,103 @@
+package build
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+// minScratchSpaceMB is the space required in a separate scratch directory by the
+// temporary files and the appliance cache of guestfish and virt-resize.
+const minScratchSpaceMB = 1024
+
+type spaceChecker interface {
+	// AvailableSpace returns the space (in MB) available to unprivileged users on the filesystem of the given path.
+	AvailableSpace(path string) (int64, error)
+}
+
+type statfsSpaceChecker struct{}
+
+func (statfsSpaceChecker) AvailableSpace(path string) (int64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, fmt.Errorf("querying filesystem of %s: %w", path, err)
+	}
+
+	return int64(stat.Bavail) * stat.Bsize / (1024 * 1024), nil
+}
+
+// CheckAvailableSpace verifies that the filesystems of the build, output and scratch directories are likely to have
+// enough space for the build, failing early instead of when the base image is copied or the registry is generated.
+func CheckAvailableSpace(ctx *image.Context) error {
+	return checkAvailableSpace(ctx, statfsSpaceChecker{})
+}
+
+func checkAvailableSpace(ctx *image.Context, checker spaceChecker) error {
+	outputImageSize, err := estimateOutputImageSize(ctx)
+	if err != nil {
+		return fmt.Errorf("estimating required space: %w", err)
+	}
+
+	requiredSpace := outputImageSize + ctx.ImageDefinition.EmbeddedArtifactRegistry.SizeBudget.ToMB()
+
+	availableSpace, err := checker.AvailableSpace(ctx.BuildDir)
+	if err != nil {
+		return fmt.Errorf("retrieving available space: %w", err)
+	}
+
+	if availableSpace < requiredSpace {
+		return fmt.Errorf("insufficient disk space in the build directory %s: %d MB available, at least %d MB required",
+			ctx.BuildDir, availableSpace, requiredSpace)
+	}
+
+	outputDir := ctx.OutputDir
+	if outputDir == "" {
+		outputDir = ctx.ImageConfigDir
+	}
+
+	availableSpace, err = checker.AvailableSpace(outputDir)
+	if err != nil {
+		return fmt.Errorf("retrieving available output space: %w", err)
+	}
+
+	if availableSpace < outputImageSize {
+		return fmt.Errorf("insufficient disk space in the output directory %s: %d MB available, at least %d MB required",
+			outputDir, availableSpace, outputImageSize)
+	}
+
+	if ctx.ScratchDir == "" || ctx.ScratchDir == ctx.BuildDir {
+		return nil
+	}
+
+	availableSpace, err = checker.AvailableSpace(ctx.ScratchDir)
+	if err != nil {
+		return fmt.Errorf("retrieving available scratch space: %w", err)
+	}
+
+	if availableSpace < minScratchSpaceMB {
+		return fmt.Errorf("insufficient disk space in the scratch directory %s: %d MB available, at least %d MB required",
+			ctx.ScratchDir, availableSpace, minScratchSpaceMB)
+	}
+
+	return nil
+}
+
+// estimateOutputImageSize estimates the size (in MB) of the output image
+// as the sum of the base image and the configured disk size.
+func estimateOutputImageSize(ctx *image.Context) (int64, error) {
+	def := ctx.ImageDefinition
+
+	baseImagePath := filepath.Join(ctx.ImageConfigDir, "base-images", def.Image.BaseImage)
+	baseImage, err := os.Stat(baseImagePath)
+	if err != nil {
+		return 0, fmt.Errorf("reading base image file info: %w", err)
+	}
+
+	size := baseImage.Size() / (1024 * 1024)
+	size += def.OperatingSystem.RawConfiguration.DiskSize.ToMB()
+
+	return size, nil
+}
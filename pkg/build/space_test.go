@@ -0,0 +1,149 @@
+package build
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+type mockSpaceChecker struct {
+	availableSpace int64
+	// pathSpace optionally overrides the available space of specific paths
+	pathSpace map[string]int64
+	err       error
+}
+
+func (m mockSpaceChecker) AvailableSpace(path string) (int64, error) {
+	if space, ok := m.pathSpace[path]; ok {
+		return space, m.err
+	}
+
+	return m.availableSpace, m.err
+}
+
+func TestCheckAvailableSpace(t *testing.T) {
+	configDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(configDir, "base-images"), 0o755))
+
+	baseImage, err := os.Create(filepath.Join(configDir, "base-images", "base.raw"))
+	require.NoError(t, err)
+	require.NoError(t, baseImage.Truncate(100*1024*1024))
+	require.NoError(t, baseImage.Close())
+
+	tests := map[string]struct {
+		checker       mockSpaceChecker
+		diskSize      image.DiskSize
+		sizeBudget    image.DiskSize
+		baseImage     string
+		outputDir     string
+		scratchDir    string
+		expectedError string
+	}{
+		"Sufficient space": {
+			checker:   mockSpaceChecker{availableSpace: 100},
+			baseImage: "base.raw",
+		},
+		"Insufficient space for base image": {
+			checker:       mockSpaceChecker{availableSpace: 99},
+			baseImage:     "base.raw",
+			expectedError: "insufficient disk space in the build directory build-dir: 99 MB available, at least 100 MB required",
+		},
+		"Insufficient space for disk size and registry": {
+			checker:       mockSpaceChecker{availableSpace: 1000},
+			baseImage:     "base.raw",
+			diskSize:      "1G",
+			sizeBudget:    "500M",
+			expectedError: "insufficient disk space in the build directory build-dir: 1000 MB available, at least 1624 MB required",
+		},
+		"Insufficient space in output directory": {
+			checker:       mockSpaceChecker{availableSpace: 2000, pathSpace: map[string]int64{"output-dir": 1000}},
+			baseImage:     "base.raw",
+			diskSize:      "1G",
+			outputDir:     "output-dir",
+			expectedError: "insufficient disk space in the output directory output-dir: 1000 MB available, at least 1124 MB required",
+		},
+		"Output directory defaults to the config directory": {
+			checker:       mockSpaceChecker{availableSpace: 1000, pathSpace: map[string]int64{configDir: 50}},
+			baseImage:     "base.raw",
+			expectedError: "insufficient disk space in the output directory " + configDir + ": 50 MB available, at least 100 MB required",
+		},
+		"Registry size budget is not required in output directory": {
+			checker:    mockSpaceChecker{availableSpace: 1000, pathSpace: map[string]int64{"output-dir": 100}},
+			baseImage:  "base.raw",
+			sizeBudget: "500M",
+			outputDir:  "output-dir",
+		},
+		"Sufficient scratch space": {
+			checker:    mockSpaceChecker{availableSpace: 1024},
+			baseImage:  "base.raw",
+			scratchDir: "scratch-dir",
+		},
+		"Insufficient scratch space": {
+			checker:       mockSpaceChecker{availableSpace: 500},
+			baseImage:     "base.raw",
+			scratchDir:    "scratch-dir",
+			expectedError: "insufficient disk space in the scratch directory scratch-dir: 500 MB available, at least 1024 MB required",
+		},
+		"Scratch directory is the build directory": {
+			checker:    mockSpaceChecker{availableSpace: 500},
+			baseImage:  "base.raw",
+			scratchDir: "build-dir",
+		},
+		"Missing base image": {
+			checker:       mockSpaceChecker{availableSpace: 1000},
+			baseImage:     "missing.raw",
+			expectedError: "estimating required space: reading base image file info",
+		},
+		"Failed space query": {
+			checker:       mockSpaceChecker{err: errors.New("statfs failed")},
+			baseImage:     "base.raw",
+			expectedError: "retrieving available space: statfs failed",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := &image.Context{
+				ImageConfigDir: configDir,
+				BuildDir:       "build-dir",
+				OutputDir:      test.outputDir,
+				ScratchDir:     test.scratchDir,
+				ImageDefinition: &image.Definition{
+					Image: image.Image{
+						BaseImage: test.baseImage,
+					},
+					OperatingSystem: image.OperatingSystem{
+						RawConfiguration: image.RawConfiguration{
+							DiskSize: test.diskSize,
+						},
+					},
+					EmbeddedArtifactRegistry: image.EmbeddedArtifactRegistry{
+						SizeBudget: test.sizeBudget,
+					},
+				},
+			}
+
+			err := checkAvailableSpace(ctx, test.checker)
+
+			if test.expectedError == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, test.expectedError)
+			}
+		})
+	}
+}
+
+func TestStatfsSpaceChecker(t *testing.T) {
+	space, err := statfsSpaceChecker{}.AvailableSpace(t.TempDir())
+	require.NoError(t, err)
+	assert.Positive(t, space)
+
+	_, err = statfsSpaceChecker{}.AvailableSpace(filepath.Join(t.TempDir(), "missing"))
+	require.Error(t, err)
+}
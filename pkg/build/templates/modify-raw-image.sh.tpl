@@ -10,6 +10,8 @@ set -euo pipefail
 #  ConfigureCombustion - If true, the combustion and artefacts directories will be included in the raw image
 #  RenameFilesystem    - If true, the filesystem of the image will be renamed (see below for information
 #                        on why this is needed)
+#  StaleDirs           - Directories copied into a reused output image by a previous build which are removed
+#                        before the combustion and artefacts directories are copied in again
 #
 # Guestfish Command Documentation: https://libguestfs.org/guestfish.1.html
 
@@ -39,6 +41,10 @@ guestfish --blocksize=$BLOCKSIZE --format=raw --rw -a {{.ImagePath}} -i <<'EOF'
   {{ .ConfigureGRUB }}
   {{ end }}
 
+  {{ range .StaleDirs }}
+  rm-rf {{ . }}
+  {{ end }}
+
   {{ if .ConfigureCombustion }}
   copy-in {{.CombustionDir}} /
   copy-in {{.ArtefactsDir}} /
@@ -8,6 +8,9 @@ set -euo pipefail
 #  OutputImageFilename - Full path and name of the ISO to create
 #  CombustionDir - Full path to the combustion directory to include in the new ISO
 #  ArtefactsDir - Full path to the artefacts directory to include in the new ISO
+#  InstallDevice - Disk to install to, or "auto" to use the first disk found at install time
+#  AutoInstallDevice - Whether the install disk is selected at install time
+#  RebootAfterInstall - Whether the system reboots automatically once the installation completes
 
 ISO_EXTRACT_DIR={{.IsoExtractDir}}
 RAW_EXTRACT_DIR={{.RawExtractDir}}
@@ -32,8 +35,15 @@ NEW_SQUASH_FILE=${RAW_EXTRACT_DIR}/${SQUASH_BASENAME}
 # Select the desired install device - assumes data destruction and makes the installation fully unattended by enabling GRUB timeout
 {{ if ne .InstallDevice "" -}}
 echo -e "set timeout=3\nset timeout_style=menu\n$(cat ${ISO_EXTRACT_DIR}/boot/grub2/grub.cfg)" > ${ISO_EXTRACT_DIR}/boot/grub2/grub.cfg
+{{ if .AutoInstallDevice -}}
+sed -i '/ignition.platform/ s|$| rd.kiwi.oem.unattended=1 |' ${ISO_EXTRACT_DIR}/boot/grub2/grub.cfg
+{{ else -}}
 sed -i '/ignition.platform/ s|$| rd.kiwi.oem.installdevice={{.InstallDevice}} |' ${ISO_EXTRACT_DIR}/boot/grub2/grub.cfg
 {{ end -}}
+{{ if .RebootAfterInstall -}}
+sed -i '/ignition.platform/ s|$| rd.kiwi.oem.reboot=1 |' ${ISO_EXTRACT_DIR}/boot/grub2/grub.cfg
+{{ end -}}
+{{ end -}}
 
 
 cd ${RAW_EXTRACT_DIR}
@@ -0,0 +1,198 @@
+package build
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"go.uber.org/zap"
+)
+
+const (
+	bootVerificationLogFile  = "boot-verification.log"
+	bootVerificationDiskFile = "boot-verification-disk.raw"
+	// bootVerificationDiskSize is the size of the disk the ISO image is installed to when verifying it boots.
+	bootVerificationDiskSize = 20 << 30
+
+	defaultBootVerificationTimeout = 15 * time.Minute
+
+	// bootVerificationMarker can be written to the console by custom scripts to signal a successful boot.
+	bootVerificationMarker = "EIB_BOOT_VERIFIED"
+)
+
+// bootVerificationMarkers are the console messages indicating that the image has successfully booted.
+var bootVerificationMarkers = [][]byte{
+	[]byte("login:"),
+	[]byte(bootVerificationMarker),
+}
+
+type bootRunner interface {
+	// Boot starts a virtual machine from the given image, writing its serial console output to console,
+	// until either the machine shuts down or the context is cancelled.
+	Boot(ctx context.Context, imagePath string, imageType string, arch image.Arch, console io.Writer) error
+}
+
+// verifyBoot boots the built image headless and checks that it reaches a login prompt
+// (or the boot verification marker) before the configured timeout.
+func (b *Builder) verifyBoot(ctx context.Context) error {
+	timeout := b.context.BootVerificationTimeout
+	if timeout == 0 {
+		timeout = defaultBootVerificationTimeout
+	}
+
+	logFilename := b.generateBuildDirFilename(bootVerificationLogFile)
+	logFile, err := os.Create(logFilename)
+	if err != nil {
+		return fmt.Errorf("creating boot verification log file %s: %w", logFilename, err)
+	}
+	defer func() {
+		if err = logFile.Close(); err != nil {
+			zap.S().Warnf("failed to close boot verification log file properly: %s", err)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	console := &bootMarkerWriter{
+		out:     logFile,
+		markers: bootVerificationMarkers,
+		onMatch: cancel,
+	}
+
+	def := b.context.ImageDefinition
+	runErr := b.bootRunner.Boot(ctx, b.generateOutputImageFilename(), def.Image.ImageType, def.Image.Arch, console)
+
+	if console.matched() {
+		return nil
+	}
+
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("image did not boot within %s", timeout)
+	}
+
+	if runErr != nil {
+		return fmt.Errorf("booting image: %w", runErr)
+	}
+
+	return fmt.Errorf("virtual machine stopped before the image finished booting")
+}
+
+// bootMarkerWriter forwards the console output of a virtual machine and invokes onMatch
+// once any of the markers is written, including markers split across writes.
+type bootMarkerWriter struct {
+	out     io.Writer
+	markers [][]byte
+	onMatch func()
+
+	mu    sync.Mutex
+	tail  []byte
+	found bool
+}
+
+func (w *bootMarkerWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.found {
+		data := append(append([]byte{}, w.tail...), p...)
+		for _, marker := range w.markers {
+			if bytes.Contains(data, marker) {
+				w.found = true
+				w.onMatch()
+				break
+			}
+		}
+
+		w.tail = data[max(0, len(data)-w.maxMarkerLen()+1):]
+	}
+
+	return w.out.Write(p)
+}
+
+func (w *bootMarkerWriter) matched() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.found
+}
+
+func (w *bootMarkerWriter) maxMarkerLen() int {
+	var length int
+	for _, marker := range w.markers {
+		length = max(length, len(marker))
+	}
+
+	return length
+}
+
+// qemuRunner boots images in a headless QEMU virtual machine.
+type qemuRunner struct {
+	buildDir string
+}
+
+func (q qemuRunner) Boot(ctx context.Context, imagePath string, imageType string, arch image.Arch, console io.Writer) error {
+	args := []string{"-nographic", "-m", "4096", "-smp", "2"}
+
+	var qemuBinary string
+	switch arch {
+	case image.ArchTypeARM:
+		qemuBinary = "qemu-system-aarch64"
+		args = append(args, "-machine", "virt,accel=kvm:tcg", "-cpu", "max", "-bios", "/usr/share/qemu/aavmf-aarch64-code.bin")
+	default:
+		qemuBinary = "qemu-system-x86_64"
+		args = append(args, "-machine", "q35,accel=kvm:tcg", "-cpu", "max")
+	}
+
+	switch imageType {
+	case image.TypeISO:
+		diskPath := filepath.Join(q.buildDir, bootVerificationDiskFile)
+		if err := createSparseFile(diskPath, bootVerificationDiskSize); err != nil {
+			return fmt.Errorf("creating installation disk: %w", err)
+		}
+		defer func() {
+			if err := os.Remove(diskPath); err != nil {
+				zap.S().Warnf("failed to remove boot verification disk %s: %s", diskPath, err)
+			}
+		}()
+
+		args = append(args,
+			"-drive", fmt.Sprintf("file=%s,format=raw,if=virtio", diskPath),
+			"-cdrom", imagePath,
+			"-boot", "once=d")
+	default:
+		args = append(args, "-drive", fmt.Sprintf("file=%s,format=raw,if=virtio,snapshot=on", imagePath))
+	}
+
+	cmd := exec.CommandContext(ctx, qemuBinary, args...)
+	cmd.Stdout = console
+	cmd.Stderr = console
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("running %s: %w", qemuBinary, err)
+	}
+
+	return nil
+}
+
+func createSparseFile(path string, size int64) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating file: %w", err)
+	}
+	defer file.Close()
+
+	if err = file.Truncate(size); err != nil {
+		return fmt.Errorf("resizing file: %w", err)
+	}
+
+	return nil
+}
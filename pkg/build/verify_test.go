@@ -0,0 +1,127 @@
+package build
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+type mockBootRunner struct {
+	console       []string
+	waitForCancel bool
+	err           error
+
+	imagePath string
+	imageType string
+	arch      image.Arch
+}
+
+func (m *mockBootRunner) Boot(ctx context.Context, imagePath string, imageType string, arch image.Arch, console io.Writer) error {
+	m.imagePath = imagePath
+	m.imageType = imageType
+	m.arch = arch
+
+	for _, output := range m.console {
+		if _, err := console.Write([]byte(output)); err != nil {
+			return err
+		}
+	}
+
+	if m.waitForCancel {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	return m.err
+}
+
+func TestVerifyBoot(t *testing.T) {
+	tests := map[string]struct {
+		runner        *mockBootRunner
+		expectedError string
+	}{
+		`login prompt`: {
+			runner: &mockBootRunner{
+				console:       []string{"Welcome to SUSE Linux Micro\n", "localhost login: "},
+				waitForCancel: true,
+			},
+		},
+		`marker split across writes`: {
+			runner: &mockBootRunner{
+				console:       []string{"combustion: EIB_BOOT", "_VERIFIED\n"},
+				waitForCancel: true,
+			},
+		},
+		`timeout`: {
+			runner: &mockBootRunner{
+				console:       []string{"Booting from Hard Disk..."},
+				waitForCancel: true,
+			},
+			expectedError: "image did not boot within 100ms",
+		},
+		`runner failure`: {
+			runner: &mockBootRunner{
+				err: fmt.Errorf("qemu-system-x86_64 not found"),
+			},
+			expectedError: "booting image: qemu-system-x86_64 not found",
+		},
+		`machine stopped`: {
+			runner: &mockBootRunner{
+				console: []string{"Kernel panic - not syncing"},
+			},
+			expectedError: "virtual machine stopped before the image finished booting",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Setup
+			buildDir := t.TempDir()
+			outputDir := t.TempDir()
+
+			builder := Builder{
+				context: &image.Context{
+					BuildDir:                buildDir,
+					OutputDir:               outputDir,
+					BootVerificationTimeout: 100 * time.Millisecond,
+					ImageDefinition: &image.Definition{
+						Image: image.Image{
+							ImageType:       image.TypeRAW,
+							Arch:            image.ArchTypeX86,
+							OutputImageName: "eib-image.raw",
+						},
+					},
+				},
+				bootRunner: test.runner,
+			}
+
+			// Test
+			err := builder.verifyBoot(context.Background())
+
+			// Verify
+			if test.expectedError == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, test.expectedError)
+			}
+
+			assert.Equal(t, filepath.Join(outputDir, "eib-image.raw"), test.runner.imagePath)
+			assert.Equal(t, image.TypeRAW, test.runner.imageType)
+			assert.Equal(t, image.ArchTypeX86, test.runner.arch)
+
+			consoleLog, err := os.ReadFile(filepath.Join(buildDir, bootVerificationLogFile))
+			require.NoError(t, err)
+
+			assert.Equal(t, strings.Join(test.runner.console, ""), string(consoleLog))
+		})
+	}
+}
@@ -1,14 +1,19 @@
 package build
 
 import (
+	"context"
+	"crypto/sha256"
 	"errors"
 	"fmt"
 	"io/fs"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"syscall"
 
 	"github.com/suse-edge/edge-image-builder/pkg/cli/cmd"
 	"github.com/suse-edge/edge-image-builder/pkg/eib"
+	"github.com/suse-edge/edge-image-builder/pkg/http"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 	"github.com/suse-edge/edge-image-builder/pkg/log"
 	"github.com/urfave/cli/v2"
@@ -48,6 +53,16 @@ func Run(_ *cli.Context) error {
 		os.Exit(1)
 	}
 
+	if cmdErr := dirWritable(args.OutputDir, "output"); cmdErr != nil {
+		cmd.LogError(cmdErr, checkBuildLogMessage)
+		os.Exit(1)
+	}
+
+	if cmdErr := dirWritable(args.ScratchDir, "scratch"); cmdErr != nil {
+		cmd.LogError(cmdErr, checkBuildLogMessage)
+		os.Exit(1)
+	}
+
 	imageDefinition, cmdErr := parseImageDefinition(args.ConfigDir, args.DefinitionFile)
 	if cmdErr != nil {
 		cmd.LogError(cmdErr, checkBuildLogMessage)
@@ -60,7 +75,18 @@ func Run(_ *cli.Context) error {
 		zap.S().Fatalf("Failed to create combustion directories: %s", err)
 	}
 
-	ctx := buildContext(buildDir, combustionDir, artefactsDir, args.ConfigDir, imageDefinition)
+	ctx := buildContext(buildDir, combustionDir, artefactsDir, imageDefinition, args)
+	ctx.BuildInfo.DefinitionChecksum = definitionChecksum(filepath.Join(args.ConfigDir, args.DefinitionFile))
+
+	if ctx.HTTPClient, cmdErr = newHTTPClient(args); cmdErr != nil {
+		cmd.LogError(cmdErr, checkBuildLogMessage)
+		os.Exit(1)
+	}
+
+	if cmdErr = haulerBinaryExecutable(args.HaulerBinary); cmdErr != nil {
+		cmd.LogError(cmdErr, checkBuildLogMessage)
+		os.Exit(1)
+	}
 
 	if cmdErr = validateImageDefinition(ctx); cmdErr != nil {
 		cmd.LogError(cmdErr, checkBuildLogMessage)
@@ -74,7 +100,15 @@ func Run(_ *cli.Context) error {
 		}
 	}()
 
-	if err = eib.Run(ctx, rootBuildDir); err != nil {
+	interruptCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err = eib.Run(interruptCtx, ctx, rootBuildDir); err != nil {
+		if interruptCtx.Err() != nil {
+			log.Audit("Build interrupted.")
+			zap.S().Fatalf("The image build was interrupted: %s", err)
+		}
+
 		log.Audit(checkBuildLogMessage)
 		zap.S().Fatalf("An error occurred building the image: %s", err)
 	}
@@ -100,6 +134,77 @@ func imageConfigDirExists(configDir string) *cmd.Error {
 	}
 }
 
+// dirWritable creates the optional directory of the given kind (e.g. "output") and checks that it is writable.
+func dirWritable(dir, kind string) *cmd.Error {
+	if dir == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return &cmd.Error{
+			UserMessage: fmt.Sprintf("The specified %s directory '%s' could not be created.", kind, dir),
+			LogMessage:  fmt.Sprintf("Creating %s dir failed: %v", kind, err),
+		}
+	}
+
+	file, err := os.CreateTemp(dir, ".eib-write-check-")
+	if err != nil {
+		return &cmd.Error{
+			UserMessage: fmt.Sprintf("The specified %s directory '%s' is not writable.", kind, dir),
+			LogMessage:  fmt.Sprintf("Writing to %s dir failed: %v", kind, err),
+		}
+	}
+
+	if err = file.Close(); err != nil {
+		zap.S().Warnf("Closing write check file '%s' failed: %v", file.Name(), err)
+	}
+
+	if err = os.Remove(file.Name()); err != nil {
+		zap.S().Warnf("Removing write check file '%s' failed: %v", file.Name(), err)
+	}
+
+	return nil
+}
+
+// newHTTPClient creates the client performing all downloads of the build.
+func newHTTPClient(args *cmd.BuildFlags) (*http.Client, *cmd.Error) {
+	client, err := http.NewClient(http.ClientConfig{
+		CABundlePath:    args.CABundle,
+		DownloadTimeout: args.DownloadTimeout,
+		Offline:         args.Offline,
+	})
+	if err != nil {
+		return nil, &cmd.Error{
+			UserMessage: fmt.Sprintf("The specified CA bundle '%s' could not be loaded.", args.CABundle),
+			LogMessage:  fmt.Sprintf("Configuring CA bundle failed: %v", err),
+		}
+	}
+
+	return client, nil
+}
+
+func haulerBinaryExecutable(haulerBinary string) *cmd.Error {
+	if haulerBinary == "" {
+		return nil
+	}
+
+	info, err := os.Stat(haulerBinary)
+	if err != nil {
+		return &cmd.Error{
+			UserMessage: fmt.Sprintf("The specified hauler binary '%s' could not be found.", haulerBinary),
+			LogMessage:  fmt.Sprintf("Reading hauler binary failed: %v", err),
+		}
+	}
+
+	if !info.Mode().IsRegular() || info.Mode().Perm()&0o111 == 0 {
+		return &cmd.Error{
+			UserMessage: fmt.Sprintf("The specified hauler binary '%s' is not an executable file.", haulerBinary),
+		}
+	}
+
+	return nil
+}
+
 func parseImageDefinition(configDir, definitionFile string) (*image.Definition, *cmd.Error) {
 	definitionFilePath := filepath.Join(configDir, definitionFile)
 
@@ -128,14 +233,40 @@ func parseImageDefinition(configDir, definitionFile string) (*image.Definition,
 	return imageDefinition, nil
 }
 
+// definitionChecksum returns the SHA-256 checksum of the definition file recorded in the build information.
+// An empty value is returned if it could not be calculated since the information is not essential to the build.
+func definitionChecksum(definitionFilePath string) string {
+	data, err := os.ReadFile(definitionFilePath)
+	if err != nil {
+		zap.S().Warnf("Calculating definition file checksum failed: %v", err)
+		return ""
+	}
+
+	return fmt.Sprintf("%x", sha256.Sum256(data))
+}
+
 // Assembles the image build context with user-provided values and implementation defaults.
-func buildContext(buildDir, combustionDir, artefactsDir, configDir string, imageDefinition *image.Definition) *image.Context {
+func buildContext(buildDir, combustionDir, artefactsDir string, imageDefinition *image.Definition, args *cmd.BuildFlags) *image.Context {
 	ctx := &image.Context{
-		ImageConfigDir:  configDir,
-		BuildDir:        buildDir,
-		CombustionDir:   combustionDir,
-		ArtefactsDir:    artefactsDir,
-		ImageDefinition: imageDefinition,
+		ImageConfigDir:           args.ConfigDir,
+		OutputDir:                args.OutputDir,
+		ScratchDir:               args.ScratchDir,
+		BuildDir:                 buildDir,
+		CombustionDir:            combustionDir,
+		ArtefactsDir:             artefactsDir,
+		ImageDefinition:          imageDefinition,
+		ParallelDownloads:        args.ParallelDownloads,
+		Preflight:                args.Preflight,
+		RenderHelmCharts:         args.RenderCharts,
+		SkipComponents:           args.SkipComponents.Value(),
+		HaulerBinaryPath:         args.HaulerBinary,
+		ImageModificationLimit:   args.MaxModifications,
+		ImageModificationLockDir: args.ModificationLocks,
+		CommandLogMaxSize:        args.CommandLogMaxSize * 1024 * 1024,
+		ReuseOutputImage:         args.ReuseOutputImage,
+		GenerateSBOM:             args.SBOM,
+		VerifyBoot:               args.VerifyBoot,
+		BootVerificationTimeout:  args.VerifyBootTimeout,
 	}
 	return ctx
 }
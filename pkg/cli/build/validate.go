@@ -13,6 +13,7 @@ import (
 	"github.com/suse-edge/edge-image-builder/pkg/image/validation"
 	"github.com/suse-edge/edge-image-builder/pkg/log"
 	"github.com/urfave/cli/v2"
+	"go.uber.org/zap"
 )
 
 const (
@@ -48,9 +49,10 @@ func Validate(_ *cli.Context) error {
 		os.Exit(1)
 	}
 
-	ctx := &image.Context{
-		ImageConfigDir:  args.ConfigDir,
-		ImageDefinition: imageDefinition,
+	ctx, err := validationContext(imageDefinition, args)
+	if err != nil {
+		cmd.LogError(err, checkValidationLogMessage)
+		os.Exit(1)
 	}
 
 	log.AuditInfo("Validating image definition...")
@@ -65,18 +67,91 @@ func Validate(_ *cli.Context) error {
 	return nil
 }
 
+// Assembles the validation context with user-provided values. The HTTP client is required
+// for the online checks (e.g. rendering the Helm charts).
+func validationContext(imageDefinition *image.Definition, args *cmd.BuildFlags) (*image.Context, *cmd.Error) {
+	client, err := newHTTPClient(args)
+	if err != nil {
+		return nil, err
+	}
+
+	return &image.Context{
+		ImageConfigDir:   args.ConfigDir,
+		ImageDefinition:  imageDefinition,
+		HTTPClient:       client,
+		Preflight:        args.Preflight,
+		RenderHelmCharts: args.RenderCharts,
+	}, nil
+}
+
 func validateImageDefinition(ctx *image.Context) *cmd.Error {
 	failedValidations := validation.ValidateDefinition(ctx)
 	if len(failedValidations) == 0 {
 		return nil
 	}
 
+	failures, warnings := splitBySeverity(failedValidations)
+
+	if len(warnings) != 0 {
+		userMessage, logMessage := formatFailedValidations(warnings)
+
+		log.Audit("Image definition validation found the following warnings:\n" + userMessage)
+		zap.S().Warn("Image definition validation warnings:\n" + logMessage)
+
+		ctx.BuildInfo.ValidationWarnings = warningMessages(warnings)
+	}
+
+	if len(failures) == 0 {
+		return nil
+	}
+
+	userMessage, logMessage := formatFailedValidations(failures)
+
+	return &cmd.Error{
+		UserMessage: "Image definition validation found the following errors:\n" + userMessage,
+		LogMessage:  "Image definition validation failures:\n" + logMessage,
+	}
+}
+
+func splitBySeverity(failedValidations map[string][]validation.FailedValidation) (failures, warnings map[string][]validation.FailedValidation) {
+	failures = map[string][]validation.FailedValidation{}
+	warnings = map[string][]validation.FailedValidation{}
+
+	for componentName, componentFailures := range failedValidations {
+		for _, cf := range componentFailures {
+			if cf.Severity == validation.SeverityWarning {
+				warnings[componentName] = append(warnings[componentName], cf)
+			} else {
+				failures[componentName] = append(failures[componentName], cf)
+			}
+		}
+	}
+
+	return failures, warnings
+}
+
+// warningMessages returns the user messages of the given warnings, ordered by component name.
+func warningMessages(warnings map[string][]validation.FailedValidation) []string {
+	orderedComponentNames := make([]string, 0, len(warnings))
+	for c := range warnings {
+		orderedComponentNames = append(orderedComponentNames, c)
+	}
+	slices.Sort(orderedComponentNames)
+
+	var messages []string
+	for _, componentName := range orderedComponentNames {
+		for _, cf := range warnings[componentName] {
+			messages = append(messages, cf.UserMessage)
+		}
+	}
+
+	return messages
+}
+
+func formatFailedValidations(failedValidations map[string][]validation.FailedValidation) (userMessage, logMessage string) {
 	logMessageBuilder := strings.Builder{}
 	userMessageBuilder := strings.Builder{}
 
-	userMessageBuilder.WriteString("Image definition validation found the following errors:\n")
-	logMessageBuilder.WriteString("Image definition validation failures:\n")
-
 	orderedComponentNames := make([]string, 0, len(failedValidations))
 	for c := range failedValidations {
 		orderedComponentNames = append(orderedComponentNames, c)
@@ -95,8 +170,5 @@ func validateImageDefinition(ctx *image.Context) *cmd.Error {
 		}
 	}
 
-	return &cmd.Error{
-		UserMessage: userMessageBuilder.String(),
-		LogMessage:  logMessageBuilder.String(),
-	}
+	return userMessageBuilder.String(), logMessageBuilder.String()
 }
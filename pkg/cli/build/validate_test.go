@@ -0,0 +1,64 @@
+package build
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/cli/cmd"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestValidationContext_RenderHelmCharts(t *testing.T) {
+	// Setup
+	args := &cmd.BuildFlags{
+		ConfigDir:    t.TempDir(),
+		RenderCharts: true,
+	}
+
+	definition := &image.Definition{
+		APIVersion: "1.0",
+		Image: image.Image{
+			ImageType:       image.TypeISO,
+			Arch:            image.ArchTypeX86,
+			BaseImage:       "base.iso",
+			OutputImageName: "out.iso",
+		},
+		Kubernetes: image.Kubernetes{
+			Version: "v1.30.3+rke2r1",
+			Helm: image.Helm{
+				Charts: []image.HelmChart{
+					{Name: "apache", RepositoryName: "suse-edge", Version: "10.7.0", TargetNamespace: "web", InstallationNamespace: "kube-system"},
+				},
+				Repositories: []image.HelmRepository{
+					{Name: "suse-edge", URL: "https://suse-edge.github.io/charts"},
+				},
+			},
+		},
+	}
+
+	// Test
+	ctx, cmdErr := validationContext(definition, args)
+
+	// Verify
+	require.Nil(t, cmdErr)
+	require.NotNil(t, ctx.HTTPClient)
+	assert.True(t, ctx.RenderHelmCharts)
+
+	assert.NotPanics(t, func() {
+		cmdErr = validateImageDefinition(ctx)
+	})
+	require.NotNil(t, cmdErr)
+	assert.Contains(t, cmdErr.UserMessage, "Helm chart 'apache' could not be rendered.")
+}
+
+func TestValidationContext_InvalidCABundle(t *testing.T) {
+	args := &cmd.BuildFlags{
+		ConfigDir: t.TempDir(),
+		CABundle:  "/does/not/exist.pem",
+	}
+
+	_, cmdErr := validationContext(&image.Definition{}, args)
+	require.NotNil(t, cmdErr)
+	assert.Equal(t, "The specified CA bundle '/does/not/exist.pem' could not be loaded.", cmdErr.UserMessage)
+}
@@ -2,14 +2,33 @@ package cmd
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
 	"github.com/urfave/cli/v2"
 )
 
 type BuildFlags struct {
-	DefinitionFile string
-	ConfigDir      string
-	RootBuildDir   string
+	DefinitionFile    string
+	ConfigDir         string
+	RootBuildDir      string
+	OutputDir         string
+	ScratchDir        string
+	ParallelDownloads int
+	CABundle          string
+	DownloadTimeout   time.Duration
+	Offline           bool
+	Preflight         bool
+	RenderCharts      bool
+	SkipComponents    cli.StringSlice
+	HaulerBinary      string
+	MaxModifications  int
+	ModificationLocks string
+	CommandLogMaxSize int64
+	SBOM              bool
+	VerifyBoot        bool
+	VerifyBootTimeout time.Duration
+	ReuseOutputImage  bool
 }
 
 var BuildArgs BuildFlags
@@ -23,11 +42,93 @@ func NewBuildCommand(action func(*cli.Context) error) *cli.Command {
 		Flags: []cli.Flag{
 			DefinitionFileFlag,
 			ConfigDirFlag,
+			PreflightFlag,
+			RenderChartsFlag,
 			&cli.StringFlag{
 				Name:        "build-dir",
 				Usage:       "Full path to the directory to store build artifacts",
 				Destination: &BuildArgs.RootBuildDir,
 			},
+			&cli.StringFlag{
+				Name:        "output-dir",
+				Usage:       "Full path to the directory to store the built image (defaults to the image configuration directory)",
+				Destination: &BuildArgs.OutputDir,
+			},
+			&cli.StringFlag{
+				Name:        "scratch-dir",
+				Usage:       "Full path to the directory to store the temporary files of guestfish and virt-resize (defaults to the build directory)",
+				Destination: &BuildArgs.ScratchDir,
+			},
+			&cli.IntFlag{
+				Name:        "parallel-downloads",
+				Usage:       "Maximum number of artifacts (e.g. Kubernetes manifests) to download concurrently",
+				Value:       4,
+				Destination: &BuildArgs.ParallelDownloads,
+			},
+			&cli.IntFlag{
+				Name:        "max-concurrent-modifications",
+				Usage:       "Maximum number of builds on the host modifying images with guestfish concurrently to limit memory usage (0 disables the limit)",
+				Destination: &BuildArgs.MaxModifications,
+			},
+			&cli.StringFlag{
+				Name: "modification-lock-dir",
+				Usage: "Full path to the directory holding the locks which limit the concurrent image modifications. " +
+					"When running in a container, this must be a host mounted path shared by all EIB containers",
+				Destination: &BuildArgs.ModificationLocks,
+			},
+			&cli.Int64Flag{
+				Name:        "command-log-max-size",
+				Usage:       "Size (in MB) after which the log files of external commands (e.g. hauler and helm) are rotated",
+				Value:       fileio.DefaultMaxLogFileSize / (1024 * 1024),
+				Destination: &BuildArgs.CommandLogMaxSize,
+			},
+			&cli.StringFlag{
+				Name:        "ca-bundle",
+				Usage:       "Full path to a PEM encoded CA bundle to trust when downloading artifacts (e.g. Kubernetes manifests)",
+				Destination: &BuildArgs.CABundle,
+			},
+			&cli.DurationFlag{
+				Name:        "download-timeout",
+				Usage:       "Maximum duration of a single artifact download (e.g. 30m)",
+				Value:       time.Hour,
+				Destination: &BuildArgs.DownloadTimeout,
+			},
+			&cli.BoolFlag{
+				Name:        "offline",
+				Usage:       "Fail the build instead of accessing the network if any artifact is not available locally",
+				Destination: &BuildArgs.Offline,
+			},
+			&cli.StringSliceFlag{
+				Name:        "skip-components",
+				Usage:       "Comma-separated list of components (e.g. rpm,registry) which are not configured in the built image",
+				Destination: &BuildArgs.SkipComponents,
+			},
+			&cli.StringFlag{
+				Name:        "hauler-binary",
+				Usage:       "Full path to a custom hauler binary used to populate the embedded artifact registry",
+				Destination: &BuildArgs.HaulerBinary,
+			},
+			&cli.BoolFlag{
+				Name:        "reuse-output-image",
+				Usage:       "Only replace the customizations of an existing RAW output image built from the same base image instead of rebuilding it",
+				Destination: &BuildArgs.ReuseOutputImage,
+			},
+			&cli.BoolFlag{
+				Name:        "sbom",
+				Usage:       "Write an SPDX software bill of materials alongside the built image",
+				Destination: &BuildArgs.SBOM,
+			},
+			&cli.BoolFlag{
+				Name:        "verify-boot",
+				Usage:       "Boot the built image in a headless QEMU virtual machine to check that it is functional",
+				Destination: &BuildArgs.VerifyBoot,
+			},
+			&cli.DurationFlag{
+				Name:        "verify-boot-timeout",
+				Usage:       "Maximum duration to wait for the built image to boot when --verify-boot is specified (e.g. 30m)",
+				Value:       15 * time.Minute,
+				Destination: &BuildArgs.VerifyBootTimeout,
+			},
 		},
 	}
 }
@@ -14,4 +14,14 @@ var (
 		Value:       "/eib",
 		Destination: &BuildArgs.ConfigDir,
 	}
+	PreflightFlag = &cli.BoolFlag{
+		Name:        "preflight",
+		Usage:       "Check that remote resources (e.g. Helm repositories) are reachable during validation",
+		Destination: &BuildArgs.Preflight,
+	}
+	RenderChartsFlag = &cli.BoolFlag{
+		Name:        "render-charts",
+		Usage:       "Template the Helm charts during validation to report rendering errors before the build",
+		Destination: &BuildArgs.RenderCharts,
+	}
 )
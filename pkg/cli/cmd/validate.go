@@ -15,6 +15,8 @@ func NewValidateCommand(action func(*cli.Context) error) *cli.Command {
 		Flags: []cli.Flag{
 			DefinitionFileFlag,
 			ConfigDirFlag,
+			PreflightFlag,
+			RenderChartsFlag,
 		},
 	}
 }
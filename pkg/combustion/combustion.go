@@ -1,12 +1,14 @@
 package combustion
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
 
 	"github.com/suse-edge/edge-image-builder/pkg/fileio"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
@@ -22,6 +24,11 @@ import (
 // Result can also be an empty slice or nil if this is not necessary.
 type configureComponent func(context *image.Context) ([]string, error)
 
+type componentWrapper struct {
+	name     string
+	runnable configureComponent
+}
+
 type networkConfigGenerator interface {
 	GenerateNetworkConfig(configDir, outputDir string, outputWriter io.Writer) error
 }
@@ -31,12 +38,12 @@ type networkConfiguratorInstaller interface {
 }
 
 type kubernetesScriptDownloader interface {
-	DownloadInstallScript(distribution, destinationPath string) (string, error)
+	DownloadInstallScript(ctx context.Context, distribution, destinationPath string) (string, error)
 }
 
 type kubernetesArtefactDownloader interface {
-	DownloadRKE2Artefacts(arch image.Arch, version, cni string, multusEnabled bool, installPath, imagesPath string) error
-	DownloadK3sArtefacts(arch image.Arch, version, installPath, imagesPath string) error
+	DownloadRKE2Artefacts(ctx context.Context, arch image.Arch, version, cni string, multusEnabled bool, installPath, imagesPath string) error
+	DownloadK3sArtefacts(ctx context.Context, arch image.Arch, version, installPath, imagesPath string) error
 }
 
 type rpmResolver interface {
@@ -47,6 +54,15 @@ type rpmRepoCreator interface {
 	Create(path string) error
 }
 
+type imageSizeResolver interface {
+	ImageSize(containerImage string, arch image.Arch) (int64, error)
+}
+
+type registryCache interface {
+	Get(identifier string) (filepath string, err error)
+	Put(identifier string, reader io.Reader) error
+}
+
 type Combustion struct {
 	NetworkConfigGenerator       networkConfigGenerator
 	NetworkConfiguratorInstaller networkConfiguratorInstaller
@@ -55,13 +71,14 @@ type Combustion struct {
 	RPMResolver                  rpmResolver
 	RPMRepoCreator               rpmRepoCreator
 	HelmClient                   image.HelmClient
+	RegistryCache                registryCache
+	ImageSizeResolver            imageSizeResolver
 }
 
 // Configure iterates over all separate Combustion components and configures them independently.
 // If all of those are successful, the Combustion script is assembled and written to the file system.
-func (c *Combustion) Configure(ctx *image.Context) error {
-	var combustionScripts []string
-
+// Cancelling runCtx terminates the running commands and skips the remaining components.
+func (c *Combustion) Configure(runCtx context.Context, ctx *image.Context) error {
 	// EIB Combustion script prefix ranges:
 	// 00-09 -- Networking
 	// 10-19 -- Operating System
@@ -76,10 +93,14 @@ func (c *Combustion) Configure(ctx *image.Context) error {
 	//   being able to override/preempt the built-in behavior
 	// - Elemental & SUMA must come after RPMs since the user must provide the
 	//   elemental and venv-salt-minion RPMs manually
-	type componentWrapper struct {
-		name     string
-		runnable configureComponent
+	configureRegistry := func(ctx *image.Context) ([]string, error) {
+		return c.configureRegistry(runCtx, ctx)
+	}
+
+	configureKubernetes := func(ctx *image.Context) ([]string, error) {
+		return c.configureKubernetes(runCtx, ctx)
 	}
+
 	combustionComponents := []componentWrapper{
 		{
 			name:     messageComponentName,
@@ -97,6 +118,14 @@ func (c *Combustion) Configure(ctx *image.Context) error {
 			name:     networkComponentName,
 			runnable: c.configureNetwork,
 		},
+		{
+			name:     hostnameComponentName,
+			runnable: configureHostname,
+		},
+		{
+			name:     hostsComponentName,
+			runnable: configureHosts,
+		},
 		{
 			name:     groupsComponentName,
 			runnable: configureGroups,
@@ -117,6 +146,14 @@ func (c *Combustion) Configure(ctx *image.Context) error {
 			name:     systemdComponentName,
 			runnable: configureSystemd,
 		},
+		{
+			name:     sysctlComponentName,
+			runnable: configureSysctl,
+		},
+		{
+			name:     firstBootComponentName,
+			runnable: configureFirstBoot,
+		},
 		{
 			name:     elementalComponentName,
 			runnable: configureElemental,
@@ -127,15 +164,19 @@ func (c *Combustion) Configure(ctx *image.Context) error {
 		},
 		{
 			name:     registryComponentName,
-			runnable: c.configureRegistry,
+			runnable: configureRegistry,
 		},
 		{
 			name:     keymapComponentName,
 			runnable: configureKeymap,
 		},
+		{
+			name:     localeComponentName,
+			runnable: configureLocale,
+		},
 		{
 			name:     k8sComponentName,
-			runnable: c.configureKubernetes,
+			runnable: configureKubernetes,
 		},
 		{
 			name:     certsComponentName,
@@ -143,17 +184,13 @@ func (c *Combustion) Configure(ctx *image.Context) error {
 		},
 	}
 
-	for _, component := range combustionComponents {
-		scripts, err := component.runnable(ctx)
-		if err != nil {
-			return fmt.Errorf("configuring component %q: %w", component.name, err)
-		}
-
-		combustionScripts = append(combustionScripts, scripts...)
+	combustionScripts, err := configureComponents(runCtx, ctx, combustionComponents)
+	if err != nil {
+		return err
 	}
 
 	var networkScript string
-	if isComponentConfigured(ctx, networkConfigDir) {
+	if isNetworkConfigured(ctx) && !isComponentSkipped(ctx, networkComponentName) {
 		networkScript = networkConfigScriptName
 	}
 
@@ -170,6 +207,89 @@ func (c *Combustion) Configure(ctx *image.Context) error {
 	return nil
 }
 
+// configureComponents runs the given components in order and aggregates the scripts they produce.
+// Since all scripts are written to the same directory, an error is returned if two components
+// produce a script with the same name instead of silently overwriting one of them.
+func configureComponents(runCtx context.Context, ctx *image.Context, components []componentWrapper) ([]string, error) {
+	var combustionScripts []string
+	scriptComponents := map[string]string{}
+
+	for _, component := range components {
+		if err := runCtx.Err(); err != nil {
+			return nil, fmt.Errorf("configuring components cancelled: %w", err)
+		}
+
+		if isComponentSkipped(ctx, component.name) {
+			log.AuditComponentSkipped(component.name)
+			zap.S().Infof("Skipping %s component. Component skipped by user request", component.name)
+			continue
+		}
+
+		scripts, err := component.runnable(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("configuring component %q: %w", component.name, err)
+		}
+
+		for _, script := range scripts {
+			if existing, ok := scriptComponents[script]; ok {
+				return nil, fmt.Errorf("combustion script %q is produced by both the %q and %q components", script, existing, component.name)
+			}
+			scriptComponents[script] = component.name
+		}
+
+		combustionScripts = append(combustionScripts, scripts...)
+	}
+
+	return combustionScripts, nil
+}
+
+// skippableComponents maps the identifiers accepted when skipping components
+// to the names of the components they refer to.
+var skippableComponents = map[string]string{
+	"certificates": certsComponentName,
+	"custom":       customComponentName,
+	"elemental":    elementalComponentName,
+	"first-boot":   firstBootComponentName,
+	"groups":       groupsComponentName,
+	"hostname":     hostnameComponentName,
+	"hosts":        hostsComponentName,
+	"identifier":   messageComponentName,
+	"keymap":       keymapComponentName,
+	"kubernetes":   k8sComponentName,
+	"locale":       localeComponentName,
+	"network":      networkComponentName,
+	"proxy":        proxyComponentName,
+	"registry":     registryComponentName,
+	"rpm":          rpmComponentName,
+	"suma":         sumaComponentName,
+	"sysctl":       sysctlComponentName,
+	"systemd":      systemdComponentName,
+	"time":         timeComponentName,
+	"users":        usersComponentName,
+}
+
+// SkippableComponents returns the sorted identifiers of the components which can be skipped.
+func SkippableComponents() []string {
+	var ids []string
+	for id := range skippableComponents {
+		ids = append(ids, id)
+	}
+
+	slices.Sort(ids)
+	return ids
+}
+
+// isComponentSkipped determines whether the user requested the component to be skipped.
+func isComponentSkipped(ctx *image.Context, componentName string) bool {
+	for _, id := range ctx.SkipComponents {
+		if skippableComponents[id] == componentName {
+			return true
+		}
+	}
+
+	return false
+}
+
 func generateComponentPath(ctx *image.Context, componentDir string) string {
 	return filepath.Join(ctx.ImageConfigDir, componentDir)
 }
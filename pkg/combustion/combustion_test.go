@@ -1,12 +1,16 @@
 package combustion
 
 import (
+	"context"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/http"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 )
 
@@ -23,12 +27,16 @@ func setupContext(t *testing.T) (ctx *image.Context, teardown func()) {
 	artefactsDir, err := os.MkdirTemp("", "eib-artefacts-")
 	require.NoError(t, err)
 
+	httpClient, err := http.NewClient(http.ClientConfig{})
+	require.NoError(t, err)
+
 	ctx = &image.Context{
 		ImageConfigDir:  configDir,
 		BuildDir:        buildDir,
 		CombustionDir:   combustionDir,
 		ArtefactsDir:    artefactsDir,
 		ImageDefinition: &image.Definition{},
+		HTTPClient:      httpClient,
 	}
 
 	return ctx, func() {
@@ -65,3 +73,136 @@ func TestIsComponentConfigured(t *testing.T) {
 	assert.False(t, isComponentConfigured(ctx, "missing-component"))
 	assert.False(t, isComponentConfigured(ctx, ""))
 }
+
+func TestConfigureSkippedComponent(t *testing.T) {
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition.OperatingSystem.Hostname = "node1"
+	ctx.SkipComponents = []string{"hostname"}
+
+	c := &Combustion{}
+	require.NoError(t, c.Configure(context.Background(), ctx))
+
+	assert.NoFileExists(t, filepath.Join(ctx.CombustionDir, hostnameScriptName))
+
+	script, err := os.ReadFile(filepath.Join(ctx.CombustionDir, "script"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(script), hostnameScriptName)
+}
+
+func TestIsComponentSkipped(t *testing.T) {
+	ctx := &image.Context{
+		SkipComponents: []string{"rpm", "registry", "unknown"},
+	}
+
+	assert.True(t, isComponentSkipped(ctx, rpmComponentName))
+	assert.True(t, isComponentSkipped(ctx, registryComponentName))
+	assert.False(t, isComponentSkipped(ctx, k8sComponentName))
+}
+
+func TestSkippableComponents(t *testing.T) {
+	components := SkippableComponents()
+
+	assert.Contains(t, components, "rpm")
+	assert.Contains(t, components, "registry")
+	assert.IsIncreasing(t, components)
+}
+
+func TestConfigureComponents(t *testing.T) {
+	ctx := &image.Context{}
+
+	components := []componentWrapper{
+		{
+			name: "first",
+			runnable: func(*image.Context) ([]string, error) {
+				return []string{"10-first.sh"}, nil
+			},
+		},
+		{
+			name: "empty",
+			runnable: func(*image.Context) ([]string, error) {
+				return nil, nil
+			},
+		},
+		{
+			name: "second",
+			runnable: func(*image.Context) ([]string, error) {
+				return []string{"20-second.sh", "21-second.sh"}, nil
+			},
+		},
+	}
+
+	scripts, err := configureComponents(context.Background(), ctx, components)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10-first.sh", "20-second.sh", "21-second.sh"}, scripts)
+}
+
+func TestConfigureComponents_DuplicateScripts(t *testing.T) {
+	ctx := &image.Context{}
+
+	components := []componentWrapper{
+		{
+			name: "first",
+			runnable: func(*image.Context) ([]string, error) {
+				return []string{"11-setup.sh"}, nil
+			},
+		},
+		{
+			name: "second",
+			runnable: func(*image.Context) ([]string, error) {
+				return []string{"12-other.sh", "11-setup.sh"}, nil
+			},
+		},
+	}
+
+	scripts, err := configureComponents(context.Background(), ctx, components)
+	require.Error(t, err)
+	assert.EqualError(t, err, `combustion script "11-setup.sh" is produced by both the "first" and "second" components`)
+	assert.Nil(t, scripts)
+}
+
+func TestCombustionScriptOrdering(t *testing.T) {
+	// Combustion executes the scripts in lexical order
+	expectedOrder := []string{
+		networkConfigScriptName,
+		hostsScriptName,
+		certsScriptName,
+		proxyScriptName,
+		installRPMsScriptName,
+		timeScriptName,
+		keymapScriptName,
+		groupsScriptName,
+		usersScriptName,
+		systemdScriptName,
+		sysctlScriptName,
+		hostnameScriptName,
+		localeScriptName,
+		firstBootScriptName,
+		k8sInstallScript,
+		registryScriptName,
+		sumaScriptName,
+		elementalScriptName,
+		messageScriptName,
+	}
+
+	scripts := slices.Clone(expectedOrder)
+	slices.Sort(scripts)
+	assert.Equal(t, expectedOrder, scripts)
+
+	prefixes := map[string]string{}
+	for _, script := range scripts {
+		prefix, _, found := strings.Cut(script, "-")
+		require.True(t, found, script)
+
+		existing, ok := prefixes[prefix]
+		assert.False(t, ok, "scripts %q and %q share the %q prefix", existing, script, prefix)
+		prefixes[prefix] = script
+	}
+
+	// Elemental & SUMA must come after RPMs since the RPMs they depend on are installed there
+	assert.Less(t, installRPMsScriptName, sumaScriptName)
+	assert.Less(t, installRPMsScriptName, elementalScriptName)
+	// Users may be assigned to the configured groups
+	assert.Less(t, groupsScriptName, usersScriptName)
+}
@@ -4,10 +4,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/suse-edge/edge-image-builder/pkg/fileio"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
 )
 
 const (
@@ -15,6 +17,8 @@ const (
 	customScriptsDir    = "scripts"
 	customFilesDir      = "files"
 	customComponentName = "custom files"
+
+	customScriptTemplateSuffix = ".tpl"
 )
 
 func configureCustomFiles(ctx *image.Context) ([]string, error) {
@@ -39,6 +43,33 @@ func configureCustomFiles(ctx *image.Context) ([]string, error) {
 	return scripts, nil
 }
 
+// CustomScriptValues are the values available to the templates of the user provided custom scripts
+// (e.g. '{{ .RegistryPort }}').
+type CustomScriptValues struct {
+	// Hostname is the 'operatingSystem/hostname' field of the image definition.
+	Hostname string
+	// APIVIP is the 'kubernetes/network/apiVIP' field of the image definition.
+	APIVIP string
+	// APIHost is the 'kubernetes/network/apiHost' field of the image definition.
+	APIHost string
+	// KubernetesVersion is the 'kubernetes/version' field of the image definition.
+	KubernetesVersion string
+	// RegistryPort is the port the embedded artifact registry listens on.
+	RegistryPort string
+}
+
+func newCustomScriptValues(ctx *image.Context) CustomScriptValues {
+	def := ctx.ImageDefinition
+
+	return CustomScriptValues{
+		Hostname:          def.OperatingSystem.Hostname,
+		APIVIP:            def.Kubernetes.Network.APIVIP,
+		APIHost:           def.Kubernetes.Network.APIHost,
+		KubernetesVersion: def.Kubernetes.Version,
+		RegistryPort:      registryPort,
+	}
+}
+
 func handleCustomFiles(ctx *image.Context) error {
 	fullFilesDir := generateComponentPath(ctx, filepath.Join(customDir, customFilesDir))
 	_, err := copyCustomFiles(fullFilesDir, ctx.CombustionDir, nil)
@@ -49,7 +80,56 @@ func handleCustomScripts(ctx *image.Context) ([]string, error) {
 	fullScriptsDir := generateComponentPath(ctx, filepath.Join(customDir, customScriptsDir))
 	executablePerms := fileio.ExecutablePerms
 	scripts, err := copyCustomFiles(fullScriptsDir, ctx.CombustionDir, &executablePerms)
-	return scripts, err
+	if err != nil {
+		return nil, err
+	}
+
+	values := newCustomScriptValues(ctx)
+	for i, script := range scripts {
+		if !strings.HasSuffix(script, customScriptTemplateSuffix) {
+			continue
+		}
+
+		var rendered string
+		if rendered, err = renderCustomScript(ctx.CombustionDir, script, &values); err != nil {
+			return nil, fmt.Errorf("templating custom script %s: %w", script, err)
+		}
+		scripts[i] = rendered
+	}
+
+	return scripts, nil
+}
+
+// renderCustomScript applies the template values to a copied custom script opted into templating
+// through the '.tpl' suffix. The rendered script is written without the suffix and its name is returned.
+func renderCustomScript(dir, script string, values *CustomScriptValues) (string, error) {
+	scriptPath := filepath.Join(dir, script)
+	rendered := strings.TrimSuffix(script, customScriptTemplateSuffix)
+	renderedPath := filepath.Join(dir, rendered)
+
+	if _, err := os.Stat(renderedPath); err == nil {
+		return "", fmt.Errorf("rendered script %s already exists", rendered)
+	}
+
+	contents, err := os.ReadFile(scriptPath)
+	if err != nil {
+		return "", fmt.Errorf("reading script: %w", err)
+	}
+
+	data, err := template.Parse(rendered, string(contents), values)
+	if err != nil {
+		return "", fmt.Errorf("parsing script template: %w", err)
+	}
+
+	if err = os.WriteFile(renderedPath, []byte(data), fileio.ExecutablePerms); err != nil {
+		return "", fmt.Errorf("writing script: %w", err)
+	}
+
+	if err = os.Remove(scriptPath); err != nil {
+		return "", fmt.Errorf("removing script template: %w", err)
+	}
+
+	return rendered, nil
 }
 
 func copyCustomFiles(fromDir, toDir string, filePermissions *os.FileMode) ([]string, error) {
@@ -89,6 +89,78 @@ func TestConfigureCustomFiles(t *testing.T) {
 	assert.Contains(t, scripts, "bar.sh")
 }
 
+func TestConfigureCustomFiles_TemplatedScripts(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition.OperatingSystem.Hostname = "node1.suse.com"
+
+	scriptsDir := filepath.Join(ctx.ImageConfigDir, customDir, customScriptsDir)
+	require.NoError(t, os.MkdirAll(scriptsDir, os.ModePerm))
+
+	templated := "#!/bin/bash\ncurl http://{{ .Hostname }}:{{ .RegistryPort }}/v2/_catalog\n"
+	require.NoError(t, os.WriteFile(filepath.Join(scriptsDir, "60-templated.sh.tpl"), []byte(templated), 0o744))
+
+	plain := "#!/bin/bash\necho ${HOSTNAME} {{ .Hostname }} {}\n"
+	require.NoError(t, os.WriteFile(filepath.Join(scriptsDir, "70-plain.sh"), []byte(plain), 0o744))
+
+	// Test
+	scripts, err := configureCustomFiles(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"60-templated.sh", "70-plain.sh"}, scripts)
+
+	contents, err := os.ReadFile(filepath.Join(ctx.CombustionDir, "60-templated.sh"))
+	require.NoError(t, err)
+	assert.Equal(t, "#!/bin/bash\ncurl http://node1.suse.com:6545/v2/_catalog\n", string(contents))
+	assert.NoFileExists(t, filepath.Join(ctx.CombustionDir, "60-templated.sh.tpl"))
+
+	stats, err := os.Stat(filepath.Join(ctx.CombustionDir, "60-templated.sh"))
+	require.NoError(t, err)
+	assert.Equal(t, fileio.ExecutablePerms, stats.Mode())
+
+	contents, err = os.ReadFile(filepath.Join(ctx.CombustionDir, "70-plain.sh"))
+	require.NoError(t, err)
+	assert.Equal(t, plain, string(contents))
+}
+
+func TestConfigureCustomFiles_InvalidScriptTemplate(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	scriptsDir := filepath.Join(ctx.ImageConfigDir, customDir, customScriptsDir)
+	require.NoError(t, os.MkdirAll(scriptsDir, os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(scriptsDir, "60-broken.sh.tpl"), []byte("echo {{ .RegistryPort }"), 0o744))
+
+	// Test
+	scripts, err := configureCustomFiles(ctx)
+
+	// Verify
+	require.ErrorContains(t, err, "templating custom script 60-broken.sh.tpl: parsing script template")
+	assert.Nil(t, scripts)
+}
+
+func TestConfigureCustomFiles_ScriptTemplateConflict(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	scriptsDir := filepath.Join(ctx.ImageConfigDir, customDir, customScriptsDir)
+	require.NoError(t, os.MkdirAll(scriptsDir, os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(scriptsDir, "60-script.sh"), []byte("echo plain"), 0o744))
+	require.NoError(t, os.WriteFile(filepath.Join(scriptsDir, "60-script.sh.tpl"), []byte("echo {{ .RegistryPort }}"), 0o744))
+
+	// Test
+	scripts, err := configureCustomFiles(ctx)
+
+	// Verify
+	require.ErrorContains(t, err, "templating custom script 60-script.sh.tpl: rendered script 60-script.sh already exists")
+	assert.Nil(t, scripts)
+}
+
 func TestConfigureFiles_NoCustomDir(t *testing.T) {
 	// Setup
 	ctx, teardown := setupContext(t)
@@ -11,6 +11,7 @@ import (
 	"github.com/suse-edge/edge-image-builder/pkg/log"
 	"github.com/suse-edge/edge-image-builder/pkg/template"
 	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
 )
 
 const (
@@ -18,6 +19,10 @@ const (
 	elementalConfigDir     = "elemental"
 	elementalScriptName    = "31-elemental.sh"
 	elementalConfigName    = "elemental_config.yaml"
+
+	// elementalEmulatedTPMSeed requests a random seed for the emulated TPM,
+	// so that each node registers with a unique identity.
+	elementalEmulatedTPMSeed = -1
 )
 
 var (
@@ -28,18 +33,42 @@ var (
 )
 
 func configureElemental(ctx *image.Context) ([]string, error) {
-	if !isComponentConfigured(ctx, elementalConfigDir) {
+	elemental := &ctx.ImageDefinition.OperatingSystem.Elemental
+
+	var registrations []elementalRegistrationConfig
+
+	switch {
+	case len(elemental.Registrations) != 0:
+		for _, registration := range elemental.Registrations {
+			configFile := fmt.Sprintf("elemental_config_%s.yaml", registration.Name)
+			if err := writeElementalConfigFile(ctx, &registration, configFile); err != nil {
+				log.AuditComponentFailed(elementalComponentName)
+				return nil, err
+			}
+
+			registrations = append(registrations, elementalRegistrationConfig{
+				ConfigFile: configFile,
+				Hostnames:  registration.Hostnames,
+			})
+		}
+	case elemental.IsConfigured():
+		registration := elemental.SingleRegistration()
+		if err := writeElementalConfigFile(ctx, &registration, elementalConfigName); err != nil {
+			log.AuditComponentFailed(elementalComponentName)
+			return nil, err
+		}
+	case isComponentConfigured(ctx, elementalConfigDir):
+		if err := copyElementalConfigFile(ctx); err != nil {
+			log.AuditComponentFailed(elementalComponentName)
+			return nil, err
+		}
+	default:
 		log.AuditComponentSkipped(elementalComponentName)
 		zap.S().Info("Skipping elemental registration component, configuration is not provided")
 		return nil, nil
 	}
 
-	if err := copyElementalConfigFile(ctx); err != nil {
-		log.AuditComponentFailed(elementalComponentName)
-		return nil, err
-	}
-
-	if err := writeElementalCombustionScript(ctx); err != nil {
+	if err := writeElementalCombustionScript(ctx, registrations); err != nil {
 		log.AuditComponentFailed(elementalComponentName)
 		return nil, err
 	}
@@ -48,8 +77,14 @@ func configureElemental(ctx *image.Context) ([]string, error) {
 	return []string{elementalScriptName}, nil
 }
 
+// elementalRegistrationConfig maps a generated registration config file to the hosts it is applied to.
+type elementalRegistrationConfig struct {
+	ConfigFile string
+	Hostnames  []string
+}
+
 func copyElementalConfigFile(ctx *image.Context) error {
-	srcFile := filepath.Join(ctx.ImageConfigDir, elementalConfigDir, elementalConfigName)
+	srcFile := ElementalConfigPath(ctx)
 	destFile := filepath.Join(ctx.CombustionDir, elementalConfigName)
 
 	err := fileio.CopyFile(srcFile, destFile, fileio.NonExecutablePerms)
@@ -60,14 +95,77 @@ func copyElementalConfigFile(ctx *image.Context) error {
 	return nil
 }
 
-func writeElementalCombustionScript(ctx *image.Context) error {
+// writeElementalConfigFile generates the Elemental registration config from a registration of the definition.
+func writeElementalConfigFile(ctx *image.Context, elemental *image.ElementalRegistration, configName string) error {
+	type registration struct {
+		URL             string `yaml:"url"`
+		CACert          string `yaml:"ca-cert,omitempty"`
+		EmulateTPM      bool   `yaml:"emulate-tpm,omitempty"`
+		EmulatedTPMSeed int    `yaml:"emulated-tpm-seed,omitempty"`
+		Auth            string `yaml:"auth,omitempty"`
+	}
+
+	config := struct {
+		Elemental struct {
+			Registration registration `yaml:"registration"`
+		} `yaml:"elemental"`
+	}{}
+
+	config.Elemental.Registration = registration{
+		URL:        elemental.RegistrationURL,
+		EmulateTPM: elemental.EmulateTPM,
+		Auth:       elemental.AuthType,
+	}
+
+	if elemental.EmulateTPM {
+		config.Elemental.Registration.EmulatedTPMSeed = elementalEmulatedTPMSeed
+	}
+
+	if elemental.CACert != "" {
+		certFile := filepath.Join(ElementalPath(ctx), elemental.CACert)
+		cert, err := os.ReadFile(certFile)
+		if err != nil {
+			return fmt.Errorf("reading elemental CA certificate %s: %w", certFile, err)
+		}
+
+		config.Elemental.Registration.CACert = string(cert)
+	}
+
+	data, err := yaml.Marshal(&config)
+	if err != nil {
+		return fmt.Errorf("serializing elemental config: %w", err)
+	}
+
+	configFile := filepath.Join(ctx.CombustionDir, configName)
+	if err = os.WriteFile(configFile, data, fileio.NonExecutablePerms); err != nil {
+		return fmt.Errorf("writing elemental config file %s: %w", configFile, err)
+	}
+
+	return nil
+}
+
+func writeElementalCombustionScript(ctx *image.Context, registrations []elementalRegistrationConfig) error {
 	elementalScriptFilename := filepath.Join(ctx.CombustionDir, elementalScriptName)
 
+	configFile := elementalConfigName
+	if len(registrations) != 0 {
+		// The registration without hostnames applies to all hosts not matching any other registration
+		configFile = ""
+		for _, registration := range registrations {
+			if len(registration.Hostnames) == 0 {
+				configFile = registration.ConfigFile
+			}
+		}
+	}
+
 	values := struct {
-		ConfigFile string
+		ConfigFile    string
+		Registrations []elementalRegistrationConfig
 	}{
-		ConfigFile: elementalConfigName,
+		ConfigFile:    configFile,
+		Registrations: registrations,
 	}
+
 	data, err := template.Parse(elementalScriptName, elementalScript, &values)
 	if err != nil {
 		return fmt.Errorf("applying template to %s: %w", elementalScriptName, err)
@@ -82,3 +180,8 @@ func writeElementalCombustionScript(ctx *image.Context) error {
 func ElementalPath(ctx *image.Context) string {
 	return filepath.Join(ctx.ImageConfigDir, elementalConfigDir)
 }
+
+// ElementalConfigPath returns the path of the user provided Elemental registration config file.
+func ElementalConfigPath(ctx *image.Context) string {
+	return filepath.Join(ElementalPath(ctx), elementalConfigName)
+}
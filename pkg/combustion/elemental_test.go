@@ -44,13 +44,91 @@ func TestCopyElementalConfigFile(t *testing.T) {
 	assert.Equal(t, "foo: bar", string(found))
 }
 
+func TestWriteElementalConfigFile(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	testConfigDir := filepath.Join(ctx.ImageConfigDir, elementalConfigDir)
+	require.NoError(t, os.Mkdir(testConfigDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(testConfigDir, "rancher.crt"), []byte("-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n"), 0o600))
+
+	registration := &image.ElementalRegistration{
+		RegistrationURL: "https://rancher.example.com/elemental/registration/abc",
+		CACert:          "rancher.crt",
+		EmulateTPM:      true,
+		AuthType:        "tpm",
+	}
+
+	// Test
+	err := writeElementalConfigFile(ctx, registration, elementalConfigName)
+
+	// Verify
+	require.NoError(t, err)
+
+	foundFile := filepath.Join(ctx.CombustionDir, elementalConfigName)
+	found, err := os.ReadFile(foundFile)
+	require.NoError(t, err)
+
+	expected := `elemental:
+    registration:
+        url: https://rancher.example.com/elemental/registration/abc
+        ca-cert: |
+            -----BEGIN CERTIFICATE-----
+            MIIB
+            -----END CERTIFICATE-----
+        emulate-tpm: true
+        emulated-tpm-seed: -1
+        auth: tpm
+`
+	assert.Equal(t, expected, string(found))
+}
+
+func TestWriteElementalConfigFile_MissingCACert(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	registration := &image.ElementalRegistration{
+		RegistrationURL: "https://rancher.example.com/elemental/registration/abc",
+		CACert:          "rancher.crt",
+	}
+
+	// Test
+	err := writeElementalConfigFile(ctx, registration, elementalConfigName)
+
+	// Verify
+	require.ErrorContains(t, err, "reading elemental CA certificate")
+}
+
+func TestConfigureElemental_Definition(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition.OperatingSystem.Elemental = image.Elemental{
+		RegistrationURL: "https://rancher.example.com/elemental/registration/abc",
+	}
+
+	// Test
+	scripts, err := configureElemental(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Equal(t, []string{elementalScriptName}, scripts)
+
+	found, err := os.ReadFile(filepath.Join(ctx.CombustionDir, elementalConfigName))
+	require.NoError(t, err)
+	assert.Equal(t, "elemental:\n    registration:\n        url: https://rancher.example.com/elemental/registration/abc\n", string(found))
+}
+
 func TestWriteElementalCombustionScript(t *testing.T) {
 	// Setup
 	ctx, teardown := setupContext(t)
 	defer teardown()
 
 	// Test
-	err := writeElementalCombustionScript(ctx)
+	err := writeElementalCombustionScript(ctx, nil)
 
 	// Verify
 	require.NoError(t, err)
@@ -67,4 +145,51 @@ func TestWriteElementalCombustionScript(t *testing.T) {
 	assert.Contains(t, found, "/etc/systemd/system/elemental-reset.service")
 	assert.Contains(t, found, "mkdir -p /opt/edge/")
 	assert.Contains(t, found, "cat <<- \\EOF > /opt/edge/elemental_node_cleanup.sh")
+	assert.Contains(t, found, "cp ./elemental_config.yaml /etc/elemental/config.yaml")
+	assert.NotContains(t, found, "declare -A registrations")
+}
+
+func TestConfigureElemental_MultipleRegistrations(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition.OperatingSystem.Elemental = image.Elemental{
+		Registrations: []image.ElementalRegistration{
+			{
+				Name:            "control-plane",
+				Hostnames:       []string{"node1.suse.com", "node2.suse.com"},
+				RegistrationURL: "https://rancher.example.com/elemental/registration/control-plane",
+			},
+			{
+				Name:            "workers",
+				RegistrationURL: "https://rancher.example.com/elemental/registration/workers",
+				AuthType:        "mac",
+			},
+		},
+	}
+
+	// Test
+	scripts, err := configureElemental(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Equal(t, []string{elementalScriptName}, scripts)
+
+	found, err := os.ReadFile(filepath.Join(ctx.CombustionDir, "elemental_config_control-plane.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "elemental:\n    registration:\n        url: https://rancher.example.com/elemental/registration/control-plane\n", string(found))
+
+	found, err = os.ReadFile(filepath.Join(ctx.CombustionDir, "elemental_config_workers.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "elemental:\n    registration:\n        url: https://rancher.example.com/elemental/registration/workers\n        auth: mac\n", string(found))
+
+	assert.NoFileExists(t, filepath.Join(ctx.CombustionDir, elementalConfigName))
+
+	foundBytes, err := os.ReadFile(filepath.Join(ctx.CombustionDir, elementalScriptName))
+	require.NoError(t, err)
+	script := string(foundBytes)
+	assert.Contains(t, script, "registrations[node1.suse.com]=elemental_config_control-plane.yaml\nregistrations[node2.suse.com]=elemental_config_control-plane.yaml\n")
+	assert.Contains(t, script, `CONFIG_FILE="${registrations[$HOSTNAME]:-elemental_config_workers.yaml}"`)
+	assert.Contains(t, script, `cp "./$CONFIG_FILE" /etc/elemental/config.yaml`)
 }
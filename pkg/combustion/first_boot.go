@@ -0,0 +1,106 @@
+package combustion
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+)
+
+const (
+	firstBootComponentName = "first boot scripts"
+	firstBootScriptName    = "18-first-boot.sh"
+	firstBootInstallDir    = "/opt/eib/first-boot"
+	firstBootDoneFile      = "/var/lib/eib-first-boot.done"
+
+	FirstBootDir        = "firstBoot"
+	FirstBootScriptsDir = "scripts"
+	FirstBootUnitName   = "eib-first-boot.service"
+)
+
+var (
+	//go:embed templates/18-first-boot.sh.tpl
+	firstBootScript string
+
+	//go:embed templates/first-boot.service.tpl
+	firstBootUnit string
+)
+
+// configureFirstBoot packages the scripts in the 'firstBoot/scripts' directory as a systemd oneshot unit,
+// which runs them on the first boot of the installed system instead of during combustion.
+func configureFirstBoot(ctx *image.Context) ([]string, error) {
+	if !isComponentConfigured(ctx, filepath.Join(FirstBootDir, FirstBootScriptsDir)) {
+		log.AuditComponentSkipped(firstBootComponentName)
+		return nil, nil
+	}
+
+	if err := writeFirstBootFiles(ctx); err != nil {
+		log.AuditComponentFailed(firstBootComponentName)
+		return nil, err
+	}
+
+	log.AuditComponentSuccessful(firstBootComponentName)
+	return []string{firstBootScriptName}, nil
+}
+
+func writeFirstBootFiles(ctx *image.Context) error {
+	srcDir := generateComponentPath(ctx, filepath.Join(FirstBootDir, FirstBootScriptsDir))
+	destDir := filepath.Join(ctx.CombustionDir, FirstBootDir)
+
+	if err := os.MkdirAll(destDir, os.ModePerm); err != nil {
+		return fmt.Errorf("creating first boot directory %s: %w", destDir, err)
+	}
+
+	executablePerms := fileio.ExecutablePerms
+	scripts, err := copyCustomFiles(srcDir, destDir, &executablePerms)
+	if err != nil {
+		return fmt.Errorf("copying first boot scripts: %w", err)
+	}
+
+	unitValues := struct {
+		Scripts    []string
+		InstallDir string
+		DoneFile   string
+	}{
+		Scripts:    scripts,
+		InstallDir: firstBootInstallDir,
+		DoneFile:   firstBootDoneFile,
+	}
+
+	data, err := template.Parse(FirstBootUnitName, firstBootUnit, &unitValues)
+	if err != nil {
+		return fmt.Errorf("applying template to %s: %w", FirstBootUnitName, err)
+	}
+
+	unitFilename := filepath.Join(destDir, FirstBootUnitName)
+	if err = os.WriteFile(unitFilename, []byte(data), fileio.NonExecutablePerms); err != nil {
+		return fmt.Errorf("writing file %s: %w", unitFilename, err)
+	}
+
+	scriptValues := struct {
+		ScriptsDir string
+		InstallDir string
+		UnitName   string
+	}{
+		ScriptsDir: FirstBootDir,
+		InstallDir: firstBootInstallDir,
+		UnitName:   FirstBootUnitName,
+	}
+
+	data, err = template.Parse(firstBootScriptName, firstBootScript, &scriptValues)
+	if err != nil {
+		return fmt.Errorf("applying template to %s: %w", firstBootScriptName, err)
+	}
+
+	scriptFilename := filepath.Join(ctx.CombustionDir, firstBootScriptName)
+	if err = os.WriteFile(scriptFilename, []byte(data), fileio.ExecutablePerms); err != nil {
+		return fmt.Errorf("writing file %s: %w", scriptFilename, err)
+	}
+
+	return nil
+}
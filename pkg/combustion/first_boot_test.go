@@ -0,0 +1,89 @@
+package combustion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+)
+
+func TestConfigureFirstBoot_NotConfigured(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	// Test
+	scripts, err := configureFirstBoot(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Nil(t, scripts)
+}
+
+func TestConfigureFirstBoot(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	scriptsDir := filepath.Join(ctx.ImageConfigDir, FirstBootDir, FirstBootScriptsDir)
+	require.NoError(t, os.MkdirAll(scriptsDir, os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(scriptsDir, "20-second.sh"), []byte("#!/bin/bash\necho second"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(scriptsDir, "10-first.sh"), []byte("#!/bin/bash\necho first"), 0o744))
+
+	// Test
+	scripts, err := configureFirstBoot(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Equal(t, []string{firstBootScriptName}, scripts)
+
+	// - the combustion script installs and enables the unit
+	scriptFilename := filepath.Join(ctx.CombustionDir, firstBootScriptName)
+	info, err := os.Stat(scriptFilename)
+	require.NoError(t, err)
+	assert.Equal(t, fileio.ExecutablePerms, info.Mode())
+
+	data, err := os.ReadFile(scriptFilename)
+	require.NoError(t, err)
+	script := string(data)
+	assert.Contains(t, script, "cp ./firstBoot/* /opt/eib/first-boot/.")
+	assert.Contains(t, script, "mv /opt/eib/first-boot/eib-first-boot.service /etc/systemd/system/eib-first-boot.service")
+	assert.Contains(t, script, "systemctl enable eib-first-boot.service")
+
+	// - the scripts are copied alongside the unit
+	firstBootDir := filepath.Join(ctx.CombustionDir, FirstBootDir)
+	for _, name := range []string{"10-first.sh", "20-second.sh"} {
+		info, err = os.Stat(filepath.Join(firstBootDir, name))
+		require.NoError(t, err)
+		assert.Equal(t, fileio.ExecutablePerms, info.Mode())
+	}
+
+	// - the unit runs the scripts in order once
+	data, err = os.ReadFile(filepath.Join(firstBootDir, FirstBootUnitName))
+	require.NoError(t, err)
+	unit := string(data)
+	assert.Contains(t, unit, "Type=oneshot")
+	assert.Contains(t, unit, "ConditionPathExists=!/var/lib/eib-first-boot.done")
+	assert.Contains(t, unit, "ExecStart=/opt/eib/first-boot/10-first.sh\nExecStart=/opt/eib/first-boot/20-second.sh\n")
+	assert.Contains(t, unit, "ExecStartPost=/usr/bin/touch /var/lib/eib-first-boot.done")
+	assert.Contains(t, unit, "WantedBy=multi-user.target")
+}
+
+func TestConfigureFirstBoot_EmptyScriptsDir(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	scriptsDir := filepath.Join(ctx.ImageConfigDir, FirstBootDir, FirstBootScriptsDir)
+	require.NoError(t, os.MkdirAll(scriptsDir, os.ModePerm))
+
+	// Test
+	scripts, err := configureFirstBoot(ctx)
+
+	// Verify
+	require.ErrorContains(t, err, "copying first boot scripts: no files found in directory")
+	assert.Nil(t, scripts)
+}
@@ -27,19 +27,25 @@ func configureGroups(ctx *image.Context) ([]string, error) {
 		return nil, nil
 	}
 
+	if err := writeGroupsCombustionScript(ctx); err != nil {
+		log.AuditComponentFailed(groupsComponentName)
+		return nil, err
+	}
+
+	log.AuditComponentSuccessful(groupsComponentName)
+	return []string{groupsScriptName}, nil
+}
+
+func writeGroupsCombustionScript(ctx *image.Context) error {
 	data, err := template.Parse(groupsScriptName, groupsScript, ctx.ImageDefinition.OperatingSystem.Groups)
 	if err != nil {
-		log.AuditComponentFailed(groupsComponentName)
-		return nil, fmt.Errorf("parsing the group script template: %w", err)
+		return fmt.Errorf("parsing the group script template: %w", err)
 	}
 
 	filename := filepath.Join(ctx.CombustionDir, groupsScriptName)
-	err = os.WriteFile(filename, []byte(data), fileio.ExecutablePerms)
-	if err != nil {
-		log.AuditComponentFailed(groupsComponentName)
-		return nil, fmt.Errorf("writing %s to the combustion directory: %w", groupsScriptName, err)
+	if err = os.WriteFile(filename, []byte(data), fileio.ExecutablePerms); err != nil {
+		return fmt.Errorf("writing %s to the combustion directory: %w", groupsScriptName, err)
 	}
 
-	log.AuditComponentSuccessful(groupsComponentName)
-	return []string{groupsScriptName}, nil
+	return nil
 }
@@ -0,0 +1,116 @@
+package combustion
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"sync"
+)
+
+type haulerStage string
+
+const (
+	haulerStageAdding haulerStage = "adding"
+	haulerStageAdded  haulerStage = "added"
+	haulerStageSaved  haulerStage = "saved"
+)
+
+type haulerProgressEvent struct {
+	Stage haulerStage
+	// Reference is the image (or archive, for the saved stage) referenced by the event.
+	Reference string
+}
+
+// The patterns are deliberately loose as hauler's log format is not a stable API.
+// Lines which do not match any of them are simply not reported as progress.
+var haulerProgressPatterns = []struct {
+	stage   haulerStage
+	pattern *regexp.Regexp
+}{
+	{
+		stage:   haulerStageAdding,
+		pattern: regexp.MustCompile(`\badding '?image'? .*?\[([^\]]+)\]`),
+	},
+	{
+		stage:   haulerStageAdded,
+		pattern: regexp.MustCompile(`\badded '?image'? .*?\[([^\]]+)\]`),
+	},
+	{
+		stage:   haulerStageSaved,
+		pattern: regexp.MustCompile(`\bsaved store .*?->\s*\[([^\]]+)\]`),
+	},
+}
+
+func parseHaulerProgress(line string) (*haulerProgressEvent, bool) {
+	for _, p := range haulerProgressPatterns {
+		if matches := p.pattern.FindStringSubmatch(line); matches != nil {
+			return &haulerProgressEvent{
+				Stage:     p.stage,
+				Reference: matches[1],
+			}, true
+		}
+	}
+
+	return nil, false
+}
+
+// haulerProgressWriter forwards hauler's output to the underlying writer
+// while reporting any recognized progress lines through the provided callback.
+type haulerProgressWriter struct {
+	out      io.Writer
+	onEvent  func(*haulerProgressEvent)
+	lock     sync.Mutex
+	leftover []byte
+}
+
+func newHaulerProgressWriter(out io.Writer, onEvent func(*haulerProgressEvent)) *haulerProgressWriter {
+	return &haulerProgressWriter{
+		out:     out,
+		onEvent: onEvent,
+	}
+}
+
+func (w *haulerProgressWriter) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	n, err := w.out.Write(p)
+	if err != nil {
+		return n, err
+	}
+
+	w.leftover = append(w.leftover, p...)
+
+	for {
+		i := bytes.IndexByte(w.leftover, '\n')
+		if i < 0 {
+			break
+		}
+
+		w.report(string(w.leftover[:i]))
+		w.leftover = w.leftover[i+1:]
+	}
+
+	return n, nil
+}
+
+// Flush reports any trailing output which was not terminated by a new line.
+func (w *haulerProgressWriter) Flush() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if len(w.leftover) != 0 {
+		w.report(string(w.leftover))
+		w.leftover = nil
+	}
+}
+
+func (w *haulerProgressWriter) report(line string) {
+	if w.onEvent == nil {
+		return
+	}
+
+	if event, ok := parseHaulerProgress(line); ok {
+		w.onEvent(event)
+	}
+}
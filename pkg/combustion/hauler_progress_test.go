@@ -0,0 +1,102 @@
+package combustion
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHaulerProgress(t *testing.T) {
+	tests := map[string]struct {
+		line          string
+		expectedEvent *haulerProgressEvent
+	}{
+		"Adding image": {
+			line: "2024-03-20 10:15:32 INF adding 'image' [docker.io/library/nginx:1.25] to the store",
+			expectedEvent: &haulerProgressEvent{
+				Stage:     haulerStageAdding,
+				Reference: "docker.io/library/nginx:1.25",
+			},
+		},
+		"Image added": {
+			line: "2024-03-20 10:15:40 INF successfully added 'image' [docker.io/library/nginx:1.25]",
+			expectedEvent: &haulerProgressEvent{
+				Stage:     haulerStageAdded,
+				Reference: "docker.io/library/nginx:1.25",
+			},
+		},
+		"Image added (legacy format)": {
+			line: "10:15AM INF added 'image' to store at [index.docker.io/library/nginx:1.25], with digest [sha256:abc]",
+			expectedEvent: &haulerProgressEvent{
+				Stage:     haulerStageAdded,
+				Reference: "index.docker.io/library/nginx:1.25",
+			},
+		},
+		"Store saved": {
+			line: "2024-03-20 10:16:02 INF saved store [store] -> [nginx-registry.tar.zst]",
+			expectedEvent: &haulerProgressEvent{
+				Stage:     haulerStageSaved,
+				Reference: "nginx-registry.tar.zst",
+			},
+		},
+		"Unrecognized line": {
+			line: "2024-03-20 10:15:33 DBG layer sha256:abc copied",
+		},
+		"Empty line": {
+			line: "",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			event, ok := parseHaulerProgress(test.line)
+
+			if test.expectedEvent == nil {
+				assert.False(t, ok)
+				assert.Nil(t, event)
+			} else {
+				require.True(t, ok)
+				assert.Equal(t, test.expectedEvent, event)
+			}
+		})
+	}
+}
+
+func TestHaulerProgressWriter(t *testing.T) {
+	// Setup
+	output := "2024-03-20 10:15:32 INF adding 'image' [docker.io/library/nginx:1.25] to the store\n" +
+		"2024-03-20 10:15:33 DBG layer sha256:abc copied\n" +
+		"2024-03-20 10:15:40 INF successfully added 'image' [docker.io/library/nginx:1.25]"
+
+	var out bytes.Buffer
+	var events []haulerProgressEvent
+
+	w := newHaulerProgressWriter(&out, func(event *haulerProgressEvent) {
+		events = append(events, *event)
+	})
+
+	// Test
+	// Write in small chunks to ensure lines split across writes are reassembled
+	data := []byte(output)
+	for len(data) > 0 {
+		n := min(7, len(data))
+
+		written, err := w.Write(data[:n])
+		require.NoError(t, err)
+		require.Equal(t, n, written)
+
+		data = data[n:]
+	}
+	w.Flush()
+
+	// Verify
+	assert.Equal(t, output, out.String())
+
+	expectedEvents := []haulerProgressEvent{
+		{Stage: haulerStageAdding, Reference: "docker.io/library/nginx:1.25"},
+		{Stage: haulerStageAdded, Reference: "docker.io/library/nginx:1.25"},
+	}
+	assert.Equal(t, expectedEvents, events)
+}
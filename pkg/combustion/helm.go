@@ -1,10 +1,34 @@
 package combustion
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
 	"github.com/suse-edge/edge-image-builder/pkg/env"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 )
 
+const (
+	HelmChartsFormatTable = "table"
+	HelmChartsFormatJSON  = "json"
+
+	// metalLBChartVersion and endpointCopierChartVersion are the chart versions deployed to serve the apiVIP
+	// unless overridden in the definition.
+	metalLBChartVersion        = "0.14.3"
+	endpointCopierChartVersion = "0.2.0"
+)
+
+// HelmChartSummary describes a Helm chart which is installed on the image.
+type HelmChartSummary struct {
+	Name          string `json:"name"`
+	RepositoryURL string `json:"repositoryURL"`
+	Version       string `json:"version"`
+	// Component indicates that the chart is automatically added for a configured component (e.g. MetalLB for the apiVIP).
+	Component bool `json:"component"`
+}
+
 func ComponentHelmCharts(ctx *image.Context) ([]image.HelmChart, []image.HelmRepository) {
 	if ctx.ImageDefinition.Kubernetes.Version == "" {
 		return nil, nil
@@ -18,14 +42,25 @@ func ComponentHelmCharts(ctx *image.Context) ([]image.HelmChart, []image.HelmRep
 	var charts []image.HelmChart
 	var repos []image.HelmRepository
 
-	if ctx.ImageDefinition.Kubernetes.Network.APIVIP != "" {
+	network := &ctx.ImageDefinition.Kubernetes.Network
+	if network.APIVIP != "" && network.IsVIPManaged() {
+		metalLBVersion := metalLBChartVersion
+		if network.MetalLBVersion != "" {
+			metalLBVersion = network.MetalLBVersion
+		}
+
+		endpointCopierVersion := endpointCopierChartVersion
+		if network.EndpointCopierVersion != "" {
+			endpointCopierVersion = network.EndpointCopierVersion
+		}
+
 		metalLBChart := image.HelmChart{
 			Name:                  "metallb",
 			RepositoryName:        suseEdgeRepositoryName,
 			TargetNamespace:       "metallb-system",
 			CreateNamespace:       true,
 			InstallationNamespace: installationNamespace,
-			Version:               "0.14.3",
+			Version:               metalLBVersion,
 		}
 
 		endpointCopierOperatorChart := image.HelmChart{
@@ -34,7 +69,7 @@ func ComponentHelmCharts(ctx *image.Context) ([]image.HelmChart, []image.HelmRep
 			TargetNamespace:       "endpoint-copier-operator",
 			CreateNamespace:       true,
 			InstallationNamespace: installationNamespace,
-			Version:               "0.2.0",
+			Version:               endpointCopierVersion,
 		}
 
 		charts = append(charts, metalLBChart, endpointCopierOperatorChart)
@@ -49,3 +84,79 @@ func ComponentHelmCharts(ctx *image.Context) ([]image.HelmChart, []image.HelmRep
 
 	return charts, repos
 }
+
+// HelmChartSummaries lists the user defined Helm charts followed by the ones automatically added for the
+// configured components, along with their repositories and versions. None of the charts are downloaded.
+func HelmChartSummaries(ctx *image.Context) []HelmChartSummary {
+	if ctx.ImageDefinition.Kubernetes.Version == "" {
+		return nil
+	}
+
+	helm := &ctx.ImageDefinition.Kubernetes.Helm
+	componentCharts, componentRepos := ComponentHelmCharts(ctx)
+
+	repositoryURLs := map[string]string{}
+	for _, repo := range helm.Repositories {
+		repositoryURLs[repo.Name] = repo.URL
+	}
+	for _, repo := range componentRepos {
+		repositoryURLs[repo.Name] = repo.URL
+	}
+
+	var summaries []HelmChartSummary
+
+	for _, chart := range helm.Charts {
+		summaries = append(summaries, HelmChartSummary{
+			Name:          chart.Name,
+			RepositoryURL: repositoryURLs[chart.RepositoryName],
+			Version:       chart.Version,
+		})
+	}
+
+	for _, chart := range componentCharts {
+		summaries = append(summaries, HelmChartSummary{
+			Name:          chart.Name,
+			RepositoryURL: repositoryURLs[chart.RepositoryName],
+			Version:       chart.Version,
+			Component:     true,
+		})
+	}
+
+	return summaries
+}
+
+// WriteHelmChartSummaries writes the given chart summaries to w either as a table or as JSON.
+func WriteHelmChartSummaries(w io.Writer, summaries []HelmChartSummary, format string) error {
+	switch format {
+	case HelmChartsFormatJSON:
+		if summaries == nil {
+			summaries = []HelmChartSummary{}
+		}
+
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(summaries); err != nil {
+			return fmt.Errorf("encoding helm chart summaries: %w", err)
+		}
+	case HelmChartsFormatTable:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "NAME\tVERSION\tREPOSITORY\tSOURCE")
+
+		for _, summary := range summaries {
+			source := "definition"
+			if summary.Component {
+				source = "component"
+			}
+
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", summary.Name, summary.Version, summary.RepositoryURL, source)
+		}
+
+		if err := tw.Flush(); err != nil {
+			return fmt.Errorf("writing helm chart summaries: %w", err)
+		}
+	default:
+		return fmt.Errorf("unsupported format '%s'", format)
+	}
+
+	return nil
+}
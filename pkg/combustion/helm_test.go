@@ -0,0 +1,170 @@
+package combustion
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/env"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestComponentHelmCharts(t *testing.T) {
+	disabled := false
+	enabled := true
+
+	tests := map[string]struct {
+		kubernetes     image.Kubernetes
+		expectedCharts []string
+		expectedRepos  []string
+	}{
+		`kubernetes not configured`: {},
+		`no apiVIP`: {
+			kubernetes: image.Kubernetes{
+				Version: "v1.30.3+rke2r1",
+			},
+		},
+		`apiVIP`: {
+			kubernetes: image.Kubernetes{
+				Version: "v1.30.3+rke2r1",
+				Network: image.Network{
+					APIVIP: "192.168.122.100",
+				},
+			},
+			expectedCharts: []string{"metallb", "endpoint-copier-operator"},
+			expectedRepos:  []string{"suse-edge"},
+		},
+		`apiVIP explicitly managed`: {
+			kubernetes: image.Kubernetes{
+				Version: "v1.30.3+k3s1",
+				Network: image.Network{
+					APIVIP:    "192.168.122.100",
+					ManageVIP: &enabled,
+				},
+			},
+			expectedCharts: []string{"metallb", "endpoint-copier-operator"},
+			expectedRepos:  []string{"suse-edge"},
+		},
+		`apiVIP not managed`: {
+			kubernetes: image.Kubernetes{
+				Version: "v1.30.3+rke2r1",
+				Network: image.Network{
+					APIVIP:    "192.168.122.100",
+					ManageVIP: &disabled,
+				},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := &image.Context{
+				ImageDefinition: &image.Definition{
+					Kubernetes: test.kubernetes,
+				},
+			}
+
+			charts, repos := ComponentHelmCharts(ctx)
+
+			var chartNames []string
+			for _, chart := range charts {
+				chartNames = append(chartNames, chart.Name)
+			}
+
+			var repoNames []string
+			for _, repo := range repos {
+				repoNames = append(repoNames, repo.Name)
+			}
+
+			assert.Equal(t, test.expectedCharts, chartNames)
+			assert.Equal(t, test.expectedRepos, repoNames)
+		})
+	}
+}
+
+func TestHelmChartSummaries(t *testing.T) {
+	ctx := &image.Context{
+		ImageDefinition: &image.Definition{
+			Kubernetes: image.Kubernetes{
+				Version: "v1.30.3+rke2r1",
+				Network: image.Network{
+					APIVIP: "192.168.122.100",
+				},
+				Helm: image.Helm{
+					Charts: []image.HelmChart{
+						{
+							Name:           "apache",
+							RepositoryName: "apache-repo",
+							Version:        "10.7.0",
+						},
+					},
+					Repositories: []image.HelmRepository{
+						{
+							Name: "apache-repo",
+							URL:  "oci://registry-1.docker.io/bitnamicharts",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	summaries := HelmChartSummaries(ctx)
+
+	expected := []HelmChartSummary{
+		{Name: "apache", RepositoryURL: "oci://registry-1.docker.io/bitnamicharts", Version: "10.7.0"},
+		{Name: "metallb", RepositoryURL: env.EdgeHelmRepository, Version: "0.14.3", Component: true},
+		{Name: "endpoint-copier-operator", RepositoryURL: env.EdgeHelmRepository, Version: "0.2.0", Component: true},
+	}
+	assert.Equal(t, expected, summaries)
+
+	var jsonOutput bytes.Buffer
+	require.NoError(t, WriteHelmChartSummaries(&jsonOutput, summaries, HelmChartsFormatJSON))
+
+	var decoded []HelmChartSummary
+	require.NoError(t, json.Unmarshal(jsonOutput.Bytes(), &decoded))
+	assert.Equal(t, expected, decoded)
+
+	var tableOutput bytes.Buffer
+	require.NoError(t, WriteHelmChartSummaries(&tableOutput, summaries, HelmChartsFormatTable))
+	assert.Contains(t, tableOutput.String(), "NAME")
+	assert.Contains(t, tableOutput.String(), "apache")
+	assert.Contains(t, tableOutput.String(), "metallb")
+	assert.Contains(t, tableOutput.String(), "endpoint-copier-operator")
+
+	assert.EqualError(t, WriteHelmChartSummaries(&tableOutput, summaries, "yaml"), "unsupported format 'yaml'")
+}
+
+func TestComponentHelmCharts_VersionOverrides(t *testing.T) {
+	ctx := &image.Context{
+		ImageDefinition: &image.Definition{
+			Kubernetes: image.Kubernetes{
+				Version: "v1.30.3+rke2r1",
+				Network: image.Network{
+					APIVIP:                "192.168.122.100",
+					MetalLBVersion:        "0.14.9",
+					EndpointCopierVersion: "0.2.1-patched.1",
+				},
+			},
+		},
+	}
+
+	charts, _ := ComponentHelmCharts(ctx)
+	require.Len(t, charts, 2)
+
+	assert.Equal(t, "metallb", charts[0].Name)
+	assert.Equal(t, "0.14.9", charts[0].Version)
+	assert.Equal(t, "endpoint-copier-operator", charts[1].Name)
+	assert.Equal(t, "0.2.1-patched.1", charts[1].Version)
+
+	ctx.ImageDefinition.Kubernetes.Network.MetalLBVersion = ""
+	ctx.ImageDefinition.Kubernetes.Network.EndpointCopierVersion = ""
+
+	charts, _ = ComponentHelmCharts(ctx)
+	require.Len(t, charts, 2)
+
+	assert.Equal(t, metalLBChartVersion, charts[0].Version)
+	assert.Equal(t, endpointCopierChartVersion, charts[1].Version)
+}
@@ -0,0 +1,50 @@
+package combustion
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+)
+
+const (
+	hostnameComponentName = "hostname"
+	hostnameScriptName    = "16-hostname-setup.sh"
+)
+
+//go:embed templates/16-hostname-setup.sh.tpl
+var hostnameScript string
+
+func configureHostname(ctx *image.Context) ([]string, error) {
+	if ctx.ImageDefinition.OperatingSystem.Hostname == "" {
+		log.AuditComponentSkipped(hostnameComponentName)
+		return nil, nil
+	}
+
+	if err := writeHostnameCombustionScript(ctx); err != nil {
+		log.AuditComponentFailed(hostnameComponentName)
+		return nil, err
+	}
+
+	log.AuditComponentSuccessful(hostnameComponentName)
+	return []string{hostnameScriptName}, nil
+}
+
+func writeHostnameCombustionScript(ctx *image.Context) error {
+	hostnameScriptFilename := filepath.Join(ctx.CombustionDir, hostnameScriptName)
+
+	data, err := template.Parse(hostnameScriptName, hostnameScript, ctx.ImageDefinition.OperatingSystem)
+	if err != nil {
+		return fmt.Errorf("applying template to %s: %w", hostnameScriptName, err)
+	}
+
+	if err := os.WriteFile(hostnameScriptFilename, []byte(data), fileio.ExecutablePerms); err != nil {
+		return fmt.Errorf("writing file %s: %w", hostnameScriptFilename, err)
+	}
+	return nil
+}
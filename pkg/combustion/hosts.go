@@ -0,0 +1,50 @@
+package combustion
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+)
+
+const (
+	hostsComponentName = "hosts"
+	hostsScriptName    = "06-hosts-setup.sh"
+)
+
+//go:embed templates/06-hosts-setup.sh.tpl
+var hostsScript string
+
+func configureHosts(ctx *image.Context) ([]string, error) {
+	if len(ctx.ImageDefinition.OperatingSystem.HostEntries) == 0 {
+		log.AuditComponentSkipped(hostsComponentName)
+		return nil, nil
+	}
+
+	if err := writeHostsCombustionScript(ctx); err != nil {
+		log.AuditComponentFailed(hostsComponentName)
+		return nil, err
+	}
+
+	log.AuditComponentSuccessful(hostsComponentName)
+	return []string{hostsScriptName}, nil
+}
+
+func writeHostsCombustionScript(ctx *image.Context) error {
+	hostsScriptFilename := filepath.Join(ctx.CombustionDir, hostsScriptName)
+
+	data, err := template.Parse(hostsScriptName, hostsScript, ctx.ImageDefinition.OperatingSystem)
+	if err != nil {
+		return fmt.Errorf("applying template to %s: %w", hostsScriptName, err)
+	}
+
+	if err := os.WriteFile(hostsScriptFilename, []byte(data), fileio.ExecutablePerms); err != nil {
+		return fmt.Errorf("writing file %s: %w", hostsScriptFilename, err)
+	}
+	return nil
+}
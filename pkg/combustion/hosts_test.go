@@ -0,0 +1,71 @@
+package combustion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestConfigureHosts_NoConf(t *testing.T) {
+	// Setup
+	var ctx image.Context
+
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{},
+	}
+
+	// Test
+	scripts, err := configureHosts(&ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Nil(t, scripts)
+}
+
+func TestConfigureHosts_FullConfiguration(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			HostEntries: []image.HostEntry{
+				{
+					IP:        "192.168.122.100",
+					Hostnames: []string{"api.cluster01.edge.suse.com", "node1.edge.suse.com"},
+				},
+				{
+					IP:        "192.168.122.101",
+					Hostnames: []string{"node2.edge.suse.com"},
+				},
+			},
+		},
+	}
+
+	// Test
+	scripts, err := configureHosts(ctx)
+
+	// Verify
+	require.NoError(t, err)
+
+	require.Len(t, scripts, 1)
+	assert.Equal(t, hostsScriptName, scripts[0])
+
+	expectedFilename := filepath.Join(ctx.CombustionDir, hostsScriptName)
+	foundBytes, err := os.ReadFile(expectedFilename)
+	require.NoError(t, err)
+
+	stats, err := os.Stat(expectedFilename)
+	require.NoError(t, err)
+	assert.Equal(t, fileio.ExecutablePerms, stats.Mode())
+
+	foundContents := string(foundBytes)
+	assert.Contains(t, foundContents, "cat <<- EOF >> /etc/hosts")
+	assert.Contains(t, foundContents, "192.168.122.100 api.cluster01.edge.suse.com node1.edge.suse.com\n")
+	assert.Contains(t, foundContents, "192.168.122.101 node2.edge.suse.com\n")
+}
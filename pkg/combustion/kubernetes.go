@@ -1,6 +1,7 @@
 package combustion
 
 import (
+	"context"
 	_ "embed"
 	"fmt"
 	"os"
@@ -50,7 +51,7 @@ var (
 	k8sVIPManifest string
 )
 
-func (c *Combustion) configureKubernetes(ctx *image.Context) ([]string, error) {
+func (c *Combustion) configureKubernetes(runCtx context.Context, ctx *image.Context) ([]string, error) {
 	version := ctx.ImageDefinition.Kubernetes.Version
 
 	if version == "" {
@@ -92,7 +93,7 @@ func (c *Combustion) configureKubernetes(ctx *image.Context) ([]string, error) {
 		return nil, fmt.Errorf("storing cluster config: %w", err)
 	}
 
-	script, err := configureFunc(ctx, cluster)
+	script, err := configureFunc(runCtx, ctx, cluster)
 	if err != nil {
 		log.AuditComponentFailed(k8sComponentName)
 		return nil, fmt.Errorf("configuring kubernetes components: %w", err)
@@ -102,7 +103,7 @@ func (c *Combustion) configureKubernetes(ctx *image.Context) ([]string, error) {
 	return []string{script}, nil
 }
 
-func (c *Combustion) kubernetesConfigurator(version string) func(*image.Context, *kubernetes.Cluster) (string, error) {
+func (c *Combustion) kubernetesConfigurator(version string) func(context.Context, *image.Context, *kubernetes.Cluster) (string, error) {
 	switch {
 	case strings.Contains(version, image.KubernetesDistroRKE2):
 		return c.configureRKE2
@@ -113,10 +114,10 @@ func (c *Combustion) kubernetesConfigurator(version string) func(*image.Context,
 	}
 }
 
-func (c *Combustion) downloadKubernetesInstallScript(ctx *image.Context, distribution string) (string, error) {
+func (c *Combustion) downloadKubernetesInstallScript(runCtx context.Context, ctx *image.Context, distribution string) (string, error) {
 	path := kubernetesArtefactsPath(ctx)
 
-	installScript, err := c.KubernetesScriptDownloader.DownloadInstallScript(distribution, path)
+	installScript, err := c.KubernetesScriptDownloader.DownloadInstallScript(runCtx, distribution, path)
 	if err != nil {
 		return "", fmt.Errorf("downloading install script: %w", err)
 	}
@@ -124,33 +125,39 @@ func (c *Combustion) downloadKubernetesInstallScript(ctx *image.Context, distrib
 	return prependArtefactPath(filepath.Join(K8sDir, installScript)), nil
 }
 
-func (c *Combustion) configureK3S(ctx *image.Context, cluster *kubernetes.Cluster) (string, error) {
+func (c *Combustion) configureK3S(runCtx context.Context, ctx *image.Context, cluster *kubernetes.Cluster) (string, error) {
 	zap.S().Info("Configuring K3s cluster")
 
-	installScript, err := c.downloadKubernetesInstallScript(ctx, image.KubernetesDistroK3S)
+	installScript, err := c.downloadKubernetesInstallScript(runCtx, ctx, image.KubernetesDistroK3S)
 	if err != nil {
 		return "", fmt.Errorf("downloading k3s install script: %w", err)
 	}
 
-	binaryPath, imagesPath, err := c.downloadK3sArtefacts(ctx)
+	binaryPath, imagesPath, err := c.downloadK3sArtefacts(runCtx, ctx)
 	if err != nil {
 		return "", fmt.Errorf("downloading k3s artefacts: %w", err)
 	}
 
-	manifestsPath, err := configureManifests(ctx)
+	manifestsPath, err := configureManifests(runCtx, ctx)
 	if err != nil {
 		return "", fmt.Errorf("configuring kubernetes manifests: %w", err)
 	}
 
+	registryMirrorsDest, err := registryMirrorsDestination(ctx.ImageDefinition.Kubernetes.Version)
+	if err != nil {
+		return "", fmt.Errorf("determining registry mirrors destination: %w", err)
+	}
+
 	templateValues := map[string]any{
-		"installScript":   installScript,
-		"apiVIP":          ctx.ImageDefinition.Kubernetes.Network.APIVIP,
-		"apiHost":         ctx.ImageDefinition.Kubernetes.Network.APIHost,
-		"binaryPath":      binaryPath,
-		"imagesPath":      imagesPath,
-		"manifestsPath":   manifestsPath,
-		"configFilePath":  prependArtefactPath(K8sDir),
-		"registryMirrors": prependArtefactPath(filepath.Join(K8sDir, registryMirrorsFileName)),
+		"installScript":       installScript,
+		"apiVIP":              ctx.ImageDefinition.Kubernetes.Network.APIVIP,
+		"apiHost":             ctx.ImageDefinition.Kubernetes.Network.APIHost,
+		"binaryPath":          binaryPath,
+		"imagesPath":          imagesPath,
+		"manifestsPath":       manifestsPath,
+		"configFilePath":      prependArtefactPath(K8sDir),
+		"registryMirrors":     prependArtefactPath(filepath.Join(K8sDir, registryMirrorsFileName)),
+		"registryMirrorsDest": registryMirrorsDest,
 	}
 
 	singleNode := len(ctx.ImageDefinition.Kubernetes.Nodes) < 2
@@ -178,7 +185,7 @@ func (c *Combustion) configureK3S(ctx *image.Context, cluster *kubernetes.Cluste
 	return storeKubernetesInstaller(ctx, "multi-node-k3s", k3sMultiNodeInstaller, templateValues)
 }
 
-func (c *Combustion) downloadK3sArtefacts(ctx *image.Context) (binaryPath, imagesPath string, err error) {
+func (c *Combustion) downloadK3sArtefacts(runCtx context.Context, ctx *image.Context) (binaryPath, imagesPath string, err error) {
 	imagesPath = filepath.Join(K8sDir, k8sImagesDir)
 	imagesDestination := filepath.Join(ctx.ArtefactsDir, imagesPath)
 	if err = os.MkdirAll(imagesDestination, os.ModePerm); err != nil {
@@ -192,6 +199,7 @@ func (c *Combustion) downloadK3sArtefacts(ctx *image.Context) (binaryPath, image
 	}
 
 	if err = c.KubernetesArtefactDownloader.DownloadK3sArtefacts(
+		runCtx,
 		ctx.ImageDefinition.Image.Arch,
 		ctx.ImageDefinition.Kubernetes.Version,
 		installDestination,
@@ -219,33 +227,39 @@ func (c *Combustion) downloadK3sArtefacts(ctx *image.Context) (binaryPath, image
 	return prependArtefactPath(binaryPath), prependArtefactPath(imagesPath), nil
 }
 
-func (c *Combustion) configureRKE2(ctx *image.Context, cluster *kubernetes.Cluster) (string, error) {
+func (c *Combustion) configureRKE2(runCtx context.Context, ctx *image.Context, cluster *kubernetes.Cluster) (string, error) {
 	zap.S().Info("Configuring RKE2 cluster")
 
-	installScript, err := c.downloadKubernetesInstallScript(ctx, image.KubernetesDistroRKE2)
+	installScript, err := c.downloadKubernetesInstallScript(runCtx, ctx, image.KubernetesDistroRKE2)
 	if err != nil {
 		return "", fmt.Errorf("downloading RKE2 install script: %w", err)
 	}
 
-	installPath, imagesPath, err := c.downloadRKE2Artefacts(ctx, cluster)
+	installPath, imagesPath, err := c.downloadRKE2Artefacts(runCtx, ctx, cluster)
 	if err != nil {
 		return "", fmt.Errorf("downloading RKE2 artefacts: %w", err)
 	}
 
-	manifestsPath, err := configureManifests(ctx)
+	manifestsPath, err := configureManifests(runCtx, ctx)
 	if err != nil {
 		return "", fmt.Errorf("configuring kubernetes manifests: %w", err)
 	}
 
+	registryMirrorsDest, err := registryMirrorsDestination(ctx.ImageDefinition.Kubernetes.Version)
+	if err != nil {
+		return "", fmt.Errorf("determining registry mirrors destination: %w", err)
+	}
+
 	templateValues := map[string]any{
-		"installScript":   installScript,
-		"apiVIP":          ctx.ImageDefinition.Kubernetes.Network.APIVIP,
-		"apiHost":         ctx.ImageDefinition.Kubernetes.Network.APIHost,
-		"installPath":     installPath,
-		"imagesPath":      imagesPath,
-		"manifestsPath":   manifestsPath,
-		"configFilePath":  prependArtefactPath(K8sDir),
-		"registryMirrors": prependArtefactPath(filepath.Join(K8sDir, registryMirrorsFileName)),
+		"installScript":       installScript,
+		"apiVIP":              ctx.ImageDefinition.Kubernetes.Network.APIVIP,
+		"apiHost":             ctx.ImageDefinition.Kubernetes.Network.APIHost,
+		"installPath":         installPath,
+		"imagesPath":          imagesPath,
+		"manifestsPath":       manifestsPath,
+		"configFilePath":      prependArtefactPath(K8sDir),
+		"registryMirrors":     prependArtefactPath(filepath.Join(K8sDir, registryMirrorsFileName)),
+		"registryMirrorsDest": registryMirrorsDest,
 	}
 
 	singleNode := len(ctx.ImageDefinition.Kubernetes.Nodes) < 2
@@ -280,7 +294,7 @@ func storeKubernetesInstaller(ctx *image.Context, templateName, templateContents
 	return k8sInstallScript, nil
 }
 
-func (c *Combustion) downloadRKE2Artefacts(ctx *image.Context, cluster *kubernetes.Cluster) (installPath, imagesPath string, err error) {
+func (c *Combustion) downloadRKE2Artefacts(runCtx context.Context, ctx *image.Context, cluster *kubernetes.Cluster) (installPath, imagesPath string, err error) {
 	cni, multusEnabled, err := cluster.ExtractCNI()
 	if err != nil {
 		return "", "", fmt.Errorf("extracting CNI from cluster config: %w", err)
@@ -299,6 +313,7 @@ func (c *Combustion) downloadRKE2Artefacts(ctx *image.Context, cluster *kubernet
 	}
 
 	if err = c.KubernetesArtefactDownloader.DownloadRKE2Artefacts(
+		runCtx,
 		ctx.ImageDefinition.Image.Arch,
 		ctx.ImageDefinition.Kubernetes.Version,
 		cni,
@@ -314,11 +329,13 @@ func (c *Combustion) downloadRKE2Artefacts(ctx *image.Context, cluster *kubernet
 
 func kubernetesVIPManifest(k *image.Kubernetes) (string, error) {
 	manifest := struct {
-		APIAddress string
-		RKE2       bool
+		APIAddress  string
+		AddressPool []string
+		RKE2        bool
 	}{
-		APIAddress: k.Network.APIVIP,
-		RKE2:       strings.Contains(k.Version, image.KubernetesDistroRKE2),
+		APIAddress:  k.Network.APIVIP,
+		AddressPool: k.Network.LoadBalancerPool,
+		RKE2:        strings.Contains(k.Version, image.KubernetesDistroRKE2),
 	}
 
 	return template.Parse("k8s-vip", k8sVIPManifest, &manifest)
@@ -358,14 +375,15 @@ func storeKubernetesConfig(config map[string]any, configPath string) error {
 	return os.WriteFile(configPath, data, fileio.NonExecutablePerms)
 }
 
-func configureManifests(ctx *image.Context) (string, error) {
+func configureManifests(runCtx context.Context, ctx *image.Context) (string, error) {
 	manifestURLs := ctx.ImageDefinition.Kubernetes.Manifests.URLs
 	localManifestsConfigured := isComponentConfigured(ctx, filepath.Join(K8sDir, k8sManifestsDir))
 
 	manifestsPath := filepath.Join(K8sDir, k8sManifestsDir)
 	manifestDestDir := filepath.Join(ctx.ArtefactsDir, manifestsPath)
 
-	if ctx.ImageDefinition.Kubernetes.Network.APIVIP != "" {
+	network := &ctx.ImageDefinition.Kubernetes.Network
+	if network.APIVIP != "" && network.IsVIPManaged() {
 		if err := os.MkdirAll(manifestDestDir, os.ModePerm); err != nil {
 			return "", fmt.Errorf("creating manifests destination dir: %w", err)
 		}
@@ -409,7 +427,7 @@ func configureManifests(ctx *image.Context) (string, error) {
 	}
 
 	if len(manifestURLs) != 0 {
-		_, err = registry.DownloadManifests(manifestURLs, manifestDestDir)
+		_, err = registry.DownloadManifests(runCtx, ctx.HTTPClient, &ctx.ImageDefinition.Kubernetes.Manifests, manifestDestDir, ctx.ParallelDownloads)
 		if err != nil {
 			return "", fmt.Errorf("downloading manifests to combustion dir: %w", err)
 		}
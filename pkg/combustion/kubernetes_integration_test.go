@@ -3,6 +3,7 @@
 package combustion
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
@@ -31,7 +32,7 @@ func TestConfigureManifestsValidDownload(t *testing.T) {
 	expectedDownloadedFilePath := filepath.Join(downloadedManifestsDestDir, "dl-manifest-1.yaml")
 
 	// Test
-	manifestsPath, err := configureManifests(ctx)
+	manifestsPath, err := configureManifests(context.Background(), ctx)
 
 	// Verify
 	require.NoError(t, err)
@@ -87,7 +88,7 @@ func TestConfigureKubernetes_SuccessfulRKE2ServerWithManifests(t *testing.T) {
 	err := fileio.CopyFile(localSampleManifestPath, filepath.Join(localManifestsSrcDir, "sample-crd.yaml"), fileio.NonExecutablePerms)
 	require.NoError(t, err)
 
-	scripts, err := c.configureKubernetes(ctx)
+	scripts, err := c.configureKubernetes(context.Background(), ctx)
 	require.NoError(t, err)
 	require.Len(t, scripts, 1)
 
@@ -1,9 +1,12 @@
 package combustion
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -19,7 +22,7 @@ type mockKubernetesScriptDownloader struct {
 	downloadScript func(distribution, destPath string) (string, error)
 }
 
-func (m mockKubernetesScriptDownloader) DownloadInstallScript(distribution, destPath string) (string, error) {
+func (m mockKubernetesScriptDownloader) DownloadInstallScript(_ context.Context, distribution, destPath string) (string, error) {
 	if m.downloadScript != nil {
 		return m.downloadScript(distribution, destPath)
 	}
@@ -33,6 +36,7 @@ type mockKubernetesArtefactDownloader struct {
 }
 
 func (m mockKubernetesArtefactDownloader) DownloadRKE2Artefacts(
+	_ context.Context,
 	arch image.Arch,
 	version string,
 	cni string,
@@ -47,7 +51,7 @@ func (m mockKubernetesArtefactDownloader) DownloadRKE2Artefacts(
 	panic("not implemented")
 }
 
-func (m mockKubernetesArtefactDownloader) DownloadK3sArtefacts(arch image.Arch, version, installPath, imagesPath string) error {
+func (m mockKubernetesArtefactDownloader) DownloadK3sArtefacts(_ context.Context, arch image.Arch, version, installPath, imagesPath string) error {
 	if m.downloadK3sArtefacts != nil {
 		return m.downloadK3sArtefacts(arch, version, installPath, imagesPath)
 	}
@@ -62,7 +66,7 @@ func TestConfigureKubernetes_Skipped(t *testing.T) {
 
 	var c Combustion
 
-	scripts, err := c.configureKubernetes(ctx)
+	scripts, err := c.configureKubernetes(context.Background(), ctx)
 	require.NoError(t, err)
 	assert.Nil(t, scripts)
 }
@@ -78,7 +82,7 @@ func TestConfigureKubernetes_UnsupportedVersion(t *testing.T) {
 
 	var c Combustion
 
-	scripts, err := c.configureKubernetes(ctx)
+	scripts, err := c.configureKubernetes(context.Background(), ctx)
 	require.Error(t, err)
 	assert.EqualError(t, err, "cannot configure kubernetes version: v1.29.0")
 	assert.Nil(t, scripts)
@@ -100,7 +104,7 @@ func TestConfigureKubernetes_ScriptInstallerErrorK3s(t *testing.T) {
 		},
 	}
 
-	scripts, err := c.configureKubernetes(ctx)
+	scripts, err := c.configureKubernetes(context.Background(), ctx)
 	require.Error(t, err)
 	assert.EqualError(t, err, "configuring kubernetes components: downloading k3s install script: downloading install script: some error")
 	assert.Nil(t, scripts)
@@ -122,7 +126,7 @@ func TestConfigureKubernetes_ScriptInstallerErrorRKE2(t *testing.T) {
 		},
 	}
 
-	scripts, err := c.configureKubernetes(ctx)
+	scripts, err := c.configureKubernetes(context.Background(), ctx)
 	require.Error(t, err)
 	assert.EqualError(t, err, "configuring kubernetes components: downloading RKE2 install script: downloading install script: some error")
 	assert.Nil(t, scripts)
@@ -149,7 +153,7 @@ func TestConfigureKubernetes_ArtefactDownloaderErrorK3s(t *testing.T) {
 		},
 	}
 
-	scripts, err := c.configureKubernetes(ctx)
+	scripts, err := c.configureKubernetes(context.Background(), ctx)
 	require.Error(t, err)
 	assert.EqualError(t, err, "configuring kubernetes components: downloading k3s artefacts: downloading artefacts: some error")
 	assert.Nil(t, scripts)
@@ -176,7 +180,7 @@ func TestConfigureKubernetes_ArtefactDownloaderErrorRKE2(t *testing.T) {
 		},
 	}
 
-	scripts, err := c.configureKubernetes(ctx)
+	scripts, err := c.configureKubernetes(context.Background(), ctx)
 	require.Error(t, err)
 	assert.EqualError(t, err, "configuring kubernetes components: downloading RKE2 artefacts: downloading artefacts: some error")
 	assert.Nil(t, scripts)
@@ -208,7 +212,7 @@ func TestConfigureKubernetes_SuccessfulSingleNodeK3sCluster(t *testing.T) {
 		},
 	}
 
-	scripts, err := c.configureKubernetes(ctx)
+	scripts, err := c.configureKubernetes(context.Background(), ctx)
 	require.NoError(t, err)
 	require.Len(t, scripts, 1)
 
@@ -226,6 +230,7 @@ func TestConfigureKubernetes_SuccessfulSingleNodeK3sCluster(t *testing.T) {
 	contents := string(b)
 	assert.Contains(t, contents, "cp $ARTEFACTS_DIR/kubernetes/images/* /var/lib/rancher/k3s/agent/images/")
 	assert.Contains(t, contents, "cp $ARTEFACTS_DIR/kubernetes/server.yaml /etc/rancher/k3s/config.yaml")
+	assert.Contains(t, contents, "cp $ARTEFACTS_DIR/kubernetes/registries.yaml /etc/rancher/k3s/registries.yaml")
 	assert.Contains(t, contents, "echo \"192.168.122.100 api.cluster01.hosted.on.edge.suse.com\" >> /etc/hosts")
 	assert.Contains(t, contents, "export INSTALL_K3S_SKIP_DOWNLOAD=true")
 	assert.Contains(t, contents, "export INSTALL_K3S_SKIP_START=true")
@@ -304,7 +309,7 @@ func TestConfigureKubernetes_SuccessfulMultiNodeK3sCluster(t *testing.T) {
 	require.NoError(t, os.MkdirAll(configDir, os.ModePerm))
 	require.NoError(t, os.WriteFile(filepath.Join(configDir, "server.yaml"), b, os.ModePerm))
 
-	scripts, err := c.configureKubernetes(ctx)
+	scripts, err := c.configureKubernetes(context.Background(), ctx)
 	require.NoError(t, err)
 	require.Len(t, scripts, 1)
 
@@ -324,6 +329,7 @@ func TestConfigureKubernetes_SuccessfulMultiNodeK3sCluster(t *testing.T) {
 	assert.Contains(t, contents, "hosts[node2.suse.com]=agent")
 	assert.Contains(t, contents, "cp $ARTEFACTS_DIR/kubernetes/images/* /var/lib/rancher/k3s/agent/images/")
 	assert.Contains(t, contents, "cp $CONFIGFILE /etc/rancher/k3s/config.yaml")
+	assert.Contains(t, contents, "cp $ARTEFACTS_DIR/kubernetes/registries.yaml /etc/rancher/k3s/registries.yaml")
 	assert.Contains(t, contents, "if [ \"$HOSTNAME\" = node1.suse.com ]; then")
 	assert.Contains(t, contents, "echo \"192.168.122.100 api.cluster01.hosted.on.edge.suse.com\" >> /etc/hosts")
 	assert.Contains(t, contents, "export INSTALL_K3S_EXEC=$NODETYPE")
@@ -410,7 +416,7 @@ func TestConfigureKubernetes_SuccessfulSingleNodeRKE2Cluster(t *testing.T) {
 		},
 	}
 
-	scripts, err := c.configureKubernetes(ctx)
+	scripts, err := c.configureKubernetes(context.Background(), ctx)
 	require.NoError(t, err)
 	require.Len(t, scripts, 1)
 
@@ -428,6 +434,7 @@ func TestConfigureKubernetes_SuccessfulSingleNodeRKE2Cluster(t *testing.T) {
 	contents := string(b)
 	assert.Contains(t, contents, "cp $ARTEFACTS_DIR/kubernetes/images/* /var/lib/rancher/rke2/agent/images/")
 	assert.Contains(t, contents, "cp $ARTEFACTS_DIR/kubernetes/server.yaml /etc/rancher/rke2/config.yaml")
+	assert.Contains(t, contents, "cp $ARTEFACTS_DIR/kubernetes/registries.yaml /etc/rancher/rke2/registries.yaml")
 	assert.Contains(t, contents, "echo \"192.168.122.100 api.cluster01.hosted.on.edge.suse.com\" >> /etc/hosts")
 	assert.Contains(t, contents, "export INSTALL_RKE2_ARTIFACT_PATH=$ARTEFACTS_DIR/kubernetes/install")
 	assert.Contains(t, contents, "sh $ARTEFACTS_DIR/kubernetes/install-kubernetes.sh")
@@ -503,7 +510,7 @@ func TestConfigureKubernetes_SuccessfulMultiNodeRKE2Cluster(t *testing.T) {
 	require.NoError(t, os.MkdirAll(configDir, os.ModePerm))
 	require.NoError(t, os.WriteFile(filepath.Join(configDir, "server.yaml"), b, os.ModePerm))
 
-	scripts, err := c.configureKubernetes(ctx)
+	scripts, err := c.configureKubernetes(context.Background(), ctx)
 	require.NoError(t, err)
 	require.Len(t, scripts, 1)
 
@@ -523,6 +530,7 @@ func TestConfigureKubernetes_SuccessfulMultiNodeRKE2Cluster(t *testing.T) {
 	assert.Contains(t, contents, "hosts[node2.suse.com]=agent")
 	assert.Contains(t, contents, "cp $ARTEFACTS_DIR/kubernetes/images/* /var/lib/rancher/rke2/agent/images/")
 	assert.Contains(t, contents, "cp $CONFIGFILE /etc/rancher/rke2/config.yaml")
+	assert.Contains(t, contents, "cp $ARTEFACTS_DIR/kubernetes/registries.yaml /etc/rancher/rke2/registries.yaml")
 	assert.Contains(t, contents, "if [ \"$HOSTNAME\" = node1.suse.com ]; then")
 	assert.Contains(t, contents, "echo \"192.168.122.100 api.cluster01.hosted.on.edge.suse.com\" >> /etc/hosts")
 	assert.Contains(t, contents, "export INSTALL_RKE2_ARTIFACT_PATH=$ARTEFACTS_DIR/kubernetes/install")
@@ -604,7 +612,7 @@ func TestConfigureKubernetes_InvalidManifestURL(t *testing.T) {
 	k8sCombDir := filepath.Join(ctx.CombustionDir, K8sDir)
 	require.NoError(t, os.Mkdir(k8sCombDir, os.ModePerm))
 
-	_, err := c.configureKubernetes(ctx)
+	_, err := c.configureKubernetes(context.Background(), ctx)
 
 	require.ErrorContains(t, err, "configuring kubernetes manifests: downloading manifests to combustion dir: downloading manifest 'k8s.io/examples/application/nginx-app.yaml': executing request: Get \"k8s.io/examples/application/nginx-app.yaml\": unsupported protocol scheme \"\"")
 }
@@ -615,9 +623,85 @@ func TestConfigureManifestsNoSetup(t *testing.T) {
 	defer teardown()
 
 	// Test
-	manifestsPath, err := configureManifests(ctx)
+	manifestsPath, err := configureManifests(context.Background(), ctx)
 
 	// Verify
 	require.NoError(t, err)
 	assert.Equal(t, "", manifestsPath)
 }
+
+func TestConfigureManifestsVIPNotManaged(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	manageVIP := false
+	ctx.ImageDefinition.Kubernetes.Network = image.Network{
+		APIVIP:    "192.168.122.100",
+		ManageVIP: &manageVIP,
+	}
+
+	// Test
+	manifestsPath, err := configureManifests(context.Background(), ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Equal(t, "", manifestsPath)
+	assert.NoFileExists(t, filepath.Join(ctx.ArtefactsDir, K8sDir, k8sManifestsDir, "k8s-vip.yaml"))
+}
+
+func TestKubernetesVIPManifest(t *testing.T) {
+	k8s := &image.Kubernetes{
+		Version: "v1.30.3+rke2r1",
+		Network: image.Network{
+			APIVIP: "192.168.122.100",
+		},
+	}
+
+	manifest, err := kubernetesVIPManifest(k8s)
+	require.NoError(t, err)
+
+	assert.Contains(t, manifest, "  addresses:\n  - 192.168.122.100/32\n  avoidBuggyIPs: true\n  serviceAllocation:\n")
+	assert.Contains(t, manifest, "  - name: rke2-api\n")
+	assert.NotContains(t, manifest, "metallb.universe.tf/loadBalancerIPs")
+}
+
+func TestKubernetesVIPManifest_LoadBalancerPool(t *testing.T) {
+	k8s := &image.Kubernetes{
+		Version: "v1.30.3+k3s1",
+		Network: image.Network{
+			APIVIP:           "192.168.122.100",
+			LoadBalancerPool: []string{"192.168.122.100-192.168.122.150", "192.168.123.0/24"},
+		},
+	}
+
+	manifest, err := kubernetesVIPManifest(k8s)
+	require.NoError(t, err)
+
+	var resources []map[string]any
+	decoder := yaml.NewDecoder(strings.NewReader(manifest))
+	for {
+		var resource map[string]any
+		if err = decoder.Decode(&resource); err != nil {
+			require.ErrorIs(t, err, io.EOF)
+			break
+		}
+		resources = append(resources, resource)
+	}
+	require.Len(t, resources, 3)
+
+	pool := resources[0]
+	assert.Equal(t, "IPAddressPool", pool["kind"])
+	assert.Equal(t, map[string]any{
+		"addresses":     []any{"192.168.122.100-192.168.122.150", "192.168.123.0/24"},
+		"avoidBuggyIPs": true,
+	}, pool["spec"])
+
+	assert.Equal(t, "L2Advertisement", resources[1]["kind"])
+
+	service := resources[2]
+	assert.Equal(t, "Service", service["kind"])
+	metadata := service["metadata"].(map[string]any)
+	assert.Equal(t, map[string]any{"metallb.universe.tf/loadBalancerIPs": "192.168.122.100"}, metadata["annotations"])
+	assert.NotContains(t, manifest, "rke2-api")
+}
@@ -0,0 +1,50 @@
+package combustion
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+)
+
+const (
+	localeComponentName = "locale"
+	localeScriptName    = "17-locale-setup.sh"
+)
+
+//go:embed templates/17-locale-setup.sh.tpl
+var localeScript string
+
+func configureLocale(ctx *image.Context) ([]string, error) {
+	if ctx.ImageDefinition.OperatingSystem.Locale == "" {
+		log.AuditComponentSkipped(localeComponentName)
+		return nil, nil
+	}
+
+	if err := writeLocaleCombustionScript(ctx); err != nil {
+		log.AuditComponentFailed(localeComponentName)
+		return nil, err
+	}
+
+	log.AuditComponentSuccessful(localeComponentName)
+	return []string{localeScriptName}, nil
+}
+
+func writeLocaleCombustionScript(ctx *image.Context) error {
+	localeScriptFilename := filepath.Join(ctx.CombustionDir, localeScriptName)
+
+	data, err := template.Parse(localeScriptName, localeScript, ctx.ImageDefinition.OperatingSystem)
+	if err != nil {
+		return fmt.Errorf("applying template to %s: %w", localeScriptName, err)
+	}
+
+	if err := os.WriteFile(localeScriptFilename, []byte(data), fileio.ExecutablePerms); err != nil {
+		return fmt.Errorf("writing file %s: %w", localeScriptFilename, err)
+	}
+	return nil
+}
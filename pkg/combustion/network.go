@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io/fs"
+	"net/netip"
 	"os"
 	"path/filepath"
 
@@ -13,6 +14,7 @@ import (
 	"github.com/suse-edge/edge-image-builder/pkg/log"
 	"github.com/suse-edge/edge-image-builder/pkg/template"
 	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
 )
 
 const (
@@ -23,6 +25,8 @@ const (
 	networkConfigDir        = "network"
 	networkConfigScriptName = "05-configure-network.sh"
 	networkCustomScriptName = "configure-network.sh"
+	// Build subdirectory storing the NMState files generated from the definition.
+	networkHostsConfigDir = "network-config"
 )
 
 //go:embed templates/05-configure-network.sh.tpl
@@ -34,6 +38,9 @@ var configureNetworkScript string
 //  2. Copies a custom network configuration script if provided
 //  3. Generates network configurations and writes the configuration script template otherwise
 //
+// The network configurations are generated either from the NMState files in the network
+// directory or from the network hosts in the definition, which are converted to NMState files first.
+//
 // Example result file layout:
 //
 //	combustion
@@ -52,7 +59,7 @@ var configureNetworkScript string
 func (c *Combustion) configureNetwork(ctx *image.Context) (scripts []string, err error) {
 	zap.S().Info("Configuring network component...")
 
-	if !isComponentConfigured(ctx, networkConfigDir) {
+	if !isNetworkConfigured(ctx) {
 		log.AuditComponentSkipped(networkComponentName)
 		zap.S().Info("Skipping network component, configuration is not provided")
 		return nil, nil
@@ -62,6 +69,30 @@ func (c *Combustion) configureNetwork(ctx *image.Context) (scripts []string, err
 		logComponentStatus(networkComponentName, err)
 	}()
 
+	combustionScript := filepath.Join(ctx.CombustionDir, networkConfigScriptName)
+	scripts = append(scripts, networkConfigScriptName)
+
+	if len(ctx.ImageDefinition.OperatingSystem.Network.Hosts) != 0 {
+		configDir := filepath.Join(ctx.BuildDir, networkHostsConfigDir)
+		if err = writeNetworkHostConfigs(ctx.ImageDefinition.OperatingSystem.Network.Hosts, configDir); err != nil {
+			return nil, fmt.Errorf("writing network host configs: %w", err)
+		}
+
+		if err = c.installNetworkConfigurator(ctx); err != nil {
+			return nil, fmt.Errorf("installing configurator: %w", err)
+		}
+
+		if err = c.generateNetworkConfig(ctx, configDir); err != nil {
+			return nil, fmt.Errorf("generating network config: %w", err)
+		}
+
+		if err = writeNetworkConfigurationScript(combustionScript); err != nil {
+			return nil, fmt.Errorf("writing network configuration script: %w", err)
+		}
+
+		return scripts, nil
+	}
+
 	networkPath := generateComponentPath(ctx, networkConfigDir)
 
 	entries, err := os.ReadDir(networkPath)
@@ -76,8 +107,6 @@ func (c *Combustion) configureNetwork(ctx *image.Context) (scripts []string, err
 	}
 
 	customScript := filepath.Join(networkPath, networkCustomScriptName)
-	combustionScript := filepath.Join(ctx.CombustionDir, networkConfigScriptName)
-	scripts = append(scripts, networkConfigScriptName)
 
 	// Copy custom network script if provided.
 	// Proceed with generating configuration otherwise.
@@ -88,7 +117,7 @@ func (c *Combustion) configureNetwork(ctx *image.Context) (scripts []string, err
 		return nil, fmt.Errorf("copying custom network script: %w", err)
 	}
 
-	if err = c.generateNetworkConfig(ctx); err != nil {
+	if err = c.generateNetworkConfig(ctx, networkPath); err != nil {
 		return nil, fmt.Errorf("generating network config: %w", err)
 	}
 
@@ -99,7 +128,11 @@ func (c *Combustion) configureNetwork(ctx *image.Context) (scripts []string, err
 	return scripts, nil
 }
 
-func (c *Combustion) generateNetworkConfig(ctx *image.Context) error {
+func isNetworkConfigured(ctx *image.Context) bool {
+	return len(ctx.ImageDefinition.OperatingSystem.Network.Hosts) != 0 || isComponentConfigured(ctx, networkConfigDir)
+}
+
+func (c *Combustion) generateNetworkConfig(ctx *image.Context, configDir string) error {
 	const networkConfigLogFile = "network-config.log"
 
 	logFilename := filepath.Join(ctx.BuildDir, networkConfigLogFile)
@@ -114,7 +147,6 @@ func (c *Combustion) generateNetworkConfig(ctx *image.Context) error {
 		}
 	}()
 
-	configDir := generateComponentPath(ctx, networkConfigDir)
 	outputDir := filepath.Join(ctx.CombustionDir, networkConfigDir)
 
 	return c.NetworkConfigGenerator.GenerateNetworkConfig(configDir, outputDir, logFile)
@@ -145,3 +177,197 @@ func writeNetworkConfigurationScript(scriptPath string) error {
 
 	return nil
 }
+
+func NetworkConfigPath(ctx *image.Context) string {
+	return generateComponentPath(ctx, networkConfigDir)
+}
+
+type nmstateConfig struct {
+	DNSResolver *nmstateDNSResolver `yaml:"dns-resolver,omitempty"`
+	Routes      *nmstateRoutes      `yaml:"routes,omitempty"`
+	Interfaces  []nmstateInterface  `yaml:"interfaces"`
+}
+
+type nmstateDNSResolver struct {
+	Config nmstateDNSConfig `yaml:"config"`
+}
+
+type nmstateDNSConfig struct {
+	Server []string `yaml:"server,omitempty"`
+	Search []string `yaml:"search,omitempty"`
+}
+
+type nmstateRoutes struct {
+	Config []nmstateRoute `yaml:"config"`
+}
+
+type nmstateRoute struct {
+	Destination      string `yaml:"destination"`
+	NextHopAddress   string `yaml:"next-hop-address"`
+	NextHopInterface string `yaml:"next-hop-interface"`
+}
+
+type nmstateInterface struct {
+	Name            string                  `yaml:"name"`
+	Type            string                  `yaml:"type"`
+	State           string                  `yaml:"state"`
+	MACAddress      string                  `yaml:"mac-address,omitempty"`
+	LinkAggregation *nmstateLinkAggregation `yaml:"link-aggregation,omitempty"`
+	IPv4            nmstateIP               `yaml:"ipv4"`
+	IPv6            nmstateIP               `yaml:"ipv6"`
+}
+
+type nmstateLinkAggregation struct {
+	Mode string   `yaml:"mode"`
+	Port []string `yaml:"port"`
+}
+
+type nmstateIP struct {
+	Enabled bool             `yaml:"enabled"`
+	DHCP    *bool            `yaml:"dhcp,omitempty"`
+	Address []nmstateAddress `yaml:"address,omitempty"`
+}
+
+type nmstateAddress struct {
+	IP           string `yaml:"ip"`
+	PrefixLength int    `yaml:"prefix-length"`
+}
+
+// writeNetworkHostConfigs converts the network hosts of the definition to NMState files
+// named after the hostnames as expected by the network config generator.
+func writeNetworkHostConfigs(hosts []image.NetworkHost, configDir string) error {
+	if err := os.MkdirAll(configDir, os.ModePerm); err != nil {
+		return fmt.Errorf("creating network config directory: %w", err)
+	}
+
+	for i := range hosts {
+		config, err := nmstateHostConfig(&hosts[i])
+		if err != nil {
+			return fmt.Errorf("converting network host '%s': %w", hosts[i].Hostname, err)
+		}
+
+		data, err := yaml.Marshal(config)
+		if err != nil {
+			return fmt.Errorf("serializing network host '%s': %w", hosts[i].Hostname, err)
+		}
+
+		filename := filepath.Join(configDir, fmt.Sprintf("%s.yaml", hosts[i].Hostname))
+		if err = os.WriteFile(filename, data, fileio.NonExecutablePerms); err != nil {
+			return fmt.Errorf("writing file %s: %w", filename, err)
+		}
+	}
+
+	return nil
+}
+
+func nmstateHostConfig(host *image.NetworkHost) (*nmstateConfig, error) {
+	config := &nmstateConfig{}
+
+	if len(host.DNSServers) != 0 || len(host.DNSSearch) != 0 {
+		config.DNSResolver = &nmstateDNSResolver{
+			Config: nmstateDNSConfig{
+				Server: host.DNSServers,
+				Search: host.DNSSearch,
+			},
+		}
+	}
+
+	if host.Gateway != "" {
+		gateway, err := netip.ParseAddr(host.Gateway)
+		if err != nil {
+			return nil, fmt.Errorf("parsing gateway: %w", err)
+		}
+
+		destination := "0.0.0.0/0"
+		if gateway.Is6() {
+			destination = "::/0"
+		}
+
+		config.Routes = &nmstateRoutes{
+			Config: []nmstateRoute{
+				{
+					Destination:      destination,
+					NextHopAddress:   host.Gateway,
+					NextHopInterface: host.GatewayInterface(),
+				},
+			},
+		}
+	}
+
+	bondPorts := make(map[string]bool)
+	for _, iface := range host.Interfaces {
+		for _, port := range iface.Ports {
+			bondPorts[port] = true
+		}
+	}
+
+	for _, iface := range host.Interfaces {
+		nmstateIface := nmstateInterface{
+			Name:       iface.Name,
+			Type:       iface.InterfaceType(),
+			State:      "up",
+			MACAddress: iface.MACAddress,
+		}
+
+		if iface.InterfaceType() == image.NetworkInterfaceTypeBond {
+			nmstateIface.LinkAggregation = &nmstateLinkAggregation{
+				Mode: iface.BondMode,
+				Port: iface.Ports,
+			}
+		}
+
+		// Bond ports are configured through the bond itself
+		if !bondPorts[iface.Name] {
+			ipv4, ipv6, err := nmstateAddresses(iface.Addresses)
+			if err != nil {
+				return nil, fmt.Errorf("parsing addresses of interface '%s': %w", iface.Name, err)
+			}
+			nmstateIface.IPv4 = ipv4
+			nmstateIface.IPv6 = ipv6
+		}
+
+		config.Interfaces = append(config.Interfaces, nmstateIface)
+	}
+
+	return config, nil
+}
+
+// nmstateAddresses splits the given static addresses into their IPv4 and IPv6 configuration.
+// IPv4 is configured through DHCP if no static address is provided.
+func nmstateAddresses(addresses []string) (ipv4, ipv6 nmstateIP, err error) {
+	if len(addresses) == 0 {
+		dhcp := true
+		ipv4 = nmstateIP{Enabled: true, DHCP: &dhcp}
+		return ipv4, ipv6, nil
+	}
+
+	for _, address := range addresses {
+		prefix, parseErr := netip.ParsePrefix(address)
+		if parseErr != nil {
+			return ipv4, ipv6, parseErr
+		}
+
+		nmstateAddr := nmstateAddress{
+			IP:           prefix.Addr().String(),
+			PrefixLength: prefix.Bits(),
+		}
+
+		if prefix.Addr().Is6() {
+			ipv6.Address = append(ipv6.Address, nmstateAddr)
+		} else {
+			ipv4.Address = append(ipv4.Address, nmstateAddr)
+		}
+	}
+
+	static := false
+	if len(ipv4.Address) != 0 {
+		ipv4.Enabled = true
+		ipv4.DHCP = &static
+	}
+	if len(ipv6.Address) != 0 {
+		ipv6.Enabled = true
+		ipv6.DHCP = &static
+	}
+
+	return ipv4, ipv6, nil
+}
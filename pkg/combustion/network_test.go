@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
 )
 
 type mockNetworkConfigGenerator struct {
@@ -193,3 +194,155 @@ func TestWriteNetworkConfigurationScript(t *testing.T) {
 
 	assertNetworkConfigScript(t, scriptPath)
 }
+
+func TestConfigureNetwork_DefinitionHosts(t *testing.T) {
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition.OperatingSystem.Network.Hosts = []image.NetworkHost{
+		{
+			Hostname: "node1.suse.com",
+			Interfaces: []image.NetworkInterface{
+				{
+					Name:       "eth0",
+					MACAddress: "34:8A:B1:4B:16:E1",
+					Addresses:  []string{"192.168.122.50/24"},
+				},
+			},
+			Gateway: "192.168.122.1",
+		},
+		{
+			Hostname: "node2.suse.com",
+			Interfaces: []image.NetworkInterface{
+				{
+					Name:       "eth0",
+					MACAddress: "34:8A:B1:4B:16:E2",
+				},
+			},
+		},
+	}
+
+	var generatedConfigDir string
+	c := Combustion{
+		NetworkConfigGenerator: mockNetworkConfigGenerator{
+			generateNetworkConfigFunc: func(configDir, outputDir string, outputWriter io.Writer) error {
+				generatedConfigDir = configDir
+				return nil
+			},
+		},
+		NetworkConfiguratorInstaller: mockNetworkConfiguratorInstaller{
+			installConfiguratorFunc: func(sourcePath, installPath string) error {
+				return nil
+			},
+		},
+	}
+
+	scripts, err := c.configureNetwork(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{networkConfigScriptName}, scripts)
+
+	assert.Equal(t, filepath.Join(ctx.BuildDir, networkHostsConfigDir), generatedConfigDir)
+	assert.FileExists(t, filepath.Join(generatedConfigDir, "node1.suse.com.yaml"))
+	assert.FileExists(t, filepath.Join(generatedConfigDir, "node2.suse.com.yaml"))
+
+	assertNetworkConfigScript(t, filepath.Join(ctx.CombustionDir, networkConfigScriptName))
+}
+
+func TestWriteNetworkHostConfigs(t *testing.T) {
+	configDir := filepath.Join(t.TempDir(), "network-config")
+
+	hosts := []image.NetworkHost{
+		{
+			Hostname: "node1.suse.com",
+			Interfaces: []image.NetworkInterface{
+				{
+					Name:       "eth0",
+					MACAddress: "34:8A:B1:4B:16:E1",
+					Addresses:  []string{"192.168.122.50/24", "fd00::50/64"},
+				},
+				{
+					Name:     "bond0",
+					Type:     image.NetworkInterfaceTypeBond,
+					BondMode: "active-backup",
+					Ports:    []string{"eth1", "eth2"},
+				},
+				{
+					Name:       "eth1",
+					MACAddress: "34:8A:B1:4B:16:E2",
+				},
+				{
+					Name:       "eth2",
+					MACAddress: "34:8A:B1:4B:16:E3",
+				},
+			},
+			Gateway:    "192.168.122.1",
+			DNSServers: []string{"192.168.122.1"},
+			DNSSearch:  []string{"suse.com"},
+		},
+	}
+
+	require.NoError(t, writeNetworkHostConfigs(hosts, configDir))
+
+	data, err := os.ReadFile(filepath.Join(configDir, "node1.suse.com.yaml"))
+	require.NoError(t, err)
+
+	expected := `dns-resolver:
+    config:
+        server:
+            - 192.168.122.1
+        search:
+            - suse.com
+routes:
+    config:
+        - destination: 0.0.0.0/0
+          next-hop-address: 192.168.122.1
+          next-hop-interface: eth0
+interfaces:
+    - name: eth0
+      type: ethernet
+      state: up
+      mac-address: 34:8A:B1:4B:16:E1
+      ipv4:
+        enabled: true
+        dhcp: false
+        address:
+            - ip: 192.168.122.50
+              prefix-length: 24
+      ipv6:
+        enabled: true
+        dhcp: false
+        address:
+            - ip: fd00::50
+              prefix-length: 64
+    - name: bond0
+      type: bond
+      state: up
+      link-aggregation:
+        mode: active-backup
+        port:
+            - eth1
+            - eth2
+      ipv4:
+        enabled: true
+        dhcp: true
+      ipv6:
+        enabled: false
+    - name: eth1
+      type: ethernet
+      state: up
+      mac-address: 34:8A:B1:4B:16:E2
+      ipv4:
+        enabled: false
+      ipv6:
+        enabled: false
+    - name: eth2
+      type: ethernet
+      state: up
+      mac-address: 34:8A:B1:4B:16:E3
+      ipv4:
+        enabled: false
+      ipv6:
+        enabled: false
+`
+	assert.Equal(t, expected, string(data))
+}
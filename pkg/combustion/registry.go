@@ -1,10 +1,15 @@
 package combustion
 
 import (
+	"context"
+	"crypto/sha256"
 	_ "embed"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"slices"
 	"strings"
@@ -25,6 +30,7 @@ const (
 	registryComponentName   = "embedded artifact registry"
 	registryLogFileName     = "embedded-registry.log"
 	hauler                  = "hauler"
+	haulerDefaultPath       = "/usr/bin/hauler"
 	registryDir             = "registry"
 	registryPort            = "6545"
 	registryMirrorsFileName = "registries.yaml"
@@ -42,13 +48,18 @@ var (
 	k8sRegistryMirrors string
 )
 
-func (c *Combustion) configureRegistry(ctx *image.Context) ([]string, error) {
+func (c *Combustion) configureRegistry(runCtx context.Context, ctx *image.Context) ([]string, error) {
 	if !IsEmbeddedArtifactRegistryConfigured(ctx) {
 		log.AuditComponentSkipped(registryComponentName)
 		return nil, nil
 	}
 
-	configured, err := c.configureEmbeddedArtifactRegistry(ctx)
+	if err := removeStaleRegistryDir(ctx); err != nil {
+		log.AuditComponentFailed(registryComponentName)
+		return nil, fmt.Errorf("cleaning registry dir: %w", err)
+	}
+
+	configured, err := c.configureEmbeddedArtifactRegistry(runCtx, ctx)
 	if err != nil {
 		log.AuditComponentFailed(registryComponentName)
 		return nil, fmt.Errorf("configuring embedded artifact registry: %w", err)
@@ -70,10 +81,15 @@ func (c *Combustion) configureRegistry(ctx *image.Context) ([]string, error) {
 	return []string{script}, nil
 }
 
-func addImageToHauler(ctx *image.Context, containerImage string) error {
-	args := []string{"store", "add", "image", containerImage, "-p", fmt.Sprintf("linux/%s", ctx.ImageDefinition.Image.Arch.Short())}
+func addImageToHauler(runCtx context.Context, ctx *image.Context, containerImage string, onProgress func(*haulerProgressEvent)) error {
+	platformArch, err := ctx.ImageDefinition.Image.Arch.ShortName()
+	if err != nil {
+		return err
+	}
+
+	args := []string{"store", "add", "image", containerImage, "-p", fmt.Sprintf("linux/%s", platformArch)}
 
-	cmd, registryLog, err := createRegistryCommand(ctx, hauler, args)
+	cmd, registryLog, err := createRegistryCommand(runCtx, ctx, haulerBinary(ctx), args)
 	if err != nil {
 		return fmt.Errorf("preparing to add image to hauler store: %w", err)
 	}
@@ -83,6 +99,12 @@ func addImageToHauler(ctx *image.Context, containerImage string) error {
 		}
 	}()
 
+	progressWriter := newHaulerProgressWriter(registryLog, onProgress)
+	defer progressWriter.Flush()
+
+	cmd.Stdout = progressWriter
+	cmd.Stderr = progressWriter
+
 	if err = cmd.Run(); err != nil {
 		return fmt.Errorf("running hauler add image command: %w: ", err)
 	}
@@ -90,10 +112,15 @@ func addImageToHauler(ctx *image.Context, containerImage string) error {
 	return nil
 }
 
-func generateRegistryTar(ctx *image.Context, imageTarDest string) error {
+func generateRegistryTar(runCtx context.Context, ctx *image.Context, imageTarDest string) error {
+	// A tar left behind by a failed run must not be partially overwritten.
+	if err := os.Remove(imageTarDest); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("removing existing registry tar: %w", err)
+	}
+
 	args := []string{"store", "save", "--filename", imageTarDest}
 
-	cmd, registryLog, err := createRegistryCommand(ctx, hauler, args)
+	cmd, registryLog, err := createRegistryCommand(runCtx, ctx, haulerBinary(ctx), args)
 	if err != nil {
 		return fmt.Errorf("preparing to generate registry tar: %w", err)
 	}
@@ -139,14 +166,23 @@ func writeRegistryScript(ctx *image.Context) (string, error) {
 	return registryScriptName, nil
 }
 
-func createRegistryCommand(ctx *image.Context, commandName string, args []string) (*exec.Cmd, *os.File, error) {
+// haulerBinary returns the path of the hauler binary which populates the registry and is copied into the image.
+func haulerBinary(ctx *image.Context) string {
+	if ctx.HaulerBinaryPath != "" {
+		return ctx.HaulerBinaryPath
+	}
+
+	return haulerDefaultPath
+}
+
+func createRegistryCommand(runCtx context.Context, ctx *image.Context, commandName string, args []string) (*exec.Cmd, *fileio.RotatingFile, error) {
 	fullLogFilename := filepath.Join(ctx.BuildDir, registryLogFileName)
-	logFile, err := os.OpenFile(fullLogFilename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, fileio.NonExecutablePerms)
+	logFile, err := fileio.OpenRotatingFile(fullLogFilename, ctx.CommandLogMaxSize, fileio.NonExecutablePerms)
 	if err != nil {
 		return nil, nil, fmt.Errorf("error opening registry log file %s: %w", registryLogFileName, err)
 	}
 
-	cmd := exec.Command(commandName, args...)
+	cmd := exec.CommandContext(runCtx, commandName, args...)
 	cmd.Stdout = logFile
 	cmd.Stderr = logFile
 
@@ -154,6 +190,10 @@ func createRegistryCommand(ctx *image.Context, commandName string, args []string
 }
 
 func IsEmbeddedArtifactRegistryConfigured(ctx *image.Context) bool {
+	if isComponentSkipped(ctx, registryComponentName) {
+		return false
+	}
+
 	return len(ctx.ImageDefinition.EmbeddedArtifactRegistry.ContainerImages) != 0 ||
 		len(ctx.ImageDefinition.Kubernetes.Manifests.URLs) != 0 ||
 		len(ctx.ImageDefinition.Kubernetes.Helm.Charts) != 0 ||
@@ -175,19 +215,69 @@ func getImageHostnames(containerImages []string) []string {
 	return hostnames
 }
 
+// registryConfig holds the credentials of an upstream registry written to the registry mirrors file.
+type registryConfig struct {
+	Host     string
+	Username string
+	Password string
+}
+
+func registryConfigs(registries []image.Registry) ([]registryConfig, error) {
+	var configs []registryConfig
+
+	for _, r := range registries {
+		password := os.Getenv(r.PasswordEnv)
+		if password == "" {
+			return nil, fmt.Errorf("environment variable '%s' is not set", r.PasswordEnv)
+		}
+
+		configs = append(configs, registryConfig{
+			Host:     r.URI,
+			Username: r.Username,
+			Password: password,
+		})
+	}
+
+	return configs, nil
+}
+
+// registryMirrorsDestination returns the path the registry mirrors file is installed to,
+// which depends on the Kubernetes distribution identified by the given version.
+func registryMirrorsDestination(version string) (string, error) {
+	var distro string
+
+	switch {
+	case strings.Contains(version, image.KubernetesDistroRKE2):
+		distro = image.KubernetesDistroRKE2
+	case strings.Contains(version, image.KubernetesDistroK3S):
+		distro = image.KubernetesDistroK3S
+	default:
+		return "", fmt.Errorf("unknown kubernetes distribution in version '%s'", version)
+	}
+
+	return path.Join("/etc/rancher", distro, registryMirrorsFileName), nil
+}
+
 func writeRegistryMirrors(ctx *image.Context, hostnames []string) error {
 	artefactsPath := kubernetesArtefactsPath(ctx)
 	if err := os.MkdirAll(artefactsPath, os.ModePerm); err != nil {
 		return fmt.Errorf("creating kubernetes artefacts path: %w", err)
 	}
 
+	configs, err := registryConfigs(ctx.ImageDefinition.EmbeddedArtifactRegistry.Registries)
+	if err != nil {
+		return fmt.Errorf("reading registry credentials: %w", err)
+	}
+
 	registriesYamlFile := filepath.Join(artefactsPath, registryMirrorsFileName)
 	registriesDef := struct {
 		Hostnames []string
 		Port      string
+		Configs   []registryConfig
 	}{
 		Hostnames: hostnames,
 		Port:      registryPort,
+		Configs:   configs,
 	}
 
 	data, err := template.Parse(registryMirrorsFileName, k8sRegistryMirrors, registriesDef)
@@ -202,7 +292,7 @@ func writeRegistryMirrors(ctx *image.Context, hostnames []string) error {
 	return nil
 }
 
-func (c *Combustion) configureEmbeddedArtifactRegistry(ctx *image.Context) (bool, error) {
+func (c *Combustion) configureEmbeddedArtifactRegistry(runCtx context.Context, ctx *image.Context) (bool, error) {
 	helmCharts, err := c.parseHelmCharts(ctx)
 	if err != nil {
 		return false, fmt.Errorf("parsing helm charts: %w", err)
@@ -212,7 +302,7 @@ func (c *Combustion) configureEmbeddedArtifactRegistry(ctx *image.Context) (bool
 		return false, fmt.Errorf("storing helm charts: %w", err)
 	}
 
-	manifestImages, err := parseManifests(ctx)
+	manifestImages, err := parseManifests(runCtx, ctx)
 	if err != nil {
 		return false, fmt.Errorf("parsing manifests: %w", err)
 	}
@@ -231,17 +321,23 @@ func (c *Combustion) configureEmbeddedArtifactRegistry(ctx *image.Context) (bool
 		}
 	}
 
+	if err = c.checkRegistrySizeBudget(ctx, images); err != nil {
+		return false, fmt.Errorf("checking registry size budget: %w", err)
+	}
+
 	artefactsPath := registryArtefactsPath(ctx)
 	if err = os.Mkdir(artefactsPath, os.ModePerm); err != nil {
 		return false, fmt.Errorf("creating registry dir: %w", err)
 	}
 
-	if err = populateRegistry(ctx, images); err != nil {
+	if err = c.populateRegistry(runCtx, ctx, images); err != nil {
 		return false, fmt.Errorf("populating registry: %w", err)
 	}
 
-	sourcePath := "/usr/bin/hauler"
-	destinationPath := filepath.Join(registryArtefactsPath(ctx), "hauler")
+	ctx.BuildInfo.EmbeddedImages = images
+
+	sourcePath := haulerBinary(ctx)
+	destinationPath := filepath.Join(registryArtefactsPath(ctx), hauler)
 	if err = fileio.CopyFile(sourcePath, destinationPath, fileio.ExecutablePerms); err != nil {
 		return false, fmt.Errorf("copying hauler binary: %w", err)
 	}
@@ -275,7 +371,12 @@ func containerImages(embeddedImages []image.ContainerImage, manifestImages []str
 	return images
 }
 
-func parseManifests(ctx *image.Context) ([]string, error) {
+// KubernetesManifestsPath returns the path to the directory containing the local Kubernetes manifests.
+func KubernetesManifestsPath(ctx *image.Context) string {
+	return generateComponentPath(ctx, filepath.Join(K8sDir, k8sManifestsDir))
+}
+
+func parseManifests(runCtx context.Context, ctx *image.Context) ([]string, error) {
 	var manifestSrcDir string
 	if componentDir := filepath.Join(K8sDir, k8sManifestsDir); isComponentConfigured(ctx, componentDir) {
 		manifestSrcDir = filepath.Join(ctx.ImageConfigDir, componentDir)
@@ -285,7 +386,7 @@ func parseManifests(ctx *image.Context) ([]string, error) {
 		return nil, fmt.Errorf("kubernetes manifests are provided but kubernetes version is not configured")
 	}
 
-	return registry.ManifestImages(ctx.ImageDefinition.Kubernetes.Manifests.URLs, manifestSrcDir)
+	return registry.ManifestImages(runCtx, ctx.HTTPClient, &ctx.ImageDefinition.Kubernetes.Manifests, manifestSrcDir, ctx.ParallelDownloads)
 }
 
 func (c *Combustion) parseHelmCharts(ctx *image.Context) ([]*registry.HelmChart, error) {
@@ -318,6 +419,12 @@ func storeHelmCharts(ctx *image.Context, helmCharts []*registry.HelmChart) error
 	}
 
 	for _, chart := range helmCharts {
+		ctx.BuildInfo.HelmCharts = append(ctx.BuildInfo.HelmCharts, image.HelmChartInfo{
+			Name:          chart.CRD.Metadata.Name,
+			Version:       chart.CRD.Spec.Version,
+			RepositoryURL: chart.CRD.Metadata.Annotations[registry.RepositoryURLAnnotation],
+		})
+
 		data, err := yaml.Marshal(chart.CRD)
 		if err != nil {
 			return fmt.Errorf("marshaling resource: %w", err)
@@ -332,31 +439,176 @@ func storeHelmCharts(ctx *image.Context, helmCharts []*registry.HelmChart) error
 	return nil
 }
 
+// checkRegistrySizeBudget estimates the combined size of the given images and fails if it exceeds
+// the configured budget. The estimation is best-effort and images whose size could not be determined are skipped.
+func (c *Combustion) checkRegistrySizeBudget(ctx *image.Context, images []string) error {
+	budget := ctx.ImageDefinition.EmbeddedArtifactRegistry.SizeBudget
+	if budget == "" || c.ImageSizeResolver == nil {
+		return nil
+	}
+
+	var totalSize int64
+	var unresolved int
+
+	for _, img := range images {
+		size, err := c.ImageSizeResolver.ImageSize(img, ctx.ImageDefinition.Image.Arch)
+		if err != nil {
+			zap.S().Warnf("Estimating the size of image '%s' failed: %s", img, err)
+			unresolved++
+			continue
+		}
+
+		totalSize += size
+	}
+
+	if unresolved != 0 {
+		log.Auditf("WARNING: The size of %d image(s) could not be determined and is not included in the registry size estimate.", unresolved)
+	}
+
+	const mb = 1024 * 1024
+	if totalSize > budget.ToMB()*mb {
+		return fmt.Errorf("estimated image size of %dM exceeds the configured budget of %s", totalSize/mb, budget)
+	}
+
+	zap.S().Infof("Estimated embedded artifact registry size: %dM (budget: %s)", totalSize/mb, budget)
+	return nil
+}
+
 func registryArtefactsPath(ctx *image.Context) string {
 	return filepath.Join(ctx.ArtefactsDir, registryDir)
 }
 
-func populateRegistry(ctx *image.Context, images []string) error {
+// removeStaleRegistryDir ensures that the registry dir does not contain leftovers (e.g. partially
+// written tars) of a previous run which would otherwise be embedded alongside the regenerated ones.
+func removeStaleRegistryDir(ctx *image.Context) error {
+	registryPath := registryArtefactsPath(ctx)
+
+	entries, err := os.ReadDir(registryPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("reading registry dir: %w", err)
+	}
+
+	zap.S().Warnf("Removing registry dir '%s' containing %d entries of a previous run", registryPath, len(entries))
+
+	if err = os.RemoveAll(registryPath); err != nil {
+		return fmt.Errorf("removing registry dir: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Combustion) populateRegistry(runCtx context.Context, ctx *image.Context, images []string) error {
 	bar := progressbar.Default(int64(len(images)), "Populating Embedded Artifact Registry...")
 	zap.S().Infof("Adding the following images to the embedded artifact registry:\n%s", images)
 
-	for _, i := range images {
-		if err := addImageToHauler(ctx, i); err != nil {
-			return fmt.Errorf("adding image to hauler: %w", err)
+	onProgress := func(event *haulerProgressEvent) {
+		zap.S().Debugf("Hauler progress: %s %s", event.Stage, event.Reference)
+
+		if event.Stage == haulerStageAdding {
+			bar.Describe(fmt.Sprintf("Populating Embedded Artifact Registry (%s)...", event.Reference))
 		}
+	}
+
+	storeKey := registryStoreIdentifier(ctx.ImageDefinition.Image.Arch, images)
 
+	for _, i := range images {
 		convertedImage := strings.ReplaceAll(i, "/", "_")
 		convertedImageName := fmt.Sprintf("%s-%s", convertedImage, registryTarSuffix)
-
 		imageTarDest := filepath.Join(registryArtefactsPath(ctx), convertedImageName)
-		if err := generateRegistryTar(ctx, imageTarDest); err != nil {
-			return fmt.Errorf("generating hauler store tar: %w", err)
+		cacheKey := registryCacheIdentifier(ctx.ImageDefinition.Image.Arch, storeKey, i)
+
+		copied, err := c.copyRegistryTarFromCache(cacheKey, imageTarDest)
+		if err != nil {
+			return fmt.Errorf("retrieving registry tar for image '%s' from cache: %w", i, err)
+		}
+
+		if !copied {
+			if err = ctx.HTTPClient.CheckOnline(i); err != nil {
+				return fmt.Errorf("adding image to hauler: %w", err)
+			}
+
+			if err = addImageToHauler(runCtx, ctx, i, onProgress); err != nil {
+				return fmt.Errorf("adding image to hauler: %w", err)
+			}
+
+			if err = generateRegistryTar(runCtx, ctx, imageTarDest); err != nil {
+				return fmt.Errorf("generating hauler store tar: %w", err)
+			}
+
+			c.cacheRegistryTar(cacheKey, imageTarDest)
 		}
 
-		if err := bar.Add(1); err != nil {
+		if err = bar.Add(1); err != nil {
 			zap.S().Debugf("Error incrementing the progress bar: %s", err)
 		}
 	}
 
 	return nil
 }
+
+func (c *Combustion) copyRegistryTarFromCache(cacheKey, destPath string) (bool, error) {
+	if c.RegistryCache == nil {
+		return false, nil
+	}
+
+	sourcePath, err := c.RegistryCache.Get(cacheKey)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("querying cache: %w", err)
+	}
+
+	zap.S().Infof("Copying registry tar with identifier '%s' from cache", cacheKey)
+
+	if err = fileio.CopyFile(sourcePath, destPath, fileio.NonExecutablePerms); err != nil {
+		return false, fmt.Errorf("copying from cache: %w", err)
+	}
+
+	return true, nil
+}
+
+// cacheRegistryTar stores the given registry tar for subsequent builds.
+// Failing to do so does not affect the current build and is only logged.
+func (c *Combustion) cacheRegistryTar(cacheKey, tarPath string) {
+	if c.RegistryCache == nil {
+		return
+	}
+
+	file, err := os.Open(tarPath)
+	if err != nil {
+		zap.S().Warnf("Opening registry tar '%s' for caching failed: %s", tarPath, err)
+		return
+	}
+	defer file.Close()
+
+	// The identifier either pins the image digest or the image list of the build,
+	// so an existing entry already holds the same content
+	if err = c.RegistryCache.Put(cacheKey, file); err != nil && !errors.Is(err, fs.ErrExist) {
+		zap.S().Warnf("Caching registry tar '%s' failed: %s", tarPath, err)
+	}
+}
+
+// registryStoreIdentifier identifies the registry store of a build by the hash of its sorted image list,
+// so that rebuilds without any changes to the images reuse the registry tars of the previous build.
+func registryStoreIdentifier(arch image.Arch, images []string) string {
+	sortedImages := slices.Clone(images)
+	slices.Sort(sortedImages)
+
+	return fmt.Sprintf("registry/%s/%x", arch, sha256.Sum256([]byte(strings.Join(sortedImages, "\n"))))
+}
+
+// registryCacheIdentifier returns the identifier under which the registry tar of the given image is cached.
+// Images referenced by digest are shared by all builds. Since the content behind a tag may change between builds,
+// images referenced by tag are scoped to the given store and only synced again once the image list changes.
+func registryCacheIdentifier(arch image.Arch, storeKey, containerImage string) string {
+	if strings.Contains(containerImage, "@") {
+		return fmt.Sprintf("registry/%s/%s", arch, containerImage)
+	}
+
+	return fmt.Sprintf("%s/%s", storeKey, containerImage)
+}
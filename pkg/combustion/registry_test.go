@@ -1,23 +1,67 @@
 package combustion
 
 import (
+	"context"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/cache"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/http"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 	"github.com/suse-edge/edge-image-builder/pkg/registry"
 )
 
+const (
+	nginxDigest = "sha256:32e76d4f34f80e479964a0fbd4c5b4f6967b5322c8d004e9cf0cb81c93510766"
+	bciDigest   = "sha256:9cd6d4b1a2a0bd2e1b1c10a5ab3a7f4d1e3a54a8d5e7d3b0a96d5f4c8e1b2a3c"
+)
+
+type mockRegistryCache struct {
+	getFunc func(identifier string) (string, error)
+	putFunc func(identifier string, reader io.Reader) error
+}
+
+func (m mockRegistryCache) Get(identifier string) (string, error) {
+	if m.getFunc != nil {
+		return m.getFunc(identifier)
+	}
+
+	panic("not implemented")
+}
+
+func (m mockRegistryCache) Put(identifier string, reader io.Reader) error {
+	if m.putFunc != nil {
+		return m.putFunc(identifier, reader)
+	}
+
+	panic("not implemented")
+}
+
+type mockImageSizeResolver struct {
+	imageSizeFunc func(containerImage string, arch image.Arch) (int64, error)
+}
+
+func (m mockImageSizeResolver) ImageSize(containerImage string, arch image.Arch) (int64, error) {
+	if m.imageSizeFunc != nil {
+		return m.imageSizeFunc(containerImage, arch)
+	}
+
+	panic("not implemented")
+}
+
 func TestCreateRegistryCommand(t *testing.T) {
 	// Setup
 	ctx, teardown := setupContext(t)
 	defer teardown()
 
 	// Test
-	cmd, logFile, err := createRegistryCommand(ctx, "testName", []string{"--flag", "test"})
+	cmd, logFile, err := createRegistryCommand(context.Background(), ctx, "testName", []string{"--flag", "test"})
 
 	// Verify
 	require.NoError(t, err)
@@ -167,11 +211,87 @@ func TestIsEmbeddedArtifactRegistryConfigured(t *testing.T) {
 	}
 }
 
+func TestGenerateRegistryTar_HaulerBinaryOverride(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	argsFile := filepath.Join(ctx.BuildDir, "hauler-args")
+	haulerScript := fmt.Sprintf("#!/bin/sh\necho \"$@\" > %s\n", argsFile)
+
+	ctx.HaulerBinaryPath = filepath.Join(ctx.BuildDir, "custom-hauler")
+	require.NoError(t, os.WriteFile(ctx.HaulerBinaryPath, []byte(haulerScript), fileio.ExecutablePerms))
+
+	// Test
+	err := generateRegistryTar(context.Background(), ctx, "registry.tar.zst")
+
+	// Verify
+	require.NoError(t, err)
+
+	foundArgs, err := os.ReadFile(argsFile)
+	require.NoError(t, err)
+	assert.Equal(t, "store save --filename registry.tar.zst\n", string(foundArgs))
+}
+
+func TestGenerateRegistryTar_ReplacesExistingTar(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	// Appends to the tar like an interrupted run would leave it behind if it was not removed
+	haulerScript := "#!/bin/sh\necho regenerated >> \"$4\"\n"
+
+	ctx.HaulerBinaryPath = filepath.Join(ctx.BuildDir, "custom-hauler")
+	require.NoError(t, os.WriteFile(ctx.HaulerBinaryPath, []byte(haulerScript), fileio.ExecutablePerms))
+
+	imageTarDest := filepath.Join(ctx.BuildDir, "hello-world-registry.tar.zst")
+	require.NoError(t, os.WriteFile(imageTarDest, []byte("stale\n"), fileio.NonExecutablePerms))
+
+	// Test
+	err := generateRegistryTar(context.Background(), ctx, imageTarDest)
+
+	// Verify
+	require.NoError(t, err)
+
+	found, err := os.ReadFile(imageTarDest)
+	require.NoError(t, err)
+	assert.Equal(t, "regenerated\n", string(found))
+}
+
+func TestRemoveStaleRegistryDir(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	// Test
+	require.NoError(t, removeStaleRegistryDir(ctx))
+
+	registryPath := filepath.Join(ctx.ArtefactsDir, registryDir)
+	require.NoError(t, os.MkdirAll(registryPath, os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(registryPath, "stale-registry.tar.zst"), []byte("stale"), fileio.NonExecutablePerms))
+
+	err := removeStaleRegistryDir(ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.NoDirExists(t, registryPath)
+}
+
+func TestHaulerBinary(t *testing.T) {
+	ctx := &image.Context{}
+	assert.Equal(t, "/usr/bin/hauler", haulerBinary(ctx))
+
+	ctx.HaulerBinaryPath = "/opt/hauler/hauler-custom"
+	assert.Equal(t, "/opt/hauler/hauler-custom", haulerBinary(ctx))
+}
+
 func TestWriteRegistryMirrorsValid(t *testing.T) {
 	// Setup
 	ctx, teardown := setupContext(t)
 	defer teardown()
 
+	ctx.ImageDefinition.Kubernetes.Version = "v1.30.3+rke2r1"
+
 	hostnames := []string{"hello-world:latest", "rgcrprod.azurecr.us/longhornio/longhorn-ui:v1.5.1", "quay.io"}
 
 	// Test
@@ -192,6 +312,101 @@ func TestWriteRegistryMirrorsValid(t *testing.T) {
 	assert.Contains(t, found, "quay.io")
 }
 
+func TestWriteRegistryMirrors_Configs(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	t.Setenv("PRIVATE_REGISTRY_PASSWORD", "s3cr3t")
+
+	ctx.ImageDefinition.Kubernetes.Version = "v1.30.3+k3s1"
+	ctx.ImageDefinition.EmbeddedArtifactRegistry.Registries = []image.Registry{
+		{
+			URI:         "private.example.com",
+			Username:    "eib",
+			PasswordEnv: "PRIVATE_REGISTRY_PASSWORD",
+		},
+	}
+
+	// Test
+	err := writeRegistryMirrors(ctx, []string{"private.example.com"})
+
+	// Verify
+	require.NoError(t, err)
+
+	foundBytes, err := os.ReadFile(filepath.Join(ctx.ArtefactsDir, K8sDir, registryMirrorsFileName))
+	require.NoError(t, err)
+
+	expected := `mirrors:
+  docker.io:
+    endpoint:
+      - "http://localhost:6545"
+  private.example.com:
+    endpoint:
+      - "http://localhost:6545"
+configs:
+  private.example.com:
+    auth:
+      username: "eib"
+      password: "s3cr3t"`
+	assert.Equal(t, expected, string(foundBytes))
+}
+
+func TestWriteRegistryMirrors_MissingPassword(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition.Kubernetes.Version = "v1.30.3+k3s1"
+	ctx.ImageDefinition.EmbeddedArtifactRegistry.Registries = []image.Registry{
+		{
+			URI:         "private.example.com",
+			Username:    "eib",
+			PasswordEnv: "EIB_UNSET_REGISTRY_PASSWORD",
+		},
+	}
+
+	// Test
+	err := writeRegistryMirrors(ctx, []string{"private.example.com"})
+
+	// Verify
+	require.EqualError(t, err, "reading registry credentials: environment variable 'EIB_UNSET_REGISTRY_PASSWORD' is not set")
+}
+
+func TestRegistryMirrorsDestination(t *testing.T) {
+	tests := map[string]struct {
+		version             string
+		expectedDestination string
+		expectedError       string
+	}{
+		"k3s": {
+			version:             "v1.30.3+k3s1",
+			expectedDestination: "/etc/rancher/k3s/registries.yaml",
+		},
+		"RKE2": {
+			version:             "v1.30.3+rke2r1",
+			expectedDestination: "/etc/rancher/rke2/registries.yaml",
+		},
+		"Unknown distribution": {
+			version:       "v1.30.3",
+			expectedError: "unknown kubernetes distribution in version 'v1.30.3'",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			destination, err := registryMirrorsDestination(test.version)
+
+			if test.expectedError == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, test.expectedError)
+			}
+			assert.Equal(t, test.expectedDestination, destination)
+		})
+	}
+}
+
 func TestGetImageHostnames(t *testing.T) {
 	// Setup
 	images := []string{
@@ -290,4 +505,240 @@ spec:
 	require.NoError(t, err)
 
 	assert.Equal(t, apacheContent, string(contents))
+
+	expectedBuildInfo := []image.HelmChartInfo{
+		{
+			Name:          "apache",
+			Version:       "10.7.0",
+			RepositoryURL: "oci://registry-1.docker.io/bitnamicharts",
+		},
+	}
+	assert.Equal(t, expectedBuildInfo, ctx.BuildInfo.HelmCharts)
+}
+
+func TestPopulateRegistry_Cached(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition.Image.Arch = image.ArchTypeX86
+
+	require.NoError(t, os.Mkdir(registryArtefactsPath(ctx), os.ModePerm))
+
+	cachedTar := filepath.Join(ctx.BuildDir, "cached.tar.zst")
+	require.NoError(t, os.WriteFile(cachedTar, []byte("cached"), 0o600))
+
+	var requested []string
+	c := Combustion{
+		RegistryCache: mockRegistryCache{
+			getFunc: func(identifier string) (string, error) {
+				requested = append(requested, identifier)
+				return cachedTar, nil
+			},
+		},
+	}
+
+	images := []string{"docker.io/library/nginx@" + nginxDigest, "registry.suse.com/bci/bci-base@" + bciDigest}
+
+	// Test
+	// Hauler is not invoked when every image is cached, otherwise this would fail
+	err := c.populateRegistry(context.Background(), ctx, images)
+
+	// Verify
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"registry/x86_64/docker.io/library/nginx@" + nginxDigest,
+		"registry/x86_64/registry.suse.com/bci/bci-base@" + bciDigest,
+	}, requested)
+
+	for _, tarName := range []string{
+		"docker.io_library_nginx@" + nginxDigest + "-registry.tar.zst",
+		"registry.suse.com_bci_bci-base@" + bciDigest + "-registry.tar.zst",
+	} {
+		contents, err := os.ReadFile(filepath.Join(registryArtefactsPath(ctx), tarName))
+		require.NoError(t, err)
+		assert.Equal(t, "cached", string(contents))
+	}
+}
+
+func TestPopulateRegistry_RebuildSkipsSync(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition.Image.Arch = image.ArchTypeX86
+
+	registryCache, err := cache.New(t.TempDir())
+	require.NoError(t, err)
+
+	c := Combustion{RegistryCache: registryCache}
+
+	callsFile := filepath.Join(ctx.BuildDir, "hauler-calls")
+	haulerScript := fmt.Sprintf("#!/bin/sh\necho \"$2\" >> %s\nif [ \"$2\" = save ]; then echo synced > \"$4\"; fi\n", callsFile)
+
+	ctx.HaulerBinaryPath = filepath.Join(ctx.BuildDir, "custom-hauler")
+	require.NoError(t, os.WriteFile(ctx.HaulerBinaryPath, []byte(haulerScript), fileio.ExecutablePerms))
+
+	images := []string{"docker.io/library/nginx:1.25", "registry.suse.com/bci/bci-base:15.5"}
+	tarPath := filepath.Join(registryArtefactsPath(ctx), "docker.io_library_nginx:1.25-registry.tar.zst")
+
+	require.NoError(t, os.Mkdir(registryArtefactsPath(ctx), os.ModePerm))
+	require.NoError(t, c.populateRegistry(context.Background(), ctx, images))
+	require.FileExists(t, tarPath)
+
+	// Rebuild without any changes, the order of the images is irrelevant
+	require.NoError(t, removeStaleRegistryDir(ctx))
+	require.NoError(t, os.Mkdir(registryArtefactsPath(ctx), os.ModePerm))
+
+	// Test
+	err = c.populateRegistry(context.Background(), ctx, []string{images[1], images[0]})
+
+	// Verify
+	require.NoError(t, err)
+
+	calls, err := os.ReadFile(callsFile)
+	require.NoError(t, err)
+	assert.Equal(t, "add\nsave\nadd\nsave\n", string(calls), "hauler is only invoked by the first build")
+
+	contents, err := os.ReadFile(tarPath)
+	require.NoError(t, err)
+	assert.Equal(t, "synced\n", string(contents))
+}
+
+func TestPopulateRegistry_ChangedImageList(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition.Image.Arch = image.ArchTypeX86
+
+	registryCache, err := cache.New(t.TempDir())
+	require.NoError(t, err)
+
+	c := Combustion{RegistryCache: registryCache}
+
+	callsFile := filepath.Join(ctx.BuildDir, "hauler-calls")
+	haulerScript := fmt.Sprintf("#!/bin/sh\nif [ \"$2\" = add ]; then echo \"$4\" >> %s; fi\nif [ \"$2\" = save ]; then echo synced > \"$4\"; fi\n", callsFile)
+
+	ctx.HaulerBinaryPath = filepath.Join(ctx.BuildDir, "custom-hauler")
+	require.NoError(t, os.WriteFile(ctx.HaulerBinaryPath, []byte(haulerScript), fileio.ExecutablePerms))
+
+	require.NoError(t, os.Mkdir(registryArtefactsPath(ctx), os.ModePerm))
+	require.NoError(t, c.populateRegistry(context.Background(), ctx, []string{
+		"docker.io/library/nginx:1.25",
+		"registry.suse.com/bci/bci-base@" + bciDigest,
+	}))
+	require.NoError(t, os.Truncate(callsFile, 0))
+
+	require.NoError(t, removeStaleRegistryDir(ctx))
+	require.NoError(t, os.Mkdir(registryArtefactsPath(ctx), os.ModePerm))
+
+	// Test
+	err = c.populateRegistry(context.Background(), ctx, []string{
+		"docker.io/library/nginx:1.25",
+		"registry.suse.com/bci/bci-base@" + bciDigest,
+		"docker.io/library/busybox:1.36",
+	})
+
+	// Verify
+	require.NoError(t, err)
+
+	// The image referenced by digest is reused while the ones referenced by tag are synced for the changed image list
+	calls, err := os.ReadFile(callsFile)
+	require.NoError(t, err)
+	assert.Equal(t, "docker.io/library/nginx:1.25\ndocker.io/library/busybox:1.36\n", string(calls))
+}
+
+func TestPopulateRegistry_Offline(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition.Image.Arch = image.ArchTypeX86
+
+	require.NoError(t, os.Mkdir(registryArtefactsPath(ctx), os.ModePerm))
+
+	c := Combustion{}
+
+	offlineClient, err := http.NewClient(http.ClientConfig{Offline: true})
+	require.NoError(t, err)
+
+	ctx.HTTPClient = offlineClient
+
+	// Test
+	err = c.populateRegistry(context.Background(), ctx, []string{"docker.io/library/nginx:1.25"})
+
+	// Verify
+	require.ErrorIs(t, err, http.ErrOffline)
+	assert.ErrorContains(t, err, "'docker.io/library/nginx:1.25' is not available locally")
+}
+
+func TestCheckRegistrySizeBudget(t *testing.T) {
+	const mb = 1024 * 1024
+
+	sizes := map[string]int64{
+		"nginx:1.25":          300 * mb,
+		"bci/bci-base:15.5":   500 * mb,
+		"unreachable.io/foo":  -1,
+		"registry.io/big:1.0": 2048 * mb,
+	}
+
+	resolver := mockImageSizeResolver{
+		imageSizeFunc: func(containerImage string, arch image.Arch) (int64, error) {
+			size := sizes[containerImage]
+			if size < 0 {
+				return 0, fmt.Errorf("registry unreachable")
+			}
+
+			return size, nil
+		},
+	}
+
+	tests := map[string]struct {
+		budget        image.DiskSize
+		images        []string
+		expectedError string
+	}{
+		"No budget": {
+			images: []string{"registry.io/big:1.0"},
+		},
+		"Within budget": {
+			budget: "1G",
+			images: []string{"nginx:1.25", "bci/bci-base:15.5"},
+		},
+		"Unreachable registry is skipped": {
+			budget: "1G",
+			images: []string{"nginx:1.25", "unreachable.io/foo"},
+		},
+		"Exceeds budget": {
+			budget:        "2G",
+			images:        []string{"nginx:1.25", "registry.io/big:1.0"},
+			expectedError: "estimated image size of 2348M exceeds the configured budget of 2G",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := &image.Context{
+				ImageDefinition: &image.Definition{
+					Image: image.Image{
+						Arch: image.ArchTypeX86,
+					},
+					EmbeddedArtifactRegistry: image.EmbeddedArtifactRegistry{
+						SizeBudget: test.budget,
+					},
+				},
+			}
+
+			c := Combustion{ImageSizeResolver: resolver}
+
+			err := c.checkRegistrySizeBudget(ctx, test.images)
+			if test.expectedError == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, test.expectedError)
+			}
+		})
+	}
 }
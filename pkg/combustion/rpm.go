@@ -7,11 +7,13 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/suse-edge/edge-image-builder/pkg/fileio"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/rpm"
 	"github.com/suse-edge/edge-image-builder/pkg/template"
 	"go.uber.org/zap"
 )
@@ -21,6 +23,9 @@ const (
 	gpgDir                = "gpg-keys"
 	installRPMsScriptName = "10-rpm-install.sh"
 	rpmComponentName      = "RPM"
+
+	// regCodeEnvPrefix marks a registration code which is read from the named environment variable.
+	regCodeEnvPrefix = "env:"
 )
 
 //go:embed templates/10-rpm-install.sh.tpl
@@ -41,8 +46,14 @@ func (c *Combustion) configureRPMs(ctx *image.Context) ([]string, error) {
 		zap.S().Warn("Disabling GPG validation for the EIB RPM resolver")
 	}
 
+	regCode, err := ResolveRegistrationCode(ctx)
+	if err != nil {
+		log.AuditComponentFailed(rpmComponentName)
+		return nil, fmt.Errorf("resolving the SUSE registration code: %w", err)
+	}
+
 	// package list specified without either a sccRegistrationCode or an additionalRepos entry
-	if len(packages.PKGList) > 0 && (packages.RegCode == "" && len(packages.AdditionalRepos) == 0) {
+	if len(packages.PKGList) > 0 && (regCode == "" && len(packages.AdditionalRepos) == 0) {
 		log.Audit("WARNING: No SUSE registration code or additional repositories provided, package resolution may fail if you're using SLE Micro as the base image")
 		zap.S().Warn("Detected packages for installation with no sccRegistrationCode or additionalRepos provided")
 	}
@@ -59,8 +70,14 @@ func (c *Combustion) configureRPMs(ctx *image.Context) ([]string, error) {
 		return nil, fmt.Errorf("creating rpm artefacts path: %w", err)
 	}
 
+	// The registration code may only be known once resolved, so provide it to the resolver
+	// without modifying the image definition
+	resolvedPackages := *packages
+	resolvedPackages.RegCode = regCode
+	resolvedPackages.RegCodeFile = ""
+
 	log.Audit("Resolving package dependencies...")
-	repoPath, pkgsList, err := c.RPMResolver.Resolve(packages, localRPMConfig, artefactsPath)
+	repoPath, pkgsList, err := c.RPMResolver.Resolve(&resolvedPackages, localRPMConfig, artefactsPath)
 	if err != nil {
 		log.AuditComponentFailed(rpmComponentName)
 		return nil, fmt.Errorf("resolving rpm/package dependencies: %w", err)
@@ -71,6 +88,8 @@ func (c *Combustion) configureRPMs(ctx *image.Context) ([]string, error) {
 		return nil, fmt.Errorf("creating resolved rpm repository: %w", err)
 	}
 
+	ctx.BuildInfo.RPMs = resolvedRPMs(repoPath)
+
 	script, err := writeRPMScript(ctx, repoPath, pkgsList)
 	if err != nil {
 		log.AuditComponentFailed(rpmComponentName)
@@ -83,6 +102,10 @@ func (c *Combustion) configureRPMs(ctx *image.Context) ([]string, error) {
 
 // SkipRPMComponent determines whether RPM configuration is needed
 func SkipRPMComponent(ctx *image.Context) bool {
+	if isComponentSkipped(ctx, rpmComponentName) {
+		return true
+	}
+
 	pkg := ctx.ImageDefinition.OperatingSystem.Packages
 
 	if isComponentConfigured(ctx, rpmDir) {
@@ -139,13 +162,15 @@ func writeRPMScript(ctx *image.Context, repoPath string, packages []string) (str
 	}
 
 	values := struct {
-		RepoPath string
-		RepoName string
-		PKGList  string
+		RepoPath       string
+		RepoName       string
+		PKGList        string
+		InstallOptions string
 	}{
-		RepoPath: prependArtefactPath(rpmDir),
-		RepoName: filepath.Base(repoPath),
-		PKGList:  strings.Join(packages, " "),
+		RepoPath:       prependArtefactPath(rpmDir),
+		RepoName:       filepath.Base(repoPath),
+		PKGList:        rpm.FormatPackageList(packages),
+		InstallOptions: strings.Join(ctx.ImageDefinition.OperatingSystem.Packages.InstallOptions, " "),
 	}
 
 	data, err := template.Parse(installRPMsScriptName, installRPMsScript, &values)
@@ -162,6 +187,72 @@ func writeRPMScript(ctx *image.Context, repoPath string, packages []string) (str
 	return installRPMsScriptName, nil
 }
 
+// resolvedRPMs returns the sorted file names of all RPMs in the resolved repository.
+// The list is only used for informational purposes so failures are logged rather than returned.
+func resolvedRPMs(repoPath string) []string {
+	var rpms []string
+
+	err := filepath.WalkDir(repoPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() && filepath.Ext(d.Name()) == ".rpm" {
+			rpms = append(rpms, d.Name())
+		}
+
+		return nil
+	})
+	if err != nil {
+		zap.S().Warnf("Listing resolved RPMs in '%s' failed: %s", repoPath, err)
+		return nil
+	}
+
+	slices.Sort(rpms)
+	return rpms
+}
+
+// ResolveRegistrationCode returns the SUSE registration code, which is either provided directly
+// in the 'sccRegistrationCode' field, referenced there as an environment variable (e.g. 'env:SCC_REG_CODE')
+// or read from the file specified in the 'sccRegistrationCodeFile' field. Relative file paths are
+// resolved against the image configuration directory.
+func ResolveRegistrationCode(ctx *image.Context) (string, error) {
+	packages := ctx.ImageDefinition.OperatingSystem.Packages
+
+	switch {
+	case packages.RegCode != "" && packages.RegCodeFile != "":
+		return "", fmt.Errorf("only one of sccRegistrationCode and sccRegistrationCodeFile may be specified")
+	case packages.RegCodeFile != "":
+		filename := packages.RegCodeFile
+		if !filepath.IsAbs(filename) {
+			filename = filepath.Join(ctx.ImageConfigDir, filename)
+		}
+
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			return "", fmt.Errorf("reading registration code file: %w", err)
+		}
+
+		regCode := strings.TrimSpace(string(data))
+		if regCode == "" {
+			return "", fmt.Errorf("registration code file %s is empty", filename)
+		}
+
+		return regCode, nil
+	case strings.HasPrefix(packages.RegCode, regCodeEnvPrefix):
+		name := strings.TrimPrefix(packages.RegCode, regCodeEnvPrefix)
+
+		regCode := strings.TrimSpace(os.Getenv(name))
+		if regCode == "" {
+			return "", fmt.Errorf("environment variable %q is not set", name)
+		}
+
+		return regCode, nil
+	default:
+		return packages.RegCode, nil
+	}
+}
+
 func RPMsPath(ctx *image.Context) string {
 	return generateComponentPath(ctx, rpmDir)
 }
@@ -193,6 +284,10 @@ func fetchLocalRPMConfig(ctx *image.Context) (*image.LocalRPMConfig, error) {
 		}
 
 		localRPMConfig.GPGKeysPath = gpgPath
+
+		if err = verifyLocalRPMs(localRPMConfig.RPMPath, gpgPath); err != nil {
+			return nil, err
+		}
 	} else if !gpgCheckDisabled {
 		if errors.Is(err, fs.ErrNotExist) {
 			return nil, fmt.Errorf("GPG validation is enabled, but '%s' directory is missing for side-loaded RPMs", gpgDir)
@@ -203,3 +298,35 @@ func fetchLocalRPMConfig(ctx *image.Context) (*image.LocalRPMConfig, error) {
 
 	return localRPMConfig, nil
 }
+
+// verifyLocalRPMs checks that all side-loaded RPMs are signed by one of the provided GPG keys.
+func verifyLocalRPMs(rpmsDir, gpgKeysDir string) error {
+	entries, err := os.ReadDir(rpmsDir)
+	if err != nil {
+		return fmt.Errorf("reading RPM directory at '%s': %w", rpmsDir, err)
+	}
+
+	var rpmFiles []string
+	for _, entry := range entries {
+		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".rpm" {
+			rpmFiles = append(rpmFiles, entry.Name())
+		}
+	}
+
+	if len(rpmFiles) == 0 {
+		return nil
+	}
+
+	keyRing, err := rpm.ReadKeyRing(gpgKeysDir)
+	if err != nil {
+		return fmt.Errorf("reading GPG keys: %w", err)
+	}
+
+	for _, rpmFile := range rpmFiles {
+		if err = rpm.VerifySignature(filepath.Join(rpmsDir, rpmFile), keyRing); err != nil {
+			return fmt.Errorf("verifying signature of '%s': %w", rpmFile, err)
+		}
+	}
+
+	return nil
+}
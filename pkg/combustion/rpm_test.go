@@ -91,6 +91,18 @@ func TestSkipRPMComponent_PopulatedPackageList(t *testing.T) {
 	assert.False(t, SkipRPMComponent(ctx))
 }
 
+func TestSkipRPMComponent_SkippedByUser(t *testing.T) {
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition.OperatingSystem.Packages = image.Packages{
+		PKGList: []string{"pkg1", "pkg2"},
+	}
+	ctx.SkipComponents = []string{"rpm"}
+
+	assert.True(t, SkipRPMComponent(ctx))
+}
+
 func TestSkipRPMComponent_EmptyRPMDir(t *testing.T) {
 	ctx, teardown := setupContext(t)
 	defer teardown()
@@ -284,6 +296,59 @@ func TestConfigureRPMs_GPGFailures(t *testing.T) {
 	}
 }
 
+func TestConfigureRPMs_SignatureVerification(t *testing.T) {
+	tests := []struct {
+		name        string
+		rpmFile     string
+		expectedErr string
+	}{
+		{
+			name:    "Signed RPM with matching key",
+			rpmFile: "signed.rpm",
+		},
+		{
+			name:        "Unsigned RPM with enabled GPG validation",
+			rpmFile:     "unsigned.rpm",
+			expectedErr: "fetching local RPM config: verifying signature of 'unsigned.rpm': package is not signed",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx, teardown := setupContext(t)
+			defer teardown()
+
+			gpgDir := filepath.Join(ctx.ImageConfigDir, rpmDir, gpgDir)
+			require.NoError(t, os.MkdirAll(gpgDir, 0o755))
+
+			require.NoError(t, fileio.CopyFile(filepath.Join("testdata", "rpms", test.rpmFile),
+				filepath.Join(ctx.ImageConfigDir, rpmDir, test.rpmFile), fileio.NonExecutablePerms))
+			require.NoError(t, fileio.CopyFile(filepath.Join("testdata", "rpms", "signing.key"),
+				filepath.Join(gpgDir, "signing.key"), fileio.NonExecutablePerms))
+
+			c := Combustion{
+				RPMRepoCreator: mockRPMRepoCreator{
+					createFunc: func(path string) error {
+						return nil
+					},
+				},
+				RPMResolver: mockRPMResolver{
+					resolveFunc: func(packages *image.Packages, localRPMConfig *image.LocalRPMConfig, outputDir string) (string, []string, error) {
+						return outputDir, []string{"signed"}, nil
+					},
+				},
+			}
+
+			_, err := c.configureRPMs(ctx)
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestConfigureRPMs_SuccessfulConfig(t *testing.T) {
 	expectedRepoName := "bar"
 	expectedDir := "/foo/bar"
@@ -357,3 +422,191 @@ func TestConfigureRPMs_SuccessfulConfig(t *testing.T) {
 	assert.Contains(t, foundContents, zypperInstall)
 	assert.Contains(t, foundContents, zypperRR)
 }
+
+func TestConfigureRPMs_ResolvedRegistrationCode(t *testing.T) {
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	t.Setenv("EIB_TEST_REG_CODE", "secret-code")
+
+	ctx.ImageDefinition.OperatingSystem.Packages = image.Packages{
+		PKGList: []string{"foo"},
+		RegCode: "env:EIB_TEST_REG_CODE",
+	}
+
+	var resolvedRegCode string
+	c := Combustion{
+		RPMRepoCreator: mockRPMRepoCreator{
+			createFunc: func(path string) error {
+				return nil
+			},
+		},
+		RPMResolver: mockRPMResolver{
+			resolveFunc: func(packages *image.Packages, localRPMConfig *image.LocalRPMConfig, outputDir string) (string, []string, error) {
+				resolvedRegCode = packages.RegCode
+				return "/foo/bar", []string{"foo"}, nil
+			},
+		},
+	}
+
+	_, err := c.configureRPMs(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, "secret-code", resolvedRegCode)
+	assert.Equal(t, "env:EIB_TEST_REG_CODE", ctx.ImageDefinition.OperatingSystem.Packages.RegCode)
+}
+
+func TestConfigureRPMs_VersionConstraints(t *testing.T) {
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition.OperatingSystem.Packages = image.Packages{
+		PKGList: []string{"foo=1.2.3", "bar>=1.2"},
+		RegCode: "regcode",
+	}
+
+	c := Combustion{
+		RPMRepoCreator: mockRPMRepoCreator{
+			createFunc: func(path string) error {
+				return nil
+			},
+		},
+		RPMResolver: mockRPMResolver{
+			resolveFunc: func(packages *image.Packages, localRPMConfig *image.LocalRPMConfig, outputDir string) (string, []string, error) {
+				return "/foo/bar", packages.PKGList, nil
+			},
+		},
+	}
+
+	_, err := c.configureRPMs(ctx)
+	require.NoError(t, err)
+
+	foundBytes, err := os.ReadFile(filepath.Join(ctx.CombustionDir, installRPMsScriptName))
+	require.NoError(t, err)
+
+	assert.Contains(t, string(foundBytes), "--auto-agree-with-licenses 'foo=1.2.3' 'bar>=1.2'")
+}
+
+func TestConfigureRPMs_InstallOptions(t *testing.T) {
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition.OperatingSystem.Packages = image.Packages{
+		PKGList:        []string{"foo"},
+		RegCode:        "regcode",
+		InstallOptions: []string{"--no-recommends"},
+	}
+
+	c := Combustion{
+		RPMRepoCreator: mockRPMRepoCreator{
+			createFunc: func(path string) error {
+				return nil
+			},
+		},
+		RPMResolver: mockRPMResolver{
+			resolveFunc: func(packages *image.Packages, localRPMConfig *image.LocalRPMConfig, outputDir string) (string, []string, error) {
+				return "/foo/bar", packages.PKGList, nil
+			},
+		},
+	}
+
+	_, err := c.configureRPMs(ctx)
+	require.NoError(t, err)
+
+	foundBytes, err := os.ReadFile(filepath.Join(ctx.CombustionDir, installRPMsScriptName))
+	require.NoError(t, err)
+
+	assert.Contains(t, string(foundBytes), "--auto-agree-with-licenses --no-recommends foo")
+}
+
+func TestResolveRegistrationCode(t *testing.T) {
+	configDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "reg-code"), []byte("file-code\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "empty"), []byte("\n"), 0o600))
+
+	t.Setenv("EIB_TEST_REG_CODE", "env-code")
+
+	tests := map[string]struct {
+		packages      image.Packages
+		expectedCode  string
+		expectedError string
+	}{
+		"Not specified": {},
+		"Plain text": {
+			packages:     image.Packages{RegCode: "plain-code"},
+			expectedCode: "plain-code",
+		},
+		"Environment variable": {
+			packages:     image.Packages{RegCode: "env:EIB_TEST_REG_CODE"},
+			expectedCode: "env-code",
+		},
+		"Missing environment variable": {
+			packages:      image.Packages{RegCode: "env:EIB_TEST_MISSING_REG_CODE"},
+			expectedError: `environment variable "EIB_TEST_MISSING_REG_CODE" is not set`,
+		},
+		"Relative file": {
+			packages:     image.Packages{RegCodeFile: "reg-code"},
+			expectedCode: "file-code",
+		},
+		"Absolute file": {
+			packages:     image.Packages{RegCodeFile: filepath.Join(configDir, "reg-code")},
+			expectedCode: "file-code",
+		},
+		"Missing file": {
+			packages:      image.Packages{RegCodeFile: "missing"},
+			expectedError: "reading registration code file",
+		},
+		"Empty file": {
+			packages:      image.Packages{RegCodeFile: "empty"},
+			expectedError: "is empty",
+		},
+		"Both forms": {
+			packages:      image.Packages{RegCode: "plain-code", RegCodeFile: "reg-code"},
+			expectedError: "only one of sccRegistrationCode and sccRegistrationCodeFile may be specified",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := &image.Context{
+				ImageConfigDir: configDir,
+				ImageDefinition: &image.Definition{
+					OperatingSystem: image.OperatingSystem{
+						Packages: test.packages,
+					},
+				},
+			}
+
+			regCode, err := ResolveRegistrationCode(ctx)
+			if test.expectedError != "" {
+				require.ErrorContains(t, err, test.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedCode, regCode)
+		})
+	}
+}
+
+func TestResolvedRPMs(t *testing.T) {
+	// Setup
+	repoPath := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(repoPath, "repodata"), 0o755))
+	require.NoError(t, os.Mkdir(filepath.Join(repoPath, "noarch"), 0o755))
+
+	for _, file := range []string{
+		"zsh-5.9-150500.1.1.x86_64.rpm",
+		"noarch/cowsay-3.03-150500.1.1.noarch.rpm",
+		"repodata/repomd.xml",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(repoPath, file), nil, 0o600))
+	}
+
+	// Test
+	rpms := resolvedRPMs(repoPath)
+
+	// Verify
+	assert.Equal(t, []string{"cowsay-3.03-150500.1.1.noarch.rpm", "zsh-5.9-150500.1.1.x86_64.rpm"}, rpms)
+	assert.Nil(t, resolvedRPMs(filepath.Join(repoPath, "missing")))
+}
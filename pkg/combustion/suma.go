@@ -3,6 +3,7 @@ package combustion
 import (
 	_ "embed"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 
@@ -15,6 +16,9 @@ import (
 const (
 	sumaComponentName = "suma"
 	sumaScriptName    = "30-suma-registration.sh"
+	sumaCACertName    = "suma-ca.crt"
+
+	SumaDir = "suma"
 )
 
 //go:embed templates/30-suma-register.sh.tpl
@@ -27,6 +31,13 @@ func configureSuma(ctx *image.Context) ([]string, error) {
 		return nil, nil
 	}
 
+	if suma.CACert != "" {
+		if err := copySumaCACert(ctx); err != nil {
+			log.AuditComponentFailed(sumaComponentName)
+			return nil, err
+		}
+	}
+
 	if err := writeSumaCombustionScript(ctx); err != nil {
 		log.AuditComponentFailed(sumaComponentName)
 		return nil, err
@@ -36,10 +47,51 @@ func configureSuma(ctx *image.Context) ([]string, error) {
 	return []string{sumaScriptName}, nil
 }
 
+func copySumaCACert(ctx *image.Context) error {
+	src := filepath.Join(ctx.ImageConfigDir, SumaDir, ctx.ImageDefinition.OperatingSystem.Suma.CACert)
+	dest := filepath.Join(ctx.CombustionDir, sumaCACertName)
+
+	if err := fileio.CopyFile(src, dest, fileio.NonExecutablePerms); err != nil {
+		return fmt.Errorf("copying SUSE Manager CA certificate: %w", err)
+	}
+
+	return nil
+}
+
 func writeSumaCombustionScript(ctx *image.Context) error {
 	sumaScriptFilename := filepath.Join(ctx.CombustionDir, sumaScriptName)
+	suma := ctx.ImageDefinition.OperatingSystem.Suma
+
+	values := struct {
+		Host          string
+		ActivationKey string
+		ProxyHost     string
+		ProxyPort     string
+		ProxyUsername string
+		ProxyPassword string
+		CACert        string
+	}{
+		Host:          suma.Host,
+		ActivationKey: suma.ActivationKey,
+	}
+
+	if suma.Proxy != "" {
+		proxyURL, err := url.Parse(suma.Proxy)
+		if err != nil {
+			return fmt.Errorf("parsing proxy URL: %w", err)
+		}
+
+		values.ProxyHost = proxyURL.Hostname()
+		values.ProxyPort = proxyURL.Port()
+		values.ProxyUsername = proxyURL.User.Username()
+		values.ProxyPassword, _ = proxyURL.User.Password()
+	}
+
+	if suma.CACert != "" {
+		values.CACert = sumaCACertName
+	}
 
-	data, err := template.Parse(sumaScriptName, sumaScript, ctx.ImageDefinition.OperatingSystem.Suma)
+	data, err := template.Parse(sumaScriptName, sumaScript, &values)
 	if err != nil {
 		return fmt.Errorf("applying template to %s: %w", sumaScriptName, err)
 	}
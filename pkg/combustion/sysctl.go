@@ -0,0 +1,59 @@
+package combustion
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/log"
+	"github.com/suse-edge/edge-image-builder/pkg/template"
+)
+
+const (
+	sysctlComponentName = "sysctl"
+	sysctlScriptName    = "15-sysctl-setup.sh"
+	sysctlDropInPath    = "/etc/sysctl.d/90-eib.conf"
+)
+
+//go:embed templates/15-sysctl-setup.sh.tpl
+var sysctlScript string
+
+func configureSysctl(ctx *image.Context) ([]string, error) {
+	if len(ctx.ImageDefinition.OperatingSystem.Sysctl) == 0 {
+		log.AuditComponentSkipped(sysctlComponentName)
+		return nil, nil
+	}
+
+	if err := writeSysctlCombustionScript(ctx); err != nil {
+		log.AuditComponentFailed(sysctlComponentName)
+		return nil, err
+	}
+
+	log.AuditComponentSuccessful(sysctlComponentName)
+	return []string{sysctlScriptName}, nil
+}
+
+func writeSysctlCombustionScript(ctx *image.Context) error {
+	sysctlScriptFilename := filepath.Join(ctx.CombustionDir, sysctlScriptName)
+
+	values := struct {
+		DropInPath string
+		Sysctl     map[string]string
+	}{
+		DropInPath: sysctlDropInPath,
+		Sysctl:     ctx.ImageDefinition.OperatingSystem.Sysctl,
+	}
+
+	data, err := template.Parse(sysctlScriptName, sysctlScript, values)
+	if err != nil {
+		return fmt.Errorf("applying template to %s: %w", sysctlScriptName, err)
+	}
+
+	if err := os.WriteFile(sysctlScriptFilename, []byte(data), fileio.ExecutablePerms); err != nil {
+		return fmt.Errorf("writing file %s: %w", sysctlScriptFilename, err)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package combustion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestConfigureSysctl_NoConf(t *testing.T) {
+	// Setup
+	var ctx image.Context
+
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{},
+	}
+
+	// Test
+	scripts, err := configureSysctl(&ctx)
+
+	// Verify
+	require.NoError(t, err)
+	assert.Nil(t, scripts)
+}
+
+func TestConfigureSysctl_FullConfiguration(t *testing.T) {
+	// Setup
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition = &image.Definition{
+		OperatingSystem: image.OperatingSystem{
+			Sysctl: map[string]string{
+				"vm.max_map_count":    "262144",
+				"net.ipv4.ip_forward": "1",
+			},
+		},
+	}
+
+	// Test
+	scripts, err := configureSysctl(ctx)
+
+	// Verify
+	require.NoError(t, err)
+
+	require.Len(t, scripts, 1)
+	assert.Equal(t, sysctlScriptName, scripts[0])
+
+	expectedFilename := filepath.Join(ctx.CombustionDir, sysctlScriptName)
+	foundBytes, err := os.ReadFile(expectedFilename)
+	require.NoError(t, err)
+
+	stats, err := os.Stat(expectedFilename)
+	require.NoError(t, err)
+	assert.Equal(t, fileio.ExecutablePerms, stats.Mode())
+
+	foundContents := string(foundBytes)
+	assert.Contains(t, foundContents, "cat <<- 'EOF' > /etc/sysctl.d/90-eib.conf\nnet.ipv4.ip_forward = 1\nvm.max_map_count = 262144\nEOF")
+	assert.Contains(t, foundContents, "sysctl -e -p /etc/sysctl.d/90-eib.conf")
+}
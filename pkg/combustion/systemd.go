@@ -28,19 +28,25 @@ func configureSystemd(ctx *image.Context) ([]string, error) {
 		return nil, nil
 	}
 
+	if err := writeSystemdCombustionScript(ctx); err != nil {
+		log.AuditComponentFailed(systemdComponentName)
+		return nil, err
+	}
+
+	log.AuditComponentSuccessful(systemdComponentName)
+	return []string{systemdScriptName}, nil
+}
+
+func writeSystemdCombustionScript(ctx *image.Context) error {
 	data, err := template.Parse(systemdScriptName, systemdTemplate, ctx.ImageDefinition.OperatingSystem.Systemd)
 	if err != nil {
-		log.AuditComponentFailed(systemdComponentName)
-		return nil, fmt.Errorf("applying systemd script template: %w", err)
+		return fmt.Errorf("applying systemd script template: %w", err)
 	}
 
 	filename := filepath.Join(ctx.CombustionDir, systemdScriptName)
-	err = os.WriteFile(filename, []byte(data), fileio.ExecutablePerms)
-	if err != nil {
-		log.AuditComponentFailed(systemdComponentName)
-		return nil, fmt.Errorf("writing systemd combustion file: %w", err)
+	if err = os.WriteFile(filename, []byte(data), fileio.ExecutablePerms); err != nil {
+		return fmt.Errorf("writing systemd combustion file: %w", err)
 	}
 
-	log.AuditComponentSuccessful(systemdComponentName)
-	return []string{systemdScriptName}, nil
+	return nil
 }
@@ -0,0 +1,131 @@
+package combustion
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+// templatedComponent describes a component which renders its combustion script from a template.
+type templatedComponent struct {
+	name       string
+	configured func(ctx *image.Context) bool
+	write      func(ctx *image.Context) error
+}
+
+var templatedComponents = []templatedComponent{
+	{
+		name:       groupsComponentName,
+		configured: func(ctx *image.Context) bool { return len(ctx.ImageDefinition.OperatingSystem.Groups) != 0 },
+		write:      writeGroupsCombustionScript,
+	},
+	{
+		name:       usersComponentName,
+		configured: func(ctx *image.Context) bool { return len(ctx.ImageDefinition.OperatingSystem.Users) != 0 },
+		write:      writeUsersCombustionScript,
+	},
+	{
+		name:       hostnameComponentName,
+		configured: func(ctx *image.Context) bool { return ctx.ImageDefinition.OperatingSystem.Hostname != "" },
+		write:      writeHostnameCombustionScript,
+	},
+	{
+		name:       hostsComponentName,
+		configured: func(ctx *image.Context) bool { return len(ctx.ImageDefinition.OperatingSystem.HostEntries) != 0 },
+		write:      writeHostsCombustionScript,
+	},
+	{
+		name:       localeComponentName,
+		configured: func(ctx *image.Context) bool { return ctx.ImageDefinition.OperatingSystem.Locale != "" },
+		write:      writeLocaleCombustionScript,
+	},
+	{
+		name:       keymapComponentName,
+		configured: func(*image.Context) bool { return true },
+		write:      writeKeymapCombustionScript,
+	},
+	{
+		name:       sysctlComponentName,
+		configured: func(ctx *image.Context) bool { return len(ctx.ImageDefinition.OperatingSystem.Sysctl) != 0 },
+		write:      writeSysctlCombustionScript,
+	},
+	{
+		name: proxyComponentName,
+		configured: func(ctx *image.Context) bool {
+			proxy := ctx.ImageDefinition.OperatingSystem.Proxy
+			return proxy.HTTPProxy != "" || proxy.HTTPSProxy != ""
+		},
+		write: writeProxyCombustionScript,
+	},
+	{
+		name: systemdComponentName,
+		configured: func(ctx *image.Context) bool {
+			systemd := ctx.ImageDefinition.OperatingSystem.Systemd
+			return len(systemd.Enable) != 0 || len(systemd.Disable) != 0
+		},
+		write: writeSystemdCombustionScript,
+	},
+	{
+		name:       timeComponentName,
+		configured: func(ctx *image.Context) bool { return ctx.ImageDefinition.OperatingSystem.Time.Timezone != "" },
+		write:      writeTimeCombustionScript,
+	},
+	{
+		name:       sumaComponentName,
+		configured: func(ctx *image.Context) bool { return ctx.ImageDefinition.OperatingSystem.Suma.Host != "" },
+		write:      writeSumaCombustionScript,
+	},
+	{
+		name:       registryComponentName,
+		configured: IsEmbeddedArtifactRegistryConfigured,
+		write: func(ctx *image.Context) error {
+			_, err := writeRegistryScript(ctx)
+			return err
+		},
+	},
+	{
+		name:       customComponentName,
+		configured: func(ctx *image.Context) bool { return isComponentConfigured(ctx, customDir) },
+		write: func(ctx *image.Context) error {
+			_, err := handleCustomScripts(ctx)
+			return err
+		},
+	},
+}
+
+// TemplateFailure describes a component whose combustion script template could not be rendered.
+type TemplateFailure struct {
+	Component string
+	Error     error
+}
+
+// ValidateTemplates renders the combustion script templates of all configured components
+// into a temporary directory, so that errors are reported before the image is built.
+func ValidateTemplates(ctx *image.Context) ([]TemplateFailure, error) {
+	dir, err := os.MkdirTemp("", "eib-templates-")
+	if err != nil {
+		return nil, fmt.Errorf("creating temporary combustion directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	dryRunCtx := *ctx
+	dryRunCtx.CombustionDir = dir
+
+	var failures []TemplateFailure
+
+	for _, component := range templatedComponents {
+		if isComponentSkipped(ctx, component.name) || !component.configured(ctx) {
+			continue
+		}
+
+		if writeErr := component.write(&dryRunCtx); writeErr != nil {
+			failures = append(failures, TemplateFailure{
+				Component: component.name,
+				Error:     writeErr,
+			})
+		}
+	}
+
+	return failures, nil
+}
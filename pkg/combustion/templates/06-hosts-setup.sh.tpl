@@ -0,0 +1,8 @@
+#!/bin/bash
+set -euo pipefail
+
+cat <<- EOF >> /etc/hosts
+{{- range .HostEntries }}
+{{ .IP }} {{ join .Hostnames " " }}
+{{- end }}
+EOF
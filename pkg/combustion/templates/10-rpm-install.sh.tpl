@@ -5,7 +5,8 @@ set -euo pipefail
 {{/* RepoPath - path to the air-gapped repository that was created by the RPM resolver */ -}}
 {{/* RepoName - name of the air-gapped repository that was created by the RPM resolver */ -}}
 {{/* PKGList  - list of packages that will be installed */ -}}
+{{/* InstallOptions - additional options passed to the zypper install command */ -}}
 
 zypper ar file://{{.RepoPath}}/{{.RepoName}} {{.RepoName}}
-zypper --no-gpg-checks install -r {{.RepoName}} -y --force-resolution --auto-agree-with-licenses {{.PKGList}}
+zypper --no-gpg-checks install -r {{.RepoName}} -y --force-resolution --auto-agree-with-licenses {{ if .InstallOptions }}{{ .InstallOptions }} {{ end }}{{.PKGList}}
 zypper rr {{.RepoName}}
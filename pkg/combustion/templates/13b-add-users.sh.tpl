@@ -30,6 +30,10 @@ useradd {{ $create_home }}{{ $uid }}{{ $primary_group }}{{ $secondary_groups }}{
 echo '{{$user.Username}}:{{$user.EncryptedPassword}}' | chpasswd -e
 {{- end }}
 
+{{- if $user.DisablePasswordAuth }}
+usermod -p '*' {{$user.Username}}
+{{- end }}
+
 {{- range $user.SSHKeys }}
 mkdir -pm700 /home/{{$user.Username}}/.ssh/
 echo '{{.}}' >> /home/{{$user.Username}}/.ssh/authorized_keys
@@ -43,6 +47,10 @@ chown -R {{$user.Username}} /home/{{$user.Username}}/.ssh
 echo '{{$user.Username}}:{{$user.EncryptedPassword}}' | chpasswd -e
 {{- end }}
 
+{{- if $user.DisablePasswordAuth }}
+usermod -p '*' {{$user.Username}}
+{{- end }}
+
 {{- range $user.SSHKeys }}
 mkdir -pm700 /{{$user.Username}}/.ssh/
 echo '{{.}}' >> /{{$user.Username}}/.ssh/authorized_keys
@@ -0,0 +1,13 @@
+#!/bin/bash
+set -euo pipefail
+
+# The delimiter is quoted so that the values are written verbatim instead of being expanded by the shell
+cat <<- 'EOF' > {{ .DropInPath }}
+{{- range $key, $value := .Sysctl }}
+{{ $key }} = {{ $value }}
+{{- end }}
+EOF
+
+# Unknown keys (e.g. for modules which are not loaded yet) are ignored
+# and will be applied by systemd-sysctl on subsequent boots
+sysctl -e -p {{ .DropInPath }}
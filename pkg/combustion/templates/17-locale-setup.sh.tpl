@@ -0,0 +1,4 @@
+#!/bin/bash
+set -euo pipefail
+
+echo "LANG={{ .Locale }}" > /etc/locale.conf
@@ -0,0 +1,9 @@
+#!/bin/bash
+set -euo pipefail
+
+mkdir -p {{ .InstallDir }}
+cp ./{{ .ScriptsDir }}/* {{ .InstallDir }}/.
+mv {{ .InstallDir }}/{{ .UnitName }} /etc/systemd/system/{{ .UnitName }}
+chmod 0755 {{ .InstallDir }}/*
+
+systemctl enable {{ .UnitName }}
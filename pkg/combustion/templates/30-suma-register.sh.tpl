@@ -1,18 +1,36 @@
 #!/bin/bash
 set -euo pipefail
 
+{{- if .CACert }}
+
+cp ./{{ .CACert }} /etc/pki/trust/anchors/{{ .CACert }}
+update-ca-certificates
+{{- end }}
+
 mkdir -p /etc/venv-salt-minion/
 
-cat <<EOF > /etc/venv-salt-minion/minion
+# The delimiter is quoted so that the values are written verbatim instead of being expanded by the shell
+cat <<'EOF' > /etc/venv-salt-minion/minion
 master: {{ .Host }}
 
 grains:
   susemanager:
-    activation_key: "{{ .ActivationKey }}"
+    activation_key: {{ printf "%q" .ActivationKey }}
 
 server_id_use_crc: adler32
 enable_legacy_startup_events: False
 enable_fqdns_grains: False
+{{- if .ProxyHost }}
+
+proxy_host: {{ .ProxyHost }}
+{{- if .ProxyPort }}
+proxy_port: {{ .ProxyPort }}
+{{- end }}
+{{- if .ProxyUsername }}
+proxy_username: {{ printf "%q" .ProxyUsername }}
+proxy_password: {{ printf "%q" .ProxyPassword }}
+{{- end }}
+{{- end }}
 
 EOF
 
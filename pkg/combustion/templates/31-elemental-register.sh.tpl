@@ -2,7 +2,31 @@
 set -euo pipefail
 
 mkdir -p /etc/elemental
+{{- if .Registrations }}
+
+declare -A registrations
+{{- range .Registrations }}
+{{- $configFile := .ConfigFile }}
+{{- range .Hostnames }}
+registrations[{{ . }}]={{ $configFile }}
+{{- end }}
+{{- end }}
+
+HOSTNAME=$(cat /etc/hostname)
+if [ ! "$HOSTNAME" ]; then
+    HOSTNAME=$(cat /proc/sys/kernel/hostname)
+fi
+
+CONFIG_FILE="${registrations[$HOSTNAME]:-{{ .ConfigFile }}}"
+if [ ! "$CONFIG_FILE" ]; then
+    echo "ERROR: Could not identify the Elemental registration of host '$HOSTNAME'"
+    exit 1
+fi
+
+cp "./$CONFIG_FILE" /etc/elemental/config.yaml
+{{- else }}
 cp ./{{ .ConfigFile }} /etc/elemental/config.yaml
+{{- end }}
 
 # Enable systemd based Elemental registration
 # Register --no-toolkit disables OS management in Rancher
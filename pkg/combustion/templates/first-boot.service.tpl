@@ -0,0 +1,16 @@
+[Unit]
+Description=Run the first boot scripts provided by Edge Image Builder
+Wants=network-online.target
+After=network-online.target
+ConditionPathExists=!{{ .DoneFile }}
+
+[Service]
+Type=oneshot
+RemainAfterExit=yes
+{{- range .Scripts }}
+ExecStart={{ $.InstallDir }}/{{ . }}
+{{- end }}
+ExecStartPost=/usr/bin/touch {{ .DoneFile }}
+
+[Install]
+WantedBy=multi-user.target
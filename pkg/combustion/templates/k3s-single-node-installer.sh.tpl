@@ -21,7 +21,7 @@ mkdir -p /etc/rancher/k3s/
 cp {{ .configFilePath }}/{{ .configFile }} /etc/rancher/k3s/config.yaml
 
 if [ -f {{ .registryMirrors }} ]; then
-cp {{ .registryMirrors }} /etc/rancher/k3s/registries.yaml
+cp {{ .registryMirrors }} {{ .registryMirrorsDest }}
 fi
 
 export INSTALL_K3S_SKIP_DOWNLOAD=true
@@ -6,6 +6,12 @@ metadata:
   namespace: metallb-system
 spec:
   addresses:
+{{- if .AddressPool }}
+{{- range .AddressPool }}
+  - {{ . }}
+{{- end }}
+  avoidBuggyIPs: true
+{{- else }}
   - {{ .APIAddress }}/32
   avoidBuggyIPs: true
   serviceAllocation:
@@ -14,6 +20,7 @@ spec:
     serviceSelectors:
       - matchExpressions:
         - {key: "serviceType", operator: In, values: [kubernetes-vip]}
+{{- end }}
 ---
 apiVersion: metallb.io/v1beta1
 kind: L2Advertisement
@@ -31,6 +38,10 @@ metadata:
   namespace: default
   labels:
     serviceType: kubernetes-vip
+{{- if .AddressPool }}
+  annotations:
+    metallb.universe.tf/loadBalancerIPs: {{ .APIAddress }}
+{{- end }}
 spec:
   ports:
 {{- if .RKE2 }}
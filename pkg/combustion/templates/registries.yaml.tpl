@@ -6,4 +6,13 @@ mirrors:
   {{ . }}:
     endpoint:
       - "http://localhost:{{ $.Port }}"
+{{- end }}
+{{- if .Configs }}
+configs:
+{{- range .Configs }}
+  {{ .Host }}:
+    auth:
+      username: {{ printf "%q" .Username }}
+      password: {{ printf "%q" .Password }}
+{{- end }}
 {{- end }}
\ No newline at end of file
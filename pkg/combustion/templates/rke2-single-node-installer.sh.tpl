@@ -21,7 +21,7 @@ mkdir -p /etc/rancher/rke2/
 cp {{ .configFilePath }}/{{ .configFile }} /etc/rancher/rke2/config.yaml
 
 if [ -f {{ .registryMirrors }} ]; then
-cp {{ .registryMirrors }} /etc/rancher/rke2/registries.yaml
+cp {{ .registryMirrors }} {{ .registryMirrorsDest }}
 fi
 
 export INSTALL_RKE2_TAR_PREFIX=/opt/rke2
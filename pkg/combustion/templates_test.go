@@ -0,0 +1,60 @@
+package combustion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestValidateTemplates(t *testing.T) {
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.ImageDefinition.OperatingSystem = image.OperatingSystem{
+		Hostname: "node1.suse.com",
+		Time: image.Time{
+			Timezone: "Europe/London",
+		},
+		Suma: image.Suma{
+			Host:  "suma.suse.com",
+			Proxy: "http://proxy:port",
+		},
+	}
+
+	scriptsDir := filepath.Join(ctx.ImageConfigDir, customDir, customScriptsDir)
+	require.NoError(t, os.MkdirAll(scriptsDir, os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(scriptsDir, "60-custom.sh.tpl"), []byte("echo {{ .Unknown }}"), 0o744))
+
+	failures, err := ValidateTemplates(ctx)
+	require.NoError(t, err)
+
+	require.Len(t, failures, 2)
+	assert.Equal(t, sumaComponentName, failures[0].Component)
+	assert.ErrorContains(t, failures[0].Error, "parsing proxy URL")
+	assert.Equal(t, customComponentName, failures[1].Component)
+	assert.ErrorContains(t, failures[1].Error, "can't evaluate field Unknown")
+
+	// - the build combustion directory must not be written to
+	entries, err := os.ReadDir(ctx.CombustionDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestValidateTemplates_SkippedComponent(t *testing.T) {
+	ctx, teardown := setupContext(t)
+	defer teardown()
+
+	ctx.SkipComponents = []string{"suma"}
+	ctx.ImageDefinition.OperatingSystem.Suma = image.Suma{
+		Host:  "suma.suse.com",
+		Proxy: "http://proxy:port",
+	}
+
+	failures, err := ValidateTemplates(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, failures)
+}
@@ -27,19 +27,25 @@ func configureUsers(ctx *image.Context) ([]string, error) {
 		return nil, nil
 	}
 
+	if err := writeUsersCombustionScript(ctx); err != nil {
+		log.AuditComponentFailed(usersComponentName)
+		return nil, err
+	}
+
+	log.AuditComponentSuccessful(usersComponentName)
+	return []string{usersScriptName}, nil
+}
+
+func writeUsersCombustionScript(ctx *image.Context) error {
 	data, err := template.Parse(usersScriptName, usersScript, ctx.ImageDefinition.OperatingSystem.Users)
 	if err != nil {
-		log.AuditComponentFailed(usersComponentName)
-		return nil, fmt.Errorf("parsing users script template: %w", err)
+		return fmt.Errorf("parsing users script template: %w", err)
 	}
 
 	filename := filepath.Join(ctx.CombustionDir, usersScriptName)
-	err = os.WriteFile(filename, []byte(data), fileio.ExecutablePerms)
-	if err != nil {
-		log.AuditComponentFailed(usersComponentName)
-		return nil, fmt.Errorf("writing %s to the combustion directory: %w", usersScriptName, err)
+	if err = os.WriteFile(filename, []byte(data), fileio.ExecutablePerms); err != nil {
+		return fmt.Errorf("writing %s to the combustion directory: %w", usersScriptName, err)
 	}
 
-	log.AuditComponentSuccessful(usersComponentName)
-	return []string{usersScriptName}, nil
+	return nil
 }
@@ -38,6 +38,12 @@ func TestConfigureUsers(t *testing.T) {
 					Username: "gamma",
 					SSHKeys:  []string{"gammakey"},
 				},
+				{
+					Username:            "delta",
+					SSHKeys:             []string{"deltakey"},
+					CreateHomeDir:       true,
+					DisablePasswordAuth: true,
+				},
 				{
 					Username:          "root",
 					EncryptedPassword: "root123",
@@ -87,6 +93,11 @@ func TestConfigureUsers(t *testing.T) {
 	assert.Contains(t, foundContents, "mkdir -pm700 /home/gamma/.ssh/")
 	assert.Contains(t, foundContents, "echo 'gammakey' >> /home/gamma/.ssh/authorized_keys")
 	assert.Contains(t, foundContents, "chown -R gamma /home/gamma/.ssh")
+	assert.NotContains(t, foundContents, "usermod -p '*' gamma")
+
+	// - SSH key only with password authentication disabled
+	assert.Contains(t, foundContents, "useradd -m delta\nusermod -p '*' delta\n")
+	assert.Contains(t, foundContents, "echo 'deltakey' >> /home/delta/.ssh/authorized_keys")
 
 	// - Special handling for root
 	assert.NotContains(t, foundContents, "useradd root")
@@ -95,6 +106,7 @@ func TestConfigureUsers(t *testing.T) {
 	assert.Contains(t, foundContents, "echo 'rootkey1' >> /root/.ssh/authorized_keys")
 	assert.Contains(t, foundContents, "echo 'rootkey2' >> /root/.ssh/authorized_keys")
 	assert.NotContains(t, foundContents, "chown -R root")
+	assert.NotContains(t, foundContents, "usermod -p '*' root")
 }
 
 func TestConfigureUsers_NoUsers(t *testing.T) {
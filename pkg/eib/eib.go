@@ -1,6 +1,7 @@
 package eib
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io/fs"
@@ -18,31 +19,56 @@ import (
 	"github.com/suse-edge/edge-image-builder/pkg/log"
 	"github.com/suse-edge/edge-image-builder/pkg/network"
 	"github.com/suse-edge/edge-image-builder/pkg/podman"
+	"github.com/suse-edge/edge-image-builder/pkg/registry"
 	"github.com/suse-edge/edge-image-builder/pkg/rpm"
 	"github.com/suse-edge/edge-image-builder/pkg/rpm/resolver"
 	"go.uber.org/zap"
 )
 
-func Run(ctx *image.Context, rootBuildDir string) error {
-	if err := appendKubernetesSELinuxRPMs(ctx); err != nil {
+// Run builds the image described by the build context. Cancelling the given context
+// (e.g. on SIGINT) aborts the pending commands and removes the partial build artifacts.
+// The pending downloads are aborted along with it if the HTTP client of the build context
+// has been created with the same context.
+func Run(ctx context.Context, buildCtx *image.Context, rootBuildDir string) error {
+	if err := build.CheckAvailableSpace(buildCtx); err != nil {
+		log.Auditf("Checking the available disk space failed: %s", err)
+		return fmt.Errorf("checking available disk space: %w", err)
+	}
+
+	c, err := prepareBuild(ctx, buildCtx, rootBuildDir)
+	if err != nil {
+		if ctx.Err() != nil {
+			log.Audit("Build cancelled. Removing partial build artifacts...")
+			build.RemoveBuildArtifacts(buildCtx.BuildDir)
+		}
+
+		return err
+	}
+
+	builder := build.NewBuilder(buildCtx, c)
+	return builder.Build(ctx)
+}
+
+// prepareBuild downloads the dependencies of the requested components and sets up the Combustion handler.
+func prepareBuild(ctx context.Context, buildCtx *image.Context, rootBuildDir string) (*combustion.Combustion, error) {
+	if err := appendKubernetesSELinuxRPMs(ctx, buildCtx); err != nil {
 		log.Auditf("Bootstrapping dependency services failed.")
-		return fmt.Errorf("configuring kubernetes selinux policy: %w", err)
+		return nil, fmt.Errorf("configuring kubernetes selinux policy: %w", err)
 	}
 
-	appendElementalRPMs(ctx)
-	appendHelm(ctx)
+	appendElementalRPMs(buildCtx)
+	appendHelm(buildCtx)
 
-	c, err := buildCombustion(ctx, rootBuildDir)
+	c, err := buildCombustion(ctx, buildCtx, rootBuildDir)
 	if err != nil {
 		log.Audit("Bootstrapping dependency services failed.")
-		return fmt.Errorf("building combustion: %w", err)
+		return nil, fmt.Errorf("building combustion: %w", err)
 	}
 
-	builder := build.NewBuilder(ctx, c)
-	return builder.Build()
+	return c, nil
 }
 
-func appendKubernetesSELinuxRPMs(ctx *image.Context) error {
+func appendKubernetesSELinuxRPMs(runCtx context.Context, ctx *image.Context) error {
 	if ctx.ImageDefinition.Kubernetes.Version == "" {
 		return nil
 	}
@@ -58,6 +84,12 @@ func appendKubernetesSELinuxRPMs(ctx *image.Context) error {
 		return nil
 	}
 
+	if ctx.ImageDefinition.Kubernetes.SkipSELinuxPackages {
+		log.AuditInfo("SELinux is enabled in the Kubernetes configuration but the SELinux RPM packages will not be installed " +
+			"since 'skipSELinuxPackages' is set. Please ensure that the necessary packages are provided.")
+		return nil
+	}
+
 	log.AuditInfo("SELinux is enabled in the Kubernetes configuration. " +
 		"The necessary RPM packages will be downloaded.")
 
@@ -78,21 +110,28 @@ func appendKubernetesSELinuxRPMs(ctx *image.Context) error {
 		return fmt.Errorf("creating directory '%s': %w", gpgKeysDir, err)
 	}
 
-	if err = kubernetes.DownloadSELinuxRPMsSigningKey(gpgKeysDir); err != nil {
-		return fmt.Errorf("downloading signing key: %w", err)
+	signingKey := ctx.ImageDefinition.Kubernetes.SELinuxSigningKey
+	if !kubernetes.IsRemoteSELinuxSigningKey(signingKey) {
+		signingKey = filepath.Join(ctx.ImageConfigDir, signingKey)
+	}
+
+	if err = kubernetes.StoreSELinuxRPMsSigningKey(runCtx, ctx.HTTPClient, signingKey, gpgKeysDir); err != nil {
+		return fmt.Errorf("storing signing key: %w", err)
 	}
 
 	return nil
 }
 
 func appendElementalRPMs(ctx *image.Context) {
-	elementalDir := combustion.ElementalPath(ctx)
-	if _, err := os.Stat(elementalDir); err != nil {
-		if !errors.Is(err, fs.ErrNotExist) {
-			zap.S().Warnf("Looking for '%s' dir failed unexpectedly: %s", elementalDir, err)
+	if !ctx.ImageDefinition.OperatingSystem.Elemental.IsConfigured() {
+		elementalDir := combustion.ElementalPath(ctx)
+		if _, err := os.Stat(elementalDir); err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				zap.S().Warnf("Looking for '%s' dir failed unexpectedly: %s", elementalDir, err)
+			}
+
+			return
 		}
-
-		return
 	}
 
 	log.AuditInfo("Elemental registration is configured. The necessary RPM packages will be downloaded.")
@@ -118,40 +157,54 @@ func appendHelm(ctx *image.Context) {
 	ctx.ImageDefinition.Kubernetes.Helm.Repositories = append(ctx.ImageDefinition.Kubernetes.Helm.Repositories, componentRepos...)
 }
 
-func buildCombustion(ctx *image.Context, rootDir string) (*combustion.Combustion, error) {
+func buildCombustion(runCtx context.Context, ctx *image.Context, rootDir string) (*combustion.Combustion, error) {
 	combustionHandler := &combustion.Combustion{
 		NetworkConfigGenerator:       network.ConfigGenerator{},
 		NetworkConfiguratorInstaller: network.ConfiguratorInstaller{},
 	}
 
 	if !combustion.SkipRPMComponent(ctx) {
-		p, err := podman.New(ctx.BuildDir)
+		p, err := podman.New(runCtx, ctx.BuildDir)
 		if err != nil {
 			return nil, fmt.Errorf("setting up Podman instance: %w", err)
 		}
 
 		imgPath := filepath.Join(ctx.ImageConfigDir, "base-images", ctx.ImageDefinition.Image.BaseImage)
 		imgType := ctx.ImageDefinition.Image.ImageType
-		baseBuilder := resolver.NewTarballBuilder(ctx.BuildDir, imgPath, imgType, p)
+		baseBuilder := resolver.NewTarballBuilder(runCtx, ctx.BuildDir, imgPath, imgType, p)
 
 		combustionHandler.RPMResolver = resolver.New(ctx.BuildDir, p, baseBuilder, "")
-		combustionHandler.RPMRepoCreator = rpm.NewRepoCreator(ctx.BuildDir)
+		combustionHandler.RPMRepoCreator = rpm.NewRepoCreator(runCtx, ctx.BuildDir)
 	}
 
-	if combustion.IsEmbeddedArtifactRegistryConfigured(ctx) {
-		certsDir := filepath.Join(ctx.ImageConfigDir, combustion.K8sDir, combustion.HelmDir, combustion.CertsDir)
-		combustionHandler.HelmClient = helm.New(ctx.BuildDir, certsDir)
-	}
+	registryConfigured := combustion.IsEmbeddedArtifactRegistryConfigured(ctx)
+	kubernetesConfigured := ctx.ImageDefinition.Kubernetes.Version != ""
 
-	if ctx.ImageDefinition.Kubernetes.Version != "" {
-		c, err := cache.New(rootDir)
-		if err != nil {
+	var c *cache.Cache
+	if registryConfigured || kubernetesConfigured {
+		var err error
+		if c, err = cache.New(rootDir); err != nil {
 			return nil, fmt.Errorf("initialising cache instance: %w", err)
 		}
+	}
+
+	if registryConfigured {
+		certsDir := filepath.Join(ctx.ImageConfigDir, combustion.K8sDir, combustion.HelmDir, combustion.CertsDir)
+		combustionHandler.HelmClient = helm.New(runCtx, ctx.HTTPClient, ctx.BuildDir, certsDir, ctx.CommandLogMaxSize)
+		combustionHandler.RegistryCache = c
+
+		// Image sizes can only be estimated by querying the registries
+		if !ctx.HTTPClient.Offline() {
+			combustionHandler.ImageSizeResolver = registry.ImageSizeResolver{}
+		}
+	}
 
-		combustionHandler.KubernetesScriptDownloader = kubernetes.ScriptDownloader{}
+	if kubernetesConfigured {
+		combustionHandler.KubernetesScriptDownloader = kubernetes.ScriptDownloader{Client: ctx.HTTPClient}
 		combustionHandler.KubernetesArtefactDownloader = kubernetes.ArtefactDownloader{
-			Cache: c,
+			Cache:      c,
+			ReleaseURL: ctx.ImageDefinition.Kubernetes.ReleaseURL,
+			Client:     ctx.HTTPClient,
 		}
 	}
 
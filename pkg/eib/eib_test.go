@@ -1,12 +1,15 @@
 package eib
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/combustion"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
 )
 
 func TestSetupBuildDirectory_EmptyRootDir(t *testing.T) {
@@ -55,3 +58,31 @@ func TestSetupBuildDir_NonEmptyRootDir(t *testing.T) {
 		})
 	}
 }
+
+func TestAppendKubernetesSELinuxRPMs_SkipSELinuxPackages(t *testing.T) {
+	// Setup
+	ctx := &image.Context{
+		ImageConfigDir: t.TempDir(),
+		ImageDefinition: &image.Definition{
+			Kubernetes: image.Kubernetes{
+				Version:             "v1.30.3+k3s1",
+				SkipSELinuxPackages: true,
+			},
+		},
+	}
+
+	serverConfigPath := combustion.KubernetesConfigPath(ctx)
+	require.NoError(t, os.MkdirAll(filepath.Dir(serverConfigPath), 0o755))
+	require.NoError(t, os.WriteFile(serverConfigPath, []byte("selinux: true\n"), 0o600))
+
+	// Test
+	err := appendKubernetesSELinuxRPMs(context.Background(), ctx)
+
+	// Verify
+	require.NoError(t, err)
+
+	packages := ctx.ImageDefinition.OperatingSystem.Packages
+	assert.Empty(t, packages.PKGList)
+	assert.Empty(t, packages.AdditionalRepos)
+	assert.NoDirExists(t, combustion.GPGKeysPath(ctx))
+}
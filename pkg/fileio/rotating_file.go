@@ -0,0 +1,182 @@
+package fileio
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+)
+
+// DefaultMaxLogFileSize is the size (in bytes) after which log files of
+// long-running external commands (hauler, helm, etc.) are rotated.
+const DefaultMaxLogFileSize int64 = 100 * 1024 * 1024
+
+const rotatedFileSuffix = ".1"
+
+// Writers for the same path share a lock so that a rotation performed by one
+// of them is never interleaved with a write from another. The lock is dropped
+// once the last writer of the path is closed.
+var (
+	rotationLocksMu sync.Mutex
+	rotationLocks   = map[string]*rotationLock{}
+)
+
+type rotationLock struct {
+	sync.Mutex
+	// users counts the open writers of the path, guarded by rotationLocksMu
+	users int
+}
+
+// RotatingFile is an append-only file which is rotated once it grows past a maximum size.
+// The previous contents are preserved under the same name suffixed with ".1",
+// overwriting any earlier rotation.
+//
+// RotatingFile is safe for concurrent use, including by multiple instances opened for the same path.
+type RotatingFile struct {
+	path    string
+	maxSize int64
+	perms   os.FileMode
+	lock    *rotationLock
+	file    *os.File
+	closed  bool
+}
+
+// OpenRotatingFile opens (or creates) the file at the given path in append mode.
+// A non-positive maxSize falls back to DefaultMaxLogFileSize.
+func OpenRotatingFile(path string, maxSize int64, perms os.FileMode) (*RotatingFile, error) {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxLogFileSize
+	}
+
+	rf := &RotatingFile{
+		path:    path,
+		maxSize: maxSize,
+		perms:   perms,
+		lock:    acquireRotationLock(path),
+	}
+
+	rf.lock.Lock()
+	defer rf.lock.Unlock()
+
+	if err := rf.open(); err != nil {
+		releaseRotationLock(path)
+		return nil, err
+	}
+
+	return rf, nil
+}
+
+func acquireRotationLock(path string) *rotationLock {
+	rotationLocksMu.Lock()
+	defer rotationLocksMu.Unlock()
+
+	lock, ok := rotationLocks[path]
+	if !ok {
+		lock = &rotationLock{}
+		rotationLocks[path] = lock
+	}
+
+	lock.users++
+	return lock
+}
+
+func releaseRotationLock(path string) {
+	rotationLocksMu.Lock()
+	defer rotationLocksMu.Unlock()
+
+	lock, ok := rotationLocks[path]
+	if !ok {
+		return
+	}
+
+	if lock.users--; lock.users == 0 {
+		delete(rotationLocks, path)
+	}
+}
+
+func (rf *RotatingFile) Write(p []byte) (int, error) {
+	rf.lock.Lock()
+	defer rf.lock.Unlock()
+
+	if err := rf.rotateIfNeeded(int64(len(p))); err != nil {
+		return 0, fmt.Errorf("rotating file: %w", err)
+	}
+
+	return rf.file.Write(p)
+}
+
+func (rf *RotatingFile) Close() error {
+	rf.lock.Lock()
+	defer rf.lock.Unlock()
+
+	if rf.closed {
+		return fs.ErrClosed
+	}
+
+	rf.closed = true
+	releaseRotationLock(rf.path)
+
+	return rf.file.Close()
+}
+
+// Name returns the path of the file being written to.
+func (rf *RotatingFile) Name() string {
+	return rf.path
+}
+
+func (rf *RotatingFile) open() error {
+	file, err := os.OpenFile(rf.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, rf.perms)
+	if err != nil {
+		return fmt.Errorf("opening file: %w", err)
+	}
+
+	rf.file = file
+	return nil
+}
+
+func (rf *RotatingFile) rotateIfNeeded(pending int64) error {
+	info, err := os.Stat(rf.path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("reading file info: %w", err)
+	}
+
+	// Another writer has already rotated (or removed) the file,
+	// so the current descriptor points at a stale file.
+	if info == nil || !rf.isCurrentFile(info) {
+		if err = rf.reopen(); err != nil {
+			return err
+		}
+
+		if info == nil {
+			return nil
+		}
+	}
+
+	if info.Size() == 0 || info.Size()+pending <= rf.maxSize {
+		return nil
+	}
+
+	if err = os.Rename(rf.path, rf.path+rotatedFileSuffix); err != nil {
+		return fmt.Errorf("renaming file: %w", err)
+	}
+
+	return rf.reopen()
+}
+
+func (rf *RotatingFile) isCurrentFile(info fs.FileInfo) bool {
+	current, err := rf.file.Stat()
+	if err != nil {
+		return false
+	}
+
+	return os.SameFile(info, current)
+}
+
+func (rf *RotatingFile) reopen() error {
+	if err := rf.file.Close(); err != nil && !errors.Is(err, fs.ErrClosed) {
+		return fmt.Errorf("closing file: %w", err)
+	}
+
+	return rf.open()
+}
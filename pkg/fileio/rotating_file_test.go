@@ -0,0 +1,98 @@
+package fileio
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotatingFile_RotatesPastThreshold(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "eib-rotating-file-test-")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	path := filepath.Join(tmpDir, "test.log")
+
+	file, err := OpenRotatingFile(path, 10, NonExecutablePerms)
+	require.NoError(t, err)
+
+	_, err = file.Write([]byte("12345678"))
+	require.NoError(t, err)
+	assert.NoFileExists(t, path+".1")
+
+	// Exceeds the threshold, the previous contents must be rotated
+	_, err = file.Write([]byte("abcdef"))
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	rotated, err := os.ReadFile(path + ".1")
+	require.NoError(t, err)
+	assert.Equal(t, "12345678", string(rotated))
+
+	current, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "abcdef", string(current))
+}
+
+func TestRotatingFile_ConcurrentWriters(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "eib-rotating-file-test-")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	path := filepath.Join(tmpDir, "test.log")
+	const line = "0123456789\n"
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		file, err := OpenRotatingFile(path, int64(len(line)*20), NonExecutablePerms)
+		require.NoError(t, err)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer file.Close()
+
+			for j := 0; j < 25; j++ {
+				_, err := file.Write([]byte(line))
+				assert.NoError(t, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, p := range []string{path, path + ".1"} {
+		contents, err := os.ReadFile(p)
+		require.NoError(t, err)
+
+		for _, l := range strings.SplitAfter(string(contents), "\n") {
+			if l != "" {
+				assert.Equal(t, line, l)
+			}
+		}
+	}
+}
+
+func TestRotatingFile_ReleasesLockOnClose(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.log")
+
+	first, err := OpenRotatingFile(path, 0, NonExecutablePerms)
+	require.NoError(t, err)
+
+	second, err := OpenRotatingFile(path, 0, NonExecutablePerms)
+	require.NoError(t, err)
+	assert.Same(t, first.lock, second.lock)
+
+	require.NoError(t, first.Close())
+	assert.Contains(t, rotationLocks, path)
+
+	require.NoError(t, second.Close())
+	assert.NotContains(t, rotationLocks, path)
+
+	assert.ErrorIs(t, second.Close(), fs.ErrClosed)
+}
@@ -1,6 +1,8 @@
 package helm
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"net/url"
@@ -10,7 +12,9 @@ import (
 	"strings"
 
 	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/http"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/kubernetes"
 	"go.uber.org/zap"
 	"gopkg.in/yaml.v3"
 )
@@ -20,19 +24,24 @@ const (
 	pullLogFileName       = "helm-pull.log"
 	repoAddLogFileName    = "helm-repo-add.log"
 	registryLoginFileName = "helm-registry-login.log"
-
-	outputFileFlags = os.O_APPEND | os.O_CREATE | os.O_WRONLY
 )
 
 type Helm struct {
-	outputDir string
-	certsDir  string
+	// context terminates the running helm commands once it is cancelled
+	context    context.Context
+	client     *http.Client
+	outputDir  string
+	certsDir   string
+	maxLogSize int64
 }
 
-func New(outputDir, certsDir string) *Helm {
+func New(ctx context.Context, client *http.Client, outputDir, certsDir string, maxLogSize int64) *Helm {
 	return &Helm{
-		outputDir: outputDir,
-		certsDir:  certsDir,
+		context:    ctx,
+		client:     client,
+		outputDir:  outputDir,
+		certsDir:   certsDir,
+		maxLogSize: maxLogSize,
 	}
 }
 
@@ -46,9 +55,13 @@ func chartPath(repoName, repoURL, chart string) string {
 }
 
 func (h *Helm) AddRepo(repo *image.HelmRepository) error {
+	if err := h.client.CheckOnline(repo.URL); err != nil {
+		return err
+	}
+
 	logFile := filepath.Join(h.outputDir, repoAddLogFileName)
 
-	file, err := os.OpenFile(logFile, outputFileFlags, fileio.NonExecutablePerms)
+	file, err := fileio.OpenRotatingFile(logFile, h.maxLogSize, fileio.NonExecutablePerms)
 	if err != nil {
 		return fmt.Errorf("opening log file: %w", err)
 	}
@@ -58,7 +71,7 @@ func (h *Helm) AddRepo(repo *image.HelmRepository) error {
 		}
 	}()
 
-	cmd := addRepoCommand(repo, h.certsDir, file)
+	cmd := addRepoCommand(h.context, repo, h.certsDir, file)
 
 	if _, err = fmt.Fprintf(file, "command: %s\n", cmd); err != nil {
 		return fmt.Errorf("writing command prefix to log file: %w", err)
@@ -67,7 +80,7 @@ func (h *Helm) AddRepo(repo *image.HelmRepository) error {
 	return cmd.Run()
 }
 
-func addRepoCommand(repo *image.HelmRepository, certsDir string, output io.Writer) *exec.Cmd {
+func addRepoCommand(ctx context.Context, repo *image.HelmRepository, certsDir string, output io.Writer) *exec.Cmd {
 	var args []string
 	args = append(args, "repo", "add", repo.Name, repo.URL)
 
@@ -82,7 +95,7 @@ func addRepoCommand(repo *image.HelmRepository, certsDir string, output io.Write
 		args = append(args, "--ca-file", caFilePath)
 	}
 
-	cmd := exec.Command("helm", args...)
+	cmd := exec.CommandContext(ctx, "helm", args...)
 	cmd.Stdout = output
 	cmd.Stderr = output
 
@@ -90,9 +103,13 @@ func addRepoCommand(repo *image.HelmRepository, certsDir string, output io.Write
 }
 
 func (h *Helm) RegistryLogin(repo *image.HelmRepository) error {
+	if err := h.client.CheckOnline(repo.URL); err != nil {
+		return err
+	}
+
 	logFile := filepath.Join(h.outputDir, registryLoginFileName)
 
-	file, err := os.OpenFile(logFile, outputFileFlags, fileio.NonExecutablePerms)
+	file, err := fileio.OpenRotatingFile(logFile, h.maxLogSize, fileio.NonExecutablePerms)
 	if err != nil {
 		return fmt.Errorf("opening log file: %w", err)
 	}
@@ -107,16 +124,43 @@ func (h *Helm) RegistryLogin(repo *image.HelmRepository) error {
 		return fmt.Errorf("getting host url: %w", err)
 	}
 
-	cmd := registryLoginCommand(host, repo, h.certsDir, file)
+	output := &bytes.Buffer{}
+	cmd := registryLoginCommand(h.context, host, repo, h.certsDir, io.MultiWriter(file, output))
 
 	if _, err = fmt.Fprintf(file, "command: %s\n", cmd); err != nil {
 		return fmt.Errorf("writing command prefix to log file: %w", err)
 	}
 
-	return cmd.Run()
+	if err = cmd.Run(); err != nil {
+		if isAuthenticationFailure(output.String()) {
+			return authenticationError(repo, fmt.Errorf("%w: %w", ErrUnauthorized, err))
+		}
+
+		return fmt.Errorf("logging into registry '%s': %w", host, err)
+	}
+
+	return nil
 }
 
-func registryLoginCommand(host string, repo *image.HelmRepository, certsDir string, output io.Writer) *exec.Cmd {
+func authenticationError(repo *image.HelmRepository, err error) error {
+	return fmt.Errorf("authentication failed for repository '%s': %w", repo.Name, err)
+}
+
+// isAuthenticationFailure reports whether the output of a failed Helm command indicates
+// that the registry rejected the provided credentials.
+func isAuthenticationFailure(output string) bool {
+	output = strings.ToLower(output)
+
+	for _, indicator := range []string{"unauthorized", "forbidden", "denied"} {
+		if strings.Contains(output, indicator) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func registryLoginCommand(ctx context.Context, host string, repo *image.HelmRepository, certsDir string, output io.Writer) *exec.Cmd {
 	var args []string
 	args = append(args, "registry", "login", host)
 
@@ -131,7 +175,7 @@ func registryLoginCommand(host string, repo *image.HelmRepository, certsDir stri
 		args = append(args, "--ca-file", caFilePath)
 	}
 
-	cmd := exec.Command("helm", args...)
+	cmd := exec.CommandContext(ctx, "helm", args...)
 	cmd.Stdout = output
 	cmd.Stderr = output
 
@@ -139,9 +183,13 @@ func registryLoginCommand(host string, repo *image.HelmRepository, certsDir stri
 }
 
 func (h *Helm) Pull(chart string, repo *image.HelmRepository, version, destDir string) (string, error) {
+	if err := h.client.CheckOnline(fmt.Sprintf("%s (%s)", chart, repo.URL)); err != nil {
+		return "", err
+	}
+
 	logFile := filepath.Join(h.outputDir, pullLogFileName)
 
-	file, err := os.OpenFile(logFile, outputFileFlags, fileio.NonExecutablePerms)
+	file, err := fileio.OpenRotatingFile(logFile, h.maxLogSize, fileio.NonExecutablePerms)
 	if err != nil {
 		return "", fmt.Errorf("opening log file: %w", err)
 	}
@@ -156,7 +204,7 @@ func (h *Helm) Pull(chart string, repo *image.HelmRepository, version, destDir s
 		return "", fmt.Errorf("creating chart dir %q: %w", chartDir, err)
 	}
 
-	cmd := pullCommand(chart, repo, version, chartDir, h.certsDir, file)
+	cmd := pullCommand(h.context, chart, repo, version, chartDir, h.certsDir, file)
 
 	if _, err = fmt.Fprintf(file, "command: %s\n", cmd); err != nil {
 		return "", fmt.Errorf("writing command prefix to log file: %w", err)
@@ -179,7 +227,7 @@ func (h *Helm) Pull(chart string, repo *image.HelmRepository, version, destDir s
 	return chartPath, nil
 }
 
-func pullCommand(chart string, repo *image.HelmRepository, version, destDir, certsDir string, output io.Writer) *exec.Cmd {
+func pullCommand(ctx context.Context, chart string, repo *image.HelmRepository, version, destDir, certsDir string, output io.Writer) *exec.Cmd {
 	path := chartPath(repo.Name, repo.URL, chart)
 
 	var args []string
@@ -202,7 +250,7 @@ func pullCommand(chart string, repo *image.HelmRepository, version, destDir, cer
 		args = append(args, "--ca-file", caFilePath)
 	}
 
-	cmd := exec.Command("helm", args...)
+	cmd := exec.CommandContext(ctx, "helm", args...)
 
 	cmd.Stdout = output
 	cmd.Stderr = output
@@ -210,10 +258,10 @@ func pullCommand(chart string, repo *image.HelmRepository, version, destDir, cer
 	return cmd
 }
 
-func (h *Helm) Template(chart, repository, version, valuesFilePath, kubeVersion, targetNamespace string) ([]map[string]any, error) {
+func (h *Helm) Template(chart, repository, version string, valuesFilePaths []string, kubeVersion, targetNamespace string, includeCRDs bool) ([]map[string]any, error) {
 	logFile := filepath.Join(h.outputDir, templateLogFileName)
 
-	file, err := os.OpenFile(logFile, outputFileFlags, fileio.NonExecutablePerms)
+	file, err := fileio.OpenRotatingFile(logFile, h.maxLogSize, fileio.NonExecutablePerms)
 	if err != nil {
 		return nil, fmt.Errorf("opening log file: %w", err)
 	}
@@ -224,7 +272,7 @@ func (h *Helm) Template(chart, repository, version, valuesFilePath, kubeVersion,
 	}()
 
 	chartContentsBuffer := new(strings.Builder)
-	cmd := templateCommand(chart, repository, version, valuesFilePath, kubeVersion, targetNamespace, io.MultiWriter(file, chartContentsBuffer), file)
+	cmd := templateCommand(h.context, chart, repository, version, valuesFilePaths, kubeVersion, targetNamespace, includeCRDs, io.MultiWriter(file, chartContentsBuffer), file)
 
 	if _, err = fmt.Fprintf(file, "command: %s\n", cmd); err != nil {
 		return nil, fmt.Errorf("writing command prefix to log file: %w", err)
@@ -243,9 +291,14 @@ func (h *Helm) Template(chart, repository, version, valuesFilePath, kubeVersion,
 	return resources, nil
 }
 
-func templateCommand(chart, repository, version, valuesFilePath, kubeVersion, targetNamespace string, stdout, stderr io.Writer) *exec.Cmd {
+func templateCommand(ctx context.Context, chart, repository, version string, valuesFilePaths []string, kubeVersion, targetNamespace string, includeCRDs bool, stdout, stderr io.Writer) *exec.Cmd {
+	crdsFlag := "--skip-crds"
+	if includeCRDs {
+		crdsFlag = "--include-crds"
+	}
+
 	var args []string
-	args = append(args, "template", "--skip-crds", chart, repository)
+	args = append(args, "template", crdsFlag, chart, repository)
 
 	if targetNamespace != "" {
 		args = append(args, "--namespace", targetNamespace)
@@ -255,13 +308,14 @@ func templateCommand(chart, repository, version, valuesFilePath, kubeVersion, ta
 		args = append(args, "--version", version)
 	}
 
-	if valuesFilePath != "" {
+	for _, valuesFilePath := range valuesFilePaths {
 		args = append(args, "-f", valuesFilePath)
 	}
 
-	args = append(args, "--kube-version", kubeVersion)
+	// Helm expects a semantic version, which the k3s and RKE2 build metadata (e.g. '+k3s1') breaks in some charts
+	args = append(args, "--kube-version", kubernetes.UpstreamVersion(kubeVersion))
 
-	cmd := exec.Command("helm", args...)
+	cmd := exec.CommandContext(ctx, "helm", args...)
 	cmd.Stdout = stdout
 	cmd.Stderr = stderr
 
@@ -2,10 +2,14 @@ package helm
 
 import (
 	"bytes"
+	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/http"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 )
 
@@ -165,7 +169,7 @@ func TestAddRepoCommand(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			cmd := addRepoCommand(test.repo, certsDir, &buf)
+			cmd := addRepoCommand(context.Background(), test.repo, certsDir, &buf)
 
 			assert.Equal(t, test.expectedArgs, cmd.Args)
 			assert.Equal(t, &buf, cmd.Stdout)
@@ -283,7 +287,7 @@ func TestRegistryLoginCommand(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			cmd := registryLoginCommand(test.host, test.repo, certsDir, &buf)
+			cmd := registryLoginCommand(context.Background(), test.host, test.repo, certsDir, &buf)
 
 			assert.Equal(t, test.expectedArgs, cmd.Args)
 			assert.Equal(t, &buf, cmd.Stdout)
@@ -292,6 +296,61 @@ func TestRegistryLoginCommand(t *testing.T) {
 	}
 }
 
+func TestRegistryLogin_Unauthorized(t *testing.T) {
+	binDir := t.TempDir()
+	helmScript := "#!/bin/sh\necho 'Error: login attempt to https://registry.example.com/v2/ failed with status: 401 Unauthorized' >&2\nexit 1\n"
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "helm"), []byte(helmScript), 0o700))
+	t.Setenv("PATH", binDir)
+
+	repo := &image.HelmRepository{
+		Name:           "private-registry",
+		URL:            "oci://registry.example.com/charts",
+		Authentication: image.HelmAuthentication{Username: "user", Password: "wrong"},
+	}
+
+	client, err := http.NewClient(http.ClientConfig{})
+	require.NoError(t, err)
+
+	h := New(context.Background(), client, t.TempDir(), certsDir, 0)
+
+	err = h.RegistryLogin(repo)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrUnauthorized)
+	assert.EqualError(t, err, "authentication failed for repository 'private-registry': unauthorized: exit status 1")
+}
+
+func TestIsAuthenticationFailure(t *testing.T) {
+	tests := map[string]struct {
+		output   string
+		expected bool
+	}{
+		`unauthorized`: {
+			output:   "Error: login attempt to https://registry.example.com/v2/ failed with status: 401 Unauthorized",
+			expected: true,
+		},
+		`forbidden`: {
+			output:   "Error: login attempt to https://registry.example.com/v2/ failed with status: 403 Forbidden",
+			expected: true,
+		},
+		`denied`: {
+			output:   "Error: denied: requested access to the resource is denied",
+			expected: true,
+		},
+		`network failure`: {
+			output: "Error: Get \"https://registry.example.com/v2/\": dial tcp: lookup registry.example.com: no such host",
+		},
+		`certificate failure`: {
+			output: "Error: Get \"https://registry.example.com/v2/\": tls: failed to verify certificate: x509: certificate signed by unknown authority",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, isAuthenticationFailure(test.output))
+		})
+	}
+}
+
 func TestPullCommand(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -487,7 +546,7 @@ func TestPullCommand(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			cmd := pullCommand(test.chart, test.repo, test.version, test.destDir, certsDir, &buf)
+			cmd := pullCommand(context.Background(), test.chart, test.repo, test.version, test.destDir, certsDir, &buf)
 
 			assert.Equal(t, test.expectedArgs, cmd.Args)
 			assert.Equal(t, &buf, cmd.Stdout)
@@ -496,6 +555,28 @@ func TestPullCommand(t *testing.T) {
 	}
 }
 
+func TestHelm_Offline(t *testing.T) {
+	client, err := http.NewClient(http.ClientConfig{Offline: true})
+	require.NoError(t, err)
+
+	h := New(context.Background(), client, t.TempDir(), certsDir, 0)
+	repo := &image.HelmRepository{
+		Name: "suse-edge",
+		URL:  "https://suse-edge.github.io/charts",
+	}
+
+	err = h.AddRepo(repo)
+	require.ErrorIs(t, err, http.ErrOffline)
+	assert.ErrorContains(t, err, "'https://suse-edge.github.io/charts' is not available locally")
+
+	err = h.RegistryLogin(repo)
+	require.ErrorIs(t, err, http.ErrOffline)
+
+	_, err = h.Pull("metallb", repo, "0.14.3", t.TempDir())
+	require.ErrorIs(t, err, http.ErrOffline)
+	assert.ErrorContains(t, err, "'metallb (https://suse-edge.github.io/charts)' is not available locally")
+}
+
 func TestTemplateCommand(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -504,7 +585,8 @@ func TestTemplateCommand(t *testing.T) {
 		version         string
 		kubeVersion     string
 		targetNamespace string
-		valuesPath      string
+		valuesPaths     []string
+		includeCRDs     bool
 		expectedArgs    []string
 	}{
 		{
@@ -514,7 +596,7 @@ func TestTemplateCommand(t *testing.T) {
 			version:         "0.2.1",
 			kubeVersion:     "v1.29.0+rke2r1",
 			targetNamespace: "kubevirt-ns",
-			valuesPath:      "/kubevirt/values.yaml",
+			valuesPaths:     []string{"/kubevirt/values.yaml"},
 			expectedArgs: []string{
 				"helm",
 				"template",
@@ -528,7 +610,43 @@ func TestTemplateCommand(t *testing.T) {
 				"-f",
 				"/kubevirt/values.yaml",
 				"--kube-version",
-				"v1.29.0+rke2r1",
+				"v1.29.0",
+			},
+		},
+		{
+			name:        "Template with multiple values files",
+			repo:        "suse-edge/kubevirt",
+			chart:       "kubevirt",
+			kubeVersion: "v1.30.3+k3s1",
+			valuesPaths: []string{"/kubevirt/base-values.yaml", "/kubevirt/edge-values.yaml"},
+			expectedArgs: []string{
+				"helm",
+				"template",
+				"--skip-crds",
+				"kubevirt",
+				"suse-edge/kubevirt",
+				"-f",
+				"/kubevirt/base-values.yaml",
+				"-f",
+				"/kubevirt/edge-values.yaml",
+				"--kube-version",
+				"v1.30.3",
+			},
+		},
+		{
+			name:        "Template including CRDs",
+			repo:        "suse-edge/kubevirt",
+			chart:       "kubevirt",
+			kubeVersion: "v1.29.0+rke2r1",
+			includeCRDs: true,
+			expectedArgs: []string{
+				"helm",
+				"template",
+				"--include-crds",
+				"kubevirt",
+				"suse-edge/kubevirt",
+				"--kube-version",
+				"v1.29.0",
 			},
 		},
 		{
@@ -543,7 +661,7 @@ func TestTemplateCommand(t *testing.T) {
 				"kubevirt",
 				"suse-edge/kubevirt",
 				"--kube-version",
-				"v1.29.0+rke2r1",
+				"v1.29.0",
 			},
 		},
 	}
@@ -553,7 +671,7 @@ func TestTemplateCommand(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			cmd := templateCommand(test.chart, test.repo, test.version, test.valuesPath, test.kubeVersion, test.targetNamespace, &stdout, &stderr)
+			cmd := templateCommand(context.Background(), test.chart, test.repo, test.version, test.valuesPaths, test.kubeVersion, test.targetNamespace, test.includeCRDs, &stdout, &stderr)
 
 			assert.Equal(t, test.expectedArgs, cmd.Args)
 			assert.Equal(t, &stdout, cmd.Stdout)
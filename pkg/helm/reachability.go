@@ -0,0 +1,179 @@
+package helm
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+const reachabilityTimeout = 30 * time.Second
+
+// ErrUnauthorized is returned when a Helm repository rejects the provided (or missing) credentials.
+var ErrUnauthorized = errors.New("unauthorized")
+
+// CheckRepository verifies that the given Helm repository can be reached with its configured
+// credentials and TLS settings. HTTP(S) repositories are checked by requesting their index
+// while OCI registries are checked through the base endpoint of the distribution API.
+// Repositories without a CA file of their own are verified against the given root CAs,
+// or the system ones if nil.
+func CheckRepository(ctx context.Context, repo *image.HelmRepository, certsDir string, rootCAs *x509.CertPool) error {
+	checkURL, err := repositoryCheckURL(repo)
+	if err != nil {
+		return err
+	}
+
+	client, err := repositoryClient(repo, certsDir, rootCAs)
+	if err != nil {
+		return fmt.Errorf("configuring client: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reachabilityTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, checkURL, http.NoBody)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+
+	if hasCredentials(repo) {
+		req.SetBasicAuth(repo.Authentication.Username, repo.Authentication.Password)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized && strings.HasPrefix(repo.URL, "oci://") {
+		// Registries using token authentication reject any request to the base endpoint
+		// without a token, credentials are validated by the token service instead
+		return checkTokenService(ctx, client, repo, resp.Header.Get("WWW-Authenticate"))
+	}
+
+	return checkStatus(resp.StatusCode)
+}
+
+func checkStatus(statusCode int) error {
+	switch {
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		return fmt.Errorf("%w: status code %d", ErrUnauthorized, statusCode)
+	case statusCode == http.StatusMethodNotAllowed:
+		// The server is reachable but does not support HEAD requests
+		return nil
+	case statusCode >= http.StatusBadRequest:
+		return fmt.Errorf("unexpected status code: %d", statusCode)
+	}
+
+	return nil
+}
+
+func checkTokenService(ctx context.Context, client *http.Client, repo *image.HelmRepository, challenge string) error {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	if !strings.EqualFold(scheme, "bearer") {
+		return checkStatus(http.StatusUnauthorized)
+	}
+
+	if !hasCredentials(repo) {
+		// Anonymous access can only be verified when pulling a particular chart
+		return nil
+	}
+
+	var realm, service string
+	for _, param := range strings.Split(params, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+		switch strings.ToLower(key) {
+		case "realm":
+			realm = strings.Trim(value, `"`)
+		case "service":
+			service = strings.Trim(value, `"`)
+		}
+	}
+
+	if realm == "" {
+		return fmt.Errorf("unsupported authentication challenge: %q", challenge)
+	}
+
+	tokenURL := realm
+	if service != "" {
+		tokenURL = fmt.Sprintf("%s?service=%s", realm, url.QueryEscape(service))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, tokenURL, http.NoBody)
+	if err != nil {
+		return fmt.Errorf("creating token request: %w", err)
+	}
+	req.SetBasicAuth(repo.Authentication.Username, repo.Authentication.Password)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("executing token request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	return checkStatus(resp.StatusCode)
+}
+
+func hasCredentials(repo *image.HelmRepository) bool {
+	return repo.Authentication.Username != "" && repo.Authentication.Password != ""
+}
+
+func repositoryCheckURL(repo *image.HelmRepository) (string, error) {
+	parsedURL, err := url.Parse(repo.URL)
+	if err != nil {
+		return "", fmt.Errorf("parsing repository URL: %w", err)
+	}
+
+	switch parsedURL.Scheme {
+	case "http", "https":
+		return url.JoinPath(repo.URL, "index.yaml")
+	case "oci":
+		scheme := "https"
+		if repo.PlainHTTP {
+			scheme = "http"
+		}
+
+		return fmt.Sprintf("%s://%s/v2/", scheme, parsedURL.Host), nil
+	default:
+		return "", fmt.Errorf("unsupported repository scheme: %s", parsedURL.Scheme)
+	}
+}
+
+func repositoryClient(repo *image.HelmRepository, certsDir string, rootCAs *x509.CertPool) (*http.Client, error) {
+	tlsConfig := &tls.Config{
+		RootCAs:    rootCAs,
+		MinVersion: tls.VersionTLS12,
+	}
+
+	switch {
+	case repo.SkipTLSVerify:
+		tlsConfig.InsecureSkipVerify = true //nolint:gosec // explicitly requested by the user
+	case repo.CAFile != "":
+		data, err := os.ReadFile(filepath.Join(certsDir, repo.CAFile))
+		if err != nil {
+			return nil, fmt.Errorf("reading CA file: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no valid PEM encoded certificates found in '%s'", repo.CAFile)
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	return &http.Client{Transport: transport}, nil
+}
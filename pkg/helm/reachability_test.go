@@ -0,0 +1,146 @@
+package helm
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestCheckRepository_HTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/charts/index.yaml":
+			w.WriteHeader(http.StatusOK)
+		case "/private/index.yaml":
+			if username, password, ok := r.BasicAuth(); !ok || username != "user" || password != "pass" {
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tests := map[string]struct {
+		repo        image.HelmRepository
+		expectedErr string
+	}{
+		"Reachable": {
+			repo: image.HelmRepository{URL: server.URL + "/charts"},
+		},
+		"Missing index": {
+			repo:        image.HelmRepository{URL: server.URL + "/typo"},
+			expectedErr: "unexpected status code: 404",
+		},
+		"Valid credentials": {
+			repo: image.HelmRepository{
+				URL:            server.URL + "/private",
+				Authentication: image.HelmAuthentication{Username: "user", Password: "pass"},
+			},
+		},
+		"Invalid credentials": {
+			repo: image.HelmRepository{
+				URL:            server.URL + "/private",
+				Authentication: image.HelmAuthentication{Username: "user", Password: "wrong"},
+			},
+			expectedErr: "unauthorized: status code 401",
+		},
+		"Unsupported scheme": {
+			repo:        image.HelmRepository{URL: "ftp://charts.example.com"},
+			expectedErr: "unsupported repository scheme: ftp",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := CheckRepository(context.Background(), &test.repo, certsDir, nil)
+			if test.expectedErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.EqualError(t, err, test.expectedErr)
+			}
+		})
+	}
+}
+
+func TestCheckRepository_OCI(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/":
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registry"`, server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+		case "/token":
+			assert.Equal(t, "registry", r.URL.Query().Get("service"))
+
+			if username, password, ok := r.BasicAuth(); !ok || username != "user" || password != "pass" {
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	repoURL := "oci://" + strings.TrimPrefix(server.URL, "http://") + "/charts"
+
+	// Anonymous access
+	err := CheckRepository(context.Background(), &image.HelmRepository{URL: repoURL, PlainHTTP: true}, certsDir, nil)
+	require.NoError(t, err)
+
+	// Valid credentials
+	err = CheckRepository(context.Background(), &image.HelmRepository{
+		URL:            repoURL,
+		PlainHTTP:      true,
+		Authentication: image.HelmAuthentication{Username: "user", Password: "pass"},
+	}, certsDir, nil)
+	require.NoError(t, err)
+
+	// Invalid credentials
+	err = CheckRepository(context.Background(), &image.HelmRepository{
+		URL:            repoURL,
+		PlainHTTP:      true,
+		Authentication: image.HelmAuthentication{Username: "user", Password: "wrong"},
+	}, certsDir, nil)
+	require.ErrorIs(t, err, ErrUnauthorized)
+}
+
+func TestCheckRepository_RootCAs(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	repo := &image.HelmRepository{URL: server.URL}
+
+	err := CheckRepository(context.Background(), repo, t.TempDir(), nil)
+	require.ErrorContains(t, err, "certificate signed by unknown authority")
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(server.Certificate())
+
+	err = CheckRepository(context.Background(), repo, t.TempDir(), rootCAs)
+	require.NoError(t, err)
+}
+
+func TestCheckRepository_Cancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := CheckRepository(ctx, &image.HelmRepository{URL: server.URL}, t.TempDir(), nil)
+	require.ErrorIs(t, err, context.Canceled)
+}
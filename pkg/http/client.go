@@ -0,0 +1,103 @@
+package http
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+)
+
+// DefaultDownloadTimeout is the maximum duration of a single file download.
+const DefaultDownloadTimeout = time.Hour
+
+// ClientConfig holds the network settings of a build.
+type ClientConfig struct {
+	// CABundlePath optionally points to a PEM encoded bundle of certificate authorities
+	// which are trusted in addition to the system ones.
+	CABundlePath string
+	// DownloadTimeout is the maximum duration of a single file download.
+	// A non-positive value falls back to DefaultDownloadTimeout.
+	DownloadTimeout time.Duration
+	// Offline makes all network dependent operations fail immediately instead of attempting to reach the network.
+	Offline bool
+}
+
+// Client performs the network operations of a build. It is created once from the build settings
+// and its configuration is not modified afterwards, so it is safe for concurrent use.
+// A nil client behaves as an online client with the default settings.
+type Client struct {
+	client *http.Client
+	// rootCAs holds the system certificate authorities along with the ones from the CA bundle,
+	// nil if no CA bundle is configured
+	rootCAs         *x509.CertPool
+	downloadTimeout time.Duration
+	offline         bool
+}
+
+// NewClient creates a client configured with the given settings.
+func NewClient(config ClientConfig) (*Client, error) {
+	client := http.DefaultClient
+	var rootCAs *x509.CertPool
+	if config.CABundlePath != "" {
+		var err error
+		if rootCAs, err = loadCABundle(config.CABundlePath); err != nil {
+			return nil, err
+		}
+
+		client = newTLSClient(rootCAs)
+	}
+
+	downloadTimeout := config.DownloadTimeout
+	if downloadTimeout <= 0 {
+		downloadTimeout = DefaultDownloadTimeout
+	}
+
+	return &Client{
+		client:          client,
+		rootCAs:         rootCAs,
+		downloadTimeout: downloadTimeout,
+		offline:         config.Offline,
+	}, nil
+}
+
+// RootCAs returns the certificate authorities trusted by the client, nil if only the system ones are trusted.
+func (c *Client) RootCAs() *x509.CertPool {
+	if c == nil {
+		return nil
+	}
+
+	return c.rootCAs
+}
+
+// loadCABundle returns a pool of the system certificate authorities extended
+// with the ones from the PEM encoded bundle at the given path.
+func loadCABundle(caBundlePath string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(caBundlePath)
+	if err != nil {
+		return nil, fmt.Errorf("reading CA bundle: %w", err)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no valid PEM encoded certificates found in '%s'", caBundlePath)
+	}
+
+	return pool, nil
+}
+
+// newTLSClient returns an HTTP client which trusts the given certificate authorities.
+func newTLSClient(rootCAs *x509.CertPool) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{
+		RootCAs:    rootCAs,
+		MinVersion: tls.VersionTLS12,
+	}
+
+	return &http.Client{Transport: transport}
+}
@@ -0,0 +1,104 @@
+package http
+
+import (
+	"context"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeCABundle(t *testing.T, dir string, server *httptest.Server) string {
+	block := &pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}
+
+	path := filepath.Join(dir, "ca.pem")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(block), 0o600))
+
+	return path
+}
+
+func TestNewClient(t *testing.T) {
+	// Setup
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, "trusted")
+	}))
+	defer server.Close()
+
+	caBundle := writeCABundle(t, t.TempDir(), server)
+
+	// Test
+	c, err := NewClient(ClientConfig{CABundlePath: caBundle})
+	require.NoError(t, err)
+
+	resp, err := c.client.Get(server.URL)
+
+	// Verify
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.NotNil(t, c.RootCAs())
+
+	_, err = http.DefaultClient.Get(server.URL)
+	require.ErrorContains(t, err, "certificate signed by unknown authority")
+}
+
+func TestNewClient_InvalidBundle(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := NewClient(ClientConfig{CABundlePath: filepath.Join(dir, "missing.pem")})
+	require.ErrorContains(t, err, "reading CA bundle")
+
+	invalidBundle := filepath.Join(dir, "invalid.pem")
+	require.NoError(t, os.WriteFile(invalidBundle, []byte("not a certificate"), 0o600))
+
+	_, err = NewClient(ClientConfig{CABundlePath: invalidBundle})
+	require.EqualError(t, err, fmt.Sprintf("no valid PEM encoded certificates found in '%s'", invalidBundle))
+}
+
+func TestNewClient_DefaultDownloadTimeout(t *testing.T) {
+	c, err := NewClient(ClientConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, DefaultDownloadTimeout, c.downloadTimeout)
+	assert.Nil(t, c.RootCAs())
+
+	c, err = NewClient(ClientConfig{DownloadTimeout: time.Minute})
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, c.downloadTimeout)
+}
+
+func TestDownloadFile_CustomCABundle(t *testing.T) {
+	// Setup
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, "manifest")
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	caBundle := writeCABundle(t, dir, server)
+
+	defaultClient, err := NewClient(ClientConfig{})
+	require.NoError(t, err)
+
+	path := filepath.Join(dir, "manifest.yaml")
+	require.ErrorContains(t, defaultClient.DownloadFile(context.Background(), server.URL, path, nil, nil), "certificate signed by unknown authority")
+
+	c, err := NewClient(ClientConfig{CABundlePath: caBundle})
+	require.NoError(t, err)
+
+	// Test
+	err = c.DownloadFile(context.Background(), server.URL, path, nil, nil)
+
+	// Verify
+	require.NoError(t, err)
+
+	contents, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "manifest", string(contents))
+}
@@ -14,12 +14,30 @@ import (
 )
 
 // DownloadFile downloads a file from the specified URL and stores it to the given path.
+// Downloads which do not complete within the configured timeout are aborted and
+// any partially written file is removed, as are downloads whose context is cancelled.
+// Fails immediately if offline mode is enabled.
 //
-// Optionally provide an additional cache writer in cases where the pending download
-// must be stored to other locations alongside the given path.
-func DownloadFile(ctx context.Context, url, path string, cache io.Writer) error {
+// Optionally provide additional request headers (e.g. authorization) and a cache writer
+// in cases where the pending download must be stored to other locations alongside the given path.
+func (c *Client) DownloadFile(ctx context.Context, url, path string, headers map[string]string, cache io.Writer) error {
+	if err := c.CheckOnline(url); err != nil {
+		return err
+	}
+
+	client, downloadTimeout := http.DefaultClient, DefaultDownloadTimeout
+	if c != nil {
+		client, downloadTimeout = c.client, c.downloadTimeout
+	}
+
 	filename := filepath.Base(path)
 
+	if ctx != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, downloadTimeout)
+		defer cancel()
+	}
+
 	zap.S().Infof("Downloading file '%s' from '%s' to '%s'...", filename, url, filepath.Dir(path))
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
@@ -27,7 +45,11 @@ func DownloadFile(ctx context.Context, url, path string, cache io.Writer) error
 		return fmt.Errorf("creating request: %w", err)
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("executing request: %w", err)
 	}
@@ -43,6 +65,20 @@ func DownloadFile(ctx context.Context, url, path string, cache io.Writer) error
 	}
 	defer file.Close()
 
+	if err = storeResponse(resp, file, filename, cache); err != nil {
+		if rmErr := os.Remove(path); rmErr != nil {
+			zap.S().Warnf("Removing partially downloaded file '%s' failed: %v", path, rmErr)
+		}
+
+		return err
+	}
+
+	zap.S().Infof("Downloading file '%s' completed", filename)
+
+	return nil
+}
+
+func storeResponse(resp *http.Response, file *os.File, filename string, cache io.Writer) error {
 	var writers []io.Writer
 	writers = append(writers, file)
 
@@ -61,11 +97,9 @@ func DownloadFile(ctx context.Context, url, path string, cache io.Writer) error
 		writers = append(writers, bar)
 	}
 
-	if _, err = io.Copy(io.MultiWriter(writers...), resp.Body); err != nil {
+	if _, err := io.Copy(io.MultiWriter(writers...), resp.Body); err != nil {
 		return fmt.Errorf("storing response: %w", err)
 	}
 
-	zap.S().Infof("Downloading file '%s' completed", filename)
-
 	return nil
 }
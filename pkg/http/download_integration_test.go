@@ -16,7 +16,10 @@ func TestDownloadFile_Successful_NoCache(t *testing.T) {
 	url := "https://raw.githubusercontent.com/suse-edge/edge-image-builder/main/README.md"
 	path := "README.md"
 
-	require.NoError(t, DownloadFile(context.Background(), url, path, nil))
+	client, err := NewClient(ClientConfig{})
+	require.NoError(t, err)
+
+	require.NoError(t, client.DownloadFile(context.Background(), url, path, nil, nil))
 	defer func() {
 		assert.NoError(t, os.Remove(path))
 	}()
@@ -30,7 +33,10 @@ func TestDownloadFile_Successful_Cache(t *testing.T) {
 
 	var sb strings.Builder
 
-	require.NoError(t, DownloadFile(context.Background(), url, path, &sb))
+	client, err := NewClient(ClientConfig{})
+	require.NoError(t, err)
+
+	require.NoError(t, client.DownloadFile(context.Background(), url, path, nil, &sb))
 	defer func() {
 		assert.NoError(t, os.Remove(path))
 	}()
@@ -2,7 +2,12 @@ package http
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -41,11 +46,91 @@ func TestDownloadFile(t *testing.T) {
 		},
 	}
 
+	client, err := NewClient(ClientConfig{})
+	require.NoError(t, err)
+
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			err := DownloadFile(test.ctx, test.url, test.path, nil)
+			err := client.DownloadFile(test.ctx, test.url, test.path, nil, nil)
 			require.Error(t, err)
 			assert.EqualError(t, err, test.expectedErr)
 		})
 	}
 }
+
+func TestDownloadFile_Timeout(t *testing.T) {
+	// Setup
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "1024")
+		fmt.Fprint(w, "partial")
+		w.(http.Flusher).Flush()
+
+		// Stall until the client gives up
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{DownloadTimeout: 100 * time.Millisecond})
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "stalled")
+
+	// Test
+	err = client.DownloadFile(context.Background(), server.URL, path, nil, nil)
+
+	// Verify
+	require.ErrorContains(t, err, "storing response")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.NoFileExists(t, path)
+}
+
+func TestDownloadFile_Cancelled(t *testing.T) {
+	// Setup
+	downloadCtx, cancel := context.WithCancel(context.Background())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "1024")
+		fmt.Fprint(w, "partial")
+		w.(http.Flusher).Flush()
+
+		// Interrupt the download midway and stall until the client gives up
+		cancel()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientConfig{})
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "interrupted")
+
+	// Test
+	err = client.DownloadFile(downloadCtx, server.URL, path, nil, nil)
+
+	// Verify
+	require.ErrorIs(t, err, context.Canceled)
+	assert.NoFileExists(t, path)
+}
+
+func TestDownloadFile_Offline(t *testing.T) {
+	// Setup
+	var requested bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = true
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "file")
+
+	client, err := NewClient(ClientConfig{Offline: true})
+	require.NoError(t, err)
+
+	// Test
+	err = client.DownloadFile(context.Background(), server.URL, path, nil, nil)
+
+	// Verify
+	require.ErrorIs(t, err, ErrOffline)
+	assert.ErrorContains(t, err, server.URL)
+	assert.False(t, requested)
+	assert.NoFileExists(t, path)
+}
@@ -0,0 +1,24 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrOffline is returned when a resource must be retrieved over the network while offline mode is enabled.
+var ErrOffline = errors.New("network access is disabled in offline mode")
+
+// Offline reports whether offline mode is enabled.
+func (c *Client) Offline() bool {
+	return c != nil && c.offline
+}
+
+// CheckOnline returns an error naming the given resource if offline mode is enabled.
+// It is to be called before any operation which retrieves the resource over the network.
+func (c *Client) CheckOnline(resource string) error {
+	if c.Offline() {
+		return fmt.Errorf("%w: '%s' is not available locally", ErrOffline, resource)
+	}
+
+	return nil
+}
@@ -0,0 +1,30 @@
+package http
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckOnline(t *testing.T) {
+	online, err := NewClient(ClientConfig{})
+	require.NoError(t, err)
+	assert.False(t, online.Offline())
+	require.NoError(t, online.CheckOnline("https://example.com/file"))
+
+	offline, err := NewClient(ClientConfig{Offline: true})
+	require.NoError(t, err)
+	assert.True(t, offline.Offline())
+
+	err = offline.CheckOnline("https://example.com/file")
+	require.ErrorIs(t, err, ErrOffline)
+	assert.EqualError(t, err, "network access is disabled in offline mode: 'https://example.com/file' is not available locally")
+}
+
+func TestCheckOnline_NilClient(t *testing.T) {
+	var c *Client
+
+	assert.False(t, c.Offline())
+	require.NoError(t, c.CheckOnline("https://example.com/file"))
+}
@@ -1,10 +1,16 @@
 package image
 
+import (
+	"time"
+
+	"github.com/suse-edge/edge-image-builder/pkg/http"
+)
+
 type HelmClient interface {
 	AddRepo(repository *HelmRepository) error
 	RegistryLogin(repository *HelmRepository) error
 	Pull(chart string, repository *HelmRepository, version, destDir string) (string, error)
-	Template(chart, repository, version, valuesFilePath, kubeVersion, targetNamespace string) ([]map[string]any, error)
+	Template(chart, repository, version string, valuesFilePaths []string, kubeVersion, targetNamespace string, includeCRDs bool) ([]map[string]any, error)
 }
 
 type LocalRPMConfig struct {
@@ -23,6 +29,70 @@ type Context struct {
 	CombustionDir string
 	// ArtefactsDir is a subdirectory under BuildDir containing the larger Combustion related files.
 	ArtefactsDir string
+	// OutputDir is the directory the built image is written to.
+	// Defaults to ImageConfigDir if unset.
+	OutputDir string
+	// ScratchDir is the directory guestfish and virt-resize store their temporary files and appliance cache in.
+	// Defaults to BuildDir if unset.
+	ScratchDir string
 	// ImageDefinition contains the image definition properties.
 	ImageDefinition *Definition
+	// CommandLogMaxSize is the size (in bytes) after which the log files of external commands are rotated.
+	// A zero value falls back to the default limit.
+	CommandLogMaxSize int64
+	// ParallelDownloads is the maximum number of concurrent artefact downloads.
+	// Downloads are performed sequentially if unset.
+	ParallelDownloads int
+	// HTTPClient performs all downloads of the build according to its network settings
+	// (e.g. the download timeout and offline mode). In offline mode all artefacts must be
+	// available locally (e.g. in the cache) and the build fails instead of accessing the network.
+	HTTPClient *http.Client
+	// Preflight enables online checks (e.g. the reachability of Helm repositories) during validation.
+	Preflight bool
+	// RenderHelmCharts enables templating the Helm charts during validation to report rendering failures early.
+	RenderHelmCharts bool
+	// ImageModificationLimit limits the number of builds on the host concurrently modifying
+	// images with guestfish and virt-resize. A non-positive value disables the limit.
+	ImageModificationLimit int
+	// ImageModificationLockDir holds the lock files enforcing ImageModificationLimit. It must be shared
+	// by all builds on the host (e.g. a host mounted path when running in containers).
+	// Defaults to a directory under the system temporary directory if unset.
+	ImageModificationLockDir string
+	// HaulerBinaryPath is the hauler binary used to populate the embedded artifact registry and copied into the image.
+	// Defaults to the binary installed in the EIB container if unset.
+	HaulerBinaryPath string
+	// SkipComponents contains the identifiers of the combustion components (e.g. "rpm") which are not configured.
+	SkipComponents []string
+	// ReuseOutputImage indicates that an existing RAW output image built from the same base image
+	// is customized again instead of being rebuilt from the base image.
+	ReuseOutputImage bool
+	// GenerateSBOM indicates that a software bill of materials is written alongside the built image.
+	GenerateSBOM bool
+	// VerifyBoot indicates that the built image is booted in a headless virtual machine to check that it is functional.
+	// A failed verification is reported but does not fail the build.
+	VerifyBoot bool
+	// BootVerificationTimeout is the maximum duration to wait for the built image to boot when VerifyBoot is set.
+	// A zero value falls back to the default timeout.
+	BootVerificationTimeout time.Duration
+	// BuildInfo aggregates the inputs resolved throughout the build which are recorded alongside the built image.
+	BuildInfo BuildInfo
+}
+
+type BuildInfo struct {
+	// DefinitionChecksum is the SHA-256 checksum of the image definition file.
+	DefinitionChecksum string
+	// HelmCharts contains the Helm charts which are installed on the image.
+	HelmCharts []HelmChartInfo
+	// EmbeddedImages contains the container images stored in the embedded artifact registry.
+	EmbeddedImages []string
+	// RPMs contains the file names of the resolved RPM packages installed on the image.
+	RPMs []string
+	// ValidationWarnings contains the user messages of the warnings reported by the image definition validation.
+	ValidationWarnings []string
+}
+
+type HelmChartInfo struct {
+	Name          string
+	Version       string
+	RepositoryURL string
 }
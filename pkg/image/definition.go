@@ -3,6 +3,8 @@ package image
 import (
 	"bytes"
 	"fmt"
+	"net/netip"
+	"os"
 	"regexp"
 	"strconv"
 	"strings"
@@ -27,6 +29,12 @@ const (
 	CNITypeCilium = "cilium"
 	CNITypeCanal  = "canal"
 	CNITypeCalico = "calico"
+
+	NetworkInterfaceTypeEthernet = "ethernet"
+	NetworkInterfaceTypeBond     = "bond"
+
+	// InstallDeviceAuto installs the operating system on the first disk found at install time.
+	InstallDeviceAuto = "auto"
 )
 
 var (
@@ -39,27 +47,55 @@ type Definition struct {
 	OperatingSystem          OperatingSystem          `yaml:"operatingSystem"`
 	EmbeddedArtifactRegistry EmbeddedArtifactRegistry `yaml:"embeddedArtifactRegistry"`
 	Kubernetes               Kubernetes               `yaml:"kubernetes"`
+	Requirements             Requirements             `yaml:"requirements"`
 }
 
 type Arch string
 
+// Short returns the short name of the architecture (e.g. 'amd64') and panics on unknown architectures.
+// It is meant for architectures which have already been validated, otherwise ShortName should be used.
 func (a Arch) Short() string {
+	short, err := a.ShortName()
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return short
+}
+
+// ShortName returns the short name of the architecture (e.g. 'amd64').
+func (a Arch) ShortName() (string, error) {
 	switch a {
 	case ArchTypeX86:
-		return "amd64"
+		return "amd64", nil
 	case ArchTypeARM:
-		return "arm64"
+		return "arm64", nil
 	default:
-		message := fmt.Sprintf("unknown arch: %s", a)
-		panic(message)
+		return "", fmt.Errorf("unknown arch: %s", a)
 	}
 }
 
 type Image struct {
-	ImageType       string `yaml:"imageType"`
-	Arch            Arch   `yaml:"arch"`
-	BaseImage       string `yaml:"baseImage"`
-	OutputImageName string `yaml:"outputImageName"`
+	ImageType         string `yaml:"imageType"`
+	Arch              Arch   `yaml:"arch"`
+	BaseImage         string `yaml:"baseImage"`
+	OutputImageName   string `yaml:"outputImageName"`
+	OutputPermissions string `yaml:"outputPermissions"`
+}
+
+// OutputFileMode parses the octal 'outputPermissions' field (e.g. '0644').
+// A zero mode is returned if the field is not set.
+func (i Image) OutputFileMode() (os.FileMode, error) {
+	if i.OutputPermissions == "" {
+		return 0, nil
+	}
+
+	mode, err := strconv.ParseUint(i.OutputPermissions, 8, 32)
+	if err != nil {
+		return 0, fmt.Errorf("parsing output permissions: %w", err)
+	}
+
+	return os.FileMode(mode), nil
 }
 
 type OperatingSystem struct {
@@ -74,10 +110,18 @@ type OperatingSystem struct {
 	Time             Time                   `yaml:"time"`
 	Proxy            Proxy                  `yaml:"proxy"`
 	Keymap           string                 `yaml:"keymap"`
+	Locale           string                 `yaml:"locale"`
+	HostEntries      []HostEntry            `yaml:"hostEntries"`
+	Sysctl           map[string]string      `yaml:"sysctl"`
+	Hostname         string                 `yaml:"hostname"`
+	Elemental        Elemental              `yaml:"elemental"`
+	Network          OperatingSystemNetwork `yaml:"network"`
 }
 
 type IsoConfiguration struct {
-	InstallDevice string `yaml:"installDevice"`
+	InstallDevice      string   `yaml:"installDevice"`
+	RebootAfterInstall bool     `yaml:"rebootAfterInstall"`
+	MaxSize            DiskSize `yaml:"maxSize"`
 }
 
 type DiskSize string
@@ -115,6 +159,12 @@ func (d DiskSize) ToMB() int64 {
 	}
 }
 
+// Requirements describes the minimum resources of the machines the image is deployed on.
+type Requirements struct {
+	MinMemory DiskSize `yaml:"minMemory"`
+	MinDisk   DiskSize `yaml:"minDisk"`
+}
+
 type RawConfiguration struct {
 	DiskSize DiskSize `yaml:"diskSize"`
 }
@@ -124,6 +174,8 @@ type Packages struct {
 	PKGList         []string  `yaml:"packageList"`
 	AdditionalRepos []AddRepo `yaml:"additionalRepos"`
 	RegCode         string    `yaml:"sccRegistrationCode"`
+	RegCodeFile     string    `yaml:"sccRegistrationCodeFile"`
+	InstallOptions  []string  `yaml:"installOptions"`
 }
 
 type AddRepo struct {
@@ -132,13 +184,14 @@ type AddRepo struct {
 }
 
 type OperatingSystemUser struct {
-	Username          string   `yaml:"username"`
-	UID               int      `yaml:"uid"`
-	EncryptedPassword string   `yaml:"encryptedPassword"`
-	SSHKeys           []string `yaml:"sshKeys"`
-	PrimaryGroup      string   `yaml:"primaryGroup"`
-	SecondaryGroups   []string `yaml:"secondaryGroups"`
-	CreateHomeDir     bool     `yaml:"createHomeDir"`
+	Username            string   `yaml:"username"`
+	UID                 int      `yaml:"uid"`
+	EncryptedPassword   string   `yaml:"encryptedPassword"`
+	SSHKeys             []string `yaml:"sshKeys"`
+	PrimaryGroup        string   `yaml:"primaryGroup"`
+	SecondaryGroups     []string `yaml:"secondaryGroups"`
+	CreateHomeDir       bool     `yaml:"createHomeDir"`
+	DisablePasswordAuth bool     `yaml:"disablePasswordAuth"`
 }
 
 type OperatingSystemGroup struct {
@@ -154,6 +207,97 @@ type Systemd struct {
 type Suma struct {
 	Host          string `yaml:"host"`
 	ActivationKey string `yaml:"activationKey"`
+	Proxy         string `yaml:"proxy"`
+	CACert        string `yaml:"caCert"`
+}
+
+type Elemental struct {
+	RegistrationURL string                  `yaml:"registrationURL"`
+	CACert          string                  `yaml:"caCert"`
+	EmulateTPM      bool                    `yaml:"emulateTPM"`
+	AuthType        string                  `yaml:"authType"`
+	Registrations   []ElementalRegistration `yaml:"registrations"`
+}
+
+// ElementalRegistration is a named registration applied to the nodes with the given hostnames.
+// A registration without hostnames is applied to all nodes not matching any other registration.
+type ElementalRegistration struct {
+	Name            string   `yaml:"name"`
+	Hostnames       []string `yaml:"hostnames"`
+	RegistrationURL string   `yaml:"registrationURL"`
+	CACert          string   `yaml:"caCert"`
+	EmulateTPM      bool     `yaml:"emulateTPM"`
+	AuthType        string   `yaml:"authType"`
+}
+
+// IsConfigured reports whether the Elemental registration is configured in the definition,
+// either through the single registration shorthand or the list of registrations.
+func (e *Elemental) IsConfigured() bool {
+	return e.RegistrationURL != "" || e.CACert != "" || e.EmulateTPM || e.AuthType != "" || len(e.Registrations) != 0
+}
+
+// SingleRegistration returns the registration specified through the shorthand fields.
+func (e *Elemental) SingleRegistration() ElementalRegistration {
+	return ElementalRegistration{
+		RegistrationURL: e.RegistrationURL,
+		CACert:          e.CACert,
+		EmulateTPM:      e.EmulateTPM,
+		AuthType:        e.AuthType,
+	}
+}
+
+// OperatingSystemNetwork describes the network configuration of each node,
+// which is converted to NMState files and fed to the network config generator.
+type OperatingSystemNetwork struct {
+	Hosts []NetworkHost `yaml:"hosts"`
+}
+
+type NetworkHost struct {
+	Hostname   string             `yaml:"hostname"`
+	Interfaces []NetworkInterface `yaml:"interfaces"`
+	Gateway    string             `yaml:"gateway"`
+	DNSServers []string           `yaml:"dnsServers"`
+	DNSSearch  []string           `yaml:"dnsSearch"`
+}
+
+// GatewayInterface returns the name of the interface with a static address
+// whose subnet contains the gateway or an empty string if there is none.
+func (h *NetworkHost) GatewayInterface() string {
+	gateway, err := netip.ParseAddr(h.Gateway)
+	if err != nil {
+		return ""
+	}
+
+	for _, iface := range h.Interfaces {
+		for _, address := range iface.Addresses {
+			prefix, prefixErr := netip.ParsePrefix(address)
+			if prefixErr == nil && prefix.Contains(gateway) {
+				return iface.Name
+			}
+		}
+	}
+
+	return ""
+}
+
+// NetworkInterface is an ethernet or bond interface. Interfaces without
+// static addresses (in CIDR notation) are configured through DHCP.
+type NetworkInterface struct {
+	Name       string   `yaml:"name"`
+	Type       string   `yaml:"type"`
+	MACAddress string   `yaml:"macAddress"`
+	Addresses  []string `yaml:"addresses"`
+	BondMode   string   `yaml:"bondMode"`
+	Ports      []string `yaml:"ports"`
+}
+
+// InterfaceType returns the type of the interface, defaulting to ethernet.
+func (i *NetworkInterface) InterfaceType() string {
+	if i.Type == "" {
+		return NetworkInterfaceTypeEthernet
+	}
+
+	return i.Type
 }
 
 type Time struct {
@@ -173,25 +317,58 @@ type Proxy struct {
 	NoProxy    []string `yaml:"noProxy"`
 }
 
+type HostEntry struct {
+	IP        string   `yaml:"ip"`
+	Hostnames []string `yaml:"hostnames"`
+}
+
 type EmbeddedArtifactRegistry struct {
 	ContainerImages []ContainerImage `yaml:"images"`
+	SizeBudget      DiskSize         `yaml:"sizeBudget"`
+	Registries      []Registry       `yaml:"registries"`
 }
 
 type ContainerImage struct {
 	Name string `yaml:"name"`
 }
 
+// Registry describes the credentials Kubernetes uses to authenticate against an upstream registry
+// when falling back from the embedded artifact registry.
+// Secrets are never stored in the definition but are instead read from environment variables.
+type Registry struct {
+	URI         string `yaml:"uri"`
+	Username    string `yaml:"username"`
+	PasswordEnv string `yaml:"passwordEnv"`
+}
+
 type Kubernetes struct {
-	Version   string    `yaml:"version"`
-	Network   Network   `yaml:"network"`
-	Nodes     []Node    `yaml:"nodes"`
-	Manifests Manifests `yaml:"manifests"`
-	Helm      Helm      `yaml:"helm"`
+	Version             string    `yaml:"version"`
+	ReleaseURL          string    `yaml:"releaseURL"`
+	SELinuxSigningKey   string    `yaml:"selinuxSigningKey"`
+	SkipSELinuxPackages bool      `yaml:"skipSELinuxPackages"`
+	Network             Network   `yaml:"network"`
+	Nodes               []Node    `yaml:"nodes"`
+	Manifests           Manifests `yaml:"manifests"`
+	Helm                Helm      `yaml:"helm"`
 }
 
 type Network struct {
-	APIHost string `yaml:"apiHost"`
-	APIVIP  string `yaml:"apiVIP"`
+	APIHost        string   `yaml:"apiHost"`
+	APIVIP         string   `yaml:"apiVIP"`
+	AdditionalSANs []string `yaml:"additionalSANs"`
+	ManageVIP      *bool    `yaml:"manageVIP"`
+	// LoadBalancerPool lists the CIDRs or IP address ranges (e.g. '192.168.122.100-192.168.122.150')
+	// MetalLB may allocate to LoadBalancer services. Must include the apiVIP.
+	LoadBalancerPool []string `yaml:"loadBalancerPool"`
+	// MetalLBVersion and EndpointCopierVersion override the versions of the charts deployed to serve the apiVIP.
+	MetalLBVersion        string `yaml:"metalLBVersion"`
+	EndpointCopierVersion string `yaml:"endpointCopierVersion"`
+}
+
+// IsVIPManaged reports whether EIB should deploy the components (e.g. MetalLB) serving the apiVIP.
+// Defaults to true unless explicitly disabled.
+func (n *Network) IsVIPManaged() bool {
+	return n.ManageVIP == nil || *n.ManageVIP
 }
 
 type Node struct {
@@ -201,7 +378,18 @@ type Node struct {
 }
 
 type Manifests struct {
-	URLs []string `yaml:"urls"`
+	URLs        []string              `yaml:"urls"`
+	Credentials []ManifestCredentials `yaml:"credentials"`
+}
+
+// ManifestCredentials describe how to authenticate against the servers hosting manifest URLs.
+// Secrets are never stored in the definition but are instead read from environment variables.
+type ManifestCredentials struct {
+	URLPrefix   string            `yaml:"urlPrefix"`
+	Headers     map[string]string `yaml:"headers"`
+	TokenEnv    string            `yaml:"tokenEnv"`
+	Username    string            `yaml:"username"`
+	PasswordEnv string            `yaml:"passwordEnv"`
 }
 
 type Helm struct {
@@ -210,13 +398,25 @@ type Helm struct {
 }
 
 type HelmChart struct {
-	Name                  string `yaml:"name"`
-	RepositoryName        string `yaml:"repositoryName"`
-	Version               string `yaml:"version"`
-	TargetNamespace       string `yaml:"targetNamespace"`
-	CreateNamespace       bool   `yaml:"createNamespace"`
-	InstallationNamespace string `yaml:"installationNamespace"`
-	ValuesFile            string `yaml:"valuesFile"`
+	Name                  string   `yaml:"name"`
+	RepositoryName        string   `yaml:"repositoryName"`
+	Version               string   `yaml:"version"`
+	TargetNamespace       string   `yaml:"targetNamespace"`
+	CreateNamespace       bool     `yaml:"createNamespace"`
+	InstallationNamespace string   `yaml:"installationNamespace"`
+	ValuesFile            string   `yaml:"valuesFile"`
+	ValuesFiles           []string `yaml:"valuesFiles"`
+	IncludeCRDs           bool     `yaml:"includeCRDs"`
+}
+
+// AllValuesFiles returns the values files of the chart in the order they should be applied,
+// regardless of whether they are specified through 'valuesFile' or 'valuesFiles'.
+func (c *HelmChart) AllValuesFiles() []string {
+	if c.ValuesFile != "" {
+		return append([]string{c.ValuesFile}, c.ValuesFiles...)
+	}
+
+	return c.ValuesFiles
 }
 
 type HelmRepository struct {
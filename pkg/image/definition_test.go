@@ -28,6 +28,11 @@ func TestParse(t *testing.T) {
 	// - Image
 	assert.Equal(t, "slemicro5.5.iso", definition.Image.BaseImage)
 	assert.Equal(t, "eibimage.iso", definition.Image.OutputImageName)
+	assert.Equal(t, "0644", definition.Image.OutputPermissions)
+
+	// - Requirements
+	assert.Equal(t, DiskSize("8G"), definition.Requirements.MinMemory)
+	assert.Equal(t, DiskSize("64G"), definition.Requirements.MinDisk)
 
 	// - Operating System -> Kernel Arguments
 	expectedKernelArgs := []string{
@@ -101,6 +106,7 @@ func TestParse(t *testing.T) {
 		"libbpf0",
 	}
 	assert.Equal(t, expectedPKGList, pkgConfig.PKGList)
+	assert.Equal(t, []string{"--no-recommends"}, pkgConfig.InstallOptions)
 	expectedAddRepos := []AddRepo{
 		{
 			URL: "https://download.nvidia.com/suse/sle15sp5/",
@@ -116,6 +122,7 @@ func TestParse(t *testing.T) {
 	// Operating System -> IsoConfiguration
 	installDevice := definition.OperatingSystem.IsoConfiguration.InstallDevice
 	assert.Equal(t, "/dev/sda", installDevice)
+	assert.True(t, definition.OperatingSystem.IsoConfiguration.RebootAfterInstall)
 
 	// Operating System -> Time
 	time := definition.OperatingSystem.Time
@@ -146,10 +153,44 @@ func TestParse(t *testing.T) {
 	keymap := definition.OperatingSystem.Keymap
 	assert.Equal(t, "us", keymap)
 
+	// Operating System -> Locale
+	locale := definition.OperatingSystem.Locale
+	assert.Equal(t, "en_US.UTF-8", locale)
+
+	// Operating System -> HostEntries
+	hostEntries := definition.OperatingSystem.HostEntries
+	require.Len(t, hostEntries, 1)
+	assert.Equal(t, "192.168.122.100", hostEntries[0].IP)
+	assert.Equal(t, []string{"api.cluster01.hosted.on.edge.suse.com", "node1.suse.com"}, hostEntries[0].Hostnames)
+
+	// Operating System -> Network
+	networkHosts := definition.OperatingSystem.Network.Hosts
+	require.Len(t, networkHosts, 1)
+	assert.Equal(t, "node1.suse.com", networkHosts[0].Hostname)
+	assert.Equal(t, "192.168.122.1", networkHosts[0].Gateway)
+	assert.Equal(t, []string{"192.168.122.1"}, networkHosts[0].DNSServers)
+	assert.Equal(t, []string{"suse.com"}, networkHosts[0].DNSSearch)
+	require.Len(t, networkHosts[0].Interfaces, 4)
+	assert.Equal(t, "eth0", networkHosts[0].Interfaces[0].Name)
+	assert.Equal(t, NetworkInterfaceTypeEthernet, networkHosts[0].Interfaces[0].InterfaceType())
+	assert.Equal(t, "34:8A:B1:4B:16:E1", networkHosts[0].Interfaces[0].MACAddress)
+	assert.Equal(t, []string{"192.168.122.50/24"}, networkHosts[0].Interfaces[0].Addresses)
+	assert.Equal(t, NetworkInterfaceTypeBond, networkHosts[0].Interfaces[1].InterfaceType())
+	assert.Equal(t, "active-backup", networkHosts[0].Interfaces[1].BondMode)
+	assert.Equal(t, []string{"eth1", "eth2"}, networkHosts[0].Interfaces[1].Ports)
+
 	// EmbeddedArtifactRegistry
 	embeddedArtifactRegistry := definition.EmbeddedArtifactRegistry
 	assert.Equal(t, "hello-world:latest", embeddedArtifactRegistry.ContainerImages[0].Name)
 	assert.Equal(t, "ghcr.io/fluxcd/flux-cli@sha256:02aa820c3a9c57d67208afcfc4bce9661658c17d15940aea369da259d2b976dd", embeddedArtifactRegistry.ContainerImages[1].Name)
+	expectedRegistries := []Registry{
+		{
+			URI:         "private.example.com",
+			Username:    "eib",
+			PasswordEnv: "PRIVATE_REGISTRY_PASSWORD",
+		},
+	}
+	assert.Equal(t, expectedRegistries, embeddedArtifactRegistry.Registries)
 
 	// Kubernetes
 	kubernetes := definition.Kubernetes
@@ -160,6 +201,7 @@ func TestParse(t *testing.T) {
 	// Network
 	assert.Equal(t, "192.168.122.100", kubernetes.Network.APIVIP)
 	assert.Equal(t, "api.cluster01.hosted.on.edge.suse.com", kubernetes.Network.APIHost)
+	assert.Equal(t, []string{"rancher.cluster01.hosted.on.edge.suse.com", "192.168.122.101"}, kubernetes.Network.AdditionalSANs)
 
 	// Nodes
 	require.Len(t, kubernetes.Nodes, 5)
@@ -248,6 +290,20 @@ func TestArch_Short(t *testing.T) {
 	})
 }
 
+func TestArch_ShortName(t *testing.T) {
+	short, err := ArchTypeX86.ShortName()
+	require.NoError(t, err)
+	assert.Equal(t, "amd64", short)
+
+	short, err = ArchTypeARM.ShortName()
+	require.NoError(t, err)
+	assert.Equal(t, "arm64", short)
+
+	short, err = Arch("abc").ShortName()
+	require.EqualError(t, err, "unknown arch: abc")
+	assert.Empty(t, short)
+}
+
 func TestDiskSize_ToMB(t *testing.T) {
 	assert.EqualValues(t, 50, DiskSize("50M").ToMB())
 	assert.EqualValues(t, 4096, DiskSize("4G").ToMB())
@@ -257,3 +313,28 @@ func TestDiskSize_ToMB(t *testing.T) {
 		DiskSize("10K").ToMB()
 	})
 }
+
+func TestHelmChartAllValuesFiles(t *testing.T) {
+	tests := map[string]struct {
+		chart    HelmChart
+		expected []string
+	}{
+		"no values files": {
+			chart: HelmChart{},
+		},
+		"values file": {
+			chart:    HelmChart{ValuesFile: "values.yaml"},
+			expected: []string{"values.yaml"},
+		},
+		"values files": {
+			chart:    HelmChart{ValuesFiles: []string{"base.yaml", "edge.yaml"}},
+			expected: []string{"base.yaml", "edge.yaml"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.chart.AllValuesFiles())
+		})
+	}
+}
@@ -12,6 +12,8 @@ func validateAPIVersion(ctx *image.Context) *FailedValidation {
 	if definitionVersion != "1.0" {
 		return &FailedValidation{
 			UserMessage: "This version of Edge Image Builder only supports version '1.0' of the definition schema.",
+			Code:        CodeUnsupported,
+			Field:       "apiVersion",
 		}
 	}
 
@@ -36,6 +36,8 @@ func TestValidateApiVersion(t *testing.T) {
 				assert.Nil(t, failure)
 			} else {
 				assert.Equal(t, test.ExpectedFailedMessage, failure.UserMessage)
+				assert.Equal(t, CodeUnsupported, failure.Code)
+				assert.Equal(t, "apiVersion", failure.Field)
 			}
 		})
 	}
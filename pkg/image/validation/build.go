@@ -0,0 +1,31 @@
+package validation
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/combustion"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+const (
+	buildComponent = "Build"
+)
+
+func validateBuild(ctx *image.Context) []FailedValidation {
+	var failures []FailedValidation
+
+	skippable := combustion.SkippableComponents()
+	for _, component := range ctx.SkipComponents {
+		if !slices.Contains(skippable, component) {
+			msg := fmt.Sprintf("The component '%s' cannot be skipped. Valid components are: %s.", component, strings.Join(skippable, ", "))
+			failures = append(failures, FailedValidation{
+				UserMessage: msg,
+				Code:        CodeUnsupported,
+			})
+		}
+	}
+
+	return failures
+}
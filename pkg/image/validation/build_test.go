@@ -0,0 +1,42 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestValidateBuild(t *testing.T) {
+	tests := map[string]struct {
+		SkipComponents         []string
+		ExpectedFailedMessages []string
+	}{
+		`no skipped components`: {},
+		`valid components`: {
+			SkipComponents: []string{"rpm", "registry", "kubernetes"},
+		},
+		`unknown component`: {
+			SkipComponents: []string{"rpm", "rancher"},
+			ExpectedFailedMessages: []string{
+				"The component 'rancher' cannot be skipped. Valid components are: certificates, custom, elemental, first-boot, groups, hostname, hosts, identifier, keymap, kubernetes, locale, network, proxy, registry, rpm, suma, sysctl, systemd, time, users.",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := image.Context{
+				SkipComponents: test.SkipComponents,
+			}
+			failures := validateBuild(&ctx)
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+		})
+	}
+}
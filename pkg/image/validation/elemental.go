@@ -0,0 +1,270 @@
+package validation
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/combustion"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	elementalComponent = "Elemental"
+	elementalField     = "operatingSystem.elemental"
+	elementalFileField = "elemental/elemental_config.yaml"
+)
+
+// elementalAuthTypes are the authentication methods supported by the Elemental registration.
+var elementalAuthTypes = []string{"tpm", "mac", "sys-uuid"}
+
+// elementalRegistrationNameRegex restricts registration names to ones usable in the generated config file names.
+var elementalRegistrationNameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
+
+func validateElemental(ctx *image.Context) []FailedValidation {
+	var failures []FailedValidation
+
+	configFile := combustion.ElementalConfigPath(ctx)
+	_, err := os.Stat(configFile)
+	configFileExists := err == nil
+
+	elemental := ctx.ImageDefinition.OperatingSystem.Elemental
+	if elemental.IsConfigured() {
+		failures = append(failures, validateElementalDefinition(ctx)...)
+
+		if configFileExists {
+			failures = append(failures, FailedValidation{
+				UserMessage: "The 'elemental' section cannot be combined with an 'elemental/elemental_config.yaml' file.",
+				Code:        CodeConflict,
+				Field:       elementalField,
+			})
+		}
+	} else if configFileExists {
+		failures = append(failures, validateElementalConfigFile(configFile)...)
+	}
+
+	return failures
+}
+
+func validateElementalDefinition(ctx *image.Context) []FailedValidation {
+	elemental := ctx.ImageDefinition.OperatingSystem.Elemental
+
+	if len(elemental.Registrations) == 0 {
+		registration := elemental.SingleRegistration()
+		return validateElementalRegistration(ctx, &registration, "")
+	}
+
+	var failures []FailedValidation
+
+	if elemental.RegistrationURL != "" || elemental.CACert != "" || elemental.EmulateTPM || elemental.AuthType != "" {
+		failures = append(failures, FailedValidation{
+			UserMessage: "The elemental 'registrations' field cannot be combined with the 'registrationURL', 'caCert', 'emulateTPM' or 'authType' fields.",
+			Code:        CodeConflict,
+			Field:       elementalField + ".registrations",
+		})
+	}
+
+	failures = append(failures, validateElementalRegistrations(elemental.Registrations)...)
+
+	for i := range elemental.Registrations {
+		prefix := fmt.Sprintf("registrations[%d].", i)
+		failures = append(failures, validateElementalRegistration(ctx, &elemental.Registrations[i], prefix)...)
+	}
+
+	return failures
+}
+
+// validateElementalRegistrations checks that the registrations can be unambiguously matched to the nodes.
+func validateElementalRegistrations(registrations []image.ElementalRegistration) []FailedValidation {
+	var failures []FailedValidation
+
+	seenNames := map[string]bool{}
+	hostnameRegistrations := map[string]string{}
+	var defaultRegistrations int
+
+	for i, registration := range registrations {
+		field := fmt.Sprintf("%s.registrations[%d]", elementalField, i)
+
+		switch {
+		case registration.Name == "":
+			failures = append(failures, FailedValidation{
+				UserMessage: "The 'name' field is required for each entry in the elemental 'registrations' list.",
+				Code:        CodeRequired,
+				Field:       field + ".name",
+			})
+		case !elementalRegistrationNameRegex.MatchString(registration.Name):
+			msg := fmt.Sprintf("The elemental registration name '%s' may only contain letters, digits, '-', '_' and '.'.", registration.Name)
+			failures = append(failures, FailedValidation{
+				UserMessage: msg,
+				Code:        CodeInvalid,
+				Field:       field + ".name",
+			})
+		case seenNames[registration.Name]:
+			msg := fmt.Sprintf("Duplicate elemental registration name '%s' found.", registration.Name)
+			failures = append(failures, FailedValidation{
+				UserMessage: msg,
+				Code:        CodeDuplicate,
+				Field:       field + ".name",
+			})
+		}
+		seenNames[registration.Name] = true
+
+		if len(registration.Hostnames) == 0 {
+			defaultRegistrations++
+		}
+
+		for j, hostname := range registration.Hostnames {
+			if other, ok := hostnameRegistrations[hostname]; ok && other != registration.Name {
+				msg := fmt.Sprintf("The hostname '%s' is assigned to both the '%s' and '%s' elemental registrations.", hostname, other, registration.Name)
+				failures = append(failures, FailedValidation{
+					UserMessage: msg,
+					Code:        CodeConflict,
+					Field:       fmt.Sprintf("%s.hostnames[%d]", field, j),
+				})
+				continue
+			}
+			hostnameRegistrations[hostname] = registration.Name
+		}
+	}
+
+	if defaultRegistrations > 1 {
+		failures = append(failures, FailedValidation{
+			UserMessage: "Only one elemental registration may be defined without 'hostnames'.",
+			Code:        CodeConflict,
+			Field:       elementalField + ".registrations",
+		})
+	}
+
+	return failures
+}
+
+func validateElementalRegistration(ctx *image.Context, registration *image.ElementalRegistration, fieldPrefix string) []FailedValidation {
+	var failures []FailedValidation
+
+	field := elementalField + "." + fieldPrefix
+
+	if registration.RegistrationURL == "" {
+		failures = append(failures, FailedValidation{
+			UserMessage: fmt.Sprintf("The '%sregistrationURL' field is required for the 'elemental' section.", fieldPrefix),
+			Code:        CodeRequired,
+			Field:       field + "registrationURL",
+		})
+	} else if msg := validateElementalURL(fieldPrefix+"registrationURL", registration.RegistrationURL); msg != "" {
+		failures = append(failures, FailedValidation{
+			UserMessage: msg,
+			Code:        CodeInvalid,
+			Field:       field + "registrationURL",
+		})
+	}
+
+	if msg := validateElementalAuthType(fieldPrefix+"authType", registration.AuthType); msg != "" {
+		failures = append(failures, FailedValidation{
+			UserMessage: msg,
+			Code:        CodeUnsupported,
+			Field:       field + "authType",
+		})
+	}
+
+	if registration.EmulateTPM && registration.AuthType != "" && registration.AuthType != "tpm" {
+		failures = append(failures, FailedValidation{
+			UserMessage: fmt.Sprintf("The elemental '%semulateTPM' field can only be used with the 'tpm' authType.", fieldPrefix),
+			Code:        CodeConflict,
+			Field:       field + "emulateTPM",
+		})
+	}
+
+	if registration.CACert != "" {
+		certFile := filepath.Join(combustion.ElementalPath(ctx), registration.CACert)
+		if _, err := os.Stat(certFile); err != nil {
+			msg := fmt.Sprintf("The elemental CA certificate '%s' could not be found at '%s'.", registration.CACert, certFile)
+			failures = append(failures, FailedValidation{
+				UserMessage: msg,
+				Error:       err,
+				Code:        CodeNotFound,
+				Field:       field + "caCert",
+			})
+		}
+	}
+
+	return failures
+}
+
+// validateElementalConfigFile checks the registration of a user provided Elemental config file.
+func validateElementalConfigFile(configFile string) []FailedValidation {
+	data, err := os.ReadFile(configFile)
+	if err != nil {
+		zap.S().Errorf("Elemental config file '%s' could not be read: %s", configFile, err)
+		return []FailedValidation{
+			{
+				UserMessage: "The elemental config file 'elemental/elemental_config.yaml' could not be read.",
+				Error:       err,
+				Code:        CodeUnreadable,
+				Field:       elementalFileField,
+			},
+		}
+	}
+
+	var config struct {
+		Elemental struct {
+			Registration struct {
+				URL  string `yaml:"url"`
+				Auth string `yaml:"auth"`
+			} `yaml:"registration"`
+		} `yaml:"elemental"`
+	}
+
+	if err = yaml.Unmarshal(data, &config); err != nil {
+		return []FailedValidation{
+			{
+				UserMessage: "The elemental config file 'elemental/elemental_config.yaml' could not be parsed.",
+				Error:       err,
+				Code:        CodeInvalid,
+				Field:       elementalFileField,
+			},
+		}
+	}
+
+	var failures []FailedValidation
+
+	registration := config.Elemental.Registration
+	if msg := validateElementalURL("url", registration.URL); msg != "" {
+		failures = append(failures, FailedValidation{
+			UserMessage: msg,
+			Code:        CodeInvalid,
+			Field:       elementalFileField,
+		})
+	}
+
+	if msg := validateElementalAuthType("auth", registration.Auth); msg != "" {
+		failures = append(failures, FailedValidation{
+			UserMessage: msg,
+			Code:        CodeUnsupported,
+			Field:       elementalFileField,
+		})
+	}
+
+	return failures
+}
+
+func validateElementalURL(field, registrationURL string) string {
+	parsedURL, err := url.Parse(registrationURL)
+	if err != nil || parsedURL.Scheme != httpsScheme || parsedURL.Host == "" {
+		return fmt.Sprintf("The elemental '%s' must be a valid https URL.", field)
+	}
+
+	return ""
+}
+
+func validateElementalAuthType(field, authType string) string {
+	if authType == "" || slices.Contains(elementalAuthTypes, authType) {
+		return ""
+	}
+
+	return fmt.Sprintf("The elemental '%s' must be one of: %s.", field, strings.Join(elementalAuthTypes, ", "))
+}
@@ -0,0 +1,267 @@
+package validation
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestValidateElementalDefinition(t *testing.T) {
+	configDir := t.TempDir()
+	elementalDir := filepath.Join(configDir, "elemental")
+	require.NoError(t, os.Mkdir(elementalDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(elementalDir, "rancher.crt"), []byte("cert"), 0o600))
+
+	tests := map[string]struct {
+		Elemental              image.Elemental
+		ExpectedFailedMessages []string
+	}{
+		`not configured`: {},
+		`valid`: {
+			Elemental: image.Elemental{
+				RegistrationURL: "https://rancher.example.com/elemental/registration/abc",
+				CACert:          "rancher.crt",
+				EmulateTPM:      true,
+				AuthType:        "tpm",
+			},
+		},
+		`missing URL`: {
+			Elemental: image.Elemental{
+				AuthType: "mac",
+			},
+			ExpectedFailedMessages: []string{
+				"The 'registrationURL' field is required for the 'elemental' section.",
+			},
+		},
+		`URL without scheme`: {
+			Elemental: image.Elemental{
+				RegistrationURL: "rancher.example.com/elemental/registration/abc",
+			},
+			ExpectedFailedMessages: []string{
+				"The elemental 'registrationURL' must be a valid https URL.",
+			},
+		},
+		`http URL`: {
+			Elemental: image.Elemental{
+				RegistrationURL: "http://rancher.example.com/elemental/registration/abc",
+			},
+			ExpectedFailedMessages: []string{
+				"The elemental 'registrationURL' must be a valid https URL.",
+			},
+		},
+		`invalid auth type`: {
+			Elemental: image.Elemental{
+				RegistrationURL: "https://rancher.example.com/elemental/registration/abc",
+				AuthType:        "password",
+			},
+			ExpectedFailedMessages: []string{
+				"The elemental 'authType' must be one of: tpm, mac, sys-uuid.",
+			},
+		},
+		`emulated TPM without TPM auth`: {
+			Elemental: image.Elemental{
+				RegistrationURL: "https://rancher.example.com/elemental/registration/abc",
+				EmulateTPM:      true,
+				AuthType:        "mac",
+			},
+			ExpectedFailedMessages: []string{
+				"The elemental 'emulateTPM' field can only be used with the 'tpm' authType.",
+			},
+		},
+		`missing CA certificate`: {
+			Elemental: image.Elemental{
+				RegistrationURL: "https://rancher.example.com/elemental/registration/abc",
+				CACert:          "missing.crt",
+			},
+			ExpectedFailedMessages: []string{
+				fmt.Sprintf("The elemental CA certificate 'missing.crt' could not be found at '%s'.", filepath.Join(elementalDir, "missing.crt")),
+			},
+		},
+		`valid registrations`: {
+			Elemental: image.Elemental{
+				Registrations: []image.ElementalRegistration{
+					{
+						Name:            "control-plane",
+						Hostnames:       []string{"node1.suse.com", "node2.suse.com"},
+						RegistrationURL: "https://rancher.example.com/elemental/registration/control-plane",
+						CACert:          "rancher.crt",
+					},
+					{
+						Name:            "workers",
+						RegistrationURL: "https://rancher.example.com/elemental/registration/workers",
+						AuthType:        "mac",
+					},
+				},
+			},
+		},
+		`registrations combined with shorthand`: {
+			Elemental: image.Elemental{
+				RegistrationURL: "https://rancher.example.com/elemental/registration/abc",
+				Registrations: []image.ElementalRegistration{
+					{
+						Name:            "workers",
+						RegistrationURL: "https://rancher.example.com/elemental/registration/workers",
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The elemental 'registrations' field cannot be combined with the 'registrationURL', 'caCert', 'emulateTPM' or 'authType' fields.",
+			},
+		},
+		`invalid registrations`: {
+			Elemental: image.Elemental{
+				Registrations: []image.ElementalRegistration{
+					{
+						Hostnames:       []string{"node1.suse.com"},
+						RegistrationURL: "https://rancher.example.com/elemental/registration/abc",
+					},
+					{
+						Name:      "control/plane",
+						Hostnames: []string{"node2.suse.com"},
+						AuthType:  "password",
+					},
+					{
+						Name:            "workers",
+						RegistrationURL: "http://rancher.example.com/elemental/registration/workers",
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'name' field is required for each entry in the elemental 'registrations' list.",
+				"The elemental registration name 'control/plane' may only contain letters, digits, '-', '_' and '.'.",
+				"The 'registrations[1].registrationURL' field is required for the 'elemental' section.",
+				"The elemental 'registrations[1].authType' must be one of: tpm, mac, sys-uuid.",
+				"The elemental 'registrations[2].registrationURL' must be a valid https URL.",
+			},
+		},
+		`ambiguous registrations`: {
+			Elemental: image.Elemental{
+				Registrations: []image.ElementalRegistration{
+					{
+						Name:            "control-plane",
+						Hostnames:       []string{"node1.suse.com", "node2.suse.com"},
+						RegistrationURL: "https://rancher.example.com/elemental/registration/control-plane",
+					},
+					{
+						Name:            "control-plane",
+						Hostnames:       []string{"node3.suse.com"},
+						RegistrationURL: "https://rancher.example.com/elemental/registration/control-plane",
+					},
+					{
+						Name:            "workers",
+						Hostnames:       []string{"node2.suse.com"},
+						RegistrationURL: "https://rancher.example.com/elemental/registration/workers",
+					},
+					{
+						Name:            "default",
+						RegistrationURL: "https://rancher.example.com/elemental/registration/default",
+					},
+					{
+						Name:            "fallback",
+						RegistrationURL: "https://rancher.example.com/elemental/registration/fallback",
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"Duplicate elemental registration name 'control-plane' found.",
+				"The hostname 'node2.suse.com' is assigned to both the 'control-plane' and 'workers' elemental registrations.",
+				"Only one elemental registration may be defined without 'hostnames'.",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := image.Context{
+				ImageConfigDir: configDir,
+				ImageDefinition: &image.Definition{
+					OperatingSystem: image.OperatingSystem{
+						Elemental: test.Elemental,
+					},
+				},
+			}
+			failures := validateElemental(&ctx)
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+		})
+	}
+}
+
+func TestValidateElementalConfigFile(t *testing.T) {
+	tests := map[string]struct {
+		Config                 string
+		Elemental              image.Elemental
+		ExpectedFailedMessages []string
+	}{
+		`valid`: {
+			Config: "elemental:\n  registration:\n    url: https://rancher.example.com/elemental/registration/abc\n    auth: tpm\n",
+		},
+		`valid without auth`: {
+			Config: "elemental:\n  registration:\n    url: https://rancher.example.com/elemental/registration/abc\n",
+		},
+		`invalid`: {
+			Config: "elemental:\n  registration:\n    url: http://rancher.example.com/elemental/registration/abc\n    auth: password\n",
+			ExpectedFailedMessages: []string{
+				"The elemental 'url' must be a valid https URL.",
+				"The elemental 'auth' must be one of: tpm, mac, sys-uuid.",
+			},
+		},
+		`missing URL`: {
+			Config: "elemental:\n  registration:\n    auth: mac\n",
+			ExpectedFailedMessages: []string{
+				"The elemental 'url' must be a valid https URL.",
+			},
+		},
+		`malformed`: {
+			Config: "elemental: [\n",
+			ExpectedFailedMessages: []string{
+				"The elemental config file 'elemental/elemental_config.yaml' could not be parsed.",
+			},
+		},
+		`combined with definition`: {
+			Config: "elemental:\n  registration:\n    url: https://rancher.example.com/elemental/registration/abc\n",
+			Elemental: image.Elemental{
+				RegistrationURL: "https://rancher.example.com/elemental/registration/abc",
+			},
+			ExpectedFailedMessages: []string{
+				"The 'elemental' section cannot be combined with an 'elemental/elemental_config.yaml' file.",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			configDir := t.TempDir()
+			elementalDir := filepath.Join(configDir, "elemental")
+			require.NoError(t, os.Mkdir(elementalDir, 0o755))
+			require.NoError(t, os.WriteFile(filepath.Join(elementalDir, "elemental_config.yaml"), []byte(test.Config), 0o600))
+
+			ctx := image.Context{
+				ImageConfigDir: configDir,
+				ImageDefinition: &image.Definition{
+					OperatingSystem: image.OperatingSystem{
+						Elemental: test.Elemental,
+					},
+				},
+			}
+			failures := validateElemental(&ctx)
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+		})
+	}
+}
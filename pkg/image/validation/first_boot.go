@@ -0,0 +1,107 @@
+package validation
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/combustion"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+const (
+	firstBootComponent = "First Boot"
+)
+
+func validateFirstBoot(ctx *image.Context) []FailedValidation {
+	field := path.Join(combustion.FirstBootDir, combustion.FirstBootScriptsDir)
+	scriptsDir := filepath.Join(ctx.ImageConfigDir, field)
+
+	entries, err := os.ReadDir(scriptsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return []FailedValidation{
+			{
+				UserMessage: "The first boot scripts directory could not be read. See the logs for more information.",
+				Error:       err,
+				Code:        CodeUnreadable,
+				Field:       field,
+			},
+		}
+	}
+
+	if len(entries) == 0 {
+		return []FailedValidation{
+			{
+				UserMessage: fmt.Sprintf("The '%s' directory must contain at least one script.", field),
+				Code:        CodeRequired,
+				Field:       field,
+			},
+		}
+	}
+
+	var failures []FailedValidation
+	names := map[string]string{}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		scriptField := path.Join(field, name)
+
+		if entry.IsDir() {
+			msg := fmt.Sprintf("The first boot scripts directory must only contain files, found directory '%s'.", name)
+			failures = append(failures, FailedValidation{
+				UserMessage: msg,
+				Code:        CodeInvalid,
+				Field:       scriptField,
+			})
+			continue
+		}
+
+		if name == combustion.FirstBootUnitName {
+			msg := fmt.Sprintf("The first boot script name '%s' is reserved.", name)
+			failures = append(failures, FailedValidation{
+				UserMessage: msg,
+				Code:        CodeConflict,
+				Field:       scriptField,
+			})
+		}
+
+		if existing, ok := names[strings.ToLower(name)]; ok {
+			msg := fmt.Sprintf("The first boot scripts '%s' and '%s' must be uniquely named regardless of case.", existing, name)
+			failures = append(failures, FailedValidation{
+				UserMessage: msg,
+				Code:        CodeDuplicate,
+				Field:       scriptField,
+			})
+		}
+		names[strings.ToLower(name)] = name
+
+		info, infoErr := entry.Info()
+		if infoErr != nil {
+			msg := fmt.Sprintf("The first boot script '%s' could not be read. See the logs for more information.", name)
+			failures = append(failures, FailedValidation{
+				UserMessage: msg,
+				Error:       infoErr,
+				Code:        CodeUnreadable,
+				Field:       scriptField,
+			})
+			continue
+		}
+
+		if info.Mode()&0o111 == 0 {
+			msg := fmt.Sprintf("The first boot script '%s' must be executable.", name)
+			failures = append(failures, FailedValidation{
+				UserMessage: msg,
+				Code:        CodeInvalid,
+				Field:       scriptField,
+			})
+		}
+	}
+
+	return failures
+}
@@ -0,0 +1,107 @@
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestValidateFirstBoot(t *testing.T) {
+	tests := map[string]struct {
+		Scripts                map[string]os.FileMode
+		Dirs                   []string
+		NoScriptsDir           bool
+		ExpectedFailedMessages []string
+		ExpectedCodes          []failureCode
+	}{
+		`not configured`: {
+			NoScriptsDir: true,
+		},
+		`valid`: {
+			Scripts: map[string]os.FileMode{
+				"10-first.sh":  0o755,
+				"20-second.sh": 0o744,
+			},
+		},
+		`empty`: {
+			ExpectedFailedMessages: []string{
+				"The 'firstBoot/scripts' directory must contain at least one script.",
+			},
+			ExpectedCodes: []failureCode{
+				{Code: CodeRequired, Field: "firstBoot/scripts"},
+			},
+		},
+		`not executable`: {
+			Scripts: map[string]os.FileMode{
+				"10-first.sh": 0o644,
+			},
+			ExpectedFailedMessages: []string{
+				"The first boot script '10-first.sh' must be executable.",
+			},
+			ExpectedCodes: []failureCode{
+				{Code: CodeInvalid, Field: "firstBoot/scripts/10-first.sh"},
+			},
+		},
+		`not uniquely named`: {
+			Scripts: map[string]os.FileMode{
+				"10-first.sh": 0o755,
+				"10-First.sh": 0o755,
+			},
+			ExpectedFailedMessages: []string{
+				"The first boot scripts '10-First.sh' and '10-first.sh' must be uniquely named regardless of case.",
+			},
+			ExpectedCodes: []failureCode{
+				{Code: CodeDuplicate, Field: "firstBoot/scripts/10-first.sh"},
+			},
+		},
+		`reserved name and directory`: {
+			Scripts: map[string]os.FileMode{
+				"eib-first-boot.service": 0o755,
+			},
+			Dirs: []string{"nested"},
+			ExpectedFailedMessages: []string{
+				"The first boot script name 'eib-first-boot.service' is reserved.",
+				"The first boot scripts directory must only contain files, found directory 'nested'.",
+			},
+			ExpectedCodes: []failureCode{
+				{Code: CodeConflict, Field: "firstBoot/scripts/eib-first-boot.service"},
+				{Code: CodeInvalid, Field: "firstBoot/scripts/nested"},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			configDir := t.TempDir()
+			scriptsDir := filepath.Join(configDir, "firstBoot", "scripts")
+
+			if !test.NoScriptsDir {
+				require.NoError(t, os.MkdirAll(scriptsDir, 0o755))
+			}
+			for script, mode := range test.Scripts {
+				require.NoError(t, os.WriteFile(filepath.Join(scriptsDir, script), []byte("#!/bin/bash"), mode))
+			}
+			for _, dir := range test.Dirs {
+				require.NoError(t, os.MkdirAll(filepath.Join(scriptsDir, dir), 0o755))
+			}
+
+			ctx := image.Context{
+				ImageConfigDir:  configDir,
+				ImageDefinition: &image.Definition{},
+			}
+			failures := validateFirstBoot(&ctx)
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+			assert.Equal(t, test.ExpectedCodes, failureCodes(failures))
+		})
+	}
+}
@@ -1,13 +1,17 @@
 package validation
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"slices"
 	"strings"
 
 	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"go.uber.org/zap"
 )
 
 const (
@@ -18,60 +22,283 @@ func validateImage(ctx *image.Context) []FailedValidation {
 	def := ctx.ImageDefinition
 
 	validImageTypes := []string{image.TypeISO, image.TypeRAW}
-	validArchTypes := []string{string(image.ArchTypeARM), string(image.ArchTypeX86)}
+	validArchTypes := []string{string(image.ArchTypeX86), string(image.ArchTypeARM)}
 
 	var failures []FailedValidation
 
 	if def.Image.ImageType == "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: "The 'imageType' field is required in the 'image' section.",
+			Code:        CodeRequired,
+			Field:       "image.imageType",
 		})
 	} else if !slices.Contains(validImageTypes, def.Image.ImageType) {
 		msg := fmt.Sprintf("The 'imageType' field must be one of: %s", strings.Join(validImageTypes, ", "))
 		failures = append(failures, FailedValidation{
 			UserMessage: msg,
+			Code:        CodeInvalid,
+			Field:       "image.imageType",
 		})
 	}
 
 	if def.Image.Arch == "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: "The 'arch' field is required in the 'image' section.",
+			Code:        CodeRequired,
+			Field:       "image.arch",
 		})
 	} else if !slices.Contains(validArchTypes, string(def.Image.Arch)) {
-		msg := fmt.Sprintf("The 'arch' field must be one of: %s", strings.Join(validArchTypes, ", "))
+		msg := fmt.Sprintf("The 'arch' field must be one of: %s.", strings.Join(validArchTypes, ", "))
 		failures = append(failures, FailedValidation{
 			UserMessage: msg,
+			Code:        CodeInvalid,
+			Field:       "image.arch",
 		})
 	}
 
 	if def.Image.OutputImageName == "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: "The 'outputImageName' field is required in the 'image' section.",
+			Code:        CodeRequired,
+			Field:       "image.outputImageName",
+		})
+	} else if !isSimpleFilename(def.Image.OutputImageName) {
+		failures = append(failures, FailedValidation{
+			UserMessage: "The 'outputImageName' must be a simple filename without path components.",
+			Code:        CodeInvalid,
+			Field:       "image.outputImageName",
 		})
 	}
 
+	failures = append(failures, validateOutputPermissions(&def.Image)...)
+
 	if def.Image.BaseImage == "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: "The 'baseImage' field is required in the 'image' section.",
+			Code:        CodeRequired,
+			Field:       "image.baseImage",
+		})
+	} else if !isSimpleFilename(def.Image.BaseImage) {
+		failures = append(failures, FailedValidation{
+			UserMessage: "The 'baseImage' must be a simple filename without path components.",
+			Code:        CodeInvalid,
+			Field:       "image.baseImage",
 		})
 	} else {
 		baseImageFilename := filepath.Join(ctx.ImageConfigDir, "base-images", def.Image.BaseImage)
 		_, err := os.Stat(baseImageFilename)
-		if err != nil {
+		if err == nil && slices.Contains(validArchTypes, string(def.Image.Arch)) {
+			failures = append(failures, validateBaseImageArch(def.Image.BaseImage, baseImageFilename, def.Image.Arch)...)
+		} else if err != nil {
 			if os.IsNotExist(err) {
 				msg := fmt.Sprintf("The specified base image '%s' cannot be found.", def.Image.BaseImage)
 				failures = append(failures, FailedValidation{
 					UserMessage: msg,
+					Code:        CodeNotFound,
+					Field:       "image.baseImage",
 				})
 			} else {
 				msg := fmt.Sprintf("The specified base image '%s' cannot be read. See the logs for more information.", def.Image.BaseImage)
 				failures = append(failures, FailedValidation{
 					UserMessage: msg,
 					Error:       err,
+					Code:        CodeUnreadable,
+					Field:       "image.baseImage",
 				})
 			}
 		}
 	}
 
+	if slices.Contains(validImageTypes, def.Image.ImageType) {
+		failures = append(failures, validateImageExtension("baseImage", def.Image.BaseImage, def.Image.ImageType)...)
+		failures = append(failures, validateImageExtension("outputImageName", def.Image.OutputImageName, def.Image.ImageType)...)
+	}
+
 	return failures
 }
+
+// imageExtensions maps the file extensions of images to the image type they indicate.
+var imageExtensions = map[string]string{
+	".iso": image.TypeISO,
+	".raw": image.TypeRAW,
+	".img": image.TypeRAW,
+}
+
+// imageTypeForExtension returns the image type indicated by the extension of the image file name.
+// An empty string is returned if the extension does not indicate a specific image type.
+func imageTypeForExtension(name string) string {
+	return imageExtensions[strings.ToLower(filepath.Ext(name))]
+}
+
+func validateImageExtension(field, name, imageType string) []FailedValidation {
+	extensionType := imageTypeForExtension(name)
+	if extensionType == "" || extensionType == imageType {
+		return nil
+	}
+
+	msg := fmt.Sprintf("The '%s' field '%s' has an extension indicating the '%s' image type, which does not match the '%s' 'imageType' field.",
+		field, name, extensionType, imageType)
+	return []FailedValidation{
+		{
+			UserMessage: msg,
+			Code:        CodeMismatch,
+			Field:       "image." + field,
+		},
+	}
+}
+
+// isSimpleFilename determines whether the name refers to a file directly within a directory,
+// rejecting absolute paths, nested paths and parent directory references.
+func isSimpleFilename(name string) bool {
+	return name == filepath.Base(name) && name != "." && name != ".."
+}
+
+func validateOutputPermissions(img *image.Image) []FailedValidation {
+	if img.OutputPermissions == "" {
+		return nil
+	}
+
+	mode, err := img.OutputFileMode()
+	switch {
+	case err != nil || mode&^os.ModePerm != 0:
+		msg := "The 'outputPermissions' field must be an octal file mode between '0000' and '0777' (e.g. '0644')."
+		return []FailedValidation{
+			{
+				UserMessage: msg,
+				Code:        CodeInvalid,
+				Field:       "image.outputPermissions",
+			},
+		}
+	case mode&0o400 == 0:
+		msg := "The 'outputPermissions' field must allow the owner to read the image."
+		return []FailedValidation{
+			{
+				UserMessage: msg,
+				Code:        CodeInvalid,
+				Field:       "image.outputPermissions",
+			},
+		}
+	case mode&0o002 != 0:
+		msg := "The 'outputPermissions' field allows all users to modify the image."
+		return []FailedValidation{
+			{
+				UserMessage: msg,
+				Severity:    SeverityWarning,
+				Code:        CodeInsecure,
+				Field:       "image.outputPermissions",
+			},
+		}
+	}
+
+	return nil
+}
+
+func validateBaseImageArch(baseImage, baseImageFilename string, arch image.Arch) []FailedValidation {
+	baseImageArch, err := detectBaseImageArch(baseImageFilename)
+	if err != nil {
+		zap.S().Warnf("Detecting the architecture of base image '%s' failed: %s", baseImage, err)
+		return nil
+	}
+
+	if baseImageArch == "" || baseImageArch == arch {
+		return nil
+	}
+
+	msg := fmt.Sprintf("The base image '%s' is built for the '%s' architecture, which does not match the '%s' 'arch' field in the 'image' section.",
+		baseImage, baseImageArch, arch)
+	return []FailedValidation{
+		{
+			UserMessage: msg,
+			Code:        CodeMismatch,
+			Field:       "image.baseImage",
+		},
+	}
+}
+
+// baseImageScanSize limits how much of the base image is inspected for EFI bootloader names.
+// Both the ISO9660 directory records and the EFI system partition of a raw image are located
+// at the beginning of the SUSE base images.
+const baseImageScanSize = 64 << 20
+
+// baseImageScanChunkSize is the amount of the base image held in memory at a time while scanning it.
+const baseImageScanChunkSize = 1 << 20
+
+// efiBootloaderNames maps the lowercase EFI bootloader file names (including their FAT 8.3 form)
+// to the architecture they are built for.
+var efiBootloaderNames = map[image.Arch][][]byte{
+	image.ArchTypeX86: {[]byte("bootx64.efi"), []byte("bootx64 efi")},
+	image.ArchTypeARM: {[]byte("bootaa64.efi"), []byte("bootaa64efi")},
+}
+
+// detectBaseImageArch identifies the architecture of the base image, first through the architecture
+// in its file name (e.g. 'SL-Micro.x86_64-6.0-Base-GM.raw') and then through the EFI bootloader
+// contained in the image. An empty architecture is returned if it could not be determined.
+func detectBaseImageArch(baseImageFilename string) (image.Arch, error) {
+	filename := filepath.Base(baseImageFilename)
+	switch {
+	case strings.Contains(filename, "."+string(image.ArchTypeX86)) && !strings.Contains(filename, "."+string(image.ArchTypeARM)):
+		return image.ArchTypeX86, nil
+	case strings.Contains(filename, "."+string(image.ArchTypeARM)) && !strings.Contains(filename, "."+string(image.ArchTypeX86)):
+		return image.ArchTypeARM, nil
+	}
+
+	file, err := os.Open(baseImageFilename)
+	if err != nil {
+		return "", fmt.Errorf("opening base image: %w", err)
+	}
+	defer file.Close()
+
+	found, err := scanEFIBootloaders(io.LimitReader(file, baseImageScanSize))
+	if err != nil {
+		return "", fmt.Errorf("reading base image: %w", err)
+	}
+
+	var detected []image.Arch
+	for _, arch := range []image.Arch{image.ArchTypeX86, image.ArchTypeARM} {
+		if found[arch] {
+			detected = append(detected, arch)
+		}
+	}
+
+	if len(detected) != 1 {
+		return "", nil
+	}
+
+	return detected[0], nil
+}
+
+// scanEFIBootloaders looks for the EFI bootloader names in the given reader chunk by chunk.
+// The end of each chunk is carried over to the next one so that names spanning two chunks are found as well.
+func scanEFIBootloaders(r io.Reader) (map[image.Arch]bool, error) {
+	var overlap int
+	for _, names := range efiBootloaderNames {
+		for _, name := range names {
+			overlap = max(overlap, len(name)-1)
+		}
+	}
+
+	found := map[image.Arch]bool{}
+	buf := make([]byte, overlap+baseImageScanChunkSize)
+	kept := 0
+
+	for {
+		n, err := io.ReadFull(r, buf[kept:])
+
+		window := bytes.ToLower(buf[:kept+n])
+		for arch, names := range efiBootloaderNames {
+			for _, name := range names {
+				if bytes.Contains(window, name) {
+					found[arch] = true
+				}
+			}
+		}
+
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return found, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		kept = copy(buf, buf[kept+n-overlap:kept+n])
+	}
+}
@@ -3,6 +3,7 @@ package validation
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -25,9 +26,14 @@ func TestValidateImage(t *testing.T) {
 	_, err = os.Create(testBaseImageFilename)
 	require.NoError(t, err)
 
+	armBaseImageFilename := filepath.Join(testImagesDir, "arm-base-image.iso")
+	err = os.WriteFile(armBaseImageFilename, []byte("CD001 EFI/BOOT/BOOTAA64.EFI;1"), 0o600)
+	require.NoError(t, err)
+
 	tests := map[string]struct {
 		ImageDefinition        image.Definition
 		ExpectedFailedMessages []string
+		ExpectedCodes          []failureCode
 	}{
 		`complete valid definition`: {
 			ImageDefinition: image.Definition{
@@ -49,6 +55,12 @@ func TestValidateImage(t *testing.T) {
 				"The 'outputImageName' field is required in the 'image' section.",
 				"The 'baseImage' field is required in the 'image' section.",
 			},
+			ExpectedCodes: []failureCode{
+				{Code: CodeRequired, Field: "image.imageType"},
+				{Code: CodeRequired, Field: "image.arch"},
+				{Code: CodeRequired, Field: "image.outputImageName"},
+				{Code: CodeRequired, Field: "image.baseImage"},
+			},
 		},
 		`invalid enum values`: {
 			ImageDefinition: image.Definition{
@@ -61,7 +73,77 @@ func TestValidateImage(t *testing.T) {
 			},
 			ExpectedFailedMessages: []string{
 				"The 'imageType' field must be one of: iso, raw",
-				"The 'arch' field must be one of: aarch64, x86_64",
+				"The 'arch' field must be one of: x86_64, aarch64.",
+			},
+			ExpectedCodes: []failureCode{
+				{Code: CodeInvalid, Field: "image.imageType"},
+				{Code: CodeInvalid, Field: "image.arch"},
+			},
+		},
+		`output image path traversal`: {
+			ImageDefinition: image.Definition{
+				Image: image.Image{
+					ImageType:       image.TypeISO,
+					Arch:            image.ArchTypeX86,
+					BaseImage:       "base-image.iso",
+					OutputImageName: "../../etc/passwd",
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'outputImageName' must be a simple filename without path components.",
+			},
+			ExpectedCodes: []failureCode{
+				{Code: CodeInvalid, Field: "image.outputImageName"},
+			},
+		},
+		`absolute output image path`: {
+			ImageDefinition: image.Definition{
+				Image: image.Image{
+					ImageType:       image.TypeISO,
+					Arch:            image.ArchTypeX86,
+					BaseImage:       "base-image.iso",
+					OutputImageName: "/tmp/evil",
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'outputImageName' must be a simple filename without path components.",
+			},
+			ExpectedCodes: []failureCode{
+				{Code: CodeInvalid, Field: "image.outputImageName"},
+			},
+		},
+		`base image path traversal`: {
+			ImageDefinition: image.Definition{
+				Image: image.Image{
+					ImageType:       image.TypeISO,
+					Arch:            image.ArchTypeX86,
+					BaseImage:       "../base-images/base-image.iso",
+					OutputImageName: "eib-created.iso",
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'baseImage' must be a simple filename without path components.",
+			},
+			ExpectedCodes: []failureCode{
+				{Code: CodeInvalid, Field: "image.baseImage"},
+			},
+		},
+		`absolute base image path`: {
+			ImageDefinition: image.Definition{
+				Image: image.Image{
+					ImageType:       image.TypeISO,
+					Arch:            image.ArchTypeX86,
+					BaseImage:       "/etc/passwd",
+					OutputImageName: "..",
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'outputImageName' must be a simple filename without path components.",
+				"The 'baseImage' must be a simple filename without path components.",
+			},
+			ExpectedCodes: []failureCode{
+				{Code: CodeInvalid, Field: "image.outputImageName"},
+				{Code: CodeInvalid, Field: "image.baseImage"},
 			},
 		},
 		`base image not found`: {
@@ -76,6 +158,25 @@ func TestValidateImage(t *testing.T) {
 			ExpectedFailedMessages: []string{
 				"The specified base image 'not-there' cannot be found.",
 			},
+			ExpectedCodes: []failureCode{
+				{Code: CodeNotFound, Field: "image.baseImage"},
+			},
+		},
+		`base image arch mismatch`: {
+			ImageDefinition: image.Definition{
+				Image: image.Image{
+					ImageType:       image.TypeISO,
+					Arch:            image.ArchTypeX86,
+					BaseImage:       "arm-base-image.iso",
+					OutputImageName: "eib-created.iso",
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The base image 'arm-base-image.iso' is built for the 'aarch64' architecture, which does not match the 'x86_64' 'arch' field in the 'image' section.",
+			},
+			ExpectedCodes: []failureCode{
+				{Code: CodeMismatch, Field: "image.baseImage"},
+			},
 		},
 	}
 
@@ -97,6 +198,150 @@ func TestValidateImage(t *testing.T) {
 			for _, expectedMessage := range test.ExpectedFailedMessages {
 				assert.Contains(t, foundMessages, expectedMessage)
 			}
+
+			assert.ElementsMatch(t, test.ExpectedCodes, failureCodes(failedValidations))
+		})
+	}
+}
+
+func TestImageTypeForExtension(t *testing.T) {
+	assert.Equal(t, image.TypeISO, imageTypeForExtension("SL-Micro.x86_64-6.0-Default-SelfInstall-GM.install.iso"))
+	assert.Equal(t, image.TypeISO, imageTypeForExtension("eib-image.ISO"))
+	assert.Equal(t, image.TypeRAW, imageTypeForExtension("SL-Micro.x86_64-6.0-Base-GM.raw"))
+	assert.Equal(t, image.TypeRAW, imageTypeForExtension("eib-image.img"))
+	assert.Equal(t, "", imageTypeForExtension("eib-image.qcow2"))
+	assert.Equal(t, "", imageTypeForExtension("eib-image"))
+	assert.Equal(t, "", imageTypeForExtension(""))
+}
+
+func TestIsSimpleFilename(t *testing.T) {
+	assert.True(t, isSimpleFilename("eib-image.iso"))
+	assert.True(t, isSimpleFilename("..eib-image.iso"))
+	assert.False(t, isSimpleFilename("images/eib-image.iso"))
+	assert.False(t, isSimpleFilename("../eib-image.iso"))
+	assert.False(t, isSimpleFilename("/tmp/eib-image.iso"))
+	assert.False(t, isSimpleFilename("eib-image.iso/"))
+	assert.False(t, isSimpleFilename(".."))
+	assert.False(t, isSimpleFilename("."))
+}
+
+func TestValidateOutputPermissions(t *testing.T) {
+	tests := map[string]struct {
+		OutputPermissions  string
+		ExpectedMessage    string
+		ExpectedSeverity   Severity
+		ExpectedCode       string
+		ExpectedNoFailures bool
+	}{
+		`not set`: {
+			ExpectedNoFailures: true,
+		},
+		`valid`: {
+			OutputPermissions:  "0644",
+			ExpectedNoFailures: true,
+		},
+		`valid without leading zero`: {
+			OutputPermissions:  "755",
+			ExpectedNoFailures: true,
+		},
+		`not octal`: {
+			OutputPermissions: "0648",
+			ExpectedMessage:   "The 'outputPermissions' field must be an octal file mode between '0000' and '0777' (e.g. '0644').",
+			ExpectedCode:      CodeInvalid,
+		},
+		`special bits`: {
+			OutputPermissions: "4755",
+			ExpectedMessage:   "The 'outputPermissions' field must be an octal file mode between '0000' and '0777' (e.g. '0644').",
+			ExpectedCode:      CodeInvalid,
+		},
+		`not readable by owner`: {
+			OutputPermissions: "0044",
+			ExpectedMessage:   "The 'outputPermissions' field must allow the owner to read the image.",
+			ExpectedCode:      CodeInvalid,
+		},
+		`writable by all users`: {
+			OutputPermissions: "0666",
+			ExpectedMessage:   "The 'outputPermissions' field allows all users to modify the image.",
+			ExpectedSeverity:  SeverityWarning,
+			ExpectedCode:      CodeInsecure,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			img := image.Image{
+				OutputPermissions: test.OutputPermissions,
+			}
+			failures := validateOutputPermissions(&img)
+
+			if test.ExpectedNoFailures {
+				assert.Empty(t, failures)
+				return
+			}
+
+			require.Len(t, failures, 1)
+			assert.Equal(t, test.ExpectedMessage, failures[0].UserMessage)
+			assert.Equal(t, test.ExpectedSeverity, failures[0].Severity)
+			assert.Equal(t, test.ExpectedCode, failures[0].Code)
+			assert.Equal(t, "image.outputPermissions", failures[0].Field)
+		})
+	}
+}
+
+func TestDetectBaseImageArch(t *testing.T) {
+	baseImagesDir := t.TempDir()
+
+	tests := map[string]struct {
+		filename     string
+		contents     string
+		expectedArch image.Arch
+	}{
+		`x86_64 file name`: {
+			filename:     "SL-Micro.x86_64-6.0-Base-GM.raw",
+			expectedArch: image.ArchTypeX86,
+		},
+		`aarch64 file name`: {
+			filename:     "SL-Micro.aarch64-6.0-Base-GM.raw",
+			expectedArch: image.ArchTypeARM,
+		},
+		`x86_64 ISO bootloader`: {
+			filename:     "base.iso",
+			contents:     "CD001 efi/boot/bootx64.efi",
+			expectedArch: image.ArchTypeX86,
+		},
+		`aarch64 EFI partition bootloader`: {
+			filename:     "base.raw",
+			contents:     "EFI        BOOTAA64EFI",
+			expectedArch: image.ArchTypeARM,
+		},
+		`bootloader spanning chunks`: {
+			filename:     "base.raw",
+			contents:     strings.Repeat("\x00", baseImageScanChunkSize+7) + "BOOTAA64EFI",
+			expectedArch: image.ArchTypeARM,
+		},
+		`bootloader after first chunk`: {
+			filename:     "base.iso",
+			contents:     strings.Repeat("\x00", 3*baseImageScanChunkSize) + "efi/boot/bootx64.efi",
+			expectedArch: image.ArchTypeX86,
+		},
+		`unknown`: {
+			filename: "base.raw",
+			contents: "no bootloader",
+		},
+		`ambiguous`: {
+			filename: "base.iso",
+			contents: "BOOTX64.EFI BOOTAA64.EFI",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			baseImageFilename := filepath.Join(baseImagesDir, test.filename)
+			require.NoError(t, os.WriteFile(baseImageFilename, []byte(test.contents), 0o600))
+
+			arch, err := detectBaseImageArch(baseImageFilename)
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedArch, arch)
 		})
 	}
 }
@@ -1,16 +1,26 @@
 package validation
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"net"
+	"net/netip"
 	"net/url"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
 	"slices"
 	"strings"
 
 	"github.com/suse-edge/edge-image-builder/pkg/combustion"
+	"github.com/suse-edge/edge-image-builder/pkg/helm"
+	"github.com/suse-edge/edge-image-builder/pkg/http"
+	"github.com/suse-edge/edge-image-builder/pkg/kubernetes"
+	"github.com/suse-edge/edge-image-builder/pkg/registry"
 	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
 
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 )
@@ -20,9 +30,47 @@ const (
 	httpScheme   = "http"
 	httpsScheme  = "https"
 	ociScheme    = "oci"
+
+	// helmControllerNamespace is the namespace watched by the Helm controller of K3s and RKE2 by default.
+	helmControllerNamespace = "kube-system"
+
+	// defaultIsoMaxSizeMB is the capacity of a single layer DVD, used unless 'isoConfiguration/maxSize' is set.
+	defaultIsoMaxSizeMB = 4482
+
+	// rke2ArtefactsSizeMB and k3sArtefactsSizeMB are rough estimates of the installer and air-gap image
+	// artefacts downloaded for the respective Kubernetes distribution.
+	rke2ArtefactsSizeMB = 1024
+	k3sArtefactsSizeMB  = 256
 )
 
-var validNodeTypes = []string{image.KubernetesNodeTypeServer, image.KubernetesNodeTypeAgent}
+var (
+	validNodeTypes = []string{image.KubernetesNodeTypeServer, image.KubernetesNodeTypeAgent}
+	envVarRegex    = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+	// componentDisplayNames are the names of the components whose Helm charts are automatically added by EIB.
+	componentDisplayNames = map[string]string{
+		"metallb":                  "MetalLB",
+		"endpoint-copier-operator": "Endpoint Copier Operator",
+	}
+
+	// k3sVersionRegex and rke2VersionRegex match the upstream release versions (e.g. 'v1.30.3+k3s1' and 'v1.30.3+rke2r1').
+	k3sVersionRegex  = regexp.MustCompile(`^v\d+\.\d+\.\d+\+k3s\d+$`)
+	rke2VersionRegex = regexp.MustCompile(`^v\d+\.\d+\.\d+\+rke2r\d+$`)
+
+	// semverRegex matches semantic versions as used by Helm charts (e.g. '0.14.3' or '0.14.3-patched.1').
+	semverRegex = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(-[0-9A-Za-z.-]+)?(\+[0-9A-Za-z.-]+)?$`)
+
+	// helmRepositoryChecker verifies that a Helm repository can be accessed during the online preflight.
+	helmRepositoryChecker = helm.CheckRepository
+
+	// isoPayloadSizeEstimator estimates the size (in MB) of the ISO image, including the Kubernetes artefacts.
+	isoPayloadSizeEstimator = estimateIsoPayloadSize
+
+	// newHelmClient creates the client used to template the Helm charts when rendering is requested.
+	newHelmClient = func(client *http.Client, outputDir, certsDir string) image.HelmClient {
+		return helm.New(context.Background(), client, outputDir, certsDir, 0)
+	}
+)
 
 func validateKubernetes(ctx *image.Context) []FailedValidation {
 	def := ctx.ImageDefinition
@@ -33,9 +81,30 @@ func validateKubernetes(ctx *image.Context) []FailedValidation {
 		return failures
 	}
 
+	failures = append(failures, validateKubernetesVersion(&def.Kubernetes)...)
+	failures = append(failures, validateKubernetesVersionFormat(&def.Kubernetes)...)
+	failures = append(failures, validateReleaseURL(&def.Kubernetes)...)
+	failures = append(failures, validateSELinuxSigningKey(ctx)...)
+	failures = append(failures, validateAPIHostTLSSAN(ctx)...)
+	failures = append(failures, validateAdditionalSANs(&def.Kubernetes)...)
+	failures = append(failures, validateLoadBalancerPool(&def.Kubernetes)...)
+	failures = append(failures, validateComponentChartVersions(&def.Kubernetes)...)
+	failures = append(failures, validateNodeIP(ctx)...)
 	failures = append(failures, validateNodes(&def.Kubernetes)...)
 	failures = append(failures, validateManifestURLs(&def.Kubernetes)...)
+	failures = append(failures, validateManifestCredentials(&def.Kubernetes)...)
 	failures = append(failures, validateHelm(&def.Kubernetes, ctx.ImageConfigDir)...)
+	failures = append(failures, validateComponentHelmCharts(ctx)...)
+	failures = append(failures, validateComponentHelmRepositories(ctx)...)
+	failures = append(failures, validateIsoPayloadSize(ctx)...)
+
+	if ctx.Preflight && !ctx.HTTPClient.Offline() {
+		failures = append(failures, validateHelmRepositoriesReachable(ctx.HTTPClient, &def.Kubernetes, ctx.ImageConfigDir)...)
+	}
+
+	if ctx.RenderHelmCharts && !ctx.HTTPClient.Offline() {
+		failures = append(failures, validateHelmChartsRender(ctx.HTTPClient, &def.Kubernetes, ctx.ImageConfigDir)...)
+	}
 
 	return failures
 }
@@ -44,6 +113,365 @@ func isKubernetesDefined(k8s *image.Kubernetes) bool {
 	return k8s.Version != ""
 }
 
+func validateKubernetesVersion(k8s *image.Kubernetes) []FailedValidation {
+	var failures []FailedValidation
+
+	if !strings.Contains(k8s.Version, image.KubernetesDistroK3S) && !strings.Contains(k8s.Version, image.KubernetesDistroRKE2) {
+		msg := fmt.Sprintf("The kubernetes 'version' '%s' must contain a recognized distribution identifier (%s or %s).",
+			k8s.Version, image.KubernetesDistroK3S, image.KubernetesDistroRKE2)
+		failures = append(failures, FailedValidation{
+			UserMessage: msg,
+			Code:        CodeInvalid,
+			Field:       "kubernetes.version",
+		})
+	}
+
+	return failures
+}
+
+func validateKubernetesVersionFormat(k8s *image.Kubernetes) []FailedValidation {
+	var versionRegex *regexp.Regexp
+	var distro, example string
+
+	switch {
+	case strings.Contains(k8s.Version, image.KubernetesDistroRKE2):
+		versionRegex, distro, example = rke2VersionRegex, image.KubernetesDistroRKE2, "v1.30.3+rke2r1"
+	case strings.Contains(k8s.Version, image.KubernetesDistroK3S):
+		versionRegex, distro, example = k3sVersionRegex, image.KubernetesDistroK3S, "v1.30.3+k3s1"
+	default:
+		// Reported by the distribution identifier check
+		return nil
+	}
+
+	if versionRegex.MatchString(k8s.Version) {
+		return nil
+	}
+
+	msg := fmt.Sprintf("The kubernetes 'version' '%s' does not match the %s release format (e.g. '%s').", k8s.Version, distro, example)
+	return []FailedValidation{
+		{
+			UserMessage: msg,
+			Code:        CodeInvalid,
+			Field:       "kubernetes.version",
+		},
+	}
+}
+
+func validateReleaseURL(k8s *image.Kubernetes) []FailedValidation {
+	if k8s.ReleaseURL == "" {
+		return nil
+	}
+
+	parsedURL, err := url.Parse(k8s.ReleaseURL)
+	if err != nil || (parsedURL.Scheme != httpScheme && parsedURL.Scheme != httpsScheme) || parsedURL.Host == "" {
+		msg := fmt.Sprintf("The kubernetes 'releaseURL' '%s' must be a valid URL beginning with either 'http://' or 'https://'.", k8s.ReleaseURL)
+		return []FailedValidation{
+			{
+				UserMessage: msg,
+				Code:        CodeInvalid,
+				Field:       "kubernetes.releaseURL",
+			},
+		}
+	}
+
+	return nil
+}
+
+func validateSELinuxSigningKey(ctx *image.Context) []FailedValidation {
+	signingKey := ctx.ImageDefinition.Kubernetes.SELinuxSigningKey
+
+	if !kubernetes.IsRemoteSELinuxSigningKey(signingKey) {
+		if filepath.IsAbs(signingKey) {
+			msg := fmt.Sprintf("The kubernetes 'selinuxSigningKey' path '%s' must be relative to the image configuration directory.", signingKey)
+			return []FailedValidation{
+				{
+					UserMessage: msg,
+					Code:        CodeInvalid,
+					Field:       "kubernetes.selinuxSigningKey",
+				},
+			}
+		}
+
+		keyPath := filepath.Join(ctx.ImageConfigDir, signingKey)
+		if info, err := os.Stat(keyPath); err != nil || info.IsDir() {
+			msg := fmt.Sprintf("The kubernetes 'selinuxSigningKey' file '%s' could not be found in the image configuration directory.", signingKey)
+			return []FailedValidation{
+				{
+					UserMessage: msg,
+					Error:       err,
+					Code:        CodeNotFound,
+					Field:       "kubernetes.selinuxSigningKey",
+				},
+			}
+		}
+
+		return nil
+	}
+
+	if signingKey != "" {
+		if parsedURL, err := url.Parse(signingKey); err != nil || parsedURL.Host == "" {
+			msg := fmt.Sprintf("The kubernetes 'selinuxSigningKey' URL '%s' could not be parsed.", signingKey)
+			return []FailedValidation{
+				{
+					UserMessage: msg,
+					Code:        CodeInvalid,
+					Field:       "kubernetes.selinuxSigningKey",
+				},
+			}
+		}
+	}
+
+	if !ctx.HTTPClient.Offline() || ctx.ImageDefinition.Kubernetes.SkipSELinuxPackages {
+		return nil
+	}
+
+	config, err := kubernetes.ParseKubernetesConfig(combustion.KubernetesConfigPath(ctx))
+	if err != nil {
+		zap.S().Warnf("Parsing the Kubernetes server config failed: %s", err)
+		return nil
+	}
+
+	if selinuxEnabled, _ := config["selinux"].(bool); !selinuxEnabled {
+		return nil
+	}
+
+	msg := "SELinux is enabled in the Kubernetes server config but the signing key of the SELinux RPMs cannot be downloaded " +
+		"in offline mode. Please provide the key locally using the kubernetes 'selinuxSigningKey' field."
+	return []FailedValidation{
+		{
+			UserMessage: msg,
+			Code:        CodeRequired,
+			Field:       "kubernetes.selinuxSigningKey",
+		},
+	}
+}
+
+func validateAPIHostTLSSAN(ctx *image.Context) []FailedValidation {
+	apiHost := ctx.ImageDefinition.Kubernetes.Network.APIHost
+	if apiHost == "" {
+		return nil
+	}
+
+	config, err := kubernetes.ParseKubernetesConfig(combustion.KubernetesConfigPath(ctx))
+	if err != nil {
+		zap.S().Warnf("Parsing the Kubernetes server config failed: %s", err)
+		return nil
+	}
+
+	if _, ok := config["tls-san"]; !ok {
+		return nil
+	}
+
+	if slices.Contains(kubernetes.TLSSANs(config), apiHost) {
+		return nil
+	}
+
+	msg := fmt.Sprintf("The 'apiHost' '%s' is not present in the server config 'tls-san' list.", apiHost)
+	return []FailedValidation{
+		{
+			UserMessage: msg,
+			Severity:    SeverityWarning,
+			Code:        CodeMismatch,
+			Field:       "kubernetes.network.apiHost",
+		},
+	}
+}
+
+func validateAdditionalSANs(k8s *image.Kubernetes) []FailedValidation {
+	var failures []FailedValidation
+
+	for i, san := range k8s.Network.AdditionalSANs {
+		if net.ParseIP(san) != nil || isValidHostname(san) {
+			continue
+		}
+
+		msg := fmt.Sprintf("The 'additionalSANs' entry '%s' in the 'network' section must be a valid hostname or IP address.", san)
+		failures = append(failures, FailedValidation{
+			UserMessage: msg,
+			Code:        CodeInvalid,
+			Field:       fmt.Sprintf("kubernetes.network.additionalSANs[%d]", i),
+		})
+	}
+
+	return failures
+}
+
+func validateLoadBalancerPool(k8s *image.Kubernetes) []FailedValidation {
+	network := &k8s.Network
+	if len(network.LoadBalancerPool) == 0 {
+		return nil
+	}
+
+	if network.APIVIP == "" || !network.IsVIPManaged() {
+		return []FailedValidation{
+			{
+				UserMessage: "The 'loadBalancerPool' field in the 'network' section can only be used when MetalLB is deployed for the 'apiVIP'.",
+				Code:        CodeConflict,
+				Field:       "kubernetes.network.loadBalancerPool",
+			},
+		}
+	}
+
+	var failures []FailedValidation
+
+	for i, entry := range network.LoadBalancerPool {
+		if _, err := addressPoolContains(entry, netip.Addr{}); err != nil {
+			msg := fmt.Sprintf("The 'loadBalancerPool' entry '%s' in the 'network' section must be a valid CIDR or IP address range "+
+				"(e.g. '192.168.122.100-192.168.122.150').", entry)
+			failures = append(failures, FailedValidation{
+				UserMessage: msg,
+				Error:       err,
+				Code:        CodeInvalid,
+				Field:       fmt.Sprintf("kubernetes.network.loadBalancerPool[%d]", i),
+			})
+		}
+	}
+
+	if len(failures) == 0 {
+		failures = append(failures, validateAPIVIPInLoadBalancerPool(network)...)
+	}
+
+	return failures
+}
+
+// validateComponentChartVersions checks the versions overriding the ones of the Helm charts
+// automatically deployed to serve the apiVIP.
+func validateComponentChartVersions(k8s *image.Kubernetes) []FailedValidation {
+	var failures []FailedValidation
+
+	versions := []struct {
+		field   string
+		version string
+	}{
+		{field: "metalLBVersion", version: k8s.Network.MetalLBVersion},
+		{field: "endpointCopierVersion", version: k8s.Network.EndpointCopierVersion},
+	}
+
+	for _, v := range versions {
+		if v.version == "" {
+			continue
+		}
+
+		if !semverRegex.MatchString(v.version) {
+			msg := fmt.Sprintf("The 'network/%s' field must be a valid semantic version (e.g. 0.14.3).", v.field)
+			failures = append(failures, FailedValidation{
+				UserMessage: msg,
+				Code:        CodeInvalid,
+				Field:       "kubernetes.network." + v.field,
+			})
+		}
+
+		if k8s.Network.APIVIP == "" || !k8s.Network.IsVIPManaged() {
+			msg := fmt.Sprintf("The 'network/%s' field has no effect unless the 'apiVIP' is set and 'manageVIP' is not disabled.", v.field)
+			failures = append(failures, FailedValidation{
+				UserMessage: msg,
+				Severity:    SeverityWarning,
+				Code:        CodeUnreferenced,
+				Field:       "kubernetes.network." + v.field,
+			})
+		}
+	}
+
+	return failures
+}
+
+// validateAPIVIPInLoadBalancerPool checks that the apiVIP is part of the configured pool,
+// as MetalLB can only advertise the addresses it owns.
+func validateAPIVIPInLoadBalancerPool(network *image.Network) []FailedValidation {
+	apiVIP, err := netip.ParseAddr(network.APIVIP)
+	if err != nil {
+		zap.S().Warnf("Parsing the apiVIP '%s' failed: %s", network.APIVIP, err)
+		return nil
+	}
+
+	for _, entry := range network.LoadBalancerPool {
+		if contains, _ := addressPoolContains(entry, apiVIP); contains {
+			return nil
+		}
+	}
+
+	msg := fmt.Sprintf("The 'apiVIP' %s is not contained in any configured LoadBalancerPool range.", network.APIVIP)
+	return []FailedValidation{
+		{
+			UserMessage: msg,
+			Code:        CodeMismatch,
+			Field:       "kubernetes.network.apiVIP",
+		},
+	}
+}
+
+// addressPoolContains reports whether the given address is part of the MetalLB address pool entry,
+// specified either as a CIDR or as an inclusive range of IP addresses.
+func addressPoolContains(entry string, addr netip.Addr) (bool, error) {
+	if strings.Contains(entry, "/") {
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			return false, err
+		}
+
+		return prefix.Contains(addr), nil
+	}
+
+	first, last, found := strings.Cut(entry, "-")
+	if !found {
+		return false, errors.New("neither a CIDR nor an IP address range")
+	}
+
+	start, err := netip.ParseAddr(strings.TrimSpace(first))
+	if err != nil {
+		return false, err
+	}
+
+	end, err := netip.ParseAddr(strings.TrimSpace(last))
+	if err != nil {
+		return false, err
+	}
+
+	if start.Is4() != end.Is4() || start.Compare(end) > 0 {
+		return false, fmt.Errorf("invalid range between %s and %s", start, end)
+	}
+
+	return start.Is4() == addr.Is4() && start.Compare(addr) <= 0 && end.Compare(addr) >= 0, nil
+}
+
+func validateNodeIP(ctx *image.Context) []FailedValidation {
+	k8s := &ctx.ImageDefinition.Kubernetes
+
+	config, err := kubernetes.ParseKubernetesConfig(combustion.KubernetesConfigPath(ctx))
+	if err != nil {
+		zap.S().Warnf("Parsing the Kubernetes server config failed: %s", err)
+		return nil
+	}
+
+	nodeIPs := kubernetes.NodeIPs(config)
+	if len(nodeIPs) == 0 {
+		return nil
+	}
+
+	configField := configRelativePath(ctx, combustion.KubernetesConfigPath(ctx))
+
+	var failures []FailedValidation
+
+	if kubernetes.ServersCount(k8s.Nodes) > 1 {
+		failures = append(failures, FailedValidation{
+			UserMessage: "The server config 'node-ip' field cannot be set when defining multiple server nodes, as it would be shared between all of them.",
+			Code:        CodeConflict,
+			Field:       configField,
+		})
+	}
+
+	if k8s.Network.APIVIP != "" && slices.Contains(nodeIPs, k8s.Network.APIVIP) {
+		msg := fmt.Sprintf("The server config 'node-ip' must not be the same as the 'apiVIP' '%s'.", k8s.Network.APIVIP)
+		failures = append(failures, FailedValidation{
+			UserMessage: msg,
+			Code:        CodeConflict,
+			Field:       configField,
+		})
+	}
+
+	return failures
+}
+
 func validateNodes(k8s *image.Kubernetes) []FailedValidation {
 	var failures []FailedValidation
 
@@ -56,6 +484,8 @@ func validateNodes(k8s *image.Kubernetes) []FailedValidation {
 	if k8s.Network.APIVIP == "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: "The 'apiVIP' field is required in the 'network' section when defining entries under 'nodes'.",
+			Code:        CodeRequired,
+			Field:       "kubernetes.network.apiVIP",
 		})
 	}
 
@@ -63,10 +493,14 @@ func validateNodes(k8s *image.Kubernetes) []FailedValidation {
 	var nodeNames []string
 	var initialisers []*image.Node
 
-	for _, node := range k8s.Nodes {
+	for i, node := range k8s.Nodes {
+		field := fmt.Sprintf("kubernetes.nodes[%d]", i)
+
 		if node.Hostname == "" {
 			failures = append(failures, FailedValidation{
 				UserMessage: "The 'hostname' field is required for entries in the 'nodes' section.",
+				Code:        CodeRequired,
+				Field:       field + ".hostname",
 			})
 		}
 
@@ -75,6 +509,8 @@ func validateNodes(k8s *image.Kubernetes) []FailedValidation {
 			msg := fmt.Sprintf("The 'type' field for entries in the 'nodes' section must be one of: %s", options)
 			failures = append(failures, FailedValidation{
 				UserMessage: msg,
+				Code:        CodeUnsupported,
+				Field:       field + ".type",
 			})
 		}
 
@@ -86,6 +522,8 @@ func validateNodes(k8s *image.Kubernetes) []FailedValidation {
 				msg := fmt.Sprintf("The node labeled with 'initialiser' must be of type '%s'.", image.KubernetesNodeTypeServer)
 				failures = append(failures, FailedValidation{
 					UserMessage: msg,
+					Code:        CodeConflict,
+					Field:       field + ".initializer",
 				})
 			}
 		}
@@ -99,6 +537,8 @@ func validateNodes(k8s *image.Kubernetes) []FailedValidation {
 		msg := fmt.Sprintf("The 'nodes' section contains duplicate entries: %s", duplicateValues)
 		failures = append(failures, FailedValidation{
 			UserMessage: msg,
+			Code:        CodeDuplicate,
+			Field:       "kubernetes.nodes",
 		})
 	}
 
@@ -106,12 +546,35 @@ func validateNodes(k8s *image.Kubernetes) []FailedValidation {
 		msg := fmt.Sprintf("There must be at least one node of type '%s' defined.", image.KubernetesNodeTypeServer)
 		failures = append(failures, FailedValidation{
 			UserMessage: msg,
+			Code:        CodeRequired,
+			Field:       "kubernetes.nodes",
 		})
 	}
 
 	if len(initialisers) > 1 {
 		failures = append(failures, FailedValidation{
 			UserMessage: "Only one node may be specified as the cluster initializer.",
+			Code:        CodeConflict,
+			Field:       "kubernetes.nodes",
+		})
+	}
+
+	// The embedded etcd tolerates the failure of a minority of the server nodes only,
+	// so an additional server which makes their number even does not improve availability.
+	var numServers int
+	for _, nodeType := range nodeTypes {
+		if nodeType == image.KubernetesNodeTypeServer {
+			numServers++
+		}
+	}
+
+	if numServers > 1 && numServers%2 == 0 {
+		msg := fmt.Sprintf("An even number of server nodes (%d) is not recommended for etcd quorum.", numServers)
+		failures = append(failures, FailedValidation{
+			UserMessage: msg,
+			Severity:    SeverityWarning,
+			Code:        CodeInvalid,
+			Field:       "kubernetes.nodes",
 		})
 	}
 
@@ -126,10 +589,14 @@ func validateManifestURLs(k8s *image.Kubernetes) []FailedValidation {
 	}
 
 	seenManifests := make(map[string]bool)
-	for _, manifest := range k8s.Manifests.URLs {
+	for i, manifest := range k8s.Manifests.URLs {
+		field := fmt.Sprintf("kubernetes.manifests.urls[%d]", i)
+
 		if !strings.HasPrefix(manifest, "http") {
 			failures = append(failures, FailedValidation{
 				UserMessage: "Entries in 'urls' must begin with either 'http://' or 'https://'.",
+				Code:        CodeInvalid,
+				Field:       field,
 			})
 		}
 
@@ -137,6 +604,8 @@ func validateManifestURLs(k8s *image.Kubernetes) []FailedValidation {
 			msg := fmt.Sprintf("The 'urls' field contains duplicate entries: %s", manifest)
 			failures = append(failures, FailedValidation{
 				UserMessage: msg,
+				Code:        CodeDuplicate,
+				Field:       field,
 			})
 		}
 
@@ -146,6 +615,94 @@ func validateManifestURLs(k8s *image.Kubernetes) []FailedValidation {
 	return failures
 }
 
+func validateManifestCredentials(k8s *image.Kubernetes) []FailedValidation {
+	var failures []FailedValidation
+
+	for i, credentials := range k8s.Manifests.Credentials {
+		field := fmt.Sprintf("kubernetes.manifests.credentials[%d]", i)
+
+		if credentials.URLPrefix == "" {
+			failures = append(failures, FailedValidation{
+				UserMessage: "The 'urlPrefix' field is required for each entry in 'credentials'.",
+				Code:        CodeRequired,
+				Field:       field + ".urlPrefix",
+			})
+			continue
+		}
+
+		for header := range credentials.Headers {
+			if strings.EqualFold(header, "Authorization") {
+				msg := fmt.Sprintf("Manifest credentials for '%s' must not define an 'Authorization' header; "+
+					"use 'tokenEnv' or 'username' and 'passwordEnv' instead.", credentials.URLPrefix)
+				failures = append(failures, FailedValidation{
+					UserMessage: msg,
+					Code:        CodeInsecure,
+					Field:       field + ".headers",
+				})
+			}
+		}
+
+		if credentials.TokenEnv != "" && credentials.Username != "" {
+			msg := fmt.Sprintf("Manifest credentials for '%s' cannot define both 'tokenEnv' and 'username'.", credentials.URLPrefix)
+			failures = append(failures, FailedValidation{
+				UserMessage: msg,
+				Code:        CodeConflict,
+				Field:       field + ".tokenEnv",
+			})
+		}
+
+		if (credentials.Username == "") != (credentials.PasswordEnv == "") {
+			missingField := field + ".passwordEnv"
+			if credentials.Username == "" {
+				missingField = field + ".username"
+			}
+
+			msg := fmt.Sprintf("Manifest credentials for '%s' must define both 'username' and 'passwordEnv'.", credentials.URLPrefix)
+			failures = append(failures, FailedValidation{
+				UserMessage: msg,
+				Code:        CodeRequired,
+				Field:       missingField,
+			})
+		}
+
+		failures = append(failures, validateCredentialsEnv(field, "tokenEnv", credentials.TokenEnv, credentials.URLPrefix)...)
+		failures = append(failures, validateCredentialsEnv(field, "passwordEnv", credentials.PasswordEnv, credentials.URLPrefix)...)
+	}
+
+	return failures
+}
+
+func validateCredentialsEnv(credentialsField, field, envVar, urlPrefix string) []FailedValidation {
+	if envVar == "" {
+		return nil
+	}
+
+	if !envVarRegex.MatchString(envVar) {
+		msg := fmt.Sprintf("The '%s' field in the manifest credentials for '%s' must be the name of an environment variable; "+
+			"secrets must not be stored in the definition.", field, urlPrefix)
+		return []FailedValidation{
+			{
+				UserMessage: msg,
+				Code:        CodeInvalid,
+				Field:       credentialsField + "." + field,
+			},
+		}
+	}
+
+	if os.Getenv(envVar) == "" {
+		msg := fmt.Sprintf("The environment variable '%s' referenced in the manifest credentials for '%s' is not set.", envVar, urlPrefix)
+		return []FailedValidation{
+			{
+				UserMessage: msg,
+				Code:        CodeNotFound,
+				Field:       credentialsField + "." + field,
+			},
+		}
+	}
+
+	return nil
+}
+
 func validateHelm(k8s *image.Kubernetes, imageConfigDir string) []FailedValidation {
 	var failures []FailedValidation
 
@@ -156,6 +713,8 @@ func validateHelm(k8s *image.Kubernetes, imageConfigDir string) []FailedValidati
 	if len(k8s.Helm.Repositories) == 0 {
 		failures = append(failures, FailedValidation{
 			UserMessage: "Helm charts defined with no Helm repositories defined.",
+			Code:        CodeRequired,
+			Field:       "kubernetes.helm.repositories",
 		})
 
 		return failures
@@ -169,66 +728,315 @@ func validateHelm(k8s *image.Kubernetes, imageConfigDir string) []FailedValidati
 	if failure := validateHelmChartDuplicates(k8s.Helm.Charts); failure != "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: failure,
+			Code:        CodeDuplicate,
+			Field:       "kubernetes.helm.charts",
 		})
 	}
 
 	seenHelmRepos := make(map[string]bool)
-	for _, chart := range k8s.Helm.Charts {
+	for i, chart := range k8s.Helm.Charts {
 		c := chart
-		failures = append(failures, validateChart(&c, helmRepositoryNames, imageConfigDir)...)
+		field := fmt.Sprintf("kubernetes.helm.charts[%d]", i)
+		failures = append(failures, validateChart(&c, field, helmRepositoryNames, imageConfigDir)...)
 
 		seenHelmRepos[chart.RepositoryName] = true
 	}
 
-	for _, repo := range k8s.Helm.Repositories {
+	for i, repo := range k8s.Helm.Repositories {
 		r := repo
-		failures = append(failures, validateRepo(&r, seenHelmRepos, imageConfigDir)...)
+		field := fmt.Sprintf("kubernetes.helm.repositories[%d]", i)
+		failures = append(failures, validateRepo(&r, field, seenHelmRepos, imageConfigDir)...)
 	}
 
+	failures = append(failures, validateHelmUnreferencedFiles(k8s, imageConfigDir)...)
+
 	return failures
 }
 
-func validateChart(chart *image.HelmChart, repositoryNames []string, imageConfigDir string) []FailedValidation {
+// validateComponentHelmCharts checks that the user defined Helm charts and repositories do not collide
+// with the ones automatically added by EIB (e.g. MetalLB when the 'apiVIP' is set), as the
+// components are only injected after the definition has been validated.
+func validateComponentHelmCharts(ctx *image.Context) []FailedValidation {
+	componentCharts, componentRepos := combustion.ComponentHelmCharts(ctx)
+	helmConfig := ctx.ImageDefinition.Kubernetes.Helm
+
+	var failures []FailedValidation
+
+	for _, componentChart := range componentCharts {
+		for i, chart := range helmConfig.Charts {
+			if chart.Name != componentChart.Name {
+				continue
+			}
+
+			msg := fmt.Sprintf("Helm chart '%s' conflicts with the chart of the same name automatically deployed when the 'apiVIP' is set. "+
+				"Either remove the chart or set 'manageVIP' to false in the 'network' section.", chart.Name)
+			failures = append(failures, FailedValidation{
+				UserMessage: msg,
+				Code:        CodeConflict,
+				Field:       fmt.Sprintf("kubernetes.helm.charts[%d].name", i),
+			})
+		}
+	}
+
+	for _, componentRepo := range componentRepos {
+		for i, repo := range helmConfig.Repositories {
+			if repo.Name != componentRepo.Name || repo.URL == componentRepo.URL {
+				continue
+			}
+
+			msg := fmt.Sprintf("Helm repository '%s' conflicts with the repository of the same name automatically added when the 'apiVIP' is set. "+
+				"Either rename the repository or use the '%s' URL.", repo.Name, componentRepo.URL)
+			failures = append(failures, FailedValidation{
+				UserMessage: msg,
+				Code:        CodeConflict,
+				Field:       fmt.Sprintf("kubernetes.helm.repositories[%d].name", i),
+			})
+		}
+	}
+
+	return failures
+}
+
+// validateComponentHelmRepositories checks that the artifact source repositories of the automatically added
+// Helm charts are configured with valid URLs, as these are set when EIB is built rather than in the definition.
+func validateComponentHelmRepositories(ctx *image.Context) []FailedValidation {
+	componentCharts, componentRepos := combustion.ComponentHelmCharts(ctx)
+
+	repositoryURLs := map[string]string{}
+	for _, repo := range componentRepos {
+		repositoryURLs[repo.Name] = repo.URL
+	}
+
+	var failures []FailedValidation
+
+	for _, chart := range componentCharts {
+		component := componentDisplayNames[chart.Name]
+		if component == "" {
+			component = chart.Name
+		}
+
+		repoURL := repositoryURLs[chart.RepositoryName]
+		if repoURL == "" {
+			failures = append(failures, FailedValidation{
+				UserMessage: fmt.Sprintf("Artifact source repository for %s is not configured.", component),
+				Code:        CodeNotFound,
+			})
+			continue
+		}
+
+		parsedURL, err := url.Parse(repoURL)
+		if err != nil || parsedURL.Host == "" || !slices.Contains([]string{httpScheme, httpsScheme, ociScheme}, parsedURL.Scheme) {
+			msg := fmt.Sprintf("Artifact source repository '%s' for %s is not a valid URL.", repoURL, component)
+			failures = append(failures, FailedValidation{
+				UserMessage: msg,
+				Error:       err,
+				Code:        CodeInvalid,
+			})
+		}
+	}
+
+	return failures
+}
+
+// validateIsoPayloadSize warns if the ISO image is likely to exceed the capacity of the target media
+// once the Kubernetes artefacts and the embedded artifact registry are added to it.
+func validateIsoPayloadSize(ctx *image.Context) []FailedValidation {
+	var failures []FailedValidation
+
+	def := ctx.ImageDefinition
+	if def.Image.ImageType != image.TypeISO {
+		return failures
+	}
+
+	maxSize := int64(defaultIsoMaxSizeMB)
+	if def.OperatingSystem.IsoConfiguration.MaxSize.IsValid() {
+		maxSize = def.OperatingSystem.IsoConfiguration.MaxSize.ToMB()
+	}
+
+	estimatedSize, err := isoPayloadSizeEstimator(ctx)
+	if err != nil {
+		zap.S().Warnf("Estimating the ISO image size failed: %s", err)
+		return failures
+	}
+
+	if estimatedSize > maxSize {
+		msg := fmt.Sprintf("The estimated size of the ISO image including the Kubernetes artefacts and the embedded artifact registry "+
+			"(%d MB) exceeds the %d MB capacity of the target media. Consider building a RAW image or setting 'isoConfiguration/maxSize'.",
+			estimatedSize, maxSize)
+		failures = append(failures, FailedValidation{
+			UserMessage: msg,
+			Severity:    SeverityWarning,
+			Code:        CodeInsufficient,
+			Field:       "operatingSystem.isoConfiguration.maxSize",
+		})
+	}
+
+	return failures
+}
+
+// estimateIsoPayloadSize estimates the size (in MB) of the ISO image as the sum of the base image, the Kubernetes
+// artefacts of the configured distribution and the size budget of the embedded artifact registry.
+func estimateIsoPayloadSize(ctx *image.Context) (int64, error) {
+	def := ctx.ImageDefinition
+
+	baseImageFilename := filepath.Join(ctx.ImageConfigDir, "base-images", def.Image.BaseImage)
+	info, err := os.Stat(baseImageFilename)
+	if err != nil {
+		return 0, fmt.Errorf("reading base image file info: %w", err)
+	}
+
+	size := info.Size() >> 20
+
+	switch {
+	case strings.Contains(def.Kubernetes.Version, image.KubernetesDistroRKE2):
+		size += rke2ArtefactsSizeMB
+	case strings.Contains(def.Kubernetes.Version, image.KubernetesDistroK3S):
+		size += k3sArtefactsSizeMB
+	}
+
+	if def.EmbeddedArtifactRegistry.SizeBudget.IsValid() {
+		size += def.EmbeddedArtifactRegistry.SizeBudget.ToMB()
+	}
+
+	return size, nil
+}
+
+// validateHelmUnreferencedFiles warns about files in the Helm values and certs directories
+// which are not referenced by any chart or repository, as these usually indicate a typo'd reference.
+func validateHelmUnreferencedFiles(k8s *image.Kubernetes, imageConfigDir string) []FailedValidation {
+	var valuesFiles []string
+	for _, chart := range k8s.Helm.Charts {
+		valuesFiles = append(valuesFiles, chart.AllValuesFiles()...)
+	}
+
+	var certFiles []string
+	for _, repo := range k8s.Helm.Repositories {
+		certFiles = append(certFiles, repo.CAFile)
+	}
+
+	helmDir := filepath.Join(imageConfigDir, combustion.K8sDir, combustion.HelmDir)
+
+	var failures []FailedValidation
+	failures = append(failures, findUnreferencedFiles(filepath.Join(helmDir, combustion.ValuesDir), valuesFiles, "values")...)
+	failures = append(failures, findUnreferencedFiles(filepath.Join(helmDir, combustion.CertsDir), certFiles, "certs")...)
+
+	return failures
+}
+
+func findUnreferencedFiles(dir string, referencedFiles []string, dirName string) []FailedValidation {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			zap.S().Warnf("Reading the Helm %s directory failed: %s", dirName, err)
+		}
+
+		return nil
+	}
+
+	var failures []FailedValidation
+
+	for _, entry := range entries {
+		if entry.IsDir() || slices.Contains(referencedFiles, entry.Name()) {
+			continue
+		}
+
+		msg := fmt.Sprintf("Unreferenced file '%s' found in the Helm %s directory.", entry.Name(), dirName)
+		field := path.Join(combustion.K8sDir, combustion.HelmDir, filepath.Base(dir), entry.Name())
+		failures = append(failures, FailedValidation{
+			UserMessage: msg,
+			Severity:    SeverityWarning,
+			Code:        CodeUnreferenced,
+			Field:       field,
+		})
+	}
+
+	return failures
+}
+
+func validateChart(chart *image.HelmChart, field string, repositoryNames []string, imageConfigDir string) []FailedValidation {
 	var failures []FailedValidation
 
 	if chart.Name == "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: "Helm chart 'name' field must be defined.",
+			Code:        CodeRequired,
+			Field:       field + ".name",
 		})
 	}
 
 	if chart.RepositoryName == "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm chart 'repositoryName' field for %q must be defined.", chart.Name),
+			Code:        CodeRequired,
+			Field:       field + ".repositoryName",
 		})
 	} else if !slices.Contains(repositoryNames, chart.RepositoryName) {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm chart 'repositoryName' %q for Helm chart %q does not match the name of any defined repository.", chart.RepositoryName, chart.Name),
+			Code:        CodeNotFound,
+			Field:       field + ".repositoryName",
 		})
 	}
 
 	if chart.Version == "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm chart 'version' field for %q field must be defined.", chart.Name),
+			Code:        CodeRequired,
+			Field:       field + ".version",
 		})
 	}
 
 	if chart.CreateNamespace && chart.TargetNamespace == "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm chart 'createNamespace' field for %q cannot be true without 'targetNamespace' being defined.", chart.Name),
+			Code:        CodeRequired,
+			Field:       field + ".targetNamespace",
+		})
+	}
+
+	if chart.InstallationNamespace != "" && chart.InstallationNamespace == chart.TargetNamespace &&
+		chart.InstallationNamespace != helmControllerNamespace {
+		msg := fmt.Sprintf("Helm chart 'installationNamespace' for %q is the same as its 'targetNamespace' %q. "+
+			"The HelmChart resource is usually installed in the '%s' namespace while the chart itself is deployed into the 'targetNamespace'.",
+			chart.Name, chart.TargetNamespace, helmControllerNamespace)
+		failures = append(failures, FailedValidation{
+			UserMessage: msg,
+			Severity:    SeverityWarning,
+			Code:        CodeConflict,
+			Field:       field + ".installationNamespace",
+		})
+	}
+
+	if chart.ValuesFile != "" && len(chart.ValuesFiles) != 0 {
+		failures = append(failures, FailedValidation{
+			UserMessage: fmt.Sprintf("Helm chart 'valuesFile' and 'valuesFiles' fields for %q cannot both be defined.", chart.Name),
+			Code:        CodeConflict,
+			Field:       field + ".valuesFiles",
 		})
 	}
 
-	if failure := validateHelmChartValues(chart.Name, chart.ValuesFile, imageConfigDir); failure != "" {
+	if failure := validateHelmChartValues(chart.Name, "valuesFile", chart.ValuesFile, imageConfigDir); failure != "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: failure,
+			Code:        CodeInvalid,
+			Field:       field + ".valuesFile",
 		})
 	}
 
+	for i, valuesFile := range chart.ValuesFiles {
+		if failure := validateHelmChartValues(chart.Name, "valuesFiles", valuesFile, imageConfigDir); failure != "" {
+			failures = append(failures, FailedValidation{
+				UserMessage: failure,
+				Code:        CodeInvalid,
+				Field:       fmt.Sprintf("%s.valuesFiles[%d]", field, i),
+			})
+		}
+	}
+
 	return failures
 }
 
-func validateRepo(repo *image.HelmRepository, seenHelmRepos map[string]bool, imageConfigDir string) []FailedValidation {
+func validateRepo(repo *image.HelmRepository, field string, seenHelmRepos map[string]bool, imageConfigDir string) []FailedValidation {
 	var failures []FailedValidation
 
 	parsedURL, err := url.Parse(repo.URL)
@@ -236,117 +1044,147 @@ func validateRepo(repo *image.HelmRepository, seenHelmRepos map[string]bool, ima
 		zap.S().Errorf("Helm repository URL '%s' could not be parsed: %s", repo.URL, err)
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm repository URL '%s' could not be parsed.", repo.URL),
+			Code:        CodeInvalid,
+			Field:       field + ".url",
 		})
 
 		return failures
 	}
 
-	failures = append(failures, validateHelmRepoName(repo, seenHelmRepos)...)
-	failures = append(failures, validateHelmRepoURL(parsedURL, repo)...)
-	failures = append(failures, validateHelmRepoAuth(repo)...)
-	failures = append(failures, validateHelmRepoArgs(parsedURL, repo)...)
+	failures = append(failures, validateHelmRepoName(repo, field, seenHelmRepos)...)
+	failures = append(failures, validateHelmRepoURL(parsedURL, repo, field)...)
+	failures = append(failures, validateHelmRepoAuth(repo, field)...)
+	failures = append(failures, validateHelmRepoArgs(parsedURL, repo, field)...)
 
 	if failure := validateHelmRepoCert(repo.Name, repo.CAFile, imageConfigDir); failure != "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: failure,
+			Code:        CodeInvalid,
+			Field:       field + ".caFile",
 		})
 	}
 
 	return failures
 }
 
-func validateHelmRepoName(repo *image.HelmRepository, seenHelmRepos map[string]bool) []FailedValidation {
+func validateHelmRepoName(repo *image.HelmRepository, field string, seenHelmRepos map[string]bool) []FailedValidation {
 	var failures []FailedValidation
 
 	if repo.Name == "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: "Helm repository 'name' field must be defined.",
+			Code:        CodeRequired,
+			Field:       field + ".name",
 		})
 	} else if !seenHelmRepos[repo.Name] {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm repository 'name' field for %q must match the 'repositoryName' field in at least one defined Helm chart.", repo.Name),
+			Code:        CodeUnreferenced,
+			Field:       field + ".name",
 		})
 	}
 
 	return failures
 }
 
-func validateHelmRepoURL(parsedURL *url.URL, repo *image.HelmRepository) []FailedValidation {
+func validateHelmRepoURL(parsedURL *url.URL, repo *image.HelmRepository, field string) []FailedValidation {
 	var failures []FailedValidation
 
 	if repo.URL == "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm repository 'url' field for %q must be defined.", repo.Name),
+			Code:        CodeRequired,
+			Field:       field + ".url",
 		})
 	} else if parsedURL.Scheme != httpScheme && parsedURL.Scheme != httpsScheme && parsedURL.Scheme != ociScheme {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm repository 'url' field for %q must begin with either 'oci://', 'http://', or 'https://'.", repo.Name),
+			Code:        CodeUnsupported,
+			Field:       field + ".url",
 		})
 	}
 
 	return failures
 }
 
-func validateHelmRepoAuth(repo *image.HelmRepository) []FailedValidation {
+func validateHelmRepoAuth(repo *image.HelmRepository, field string) []FailedValidation {
 	var failures []FailedValidation
 
 	if repo.Authentication.Username != "" && repo.Authentication.Password == "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm repository 'password' field not defined for %q.", repo.Name),
+			Code:        CodeRequired,
+			Field:       field + ".authentication.password",
 		})
 	}
 
 	if repo.Authentication.Username == "" && repo.Authentication.Password != "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm repository 'username' field not defined for %q.", repo.Name),
+			Code:        CodeRequired,
+			Field:       field + ".authentication.username",
 		})
 	}
 
 	return failures
 }
 
-func validateHelmRepoArgs(parsedURL *url.URL, repo *image.HelmRepository) []FailedValidation {
+func validateHelmRepoArgs(parsedURL *url.URL, repo *image.HelmRepository, field string) []FailedValidation {
 	var failures []FailedValidation
 
 	if repo.SkipTLSVerify && repo.PlainHTTP {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm repository 'plainHTTP' and 'skipTLSVerify' fields for %q cannot both be true.", repo.Name),
+			Code:        CodeConflict,
+			Field:       field + ".skipTLSVerify",
 		})
 	}
 
 	if parsedURL.Scheme == httpScheme && !repo.PlainHTTP {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm repository 'url' field for %q contains 'http://' but 'plainHTTP' field is false.", repo.Name),
+			Code:        CodeMismatch,
+			Field:       field + ".plainHTTP",
 		})
 	}
 
 	if parsedURL.Scheme == httpsScheme && repo.PlainHTTP {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm repository 'url' field for %q contains 'https://' but 'plainHTTP' field is true.", repo.Name),
+			Code:        CodeMismatch,
+			Field:       field + ".plainHTTP",
 		})
 	}
 
 	if parsedURL.Scheme == httpScheme && repo.SkipTLSVerify {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm repository 'url' field for %q contains 'http://' but 'skipTLSVerify' field is true.", repo.Name),
+			Code:        CodeConflict,
+			Field:       field + ".skipTLSVerify",
 		})
 	}
 
 	if repo.SkipTLSVerify && repo.CAFile != "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm repository 'caFile' field for %q cannot be defined while 'skipTLSVerify' is true.", repo.Name),
+			Code:        CodeConflict,
+			Field:       field + ".caFile",
 		})
 	}
 
 	if repo.PlainHTTP && repo.CAFile != "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm repository 'caFile' field for %q cannot be defined while 'plainHTTP' is true.", repo.Name),
+			Code:        CodeConflict,
+			Field:       field + ".caFile",
 		})
 	}
 
 	if parsedURL.Scheme == httpScheme && repo.CAFile != "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: fmt.Sprintf("Helm repository 'url' field for %q contains 'http://' but 'caFile' field is defined.", repo.Name),
+			Code:        CodeConflict,
+			Field:       field + ".caFile",
 		})
 	}
 
@@ -378,17 +1216,17 @@ func validateHelmRepoCert(repoName, certFile string, imageConfigDir string) stri
 	return ""
 }
 
-func validateHelmChartValues(chartName, valuesFile string, imageConfigDir string) string {
+func validateHelmChartValues(chartName, field, valuesFile string, imageConfigDir string) string {
 	if valuesFile == "" {
 		return ""
 	}
 
 	if filepath.Ext(valuesFile) != ".yaml" && filepath.Ext(valuesFile) != ".yml" {
-		return fmt.Sprintf("Helm chart 'valuesFile' field for %q must be the name of a valid yaml file ending in '.yaml' or '.yml'.", chartName)
+		return fmt.Sprintf("Helm chart '%s' field for %q must be the name of a valid yaml file ending in '.yaml' or '.yml'.", field, chartName)
 	}
 
 	valuesFilePath := filepath.Join(imageConfigDir, combustion.K8sDir, combustion.HelmDir, combustion.ValuesDir, valuesFile)
-	_, err := os.Stat(valuesFilePath)
+	data, err := os.ReadFile(valuesFilePath)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
 			return fmt.Sprintf("Helm chart values file '%s' could not be found at '%s'.", valuesFile, valuesFilePath)
@@ -398,9 +1236,113 @@ func validateHelmChartValues(chartName, valuesFile string, imageConfigDir string
 		return fmt.Sprintf("Helm chart values file '%s' could not be read.", valuesFile)
 	}
 
+	var values map[string]any
+	if err = yaml.Unmarshal(data, &values); err != nil {
+		zap.S().Errorf("Helm chart values file '%s' could not be parsed: %s", valuesFile, err)
+		return fmt.Sprintf("Helm chart values file '%s' is not a valid YAML document.", valuesFile)
+	}
+
 	return ""
 }
 
+// validateHelmRepositoriesReachable performs an online check of each Helm repository
+// referenced by the configured charts.
+func validateHelmRepositoriesReachable(client *http.Client, k8s *image.Kubernetes, imageConfigDir string) []FailedValidation {
+	var failures []FailedValidation
+
+	var referencedRepositories []string
+	for _, chart := range k8s.Helm.Charts {
+		referencedRepositories = append(referencedRepositories, chart.RepositoryName)
+	}
+
+	certsDir := filepath.Join(imageConfigDir, combustion.K8sDir, combustion.HelmDir, combustion.CertsDir)
+
+	for i := range k8s.Helm.Repositories {
+		repo := &k8s.Helm.Repositories[i]
+		if !slices.Contains(referencedRepositories, repo.Name) {
+			continue
+		}
+
+		field := fmt.Sprintf("kubernetes.helm.repositories[%d]", i)
+
+		err := helmRepositoryChecker(context.Background(), repo, certsDir, client.RootCAs())
+		switch {
+		case err == nil:
+			continue
+		case errors.Is(err, helm.ErrUnauthorized):
+			failures = append(failures, FailedValidation{
+				UserMessage: fmt.Sprintf("Helm repository %q rejected the configured authentication.", repo.Name),
+				Error:       err,
+				Code:        CodeInvalid,
+				Field:       field + ".authentication",
+			})
+		default:
+			failures = append(failures, FailedValidation{
+				UserMessage: fmt.Sprintf("Helm repository %q could not be reached at '%s'.", repo.Name, repo.URL),
+				Error:       err,
+				Code:        CodeNotFound,
+				Field:       field + ".url",
+			})
+		}
+	}
+
+	return failures
+}
+
+// validateHelmChartsRender templates every Helm chart with its values files, so that
+// charts failing to render are reported before the build rather than deep into it.
+func validateHelmChartsRender(client *http.Client, k8s *image.Kubernetes, imageConfigDir string) []FailedValidation {
+	if len(k8s.Helm.Charts) == 0 {
+		return nil
+	}
+
+	renderDir, err := os.MkdirTemp("", "eib-helm-render-")
+	if err != nil {
+		return []FailedValidation{
+			{
+				UserMessage: "Setting up the directory for rendering the Helm charts failed.",
+				Error:       err,
+				Code:        CodeUnreadable,
+			},
+		}
+	}
+	defer func() {
+		if err = os.RemoveAll(renderDir); err != nil {
+			zap.S().Warnf("Removing the Helm render directory '%s' failed: %s", renderDir, err)
+		}
+	}()
+
+	helmDir := filepath.Join(imageConfigDir, combustion.K8sDir, combustion.HelmDir)
+	helmClient := newHelmClient(client, renderDir, filepath.Join(helmDir, combustion.CertsDir))
+	valuesDir := filepath.Join(helmDir, combustion.ValuesDir)
+
+	var failures []FailedValidation
+
+	for i := range k8s.Helm.Charts {
+		chart := &k8s.Helm.Charts[i]
+
+		repoIndex := slices.IndexFunc(k8s.Helm.Repositories, func(repo image.HelmRepository) bool {
+			return repo.Name == chart.RepositoryName
+		})
+		if repoIndex == -1 {
+			// Reported by the chart validation
+			continue
+		}
+		repo := &k8s.Helm.Repositories[repoIndex]
+
+		if renderErr := registry.RenderHelmChart(chart, repo, valuesDir, renderDir, k8s.Version, helmClient); renderErr != nil {
+			failures = append(failures, FailedValidation{
+				UserMessage: fmt.Sprintf("Helm chart '%s' could not be rendered.", chart.Name),
+				Error:       renderErr,
+				Code:        CodeInvalid,
+				Field:       fmt.Sprintf("kubernetes.helm.charts[%d]", i),
+			})
+		}
+	}
+
+	return failures
+}
+
 func validateHelmChartDuplicates(charts []image.HelmChart) string {
 	seenHelmCharts := make(map[string]bool)
 
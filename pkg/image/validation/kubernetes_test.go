@@ -1,11 +1,21 @@
 package validation
 
 import (
+	"context"
+	"crypto/x509"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/combustion"
+	"github.com/suse-edge/edge-image-builder/pkg/env"
+	"github.com/suse-edge/edge-image-builder/pkg/helm"
+	"github.com/suse-edge/edge-image-builder/pkg/http"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 )
 
@@ -93,6 +103,7 @@ func TestValidateKubernetes(t *testing.T) {
 				},
 			},
 			ExpectedFailedMessages: []string{
+				"The kubernetes 'version' '1.0' must contain a recognized distribution identifier (k3s or rke2).",
 				"The 'hostname' field is required for entries in the 'nodes' section.",
 				"Entries in 'urls' must begin with either 'http://' or 'https://'.",
 				"Helm chart 'name' field must be defined.",
@@ -140,302 +151,1118 @@ func TestIsKubernetesDefined(t *testing.T) {
 	assert.False(t, result)
 }
 
-func TestValidateNodes(t *testing.T) {
+func TestValidateKubernetesVersion(t *testing.T) {
 	tests := map[string]struct {
-		K8s                    image.Kubernetes
+		Version                string
 		ExpectedFailedMessages []string
 	}{
-		`valid`: {
-			K8s: image.Kubernetes{
-				Network: validNetwork,
-				Nodes: []image.Node{
-					{
-						Hostname: "agent1",
-						Type:     image.KubernetesNodeTypeAgent,
-					},
-					{
-						Hostname:    "server",
-						Type:        image.KubernetesNodeTypeServer,
-						Initialiser: true,
-					},
-				},
-			},
+		`k3s`: {
+			Version: "v1.30.3+k3s1",
 		},
-		`no nodes`: {
-			K8s: image.Kubernetes{
-				Nodes: []image.Node{},
-			},
+		`rke2`: {
+			Version: "v1.30.3+rke2r1",
 		},
-		`with nodes - no network config`: {
-			K8s: image.Kubernetes{
-				Network: image.Network{},
-				Nodes: []image.Node{
-					{
-						Hostname: "host1",
-						Type:     image.KubernetesNodeTypeServer,
-					},
-					{
-						Hostname: "host2",
-						Type:     image.KubernetesNodeTypeAgent,
-					},
-				},
-			},
+		`missing distribution`: {
+			Version: "v1.30.3",
 			ExpectedFailedMessages: []string{
-				"The 'apiVIP' field is required in the 'network' section when defining entries under 'nodes'.",
+				"The kubernetes 'version' 'v1.30.3' must contain a recognized distribution identifier (k3s or rke2).",
 			},
 		},
-		`no hostname`: {
-			K8s: image.Kubernetes{
-				Network: validNetwork,
-				Nodes: []image.Node{
-					{
-						Hostname: "host1",
-						Type:     image.KubernetesNodeTypeServer,
-					},
-					{
-						Type: image.KubernetesNodeTypeServer,
-					},
-				},
-			},
+		`unknown distribution`: {
+			Version: "v1.30.3+k0s.0",
 			ExpectedFailedMessages: []string{
-				"The 'hostname' field is required for entries in the 'nodes' section.",
+				"The kubernetes 'version' 'v1.30.3+k0s.0' must contain a recognized distribution identifier (k3s or rke2).",
 			},
 		},
-		`missing type`: {
-			K8s: image.Kubernetes{
-				Network: validNetwork,
-				Nodes: []image.Node{
-					{
-						Hostname: "host1",
-						Type:     image.KubernetesNodeTypeServer,
-					},
-					{
-						Hostname: "valid",
-					},
-				},
-			},
-			ExpectedFailedMessages: []string{
-				fmt.Sprintf("The 'type' field for entries in the 'nodes' section must be one of: %s", strings.Join(validNodeTypes, ", ")),
-			},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			k8s := image.Kubernetes{
+				Version: test.Version,
+			}
+			failures := validateKubernetesVersion(&k8s)
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+		})
+	}
+}
+
+func TestValidateKubernetesVersionFormat(t *testing.T) {
+	tests := map[string]struct {
+		Version                string
+		ExpectedFailedMessages []string
+	}{
+		`k3s well-formed`: {
+			Version: "v1.30.3+k3s1",
 		},
-		`invalid type`: {
-			K8s: image.Kubernetes{
-				Network: validNetwork,
-				Nodes: []image.Node{
-					{
-						Hostname: "valid",
-						Type:     image.KubernetesNodeTypeServer,
-					},
-					{
-						Hostname: "invalid",
-						Type:     "abnormal",
-					},
-				},
-			},
+		`k3s missing prefix`: {
+			Version: "1.30.3+k3s1",
 			ExpectedFailedMessages: []string{
-				fmt.Sprintf("The 'type' field for entries in the 'nodes' section must be one of: %s", strings.Join(validNodeTypes, ", ")),
+				"The kubernetes 'version' '1.30.3+k3s1' does not match the k3s release format (e.g. 'v1.30.3+k3s1').",
 			},
 		},
-		`incorrect initialiser type`: {
-			K8s: image.Kubernetes{
-				Network: validNetwork,
-				Nodes: []image.Node{
-					{
-						Hostname: "valid",
-						Type:     image.KubernetesNodeTypeServer,
-					},
-					{
-						Hostname:    "invalid",
-						Initialiser: true,
-						Type:        image.KubernetesNodeTypeAgent,
-					},
-				},
-			},
+		`k3s with rke2 revision`: {
+			Version: "v1.30.3+k3sr1",
 			ExpectedFailedMessages: []string{
-				fmt.Sprintf("The node labeled with 'initialiser' must be of type '%s'.", image.KubernetesNodeTypeServer),
+				"The kubernetes 'version' 'v1.30.3+k3sr1' does not match the k3s release format (e.g. 'v1.30.3+k3s1').",
 			},
 		},
-		`duplicate entries`: {
-			K8s: image.Kubernetes{
-				Network: validNetwork,
-				Nodes: []image.Node{
-					{
-						Hostname:    "foo",
-						Type:        image.KubernetesNodeTypeServer,
-						Initialiser: true,
-					},
-					{
-						Hostname: "bar",
-						Type:     image.KubernetesNodeTypeAgent,
-					},
-					{
-						Hostname: "bar",
-						Type:     image.KubernetesNodeTypeAgent,
-					},
-					{
-						Hostname: "foo",
-						Type:     image.KubernetesNodeTypeAgent,
-					},
-				},
-			},
-			ExpectedFailedMessages: []string{
-				"The 'nodes' section contains duplicate entries: bar, foo",
-			},
+		`rke2 well-formed`: {
+			Version: "v1.30.3+rke2r1",
 		},
-		`no server node`: {
-			K8s: image.Kubernetes{
-				Network: validNetwork,
-				Nodes: []image.Node{
-					{
-						Hostname: "foo",
-						Type:     image.KubernetesNodeTypeAgent,
-					},
-					{
-						Hostname: "bar",
-						Type:     image.KubernetesNodeTypeAgent,
-					},
-				},
-			},
+		`rke2 missing patch version`: {
+			Version: "v1.30+rke2r1",
 			ExpectedFailedMessages: []string{
-				fmt.Sprintf("There must be at least one node of type '%s' defined.", image.KubernetesNodeTypeServer),
+				"The kubernetes 'version' 'v1.30+rke2r1' does not match the rke2 release format (e.g. 'v1.30.3+rke2r1').",
 			},
 		},
-		`multiple initialisers`: {
-			K8s: image.Kubernetes{
-				Network: validNetwork,
-				Nodes: []image.Node{
-					{
-						Hostname:    "foo",
-						Type:        image.KubernetesNodeTypeServer,
-						Initialiser: true,
-					},
-					{
-						Hostname:    "bar",
-						Type:        image.KubernetesNodeTypeServer,
-						Initialiser: true,
-					},
-				},
-			},
+		`rke2 missing revision`: {
+			Version: "v1.30.3+rke2",
 			ExpectedFailedMessages: []string{
-				"Only one node may be specified as the cluster initializer.",
+				"The kubernetes 'version' 'v1.30.3+rke2' does not match the rke2 release format (e.g. 'v1.30.3+rke2r1').",
 			},
 		},
+		`unknown distribution`: {
+			Version: "v1.30.3",
+		},
 	}
 
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
-			k := test.K8s
-			failures := validateNodes(&k)
-			assert.Len(t, failures, len(test.ExpectedFailedMessages))
+			k8s := image.Kubernetes{
+				Version: test.Version,
+			}
+			failures := validateKubernetesVersionFormat(&k8s)
 
 			var foundMessages []string
 			for _, foundValidation := range failures {
 				foundMessages = append(foundMessages, foundValidation.UserMessage)
 			}
 
-			for _, expectedMessage := range test.ExpectedFailedMessages {
-				assert.Contains(t, foundMessages, expectedMessage)
-			}
-
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
 		})
 	}
 }
 
-func TestValidateManifestURLs(t *testing.T) {
+func TestValidateReleaseURL(t *testing.T) {
 	tests := map[string]struct {
-		K8s                    image.Kubernetes
+		ReleaseURL             string
 		ExpectedFailedMessages []string
 	}{
-		`valid`: {
-			K8s: image.Kubernetes{
-				Manifests: image.Manifests{
-					URLs: []string{
-						"http://valid1.com",
-						"https://valid2.com",
-					},
-				},
-			},
+		`not specified`: {},
+		`https`: {
+			ReleaseURL: "https://mirror.example.com/k3s-io/k3s/releases/download",
 		},
-		`no URLs`: {
-			K8s: image.Kubernetes{
-				Manifests: image.Manifests{},
-			},
+		`http with port`: {
+			ReleaseURL: "http://192.168.1.10:8080/rke2",
 		},
-		`invalid prefix`: {
-			K8s: image.Kubernetes{
-				Manifests: image.Manifests{
-					URLs: []string{
-						"http://valid.com",
-						"https://also-valid.com",
-						"invalid.com",
-						"nope.com",
-					},
-				},
+		`missing scheme`: {
+			ReleaseURL: "mirror.example.com/rke2",
+			ExpectedFailedMessages: []string{
+				"The kubernetes 'releaseURL' 'mirror.example.com/rke2' must be a valid URL beginning with either 'http://' or 'https://'.",
 			},
+		},
+		`unsupported scheme`: {
+			ReleaseURL: "oci://mirror.example.com/rke2",
 			ExpectedFailedMessages: []string{
-				"Entries in 'urls' must begin with either 'http://' or 'https://'.",
-				"Entries in 'urls' must begin with either 'http://' or 'https://'.",
+				"The kubernetes 'releaseURL' 'oci://mirror.example.com/rke2' must be a valid URL beginning with either 'http://' or 'https://'.",
 			},
 		},
-		`duplicate URLs`: {
-			K8s: image.Kubernetes{
-				Manifests: image.Manifests{
-					URLs: []string{
-						"http://foo.com",
-						"http://bar.com",
-						"http://foo.com",
-						"http://bar.com",
-					},
-				},
+		`missing host`: {
+			ReleaseURL: "https:///rke2",
+			ExpectedFailedMessages: []string{
+				"The kubernetes 'releaseURL' 'https:///rke2' must be a valid URL beginning with either 'http://' or 'https://'.",
 			},
+		},
+		`unparsable`: {
+			ReleaseURL: "https://mirror example.com/%zz",
 			ExpectedFailedMessages: []string{
-				"The 'urls' field contains duplicate entries: http://foo.com",
-				"The 'urls' field contains duplicate entries: http://bar.com",
+				"The kubernetes 'releaseURL' 'https://mirror example.com/%zz' must be a valid URL beginning with either 'http://' or 'https://'.",
 			},
 		},
 	}
 
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
-			k := test.K8s
-			failures := validateManifestURLs(&k)
-			assert.Len(t, failures, len(test.ExpectedFailedMessages))
+			k8s := image.Kubernetes{
+				Version:    "v1.30.3+k3s1",
+				ReleaseURL: test.ReleaseURL,
+			}
+			failures := validateReleaseURL(&k8s)
 
 			var foundMessages []string
 			for _, foundValidation := range failures {
 				foundMessages = append(foundMessages, foundValidation.UserMessage)
 			}
 
-			for _, expectedMessage := range test.ExpectedFailedMessages {
-				assert.Contains(t, foundMessages, expectedMessage)
-			}
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
 		})
 	}
 }
 
-func TestValidateHelmCharts(t *testing.T) {
+func TestValidateSELinuxSigningKey(t *testing.T) {
+	configDir := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "rancher.key"), []byte("key"), 0o600))
+
+	serverConfigPath := combustion.KubernetesConfigPath(&image.Context{ImageConfigDir: configDir})
+	require.NoError(t, os.MkdirAll(filepath.Dir(serverConfigPath), 0o755))
+	require.NoError(t, os.WriteFile(serverConfigPath, []byte("selinux: true\n"), 0o600))
+
+	offlineMessage := "SELinux is enabled in the Kubernetes server config but the signing key of the SELinux RPMs cannot be downloaded " +
+		"in offline mode. Please provide the key locally using the kubernetes 'selinuxSigningKey' field."
+
 	tests := map[string]struct {
-		K8s                    image.Kubernetes
+		SigningKey             string
+		Offline                bool
+		SkipSELinuxPackages    bool
 		ExpectedFailedMessages []string
 	}{
-		`valid`: {
-			K8s: image.Kubernetes{
-				Helm: image.Helm{
-					Charts: []image.HelmChart{
-						{
-							Name:                  "apache",
-							RepositoryName:        "apache-repo",
-							TargetNamespace:       "web",
-							CreateNamespace:       true,
-							InstallationNamespace: "kube-system",
-							Version:               "10.7.0",
-						},
-					},
-					Repositories: []image.HelmRepository{
-						{
-							Name: "apache-repo",
-							URL:  "oci://registry-1.docker.io/bitnamicharts",
-							Authentication: image.HelmAuthentication{
+		`default key`: {},
+		`custom URL`: {
+			SigningKey: "https://mirror.example.com/rancher.key",
+		},
+		`malformed URL`: {
+			SigningKey: "https://",
+			ExpectedFailedMessages: []string{
+				"The kubernetes 'selinuxSigningKey' URL 'https://' could not be parsed.",
+			},
+		},
+		`local key`: {
+			SigningKey: "rancher.key",
+		},
+		`local key in offline mode`: {
+			SigningKey: "rancher.key",
+			Offline:    true,
+		},
+		`missing local key`: {
+			SigningKey: "missing.key",
+			ExpectedFailedMessages: []string{
+				"The kubernetes 'selinuxSigningKey' file 'missing.key' could not be found in the image configuration directory.",
+			},
+		},
+		`absolute local key`: {
+			SigningKey: "/etc/rancher.key",
+			ExpectedFailedMessages: []string{
+				"The kubernetes 'selinuxSigningKey' path '/etc/rancher.key' must be relative to the image configuration directory.",
+			},
+		},
+		`default key in offline mode`: {
+			Offline: true,
+			ExpectedFailedMessages: []string{
+				offlineMessage,
+			},
+		},
+		`custom URL in offline mode`: {
+			SigningKey: "https://mirror.example.com/rancher.key",
+			Offline:    true,
+			ExpectedFailedMessages: []string{
+				offlineMessage,
+			},
+		},
+		`skipped packages in offline mode`: {
+			Offline:             true,
+			SkipSELinuxPackages: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			client, err := http.NewClient(http.ClientConfig{Offline: test.Offline})
+			require.NoError(t, err)
+
+			ctx := image.Context{
+				ImageConfigDir: configDir,
+				HTTPClient:     client,
+				ImageDefinition: &image.Definition{
+					Kubernetes: image.Kubernetes{
+						Version:             "v1.30.3+rke2r1",
+						SELinuxSigningKey:   test.SigningKey,
+						SkipSELinuxPackages: test.SkipSELinuxPackages,
+					},
+				},
+			}
+			failures := validateSELinuxSigningKey(&ctx)
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+		})
+	}
+}
+
+func TestValidateSELinuxSigningKey_OfflineWithoutSELinux(t *testing.T) {
+	client, err := http.NewClient(http.ClientConfig{Offline: true})
+	require.NoError(t, err)
+
+	ctx := image.Context{
+		ImageConfigDir: t.TempDir(),
+		HTTPClient:     client,
+		ImageDefinition: &image.Definition{
+			Kubernetes: image.Kubernetes{
+				Version: "v1.30.3+rke2r1",
+			},
+		},
+	}
+
+	assert.Empty(t, validateSELinuxSigningKey(&ctx))
+}
+
+func TestValidateAPIHostTLSSAN(t *testing.T) {
+	tests := map[string]struct {
+		ServerConfig           string
+		APIHost                string
+		ExpectedFailedMessages []string
+	}{
+		`no apiHost`: {
+			ServerConfig: "tls-san:\n  - api.edge.suse.com\n",
+		},
+		`no tls-san`: {
+			ServerConfig: "selinux: true\n",
+			APIHost:      "api.cluster01.edge.suse.com",
+		},
+		`apiHost present`: {
+			ServerConfig: "tls-san:\n  - api.edge.suse.com\n  - api.cluster01.edge.suse.com\n",
+			APIHost:      "api.cluster01.edge.suse.com",
+		},
+		`apiHost present in comma-separated list`: {
+			ServerConfig: "tls-san: api.edge.suse.com, api.cluster01.edge.suse.com\n",
+			APIHost:      "api.cluster01.edge.suse.com",
+		},
+		`apiHost absent`: {
+			ServerConfig: "tls-san:\n  - api.edge.suse.com\n",
+			APIHost:      "api.cluster01.edge.suse.com",
+			ExpectedFailedMessages: []string{
+				"The 'apiHost' 'api.cluster01.edge.suse.com' is not present in the server config 'tls-san' list.",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := image.Context{
+				ImageConfigDir: t.TempDir(),
+				ImageDefinition: &image.Definition{
+					Kubernetes: image.Kubernetes{
+						Version: "v1.30.3+rke2r1",
+						Network: image.Network{
+							APIHost: test.APIHost,
+						},
+					},
+				},
+			}
+
+			serverConfigPath := combustion.KubernetesConfigPath(&ctx)
+			require.NoError(t, os.MkdirAll(filepath.Dir(serverConfigPath), 0o755))
+			require.NoError(t, os.WriteFile(serverConfigPath, []byte(test.ServerConfig), 0o600))
+
+			failures := validateAPIHostTLSSAN(&ctx)
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+				assert.Equal(t, SeverityWarning, foundValidation.Severity)
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+		})
+	}
+}
+
+func TestValidateAdditionalSANs(t *testing.T) {
+	tests := map[string]struct {
+		AdditionalSANs         []string
+		ExpectedFailedMessages []string
+	}{
+		`no additional SANs`: {},
+		`valid`: {
+			AdditionalSANs: []string{"api.cluster01.edge.suse.com", "rancher", "192.168.122.51", "fd00::51"},
+		},
+		`invalid`: {
+			AdditionalSANs: []string{"api.cluster01.edge.suse.com", "https://rancher.edge.suse.com", "", "bad_host"},
+			ExpectedFailedMessages: []string{
+				"The 'additionalSANs' entry 'https://rancher.edge.suse.com' in the 'network' section must be a valid hostname or IP address.",
+				"The 'additionalSANs' entry '' in the 'network' section must be a valid hostname or IP address.",
+				"The 'additionalSANs' entry 'bad_host' in the 'network' section must be a valid hostname or IP address.",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			k8s := image.Kubernetes{
+				Network: image.Network{
+					AdditionalSANs: test.AdditionalSANs,
+				},
+			}
+			failures := validateAdditionalSANs(&k8s)
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+		})
+	}
+}
+
+func TestValidateLoadBalancerPool(t *testing.T) {
+	manageVIP := false
+
+	tests := map[string]struct {
+		Network                image.Network
+		ExpectedFailedMessages []string
+	}{
+		`no pool`: {
+			Network: image.Network{
+				APIVIP: "192.168.122.100",
+			},
+		},
+		`valid CIDR`: {
+			Network: image.Network{
+				APIVIP:           "192.168.122.100",
+				LoadBalancerPool: []string{"192.168.122.96/28"},
+			},
+		},
+		`valid ranges`: {
+			Network: image.Network{
+				APIVIP:           "192.168.122.100",
+				LoadBalancerPool: []string{"192.168.122.50-192.168.122.60", "192.168.122.100-192.168.122.150", "fd00::10-fd00::20"},
+			},
+		},
+		`valid single address range`: {
+			Network: image.Network{
+				APIVIP:           "192.168.122.100",
+				LoadBalancerPool: []string{"192.168.122.100-192.168.122.100"},
+			},
+		},
+		`missing apiVIP`: {
+			Network: image.Network{
+				LoadBalancerPool: []string{"192.168.122.96/28"},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'loadBalancerPool' field in the 'network' section can only be used when MetalLB is deployed for the 'apiVIP'.",
+			},
+		},
+		`unmanaged apiVIP`: {
+			Network: image.Network{
+				APIVIP:           "192.168.122.100",
+				ManageVIP:        &manageVIP,
+				LoadBalancerPool: []string{"192.168.122.96/28"},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'loadBalancerPool' field in the 'network' section can only be used when MetalLB is deployed for the 'apiVIP'.",
+			},
+		},
+		`invalid entries`: {
+			Network: image.Network{
+				APIVIP: "192.168.122.100",
+				LoadBalancerPool: []string{
+					"192.168.122.96/28",
+					"192.168.122.300/24",
+					"192.168.122.100",
+					"192.168.122.150-192.168.122.100",
+					"192.168.122.100-fd00::20",
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'loadBalancerPool' entry '192.168.122.300/24' in the 'network' section must be a valid CIDR or IP address range (e.g. '192.168.122.100-192.168.122.150').",
+				"The 'loadBalancerPool' entry '192.168.122.100' in the 'network' section must be a valid CIDR or IP address range (e.g. '192.168.122.100-192.168.122.150').",
+				"The 'loadBalancerPool' entry '192.168.122.150-192.168.122.100' in the 'network' section must be a valid CIDR or IP address range (e.g. '192.168.122.100-192.168.122.150').",
+				"The 'loadBalancerPool' entry '192.168.122.100-fd00::20' in the 'network' section must be a valid CIDR or IP address range (e.g. '192.168.122.100-192.168.122.150').",
+			},
+		},
+		`apiVIP not included`: {
+			Network: image.Network{
+				APIVIP:           "192.168.122.100",
+				LoadBalancerPool: []string{"192.168.122.0/26", "192.168.122.101-192.168.122.150"},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'apiVIP' 192.168.122.100 is not contained in any configured LoadBalancerPool range.",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			k8s := image.Kubernetes{
+				Network: test.Network,
+			}
+			failures := validateLoadBalancerPool(&k8s)
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+		})
+	}
+}
+
+func TestValidateComponentChartVersions(t *testing.T) {
+	manageVIP := false
+
+	tests := map[string]struct {
+		Network                image.Network
+		ExpectedFailedMessages []string
+	}{
+		`no overrides`: {
+			Network: image.Network{
+				APIVIP: "192.168.122.100",
+			},
+		},
+		`valid overrides`: {
+			Network: image.Network{
+				APIVIP:                "192.168.122.100",
+				MetalLBVersion:        "0.14.9",
+				EndpointCopierVersion: "0.2.1-patched.1+build.5",
+			},
+		},
+		`invalid overrides`: {
+			Network: image.Network{
+				APIVIP:                "192.168.122.100",
+				MetalLBVersion:        "v0.14.9",
+				EndpointCopierVersion: "0.2",
+			},
+			ExpectedFailedMessages: []string{
+				"The 'network/metalLBVersion' field must be a valid semantic version (e.g. 0.14.3).",
+				"The 'network/endpointCopierVersion' field must be a valid semantic version (e.g. 0.14.3).",
+			},
+		},
+		`overrides without managed apiVIP`: {
+			Network: image.Network{
+				APIVIP:         "192.168.122.100",
+				ManageVIP:      &manageVIP,
+				MetalLBVersion: "0.14.9",
+			},
+			ExpectedFailedMessages: []string{
+				"The 'network/metalLBVersion' field has no effect unless the 'apiVIP' is set and 'manageVIP' is not disabled.",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			k8s := image.Kubernetes{
+				Network: test.Network,
+			}
+			failures := validateComponentChartVersions(&k8s)
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+		})
+	}
+}
+
+func TestValidateAPIVIPInLoadBalancerPool(t *testing.T) {
+	tests := map[string]struct {
+		APIVIP                 string
+		LoadBalancerPool       []string
+		ExpectedFailedMessages []string
+	}{
+		`IPv4 in CIDR`: {
+			APIVIP:           "192.168.122.100",
+			LoadBalancerPool: []string{"fd00::/64", "192.168.122.96/28"},
+		},
+		`IPv4 at range boundary`: {
+			APIVIP:           "192.168.122.150",
+			LoadBalancerPool: []string{"192.168.122.100-192.168.122.150"},
+		},
+		`IPv4 out of range`: {
+			APIVIP:           "192.168.122.151",
+			LoadBalancerPool: []string{"192.168.122.100-192.168.122.150", "192.168.122.0/25"},
+			ExpectedFailedMessages: []string{
+				"The 'apiVIP' 192.168.122.151 is not contained in any configured LoadBalancerPool range.",
+			},
+		},
+		`IPv6 in CIDR`: {
+			APIVIP:           "fd00::100",
+			LoadBalancerPool: []string{"192.168.122.0/24", "fd00::/112"},
+		},
+		`IPv6 in range`: {
+			APIVIP:           "fd00::15",
+			LoadBalancerPool: []string{"fd00::10-fd00::20"},
+		},
+		`IPv6 out of range`: {
+			APIVIP:           "fd00::1:100",
+			LoadBalancerPool: []string{"fd00::/120", "fd00::10-fd00::20"},
+			ExpectedFailedMessages: []string{
+				"The 'apiVIP' fd00::1:100 is not contained in any configured LoadBalancerPool range.",
+			},
+		},
+		`IPv6 apiVIP with IPv4 pool`: {
+			APIVIP:           "fd00::100",
+			LoadBalancerPool: []string{"0.0.0.0/0", "0.0.0.0-255.255.255.255"},
+			ExpectedFailedMessages: []string{
+				"The 'apiVIP' fd00::100 is not contained in any configured LoadBalancerPool range.",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			network := image.Network{
+				APIVIP:           test.APIVIP,
+				LoadBalancerPool: test.LoadBalancerPool,
+			}
+			failures := validateAPIVIPInLoadBalancerPool(&network)
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+		})
+	}
+}
+
+func TestValidateNodeIP(t *testing.T) {
+	servers := []image.Node{
+		{
+			Hostname: "node1.suse.com",
+			Type:     image.KubernetesNodeTypeServer,
+		},
+		{
+			Hostname: "node2.suse.com",
+			Type:     image.KubernetesNodeTypeServer,
+		},
+	}
+
+	tests := map[string]struct {
+		ServerConfig           string
+		K8s                    image.Kubernetes
+		ExpectedFailedMessages []string
+	}{
+		`no node-ip`: {
+			ServerConfig: "selinux: true\n",
+			K8s: image.Kubernetes{
+				Network: validNetwork,
+				Nodes:   servers,
+			},
+		},
+		`single node`: {
+			ServerConfig: "node-ip: 192.168.122.50\n",
+			K8s: image.Kubernetes{
+				Network: image.Network{
+					APIVIP: "192.168.100.1",
+				},
+			},
+		},
+		`single node without apiVIP`: {
+			ServerConfig: "node-ip: 192.168.122.50\n",
+		},
+		`single node matching apiVIP`: {
+			ServerConfig: "node-ip: 192.168.122.50, fd00::50\n",
+			K8s: image.Kubernetes{
+				Network: image.Network{
+					APIVIP: "192.168.122.50",
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The server config 'node-ip' must not be the same as the 'apiVIP' '192.168.122.50'.",
+			},
+		},
+		`multiple servers`: {
+			ServerConfig: "node-ip: 192.168.122.50\n",
+			K8s: image.Kubernetes{
+				Network: validNetwork,
+				Nodes:   servers,
+			},
+			ExpectedFailedMessages: []string{
+				"The server config 'node-ip' field cannot be set when defining multiple server nodes, as it would be shared between all of them.",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := image.Context{
+				ImageConfigDir: t.TempDir(),
+				ImageDefinition: &image.Definition{
+					Kubernetes: test.K8s,
+				},
+			}
+
+			serverConfigPath := combustion.KubernetesConfigPath(&ctx)
+			require.NoError(t, os.MkdirAll(filepath.Dir(serverConfigPath), 0o755))
+			require.NoError(t, os.WriteFile(serverConfigPath, []byte(test.ServerConfig), 0o600))
+
+			failures := validateNodeIP(&ctx)
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+		})
+	}
+}
+
+func TestValidateNodes(t *testing.T) {
+	tests := map[string]struct {
+		K8s                    image.Kubernetes
+		ExpectedFailedMessages []string
+	}{
+		`valid`: {
+			K8s: image.Kubernetes{
+				Network: validNetwork,
+				Nodes: []image.Node{
+					{
+						Hostname: "agent1",
+						Type:     image.KubernetesNodeTypeAgent,
+					},
+					{
+						Hostname:    "server",
+						Type:        image.KubernetesNodeTypeServer,
+						Initialiser: true,
+					},
+				},
+			},
+		},
+		`no nodes`: {
+			K8s: image.Kubernetes{
+				Nodes: []image.Node{},
+			},
+		},
+		`with nodes - no network config`: {
+			K8s: image.Kubernetes{
+				Network: image.Network{},
+				Nodes: []image.Node{
+					{
+						Hostname: "host1",
+						Type:     image.KubernetesNodeTypeServer,
+					},
+					{
+						Hostname: "host2",
+						Type:     image.KubernetesNodeTypeAgent,
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'apiVIP' field is required in the 'network' section when defining entries under 'nodes'.",
+			},
+		},
+		`no hostname`: {
+			K8s: image.Kubernetes{
+				Network: validNetwork,
+				Nodes: []image.Node{
+					{
+						Hostname: "host1",
+						Type:     image.KubernetesNodeTypeServer,
+					},
+					{
+						Type: image.KubernetesNodeTypeServer,
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'hostname' field is required for entries in the 'nodes' section.",
+				"An even number of server nodes (2) is not recommended for etcd quorum.",
+			},
+		},
+		`missing type`: {
+			K8s: image.Kubernetes{
+				Network: validNetwork,
+				Nodes: []image.Node{
+					{
+						Hostname: "host1",
+						Type:     image.KubernetesNodeTypeServer,
+					},
+					{
+						Hostname: "valid",
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				fmt.Sprintf("The 'type' field for entries in the 'nodes' section must be one of: %s", strings.Join(validNodeTypes, ", ")),
+			},
+		},
+		`invalid type`: {
+			K8s: image.Kubernetes{
+				Network: validNetwork,
+				Nodes: []image.Node{
+					{
+						Hostname: "valid",
+						Type:     image.KubernetesNodeTypeServer,
+					},
+					{
+						Hostname: "invalid",
+						Type:     "abnormal",
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				fmt.Sprintf("The 'type' field for entries in the 'nodes' section must be one of: %s", strings.Join(validNodeTypes, ", ")),
+			},
+		},
+		`incorrect initialiser type`: {
+			K8s: image.Kubernetes{
+				Network: validNetwork,
+				Nodes: []image.Node{
+					{
+						Hostname: "valid",
+						Type:     image.KubernetesNodeTypeServer,
+					},
+					{
+						Hostname:    "invalid",
+						Initialiser: true,
+						Type:        image.KubernetesNodeTypeAgent,
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				fmt.Sprintf("The node labeled with 'initialiser' must be of type '%s'.", image.KubernetesNodeTypeServer),
+			},
+		},
+		`duplicate entries`: {
+			K8s: image.Kubernetes{
+				Network: validNetwork,
+				Nodes: []image.Node{
+					{
+						Hostname:    "foo",
+						Type:        image.KubernetesNodeTypeServer,
+						Initialiser: true,
+					},
+					{
+						Hostname: "bar",
+						Type:     image.KubernetesNodeTypeAgent,
+					},
+					{
+						Hostname: "bar",
+						Type:     image.KubernetesNodeTypeAgent,
+					},
+					{
+						Hostname: "foo",
+						Type:     image.KubernetesNodeTypeAgent,
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'nodes' section contains duplicate entries: bar, foo",
+			},
+		},
+		`no server node`: {
+			K8s: image.Kubernetes{
+				Network: validNetwork,
+				Nodes: []image.Node{
+					{
+						Hostname: "foo",
+						Type:     image.KubernetesNodeTypeAgent,
+					},
+					{
+						Hostname: "bar",
+						Type:     image.KubernetesNodeTypeAgent,
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				fmt.Sprintf("There must be at least one node of type '%s' defined.", image.KubernetesNodeTypeServer),
+			},
+		},
+		`multiple initialisers`: {
+			K8s: image.Kubernetes{
+				Network: validNetwork,
+				Nodes: []image.Node{
+					{
+						Hostname:    "foo",
+						Type:        image.KubernetesNodeTypeServer,
+						Initialiser: true,
+					},
+					{
+						Hostname:    "bar",
+						Type:        image.KubernetesNodeTypeServer,
+						Initialiser: true,
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"Only one node may be specified as the cluster initializer.",
+				"An even number of server nodes (2) is not recommended for etcd quorum.",
+			},
+		},
+		`two servers`: {
+			K8s: image.Kubernetes{
+				Network: validNetwork,
+				Nodes: []image.Node{
+					{
+						Hostname: "server1",
+						Type:     image.KubernetesNodeTypeServer,
+					},
+					{
+						Hostname: "server2",
+						Type:     image.KubernetesNodeTypeServer,
+					},
+					{
+						Hostname: "agent1",
+						Type:     image.KubernetesNodeTypeAgent,
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"An even number of server nodes (2) is not recommended for etcd quorum.",
+			},
+		},
+		`three servers`: {
+			K8s: image.Kubernetes{
+				Network: validNetwork,
+				Nodes: []image.Node{
+					{
+						Hostname: "server1",
+						Type:     image.KubernetesNodeTypeServer,
+					},
+					{
+						Hostname: "server2",
+						Type:     image.KubernetesNodeTypeServer,
+					},
+					{
+						Hostname: "server3",
+						Type:     image.KubernetesNodeTypeServer,
+					},
+				},
+			},
+		},
+		`one server`: {
+			K8s: image.Kubernetes{
+				Network: validNetwork,
+				Nodes: []image.Node{
+					{
+						Hostname: "server1",
+						Type:     image.KubernetesNodeTypeServer,
+					},
+					{
+						Hostname: "agent1",
+						Type:     image.KubernetesNodeTypeAgent,
+					},
+					{
+						Hostname: "agent2",
+						Type:     image.KubernetesNodeTypeAgent,
+					},
+				},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			k := test.K8s
+			failures := validateNodes(&k)
+			assert.Len(t, failures, len(test.ExpectedFailedMessages))
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+			}
+
+			for _, expectedMessage := range test.ExpectedFailedMessages {
+				assert.Contains(t, foundMessages, expectedMessage)
+			}
+
+		})
+	}
+}
+
+func TestValidateManifestURLs(t *testing.T) {
+	tests := map[string]struct {
+		K8s                    image.Kubernetes
+		ExpectedFailedMessages []string
+	}{
+		`valid`: {
+			K8s: image.Kubernetes{
+				Manifests: image.Manifests{
+					URLs: []string{
+						"http://valid1.com",
+						"https://valid2.com",
+					},
+				},
+			},
+		},
+		`no URLs`: {
+			K8s: image.Kubernetes{
+				Manifests: image.Manifests{},
+			},
+		},
+		`invalid prefix`: {
+			K8s: image.Kubernetes{
+				Manifests: image.Manifests{
+					URLs: []string{
+						"http://valid.com",
+						"https://also-valid.com",
+						"invalid.com",
+						"nope.com",
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"Entries in 'urls' must begin with either 'http://' or 'https://'.",
+				"Entries in 'urls' must begin with either 'http://' or 'https://'.",
+			},
+		},
+		`duplicate URLs`: {
+			K8s: image.Kubernetes{
+				Manifests: image.Manifests{
+					URLs: []string{
+						"http://foo.com",
+						"http://bar.com",
+						"http://foo.com",
+						"http://bar.com",
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'urls' field contains duplicate entries: http://foo.com",
+				"The 'urls' field contains duplicate entries: http://bar.com",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			k := test.K8s
+			failures := validateManifestURLs(&k)
+			assert.Len(t, failures, len(test.ExpectedFailedMessages))
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+			}
+
+			for _, expectedMessage := range test.ExpectedFailedMessages {
+				assert.Contains(t, foundMessages, expectedMessage)
+			}
+		})
+	}
+}
+
+func TestValidateManifestCredentials(t *testing.T) {
+	t.Setenv("EIB_TEST_MANIFEST_TOKEN", "token")
+
+	tests := map[string]struct {
+		Credentials            []image.ManifestCredentials
+		ExpectedFailedMessages []string
+	}{
+		`no credentials`: {},
+		`valid credentials`: {
+			Credentials: []image.ManifestCredentials{
+				{
+					URLPrefix: "https://gateway.example.com/",
+					TokenEnv:  "EIB_TEST_MANIFEST_TOKEN",
+					Headers: map[string]string{
+						"X-Tenant": "edge",
+					},
+				},
+				{
+					URLPrefix:   "https://other.example.com/",
+					Username:    "admin",
+					PasswordEnv: "EIB_TEST_MANIFEST_TOKEN",
+				},
+			},
+		},
+		`missing url prefix`: {
+			Credentials: []image.ManifestCredentials{
+				{
+					TokenEnv: "EIB_TEST_MANIFEST_TOKEN",
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'urlPrefix' field is required for each entry in 'credentials'.",
+			},
+		},
+		`plaintext secrets`: {
+			Credentials: []image.ManifestCredentials{
+				{
+					URLPrefix: "https://gateway.example.com/",
+					TokenEnv:  "ghp_abc123!",
+					Headers: map[string]string{
+						"authorization": "Bearer abc123",
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"Manifest credentials for 'https://gateway.example.com/' must not define an 'Authorization' header; " +
+					"use 'tokenEnv' or 'username' and 'passwordEnv' instead.",
+				"The 'tokenEnv' field in the manifest credentials for 'https://gateway.example.com/' must be the name of " +
+					"an environment variable; secrets must not be stored in the definition.",
+			},
+		},
+		`conflicting and incomplete credentials`: {
+			Credentials: []image.ManifestCredentials{
+				{
+					URLPrefix: "https://gateway.example.com/",
+					TokenEnv:  "EIB_TEST_UNSET_VARIABLE",
+					Username:  "admin",
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"Manifest credentials for 'https://gateway.example.com/' cannot define both 'tokenEnv' and 'username'.",
+				"Manifest credentials for 'https://gateway.example.com/' must define both 'username' and 'passwordEnv'.",
+				"The environment variable 'EIB_TEST_UNSET_VARIABLE' referenced in the manifest credentials for " +
+					"'https://gateway.example.com/' is not set.",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			k := image.Kubernetes{
+				Manifests: image.Manifests{
+					Credentials: test.Credentials,
+				},
+			}
+			failures := validateManifestCredentials(&k)
+			assert.Len(t, failures, len(test.ExpectedFailedMessages))
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+			}
+
+			for _, expectedMessage := range test.ExpectedFailedMessages {
+				assert.Contains(t, foundMessages, expectedMessage)
+			}
+		})
+	}
+}
+
+func TestValidateHelmCharts(t *testing.T) {
+	tests := map[string]struct {
+		K8s                    image.Kubernetes
+		ExpectedFailedMessages []string
+	}{
+		`valid`: {
+			K8s: image.Kubernetes{
+				Helm: image.Helm{
+					Charts: []image.HelmChart{
+						{
+							Name:                  "apache",
+							RepositoryName:        "apache-repo",
+							TargetNamespace:       "web",
+							CreateNamespace:       true,
+							InstallationNamespace: "kube-system",
+							Version:               "10.7.0",
+						},
+					},
+					Repositories: []image.HelmRepository{
+						{
+							Name: "apache-repo",
+							URL:  "oci://registry-1.docker.io/bitnamicharts",
+							Authentication: image.HelmAuthentication{
 								Username: "user",
 								Password: "pass",
 							},
@@ -444,7 +1271,171 @@ func TestValidateHelmCharts(t *testing.T) {
 				},
 			},
 		},
-		`helm no repos`: {
+		`helm no repos`: {
+			K8s: image.Kubernetes{
+				Helm: image.Helm{
+					Charts: []image.HelmChart{
+						{
+							Name:           "apache",
+							RepositoryName: "apache-repo",
+							Version:        "10.7.0",
+						},
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"Helm charts defined with no Helm repositories defined.",
+			},
+		},
+		`helm chart no name`: {
+			K8s: image.Kubernetes{
+				Helm: image.Helm{
+					Charts: []image.HelmChart{
+						{
+							Name:           "",
+							RepositoryName: "apache-repo",
+							Version:        "10.7.0",
+						},
+					},
+					Repositories: []image.HelmRepository{
+						{
+							Name: "apache-repo",
+							URL:  "oci://registry-1.docker.io/bitnamicharts",
+						},
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"Helm chart 'name' field must be defined.",
+			},
+		},
+		`helm chart undefined repository name`: {
+			K8s: image.Kubernetes{
+				Helm: image.Helm{
+					Charts: []image.HelmChart{
+						{
+							Name:           "kubevirt",
+							RepositoryName: "suse-edge",
+							Version:        "0.2.2",
+						},
+						{
+							Name:           "metallb",
+							RepositoryName: "",
+							Version:        "0.14.3",
+						},
+					},
+					Repositories: []image.HelmRepository{
+						{
+							Name: "suse-edge",
+							URL:  "https://suse-edge.github.io/charts",
+						},
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"Helm chart 'repositoryName' field for \"metallb\" must be defined.",
+			},
+		},
+		`helm chart no matching repository name`: {
+			K8s: image.Kubernetes{
+				Helm: image.Helm{
+					Charts: []image.HelmChart{
+						{
+							Name:           "kubevirt",
+							RepositoryName: "suse-edge",
+							Version:        "0.2.2",
+						},
+						{
+							Name:           "metallb",
+							RepositoryName: "this-is-not-suse-edge",
+							Version:        "0.14.3",
+						},
+					},
+					Repositories: []image.HelmRepository{
+						{
+							Name: "suse-edge",
+							URL:  "https://suse-edge.github.io/charts",
+						},
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"Helm chart 'repositoryName' \"this-is-not-suse-edge\" for Helm chart \"metallb\" does not match the name of any defined repository.",
+			},
+		},
+		`helm chart no version`: {
+			K8s: image.Kubernetes{
+				Helm: image.Helm{
+					Charts: []image.HelmChart{
+						{
+							Name:           "apache",
+							RepositoryName: "apache-repo",
+							Version:        "",
+						},
+					},
+					Repositories: []image.HelmRepository{
+						{
+							Name: "apache-repo",
+							URL:  "oci://registry-1.docker.io/bitnamicharts",
+						},
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"Helm chart 'version' field for \"apache\" field must be defined.",
+			},
+		},
+		`helm chart create namespace no target`: {
+			K8s: image.Kubernetes{
+				Helm: image.Helm{
+					Charts: []image.HelmChart{
+						{
+							Name:            "apache",
+							RepositoryName:  "apache-repo",
+							Version:         "10.7.0",
+							CreateNamespace: true,
+						},
+					},
+					Repositories: []image.HelmRepository{
+						{
+							Name: "apache-repo",
+							URL:  "oci://registry-1.docker.io/bitnamicharts",
+						},
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"Helm chart 'createNamespace' field for \"apache\" cannot be true without 'targetNamespace' being defined.",
+			},
+		},
+		`helm chart duplicate name`: {
+			K8s: image.Kubernetes{
+				Helm: image.Helm{
+					Charts: []image.HelmChart{
+						{
+							Name:           "apache",
+							RepositoryName: "apache-repo",
+							Version:        "10.7.0",
+						},
+						{
+							Name:           "apache",
+							RepositoryName: "apache-repo",
+							Version:        "10.7.0",
+						},
+					},
+					Repositories: []image.HelmRepository{
+						{
+							Name: "apache-repo",
+							URL:  "oci://registry-1.docker.io/bitnamicharts",
+						},
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'helmCharts' field contains duplicate entries: apache",
+			},
+		},
+		`helm chart invalid values file`: {
 			K8s: image.Kubernetes{
 				Helm: image.Helm{
 					Charts: []image.HelmChart{
@@ -452,22 +1443,30 @@ func TestValidateHelmCharts(t *testing.T) {
 							Name:           "apache",
 							RepositoryName: "apache-repo",
 							Version:        "10.7.0",
+							ValuesFile:     "invalid",
+						},
+					},
+					Repositories: []image.HelmRepository{
+						{
+							Name: "apache-repo",
+							URL:  "oci://registry-1.docker.io/bitnamicharts",
 						},
 					},
 				},
 			},
 			ExpectedFailedMessages: []string{
-				"Helm charts defined with no Helm repositories defined.",
+				"Helm chart 'valuesFile' field for \"apache\" must be the name of a valid yaml file ending in '.yaml' or '.yml'.",
 			},
 		},
-		`helm chart no name`: {
+		`helm chart nonexistent values file`: {
 			K8s: image.Kubernetes{
 				Helm: image.Helm{
 					Charts: []image.HelmChart{
 						{
-							Name:           "",
+							Name:           "apache",
 							RepositoryName: "apache-repo",
 							Version:        "10.7.0",
+							ValuesFile:     "nonexistent.yaml",
 						},
 					},
 					Repositories: []image.HelmRepository{
@@ -479,109 +1478,172 @@ func TestValidateHelmCharts(t *testing.T) {
 				},
 			},
 			ExpectedFailedMessages: []string{
-				"Helm chart 'name' field must be defined.",
+				"Helm chart values file 'nonexistent.yaml' could not be found at 'kubernetes/helm/values/nonexistent.yaml'.",
 			},
 		},
-		`helm chart undefined repository name`: {
+		`helm chart installation namespace matches target namespace`: {
 			K8s: image.Kubernetes{
 				Helm: image.Helm{
 					Charts: []image.HelmChart{
 						{
-							Name:           "kubevirt",
-							RepositoryName: "suse-edge",
-							Version:        "0.2.2",
+							Name:                  "apache",
+							RepositoryName:        "apache-repo",
+							Version:               "10.7.0",
+							TargetNamespace:       "web",
+							InstallationNamespace: "web",
 						},
 						{
-							Name:           "metallb",
-							RepositoryName: "",
-							Version:        "0.14.3",
+							Name:                  "metallb",
+							RepositoryName:        "apache-repo",
+							Version:               "0.14.3",
+							TargetNamespace:       "kube-system",
+							InstallationNamespace: "kube-system",
+						},
+						{
+							Name:                  "rancher",
+							RepositoryName:        "apache-repo",
+							Version:               "2.8.4",
+							TargetNamespace:       "cattle-system",
+							InstallationNamespace: "kube-system",
 						},
 					},
 					Repositories: []image.HelmRepository{
 						{
-							Name: "suse-edge",
-							URL:  "https://suse-edge.github.io/charts",
+							Name: "apache-repo",
+							URL:  "oci://registry-1.docker.io/bitnamicharts",
 						},
 					},
 				},
 			},
 			ExpectedFailedMessages: []string{
-				"Helm chart 'repositoryName' field for \"metallb\" must be defined.",
+				"Helm chart 'installationNamespace' for \"apache\" is the same as its 'targetNamespace' \"web\". " +
+					"The HelmChart resource is usually installed in the 'kube-system' namespace while the chart itself is deployed into the 'targetNamespace'.",
 			},
 		},
-		`helm chart no matching repository name`: {
+		`helm chart both values fields`: {
 			K8s: image.Kubernetes{
 				Helm: image.Helm{
 					Charts: []image.HelmChart{
 						{
-							Name:           "kubevirt",
-							RepositoryName: "suse-edge",
-							Version:        "0.2.2",
+							Name:           "apache",
+							RepositoryName: "apache-repo",
+							Version:        "10.7.0",
+							ValuesFile:     "invalid",
+							ValuesFiles:    []string{"nonexistent.yaml", "invalid"},
 						},
+					},
+					Repositories: []image.HelmRepository{
 						{
-							Name:           "metallb",
-							RepositoryName: "this-is-not-suse-edge",
-							Version:        "0.14.3",
+							Name: "apache-repo",
+							URL:  "oci://registry-1.docker.io/bitnamicharts",
+						},
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"Helm chart 'valuesFile' and 'valuesFiles' fields for \"apache\" cannot both be defined.",
+				"Helm chart 'valuesFile' field for \"apache\" must be the name of a valid yaml file ending in '.yaml' or '.yml'.",
+				"Helm chart values file 'nonexistent.yaml' could not be found at 'kubernetes/helm/values/nonexistent.yaml'.",
+				"Helm chart 'valuesFiles' field for \"apache\" must be the name of a valid yaml file ending in '.yaml' or '.yml'.",
+			},
+		},
+		`helm repository no name`: {
+			K8s: image.Kubernetes{
+				Helm: image.Helm{
+					Charts: []image.HelmChart{
+						{
+							Name:           "apache",
+							RepositoryName: "apache-repo",
+							Version:        "10.7.0",
 						},
 					},
 					Repositories: []image.HelmRepository{
 						{
-							Name: "suse-edge",
+							Name: "",
 							URL:  "https://suse-edge.github.io/charts",
 						},
+						{
+							Name: "apache-repo",
+							URL:  "oci://registry-1.docker.io/bitnamicharts",
+						},
 					},
 				},
 			},
 			ExpectedFailedMessages: []string{
-				"Helm chart 'repositoryName' \"this-is-not-suse-edge\" for Helm chart \"metallb\" does not match the name of any defined repository.",
+				"Helm repository 'name' field must be defined.",
 			},
 		},
-		`helm chart no version`: {
+		`helm repository no url`: {
 			K8s: image.Kubernetes{
 				Helm: image.Helm{
 					Charts: []image.HelmChart{
 						{
 							Name:           "apache",
 							RepositoryName: "apache-repo",
-							Version:        "",
+							Version:        "10.7.0",
 						},
 					},
 					Repositories: []image.HelmRepository{
 						{
 							Name: "apache-repo",
-							URL:  "oci://registry-1.docker.io/bitnamicharts",
+							URL:  "",
 						},
 					},
 				},
 			},
 			ExpectedFailedMessages: []string{
-				"Helm chart 'version' field for \"apache\" field must be defined.",
+				"Helm repository 'url' field for \"apache-repo\" must be defined.",
 			},
 		},
-		`helm chart create namespace no target`: {
+		`helm repository invalid url`: {
 			K8s: image.Kubernetes{
 				Helm: image.Helm{
 					Charts: []image.HelmChart{
 						{
-							Name:            "apache",
-							RepositoryName:  "apache-repo",
-							Version:         "10.7.0",
-							CreateNamespace: true,
+							Name:           "apache",
+							RepositoryName: "apache-repo",
+							Version:        "10.7.0",
+						},
+					},
+					Repositories: []image.HelmRepository{
+						{
+							Name: "apache-repo",
+							URL:  "invalid.repo.io/bitnami",
+						},
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"Helm repository 'url' field for \"apache-repo\" must begin with either 'oci://', 'http://', or 'https://'.",
+			},
+		},
+		`helm repository username no password`: {
+			K8s: image.Kubernetes{
+				Helm: image.Helm{
+					Charts: []image.HelmChart{
+						{
+							Name:           "apache",
+							RepositoryName: "apache-repo",
+							Version:        "10.7.0",
 						},
 					},
 					Repositories: []image.HelmRepository{
 						{
 							Name: "apache-repo",
 							URL:  "oci://registry-1.docker.io/bitnamicharts",
+							Authentication: image.HelmAuthentication{
+								Username: "user",
+								Password: "",
+							},
 						},
 					},
 				},
 			},
 			ExpectedFailedMessages: []string{
-				"Helm chart 'createNamespace' field for \"apache\" cannot be true without 'targetNamespace' being defined.",
+				"Helm repository 'password' field not defined for \"apache-repo\".",
 			},
 		},
-		`helm chart duplicate name`: {
+		`helm repository password no username`: {
 			K8s: image.Kubernetes{
 				Helm: image.Helm{
 					Charts: []image.HelmChart{
@@ -590,6 +1652,27 @@ func TestValidateHelmCharts(t *testing.T) {
 							RepositoryName: "apache-repo",
 							Version:        "10.7.0",
 						},
+					},
+					Repositories: []image.HelmRepository{
+						{
+							Name: "apache-repo",
+							URL:  "oci://registry-1.docker.io/bitnamicharts",
+							Authentication: image.HelmAuthentication{
+								Username: "",
+								Password: "pass",
+							},
+						},
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"Helm repository 'username' field not defined for \"apache-repo\".",
+			},
+		},
+		`helm repository both skipTLSVerify and plainHTTP true`: {
+			K8s: image.Kubernetes{
+				Helm: image.Helm{
+					Charts: []image.HelmChart{
 						{
 							Name:           "apache",
 							RepositoryName: "apache-repo",
@@ -598,111 +1681,140 @@ func TestValidateHelmCharts(t *testing.T) {
 					},
 					Repositories: []image.HelmRepository{
 						{
-							Name: "apache-repo",
-							URL:  "oci://registry-1.docker.io/bitnamicharts",
+							Name:          "apache-repo",
+							URL:           "oci://registry-1.docker.io/bitnamicharts",
+							SkipTLSVerify: true,
+							PlainHTTP:     true,
+						},
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"Helm repository 'plainHTTP' and 'skipTLSVerify' fields for \"apache-repo\" cannot both be true.",
+			},
+		},
+		`helm repository skipTLSVerify true for http`: {
+			K8s: image.Kubernetes{
+				Helm: image.Helm{
+					Charts: []image.HelmChart{
+						{
+							Name:           "metallb",
+							RepositoryName: "suse-edge",
+							Version:        "0.14.3",
+						},
+					},
+					Repositories: []image.HelmRepository{
+						{
+							Name:          "suse-edge",
+							URL:           "http://suse-edge.github.io/charts",
+							SkipTLSVerify: true,
 						},
 					},
 				},
 			},
 			ExpectedFailedMessages: []string{
-				"The 'helmCharts' field contains duplicate entries: apache",
+				"Helm repository 'url' field for \"suse-edge\" contains 'http://' but 'plainHTTP' field is false.",
+				"Helm repository 'url' field for \"suse-edge\" contains 'http://' but 'skipTLSVerify' field is true.",
 			},
 		},
-		`helm chart invalid values file`: {
+		`helm repository plainHTTP false for http`: {
 			K8s: image.Kubernetes{
 				Helm: image.Helm{
 					Charts: []image.HelmChart{
 						{
-							Name:           "apache",
-							RepositoryName: "apache-repo",
-							Version:        "10.7.0",
-							ValuesFile:     "invalid",
+							Name:           "metallb",
+							RepositoryName: "suse-edge",
+							Version:        "0.14.3",
 						},
 					},
 					Repositories: []image.HelmRepository{
 						{
-							Name: "apache-repo",
-							URL:  "oci://registry-1.docker.io/bitnamicharts",
+							Name:      "suse-edge",
+							URL:       "http://suse-edge.github.io/charts",
+							PlainHTTP: false,
 						},
 					},
 				},
 			},
 			ExpectedFailedMessages: []string{
-				"Helm chart 'valuesFile' field for \"apache\" must be the name of a valid yaml file ending in '.yaml' or '.yml'.",
+				"Helm repository 'url' field for \"suse-edge\" contains 'http://' but 'plainHTTP' field is false.",
 			},
 		},
-		`helm chart nonexistent values file`: {
+		`helm repository plainHTTP true for https`: {
 			K8s: image.Kubernetes{
 				Helm: image.Helm{
 					Charts: []image.HelmChart{
 						{
-							Name:           "apache",
-							RepositoryName: "apache-repo",
-							Version:        "10.7.0",
-							ValuesFile:     "nonexistent.yaml",
+							Name:           "metallb",
+							RepositoryName: "suse-edge",
+							Version:        "0.14.3",
 						},
 					},
 					Repositories: []image.HelmRepository{
 						{
-							Name: "apache-repo",
-							URL:  "oci://registry-1.docker.io/bitnamicharts",
+							Name:      "suse-edge",
+							URL:       "https://suse-edge.github.io/charts",
+							PlainHTTP: true,
 						},
 					},
 				},
 			},
 			ExpectedFailedMessages: []string{
-				"Helm chart values file 'nonexistent.yaml' could not be found at 'kubernetes/helm/values/nonexistent.yaml'.",
+				"Helm repository 'url' field for \"suse-edge\" contains 'https://' but 'plainHTTP' field is true.",
 			},
 		},
-		`helm repository no name`: {
+		`helm repository plainHTTP and ca file`: {
 			K8s: image.Kubernetes{
 				Helm: image.Helm{
 					Charts: []image.HelmChart{
 						{
-							Name:           "apache",
-							RepositoryName: "apache-repo",
-							Version:        "10.7.0",
+							Name:           "metallb",
+							RepositoryName: "suse-edge",
+							Version:        "0.14.3",
 						},
 					},
 					Repositories: []image.HelmRepository{
 						{
-							Name: "",
-							URL:  "https://suse-edge.github.io/charts",
-						},
-						{
-							Name: "apache-repo",
-							URL:  "oci://registry-1.docker.io/bitnamicharts",
+							Name:      "suse-edge",
+							URL:       "http://suse-edge.github.io/charts",
+							PlainHTTP: true,
+							CAFile:    "suse-edge.crt",
 						},
 					},
 				},
 			},
 			ExpectedFailedMessages: []string{
-				"Helm repository 'name' field must be defined.",
+				"Helm repository 'caFile' field for \"suse-edge\" cannot be defined while 'plainHTTP' is true.",
+				"Helm repository 'url' field for \"suse-edge\" contains 'http://' but 'caFile' field is defined.",
+				"Helm repo cert file/bundle 'suse-edge.crt' could not be found at 'kubernetes/helm/certs/suse-edge.crt'.",
 			},
 		},
-		`helm repository no url`: {
+		`helm repository skipTLSVerify and ca file`: {
 			K8s: image.Kubernetes{
 				Helm: image.Helm{
 					Charts: []image.HelmChart{
 						{
-							Name:           "apache",
-							RepositoryName: "apache-repo",
-							Version:        "10.7.0",
+							Name:           "metallb",
+							RepositoryName: "suse-edge",
+							Version:        "0.14.3",
 						},
 					},
 					Repositories: []image.HelmRepository{
 						{
-							Name: "apache-repo",
-							URL:  "",
+							Name:          "suse-edge",
+							URL:           "https://suse-edge.github.io/charts",
+							SkipTLSVerify: true,
+							CAFile:        "suse-edge.crt",
 						},
 					},
 				},
 			},
 			ExpectedFailedMessages: []string{
-				"Helm repository 'url' field for \"apache-repo\" must be defined.",
+				"Helm repository 'caFile' field for \"suse-edge\" cannot be defined while 'skipTLSVerify' is true.",
+				"Helm repo cert file/bundle 'suse-edge.crt' could not be found at 'kubernetes/helm/certs/suse-edge.crt'.",
 			},
 		},
-		`helm repository invalid url`: {
+		`helm repo nonexistent cert file`: {
 			K8s: image.Kubernetes{
 				Helm: image.Helm{
 					Charts: []image.HelmChart{
@@ -714,17 +1826,18 @@ func TestValidateHelmCharts(t *testing.T) {
 					},
 					Repositories: []image.HelmRepository{
 						{
-							Name: "apache-repo",
-							URL:  "invalid.repo.io/bitnami",
+							Name:   "apache-repo",
+							URL:    "oci://registry-1.docker.io/bitnamicharts",
+							CAFile: "nonexistent-apache.crt",
 						},
 					},
 				},
 			},
 			ExpectedFailedMessages: []string{
-				"Helm repository 'url' field for \"apache-repo\" must begin with either 'oci://', 'http://', or 'https://'.",
+				"Helm repo cert file/bundle 'nonexistent-apache.crt' could not be found at 'kubernetes/helm/certs/nonexistent-apache.crt'.",
 			},
 		},
-		`helm repository username no password`: {
+		`helm repo invalid cert file`: {
 			K8s: image.Kubernetes{
 				Helm: image.Helm{
 					Charts: []image.HelmChart{
@@ -736,254 +1849,602 @@ func TestValidateHelmCharts(t *testing.T) {
 					},
 					Repositories: []image.HelmRepository{
 						{
-							Name: "apache-repo",
-							URL:  "oci://registry-1.docker.io/bitnamicharts",
-							Authentication: image.HelmAuthentication{
-								Username: "user",
-								Password: "",
-							},
+							Name:   "apache-repo",
+							URL:    "oci://registry-1.docker.io/bitnamicharts",
+							CAFile: "invalid-cert",
 						},
 					},
 				},
 			},
 			ExpectedFailedMessages: []string{
-				"Helm repository 'password' field not defined for \"apache-repo\".",
+				"Helm chart 'caFile' field for \"apache-repo\" must be the name of a valid cert file/bundle with one of the " +
+					"following extensions: .pem, .crt, .cer",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			k := test.K8s
+			failures := validateHelm(&k, "")
+			assert.Len(t, failures, len(test.ExpectedFailedMessages))
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+			}
+
+			for _, expectedMessage := range test.ExpectedFailedMessages {
+				assert.Contains(t, foundMessages, expectedMessage)
+			}
+		})
+	}
+}
+
+func TestValidateHelmChartValues(t *testing.T) {
+	configDir := t.TempDir()
+
+	valuesDir := filepath.Join(configDir, combustion.K8sDir, combustion.HelmDir, combustion.ValuesDir)
+	require.NoError(t, os.MkdirAll(valuesDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(valuesDir, "base.yaml"), []byte("replicaCount: 1\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(valuesDir, "broken.yaml"), []byte("replicaCount: [1\n"), 0o600))
+
+	assert.Empty(t, validateHelmChartValues("apache", "valuesFiles", "base.yaml", configDir))
+	assert.Equal(t, "Helm chart values file 'broken.yaml' is not a valid YAML document.",
+		validateHelmChartValues("apache", "valuesFiles", "broken.yaml", configDir))
+}
+
+func TestValidateHelmUnreferencedFiles(t *testing.T) {
+	configDir := t.TempDir()
+
+	valuesDir := filepath.Join(configDir, combustion.K8sDir, combustion.HelmDir, combustion.ValuesDir)
+	require.NoError(t, os.MkdirAll(valuesDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(valuesDir, "apache-values.yaml"), []byte(""), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(valuesDir, "apache-vaules.yaml"), []byte(""), 0o600))
+
+	certsDir := filepath.Join(configDir, combustion.K8sDir, combustion.HelmDir, combustion.CertsDir)
+	require.NoError(t, os.MkdirAll(certsDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(certsDir, "apache.crt"), []byte(""), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(certsDir, "old-apache.crt"), []byte(""), 0o600))
+
+	k8s := image.Kubernetes{
+		Helm: image.Helm{
+			Charts: []image.HelmChart{
+				{
+					Name:           "apache",
+					RepositoryName: "apache-repo",
+					ValuesFile:     "apache-values.yaml",
+				},
+			},
+			Repositories: []image.HelmRepository{
+				{
+					Name:   "apache-repo",
+					URL:    "https://suse-edge.github.io/charts",
+					CAFile: "apache.crt",
+				},
+			},
+		},
+	}
+
+	failures := validateHelmUnreferencedFiles(&k8s, configDir)
+
+	var foundMessages []string
+	for _, foundValidation := range failures {
+		foundMessages = append(foundMessages, foundValidation.UserMessage)
+		assert.Equal(t, SeverityWarning, foundValidation.Severity)
+	}
+
+	assert.Equal(t, []string{
+		"Unreferenced file 'apache-vaules.yaml' found in the Helm values directory.",
+		"Unreferenced file 'old-apache.crt' found in the Helm certs directory.",
+	}, foundMessages)
+}
+
+func TestValidateHelmUnreferencedFiles_MissingDirectories(t *testing.T) {
+	k8s := image.Kubernetes{
+		Helm: image.Helm{
+			Charts: []image.HelmChart{
+				{
+					Name:           "apache",
+					RepositoryName: "apache-repo",
+				},
+			},
+		},
+	}
+
+	assert.Empty(t, validateHelmUnreferencedFiles(&k8s, t.TempDir()))
+}
+
+func TestValidateComponentHelmCharts(t *testing.T) {
+	manageVIP := false
+
+	userMetalLB := image.Helm{
+		Charts: []image.HelmChart{
+			{
+				Name:                  "metallb",
+				RepositoryName:        "metallb-repo",
+				TargetNamespace:       "metallb-system",
+				InstallationNamespace: "kube-system",
+				Version:               "0.14.8",
+			},
+		},
+		Repositories: []image.HelmRepository{
+			{
+				Name: "metallb-repo",
+				URL:  "https://metallb.github.io/metallb",
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		Network                image.Network
+		Helm                   image.Helm
+		ExpectedFailedMessages []string
+	}{
+		`user metallb chart without apiVIP`: {
+			Helm: userMetalLB,
+		},
+		`user metallb chart with apiVIP`: {
+			Network: image.Network{
+				APIVIP: "192.168.122.100",
+			},
+			Helm: userMetalLB,
+			ExpectedFailedMessages: []string{
+				"Helm chart 'metallb' conflicts with the chart of the same name automatically deployed when the 'apiVIP' is set. " +
+					"Either remove the chart or set 'manageVIP' to false in the 'network' section.",
+			},
+		},
+		`user metallb chart with unmanaged apiVIP`: {
+			Network: image.Network{
+				APIVIP:    "192.168.122.100",
+				ManageVIP: &manageVIP,
+			},
+			Helm: userMetalLB,
+		},
+		`same repository name with a different URL`: {
+			Network: image.Network{
+				APIVIP: "192.168.122.100",
+			},
+			Helm: image.Helm{
+				Charts: []image.HelmChart{
+					{
+						Name:           "apache",
+						RepositoryName: "suse-edge",
+					},
+				},
+				Repositories: []image.HelmRepository{
+					{
+						Name: "suse-edge",
+						URL:  "https://charts.example.com",
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"Helm repository 'suse-edge' conflicts with the repository of the same name automatically added when the 'apiVIP' is set. " +
+					"Either rename the repository or use the 'https://suse-edge.github.io/charts' URL.",
+			},
+		},
+		`same repository`: {
+			Network: image.Network{
+				APIVIP: "192.168.122.100",
+			},
+			Helm: image.Helm{
+				Charts: []image.HelmChart{
+					{
+						Name:           "kubevirt",
+						RepositoryName: "suse-edge",
+					},
+				},
+				Repositories: []image.HelmRepository{
+					{
+						Name: "suse-edge",
+						URL:  "https://suse-edge.github.io/charts",
+					},
+				},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := image.Context{
+				ImageDefinition: &image.Definition{
+					Kubernetes: image.Kubernetes{
+						Version: "v1.30.3+rke2r1",
+						Network: test.Network,
+						Helm:    test.Helm,
+					},
+				},
+			}
+
+			failures := validateComponentHelmCharts(&ctx)
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+		})
+	}
+}
+
+func TestValidateHelmRepositoriesReachable(t *testing.T) {
+	k8s := image.Kubernetes{
+		Version: "v1.29.0+rke2r1",
+		Helm: image.Helm{
+			Charts: []image.HelmChart{
+				{Name: "apache", RepositoryName: "reachable", Version: "10.7.0"},
+				{Name: "metallb", RepositoryName: "private", Version: "0.14.3"},
+				{Name: "kubevirt", RepositoryName: "typo", Version: "0.2.4"},
+			},
+			Repositories: []image.HelmRepository{
+				{Name: "reachable", URL: "https://charts.example.com"},
+				{Name: "private", URL: "oci://registry.example.com/charts"},
+				{Name: "typo", URL: "https://chrts.example.com"},
+			},
+		},
+	}
+
+	offlineClient, err := http.NewClient(http.ClientConfig{Offline: true})
+	require.NoError(t, err)
+
+	var checked []string
+	defer func(checker func(context.Context, *image.HelmRepository, string, *x509.CertPool) error) {
+		helmRepositoryChecker = checker
+	}(helmRepositoryChecker)
+
+	helmRepositoryChecker = func(_ context.Context, repo *image.HelmRepository, _ string, _ *x509.CertPool) error {
+		checked = append(checked, repo.Name)
+
+		switch repo.Name {
+		case "private":
+			return fmt.Errorf("%w: status code 401", helm.ErrUnauthorized)
+		case "typo":
+			return errors.New("executing request: no such host")
+		default:
+			return nil
+		}
+	}
+
+	tests := map[string]struct {
+		Context                image.Context
+		ExpectedChecked        []string
+		ExpectedFailedMessages []string
+	}{
+		`preflight disabled`: {
+			Context: image.Context{},
+		},
+		`preflight enabled`: {
+			Context: image.Context{
+				Preflight: true,
+			},
+			ExpectedChecked: []string{"reachable", "private", "typo"},
+			ExpectedFailedMessages: []string{
+				"Helm repository \"private\" rejected the configured authentication.",
+				"Helm repository \"typo\" could not be reached at 'https://chrts.example.com'.",
+			},
+		},
+		`preflight skipped in offline mode`: {
+			Context: image.Context{
+				Preflight:  true,
+				HTTPClient: offlineClient,
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			checked = nil
+
+			ctx := test.Context
+			ctx.ImageDefinition = &image.Definition{Kubernetes: k8s}
+
+			failures := validateKubernetes(&ctx)
+			assert.Len(t, failures, len(test.ExpectedFailedMessages))
+			assert.Equal(t, test.ExpectedChecked, checked)
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+				assert.Error(t, foundValidation.Error)
+			}
+
+			for _, expectedMessage := range test.ExpectedFailedMessages {
+				assert.Contains(t, foundMessages, expectedMessage)
+			}
+		})
+	}
+
+	// Repositories which are not referenced by any chart are not checked
+	checked = nil
+	k8s.Helm.Charts = k8s.Helm.Charts[:1]
+
+	failures := validateHelmRepositoriesReachable(nil, &k8s, "")
+	assert.Empty(t, failures)
+	assert.Equal(t, []string{"reachable"}, checked)
+}
+
+type fakeHelmClient struct {
+	templated []string
+}
+
+func (f *fakeHelmClient) AddRepo(*image.HelmRepository) error {
+	return nil
+}
+
+func (f *fakeHelmClient) RegistryLogin(*image.HelmRepository) error {
+	return nil
+}
+
+func (f *fakeHelmClient) Pull(chart string, _ *image.HelmRepository, version, destDir string) (string, error) {
+	return filepath.Join(destDir, fmt.Sprintf("%s-%s.tgz", chart, version)), nil
+}
+
+func (f *fakeHelmClient) Template(chart, _, _ string, _ []string, _, _ string, _ bool) ([]map[string]any, error) {
+	f.templated = append(f.templated, chart)
+
+	if chart == "broken" {
+		return nil, fmt.Errorf("executing helm template command for chart %s: exit status 1", chart)
+	}
+
+	return nil, nil
+}
+
+func TestValidateHelmChartsRender(t *testing.T) {
+	k8s := image.Kubernetes{
+		Version: "v1.29.0+rke2r1",
+		Helm: image.Helm{
+			Charts: []image.HelmChart{
+				{Name: "apache", RepositoryName: "suse-edge", Version: "10.7.0", ValuesFile: "apache-values.yaml"},
+				{Name: "broken", RepositoryName: "suse-edge", Version: "1.0.0"},
+				{Name: "orphan", RepositoryName: "missing", Version: "1.0.0"},
+			},
+			Repositories: []image.HelmRepository{
+				{Name: "suse-edge", URL: "https://suse-edge.github.io/charts"},
 			},
 		},
-		`helm repository password no username`: {
-			K8s: image.Kubernetes{
+	}
+
+	client := &fakeHelmClient{}
+
+	defer func(factory func(*http.Client, string, string) image.HelmClient) {
+		newHelmClient = factory
+	}(newHelmClient)
+
+	newHelmClient = func(*http.Client, string, string) image.HelmClient {
+		return client
+	}
+
+	failures := validateHelmChartsRender(nil, &k8s, t.TempDir())
+
+	assert.Equal(t, []string{"apache", "broken"}, client.templated)
+
+	require.Len(t, failures, 1)
+	assert.Equal(t, "Helm chart 'broken' could not be rendered.", failures[0].UserMessage)
+	assert.EqualError(t, failures[0].Error, "templating chart: executing helm template command for chart broken: exit status 1")
+}
+
+func TestValidateKubernetesHelmChartsRenderOffline(t *testing.T) {
+	defer func(factory func(*http.Client, string, string) image.HelmClient) {
+		newHelmClient = factory
+	}(newHelmClient)
+
+	newHelmClient = func(*http.Client, string, string) image.HelmClient {
+		panic("charts must not be rendered in offline mode")
+	}
+
+	client, err := http.NewClient(http.ClientConfig{Offline: true})
+	require.NoError(t, err)
+
+	ctx := image.Context{
+		ImageConfigDir:   t.TempDir(),
+		RenderHelmCharts: true,
+		HTTPClient:       client,
+		ImageDefinition: &image.Definition{
+			Kubernetes: image.Kubernetes{
+				Version: "v1.29.0+rke2r1",
 				Helm: image.Helm{
 					Charts: []image.HelmChart{
-						{
-							Name:           "apache",
-							RepositoryName: "apache-repo",
-							Version:        "10.7.0",
-						},
+						{Name: "apache", RepositoryName: "suse-edge", Version: "10.7.0", TargetNamespace: "web", InstallationNamespace: "kube-system"},
 					},
 					Repositories: []image.HelmRepository{
-						{
-							Name: "apache-repo",
-							URL:  "oci://registry-1.docker.io/bitnamicharts",
-							Authentication: image.HelmAuthentication{
-								Username: "",
-								Password: "pass",
-							},
-						},
+						{Name: "suse-edge", URL: "https://suse-edge.github.io/charts"},
 					},
 				},
 			},
-			ExpectedFailedMessages: []string{
-				"Helm repository 'username' field not defined for \"apache-repo\".",
-			},
 		},
-		`helm repository both skipTLSVerify and plainHTTP true`: {
-			K8s: image.Kubernetes{
-				Helm: image.Helm{
-					Charts: []image.HelmChart{
-						{
-							Name:           "apache",
-							RepositoryName: "apache-repo",
-							Version:        "10.7.0",
-						},
-					},
-					Repositories: []image.HelmRepository{
-						{
-							Name:          "apache-repo",
-							URL:           "oci://registry-1.docker.io/bitnamicharts",
-							SkipTLSVerify: true,
-							PlainHTTP:     true,
-						},
-					},
+	}
+
+	assert.NotPanics(t, func() {
+		validateKubernetes(&ctx)
+	})
+}
+
+func TestValidateIsoPayloadSize(t *testing.T) {
+	defer func(estimator func(*image.Context) (int64, error)) {
+		isoPayloadSizeEstimator = estimator
+	}(isoPayloadSizeEstimator)
+
+	isoPayloadSizeEstimator = func(ctx *image.Context) (int64, error) {
+		if ctx.ImageDefinition.Kubernetes.Version == "v1.30.3+k3s1" {
+			return 0, errors.New("reading base image file info")
+		}
+
+		return 6000, nil
+	}
+
+	tests := map[string]struct {
+		Definition             image.Definition
+		ExpectedFailedMessages []string
+	}{
+		`raw image`: {
+			Definition: image.Definition{
+				Image: image.Image{
+					ImageType: image.TypeRAW,
 				},
 			},
-			ExpectedFailedMessages: []string{
-				"Helm repository 'plainHTTP' and 'skipTLSVerify' fields for \"apache-repo\" cannot both be true.",
-			},
 		},
-		`helm repository skipTLSVerify true for http`: {
-			K8s: image.Kubernetes{
-				Helm: image.Helm{
-					Charts: []image.HelmChart{
-						{
-							Name:           "metallb",
-							RepositoryName: "suse-edge",
-							Version:        "0.14.3",
-						},
-					},
-					Repositories: []image.HelmRepository{
-						{
-							Name:          "suse-edge",
-							URL:           "http://suse-edge.github.io/charts",
-							SkipTLSVerify: true,
-						},
-					},
+		`exceeds default capacity`: {
+			Definition: image.Definition{
+				Image: image.Image{
+					ImageType: image.TypeISO,
 				},
 			},
 			ExpectedFailedMessages: []string{
-				"Helm repository 'url' field for \"suse-edge\" contains 'http://' but 'plainHTTP' field is false.",
-				"Helm repository 'url' field for \"suse-edge\" contains 'http://' but 'skipTLSVerify' field is true.",
+				"The estimated size of the ISO image including the Kubernetes artefacts and the embedded artifact registry (6000 MB) " +
+					"exceeds the 4482 MB capacity of the target media. Consider building a RAW image or setting 'isoConfiguration/maxSize'.",
 			},
 		},
-		`helm repository plainHTTP false for http`: {
-			K8s: image.Kubernetes{
-				Helm: image.Helm{
-					Charts: []image.HelmChart{
-						{
-							Name:           "metallb",
-							RepositoryName: "suse-edge",
-							Version:        "0.14.3",
-						},
-					},
-					Repositories: []image.HelmRepository{
-						{
-							Name:      "suse-edge",
-							URL:       "http://suse-edge.github.io/charts",
-							PlainHTTP: false,
-						},
+		`within configured capacity`: {
+			Definition: image.Definition{
+				Image: image.Image{
+					ImageType: image.TypeISO,
+				},
+				OperatingSystem: image.OperatingSystem{
+					IsoConfiguration: image.IsoConfiguration{
+						MaxSize: "25G",
 					},
 				},
 			},
-			ExpectedFailedMessages: []string{
-				"Helm repository 'url' field for \"suse-edge\" contains 'http://' but 'plainHTTP' field is false.",
-			},
 		},
-		`helm repository plainHTTP true for https`: {
-			K8s: image.Kubernetes{
-				Helm: image.Helm{
-					Charts: []image.HelmChart{
-						{
-							Name:           "metallb",
-							RepositoryName: "suse-edge",
-							Version:        "0.14.3",
-						},
-					},
-					Repositories: []image.HelmRepository{
-						{
-							Name:      "suse-edge",
-							URL:       "https://suse-edge.github.io/charts",
-							PlainHTTP: true,
-						},
+		`exceeds configured capacity`: {
+			Definition: image.Definition{
+				Image: image.Image{
+					ImageType: image.TypeISO,
+				},
+				OperatingSystem: image.OperatingSystem{
+					IsoConfiguration: image.IsoConfiguration{
+						MaxSize: "700M",
 					},
 				},
 			},
 			ExpectedFailedMessages: []string{
-				"Helm repository 'url' field for \"suse-edge\" contains 'https://' but 'plainHTTP' field is true.",
+				"The estimated size of the ISO image including the Kubernetes artefacts and the embedded artifact registry (6000 MB) " +
+					"exceeds the 700 MB capacity of the target media. Consider building a RAW image or setting 'isoConfiguration/maxSize'.",
 			},
 		},
-		`helm repository plainHTTP and ca file`: {
-			K8s: image.Kubernetes{
-				Helm: image.Helm{
-					Charts: []image.HelmChart{
-						{
-							Name:           "metallb",
-							RepositoryName: "suse-edge",
-							Version:        "0.14.3",
-						},
-					},
-					Repositories: []image.HelmRepository{
-						{
-							Name:      "suse-edge",
-							URL:       "http://suse-edge.github.io/charts",
-							PlainHTTP: true,
-							CAFile:    "suse-edge.crt",
-						},
-					},
+		`estimation failure`: {
+			Definition: image.Definition{
+				Image: image.Image{
+					ImageType: image.TypeISO,
+				},
+				Kubernetes: image.Kubernetes{
+					Version: "v1.30.3+k3s1",
 				},
-			},
-			ExpectedFailedMessages: []string{
-				"Helm repository 'caFile' field for \"suse-edge\" cannot be defined while 'plainHTTP' is true.",
-				"Helm repository 'url' field for \"suse-edge\" contains 'http://' but 'caFile' field is defined.",
-				"Helm repo cert file/bundle 'suse-edge.crt' could not be found at 'kubernetes/helm/certs/suse-edge.crt'.",
 			},
 		},
-		`helm repository skipTLSVerify and ca file`: {
-			K8s: image.Kubernetes{
-				Helm: image.Helm{
-					Charts: []image.HelmChart{
-						{
-							Name:           "metallb",
-							RepositoryName: "suse-edge",
-							Version:        "0.14.3",
-						},
-					},
-					Repositories: []image.HelmRepository{
-						{
-							Name:          "suse-edge",
-							URL:           "https://suse-edge.github.io/charts",
-							SkipTLSVerify: true,
-							CAFile:        "suse-edge.crt",
-						},
-					},
-				},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			def := test.Definition
+			ctx := image.Context{
+				ImageDefinition: &def,
+			}
+			failures := validateIsoPayloadSize(&ctx)
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+				assert.Equal(t, SeverityWarning, foundValidation.Severity)
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+		})
+	}
+}
+
+func TestEstimateIsoPayloadSize(t *testing.T) {
+	configDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(configDir, "base-images"), 0o755))
+
+	baseImage, err := os.Create(filepath.Join(configDir, "base-images", "base.iso"))
+	require.NoError(t, err)
+	require.NoError(t, baseImage.Truncate(1024<<20))
+	require.NoError(t, baseImage.Close())
+
+	ctx := image.Context{
+		ImageConfigDir: configDir,
+		ImageDefinition: &image.Definition{
+			Image: image.Image{
+				BaseImage: "base.iso",
 			},
-			ExpectedFailedMessages: []string{
-				"Helm repository 'caFile' field for \"suse-edge\" cannot be defined while 'skipTLSVerify' is true.",
-				"Helm repo cert file/bundle 'suse-edge.crt' could not be found at 'kubernetes/helm/certs/suse-edge.crt'.",
+			Kubernetes: image.Kubernetes{
+				Version: "v1.30.3+rke2r1",
+			},
+			EmbeddedArtifactRegistry: image.EmbeddedArtifactRegistry{
+				SizeBudget: "2G",
 			},
 		},
-		`helm repo nonexistent cert file`: {
-			K8s: image.Kubernetes{
-				Helm: image.Helm{
-					Charts: []image.HelmChart{
-						{
-							Name:           "apache",
-							RepositoryName: "apache-repo",
-							Version:        "10.7.0",
-						},
-					},
-					Repositories: []image.HelmRepository{
-						{
-							Name:   "apache-repo",
-							URL:    "oci://registry-1.docker.io/bitnamicharts",
-							CAFile: "nonexistent-apache.crt",
-						},
-					},
-				},
+	}
+
+	size, err := estimateIsoPayloadSize(&ctx)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1024+rke2ArtefactsSizeMB+2048, size)
+}
+
+func TestValidateComponentHelmRepositories(t *testing.T) {
+	defer func(repository string) {
+		env.EdgeHelmRepository = repository
+	}(env.EdgeHelmRepository)
+
+	tests := map[string]struct {
+		Repository             string
+		Network                image.Network
+		ExpectedFailedMessages []string
+	}{
+		`configured`: {
+			Repository: "https://suse-edge.github.io/charts",
+			Network: image.Network{
+				APIVIP: "192.168.122.100",
+			},
+		},
+		`not configured without apiVIP`: {
+			Repository: "",
+		},
+		`not configured`: {
+			Repository: "",
+			Network: image.Network{
+				APIVIP: "192.168.122.100",
 			},
 			ExpectedFailedMessages: []string{
-				"Helm repo cert file/bundle 'nonexistent-apache.crt' could not be found at 'kubernetes/helm/certs/nonexistent-apache.crt'.",
+				"Artifact source repository for MetalLB is not configured.",
+				"Artifact source repository for Endpoint Copier Operator is not configured.",
 			},
 		},
-		`helm repo invalid cert file`: {
-			K8s: image.Kubernetes{
-				Helm: image.Helm{
-					Charts: []image.HelmChart{
-						{
-							Name:           "apache",
-							RepositoryName: "apache-repo",
-							Version:        "10.7.0",
-						},
-					},
-					Repositories: []image.HelmRepository{
-						{
-							Name:   "apache-repo",
-							URL:    "oci://registry-1.docker.io/bitnamicharts",
-							CAFile: "invalid-cert",
-						},
-					},
-				},
+		`malformed`: {
+			Repository: "suse-edge.github.io/charts",
+			Network: image.Network{
+				APIVIP: "192.168.122.100",
 			},
 			ExpectedFailedMessages: []string{
-				"Helm chart 'caFile' field for \"apache-repo\" must be the name of a valid cert file/bundle with one of the " +
-					"following extensions: .pem, .crt, .cer",
+				"Artifact source repository 'suse-edge.github.io/charts' for MetalLB is not a valid URL.",
+				"Artifact source repository 'suse-edge.github.io/charts' for Endpoint Copier Operator is not a valid URL.",
 			},
 		},
 	}
 
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
-			k := test.K8s
-			failures := validateHelm(&k, "")
-			assert.Len(t, failures, len(test.ExpectedFailedMessages))
+			env.EdgeHelmRepository = test.Repository
+
+			ctx := image.Context{
+				ImageDefinition: &image.Definition{
+					Kubernetes: image.Kubernetes{
+						Version: "v1.30.3+rke2r1",
+						Network: test.Network,
+					},
+				},
+			}
+			failures := validateComponentHelmRepositories(&ctx)
 
 			var foundMessages []string
 			for _, foundValidation := range failures {
 				foundMessages = append(foundMessages, foundValidation.UserMessage)
 			}
 
-			for _, expectedMessage := range test.ExpectedFailedMessages {
-				assert.Contains(t, foundMessages, expectedMessage)
-			}
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
 		})
 	}
 }
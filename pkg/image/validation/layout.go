@@ -0,0 +1,146 @@
+package validation
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"go.uber.org/zap"
+)
+
+const (
+	layoutComponent = "Layout"
+
+	// maxLayoutSuggestionDistance is the largest edit distance between a misnamed directory
+	// and an expected one for the latter to be suggested.
+	maxLayoutSuggestionDistance = 2
+)
+
+// knownDirectories maps the directories of the image configuration directory
+// to the subdirectories EIB expects to find in them.
+var knownDirectories = map[string][]string{
+	"":                {"base-images", "certificates", "custom", "elemental", "firstBoot", "kubernetes", "network", "rpms", "suma"},
+	"custom":          {"files", "scripts"},
+	"firstBoot":       {"scripts"},
+	"rpms":            {"gpg-keys"},
+	"kubernetes":      {"config", "helm", "install", "manifests"},
+	"kubernetes/helm": {"certs", "values"},
+}
+
+func validateLayout(ctx *image.Context) []FailedValidation {
+	var failures []FailedValidation
+
+	var parents []string
+	for parent := range knownDirectories {
+		parents = append(parents, parent)
+	}
+	slices.Sort(parents)
+
+	for _, parent := range parents {
+		entries, err := os.ReadDir(filepath.Join(ctx.ImageConfigDir, parent))
+		if err != nil {
+			if !os.IsNotExist(err) {
+				zap.S().Warnf("Reading the '%s' configuration directory failed: %s", parent, err)
+			}
+			continue
+		}
+
+		for _, entry := range entries {
+			if !entry.IsDir() || slices.Contains(knownDirectories[parent], entry.Name()) {
+				continue
+			}
+
+			dir := path.Join(parent, entry.Name())
+			suggestion := suggestDirectory(entry.Name())
+			if suggestion == "" {
+				continue
+			}
+
+			msg := fmt.Sprintf("Unexpected directory '%s' found in the image configuration directory. Did you mean '%s'?", dir, suggestion)
+			failures = append(failures, FailedValidation{
+				UserMessage: msg,
+				Severity:    SeverityWarning,
+				Code:        CodeUnsupported,
+				Field:       dir,
+			})
+		}
+	}
+
+	return failures
+}
+
+// suggestDirectory returns the expected directory closest to the given directory name.
+// Names are compared against both the last element of the expected directories (e.g. 'manifests')
+// and the dash-joined form of their nested paths (e.g. 'helm-values'). An empty string is
+// returned if no expected directory is close enough.
+func suggestDirectory(name string) string {
+	var suggestion string
+	bestDistance := maxLayoutSuggestionDistance + 1
+
+	name = strings.ToLower(name)
+
+	for _, dir := range expectedDirectories() {
+		elements := strings.Split(dir, "/")
+
+		for i := range elements {
+			candidate := strings.Join(elements[i:], "-")
+
+			distance := editDistance(name, candidate)
+			// Avoid suggesting unrelated directories for very short names
+			if distance*3 > len(name) {
+				continue
+			}
+
+			if distance < bestDistance || (distance == bestDistance && len(dir) < len(suggestion)) {
+				bestDistance = distance
+				suggestion = dir
+			}
+		}
+	}
+
+	return suggestion
+}
+
+func expectedDirectories() []string {
+	var dirs []string
+
+	for parent, children := range knownDirectories {
+		for _, child := range children {
+			dirs = append(dirs, path.Join(parent, child))
+		}
+	}
+
+	slices.Sort(dirs)
+	return dirs
+}
+
+// editDistance calculates the Levenshtein distance between the two strings.
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
@@ -0,0 +1,92 @@
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestValidateLayout(t *testing.T) {
+	tests := map[string]struct {
+		Dirs                   []string
+		Files                  []string
+		ExpectedFailedMessages []string
+	}{
+		`empty`: {},
+		`valid`: {
+			Dirs: []string{
+				"base-images",
+				"custom/files/nested",
+				"custom/scripts",
+				"kubernetes/config",
+				"kubernetes/helm/values",
+				"kubernetes/manifests",
+				"rpms/gpg-keys",
+			},
+		},
+		`unrelated directories`: {
+			Dirs: []string{"docs", "kubernetes/backup"},
+		},
+		`misnamed helm values`: {
+			Dirs: []string{"helm-values"},
+			ExpectedFailedMessages: []string{
+				"Unexpected directory 'helm-values' found in the image configuration directory. Did you mean 'kubernetes/helm/values'?",
+			},
+		},
+		`misnamed manifests`: {
+			Dirs: []string{"kubernetes/manifest"},
+			ExpectedFailedMessages: []string{
+				"Unexpected directory 'kubernetes/manifest' found in the image configuration directory. Did you mean 'kubernetes/manifests'?",
+			},
+		},
+		`misplaced directories`: {
+			Dirs: []string{"custom/gpg-keys", "manifests", "rpm"},
+			ExpectedFailedMessages: []string{
+				"Unexpected directory 'manifests' found in the image configuration directory. Did you mean 'kubernetes/manifests'?",
+				"Unexpected directory 'rpm' found in the image configuration directory. Did you mean 'rpms'?",
+				"Unexpected directory 'custom/gpg-keys' found in the image configuration directory. Did you mean 'rpms/gpg-keys'?",
+			},
+		},
+		`misnamed file is ignored`: {
+			Files: []string{"manifest"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			configDir := t.TempDir()
+			for _, dir := range test.Dirs {
+				require.NoError(t, os.MkdirAll(filepath.Join(configDir, dir), 0o755))
+			}
+			for _, file := range test.Files {
+				require.NoError(t, os.WriteFile(filepath.Join(configDir, file), []byte(""), 0o600))
+			}
+
+			ctx := image.Context{
+				ImageConfigDir: configDir,
+			}
+			failures := validateLayout(&ctx)
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+				assert.Equal(t, SeverityWarning, foundValidation.Severity)
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+		})
+	}
+}
+
+func TestSuggestDirectory(t *testing.T) {
+	assert.Equal(t, "kubernetes/helm/values", suggestDirectory("helm-values"))
+	assert.Equal(t, "kubernetes/helm/values", suggestDirectory("helm_values"))
+	assert.Equal(t, "kubernetes/manifests", suggestDirectory("Manifest"))
+	assert.Equal(t, "certificates", suggestDirectory("certificate"))
+	assert.Equal(t, "", suggestDirectory("docs"))
+	assert.Equal(t, "", suggestDirectory("a"))
+}
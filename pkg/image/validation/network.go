@@ -0,0 +1,376 @@
+package validation
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/suse-edge/edge-image-builder/pkg/combustion"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+// bondModes are the link aggregation modes supported by NMState.
+var bondModes = []string{"balance-rr", "active-backup", "balance-xor", "broadcast", "802.3ad", "balance-tlb", "balance-alb"}
+
+const networkHostsField = "operatingSystem.network.hosts"
+
+func validateNetwork(ctx *image.Context) []FailedValidation {
+	var failures []FailedValidation
+
+	failures = append(failures, validateNetworkHosts(ctx)...)
+	failures = append(failures, validateNetworkDirectoryDNS(combustion.NetworkConfigPath(ctx))...)
+
+	return failures
+}
+
+func validateNetworkHosts(ctx *image.Context) []FailedValidation {
+	hosts := ctx.ImageDefinition.OperatingSystem.Network.Hosts
+	if len(hosts) == 0 {
+		return nil
+	}
+
+	var failures []FailedValidation
+
+	if _, err := os.Stat(combustion.NetworkConfigPath(ctx)); err == nil {
+		msg := "The 'network' section of the operating system cannot be combined with the 'network' configuration directory."
+		failures = append(failures, FailedValidation{
+			UserMessage: msg,
+			Code:        CodeConflict,
+			Field:       networkHostsField,
+		})
+	}
+
+	seenHostnames := make(map[string]bool)
+	for i := range hosts {
+		host := &hosts[i]
+		field := fmt.Sprintf("%s[%d]", networkHostsField, i)
+
+		if host.Hostname == "" {
+			failures = append(failures, FailedValidation{
+				UserMessage: "The 'hostname' field is required for all entries under 'network.hosts'.",
+				Code:        CodeRequired,
+				Field:       field + ".hostname",
+			})
+		} else if !isValidHostname(host.Hostname) {
+			msg := fmt.Sprintf("The network host hostname '%s' is not a valid RFC 1123 hostname.", host.Hostname)
+			failures = append(failures, FailedValidation{
+				UserMessage: msg,
+				Code:        CodeInvalid,
+				Field:       field + ".hostname",
+			})
+		}
+
+		if host.Hostname != "" && seenHostnames[host.Hostname] {
+			msg := fmt.Sprintf("Duplicate network host hostname found: %s", host.Hostname)
+			failures = append(failures, FailedValidation{
+				UserMessage: msg,
+				Code:        CodeDuplicate,
+				Field:       field + ".hostname",
+			})
+		}
+		seenHostnames[host.Hostname] = true
+
+		source := fmt.Sprintf("network host '%s'", host.Hostname)
+
+		failures = append(failures, validateNetworkInterfaces(host, field)...)
+		failures = append(failures, validateNetworkGateway(host, field)...)
+		failures = append(failures, validateDNS(source, field+".dnsServers", field+".dnsSearch", host.DNSServers, host.DNSSearch)...)
+	}
+
+	return failures
+}
+
+func validateNetworkInterfaces(host *image.NetworkHost, hostField string) []FailedValidation {
+	var failures []FailedValidation
+
+	if len(host.Interfaces) == 0 {
+		msg := fmt.Sprintf("Network host '%s' must contain at least one interface.", host.Hostname)
+		failures = append(failures, FailedValidation{
+			UserMessage: msg,
+			Code:        CodeRequired,
+			Field:       hostField + ".interfaces",
+		})
+	}
+
+	interfaceTypes := make(map[string]string)
+	for i, iface := range host.Interfaces {
+		field := fmt.Sprintf("%s.interfaces[%d]", hostField, i)
+
+		if iface.Name == "" {
+			msg := fmt.Sprintf("The 'name' field is required for all interfaces of network host '%s'.", host.Hostname)
+			failures = append(failures, FailedValidation{
+				UserMessage: msg,
+				Code:        CodeRequired,
+				Field:       field + ".name",
+			})
+			continue
+		}
+
+		if _, exists := interfaceTypes[iface.Name]; exists {
+			msg := fmt.Sprintf("Duplicate interface name '%s' found for network host '%s'.", iface.Name, host.Hostname)
+			failures = append(failures, FailedValidation{
+				UserMessage: msg,
+				Code:        CodeDuplicate,
+				Field:       field + ".name",
+			})
+		}
+		interfaceTypes[iface.Name] = iface.InterfaceType()
+	}
+
+	bondPorts := make(map[string]string)
+	for i, iface := range host.Interfaces {
+		if iface.Name == "" {
+			continue
+		}
+
+		field := fmt.Sprintf("%s.interfaces[%d]", hostField, i)
+
+		switch iface.InterfaceType() {
+		case image.NetworkInterfaceTypeEthernet:
+			failures = append(failures, validateEthernetInterface(host.Hostname, &iface, field)...)
+		case image.NetworkInterfaceTypeBond:
+			failures = append(failures, validateBondInterface(host.Hostname, &iface, field, interfaceTypes, bondPorts)...)
+		default:
+			msg := fmt.Sprintf("Interface '%s' of network host '%s' has an invalid type '%s'. It must be one of: %s, %s.",
+				iface.Name, host.Hostname, iface.Type, image.NetworkInterfaceTypeEthernet, image.NetworkInterfaceTypeBond)
+			failures = append(failures, FailedValidation{
+				UserMessage: msg,
+				Code:        CodeUnsupported,
+				Field:       field + ".type",
+			})
+		}
+
+		for j, address := range iface.Addresses {
+			if _, err := netip.ParsePrefix(address); err != nil {
+				msg := fmt.Sprintf("Address '%s' of interface '%s' of network host '%s' must be an IP address in CIDR notation (e.g. '192.168.122.50/24').",
+					address, iface.Name, host.Hostname)
+				failures = append(failures, FailedValidation{
+					UserMessage: msg,
+					Error:       err,
+					Code:        CodeInvalid,
+					Field:       fmt.Sprintf("%s.addresses[%d]", field, j),
+				})
+			}
+		}
+	}
+
+	for i, iface := range host.Interfaces {
+		if _, isPort := bondPorts[iface.Name]; isPort && len(iface.Addresses) != 0 {
+			msg := fmt.Sprintf("Interface '%s' of network host '%s' cannot have addresses since it is a port of bond '%s'.",
+				iface.Name, host.Hostname, bondPorts[iface.Name])
+			failures = append(failures, FailedValidation{
+				UserMessage: msg,
+				Code:        CodeConflict,
+				Field:       fmt.Sprintf("%s.interfaces[%d].addresses", hostField, i),
+			})
+		}
+	}
+
+	return failures
+}
+
+func validateEthernetInterface(hostname string, iface *image.NetworkInterface, field string) []FailedValidation {
+	var failures []FailedValidation
+
+	// The network configurator identifies the node through the MAC addresses of its ethernet interfaces
+	if iface.MACAddress == "" {
+		msg := fmt.Sprintf("The 'macAddress' field is required for ethernet interface '%s' of network host '%s'.", iface.Name, hostname)
+		failures = append(failures, FailedValidation{
+			UserMessage: msg,
+			Code:        CodeRequired,
+			Field:       field + ".macAddress",
+		})
+	} else if _, err := net.ParseMAC(iface.MACAddress); err != nil {
+		msg := fmt.Sprintf("The 'macAddress' value '%s' of interface '%s' of network host '%s' is not a valid MAC address.",
+			iface.MACAddress, iface.Name, hostname)
+		failures = append(failures, FailedValidation{
+			UserMessage: msg,
+			Error:       err,
+			Code:        CodeInvalid,
+			Field:       field + ".macAddress",
+		})
+	}
+
+	if iface.BondMode != "" || len(iface.Ports) != 0 {
+		msg := fmt.Sprintf("The 'bondMode' and 'ports' fields can only be specified for bond interfaces, found for interface '%s' of network host '%s'.",
+			iface.Name, hostname)
+		bondField := field + ".bondMode"
+		if iface.BondMode == "" {
+			bondField = field + ".ports"
+		}
+
+		failures = append(failures, FailedValidation{
+			UserMessage: msg,
+			Code:        CodeConflict,
+			Field:       bondField,
+		})
+	}
+
+	return failures
+}
+
+func validateBondInterface(hostname string, iface *image.NetworkInterface, field string, interfaceTypes, bondPorts map[string]string) []FailedValidation {
+	var failures []FailedValidation
+
+	if !slices.Contains(bondModes, iface.BondMode) {
+		msg := fmt.Sprintf("The 'bondMode' of bond '%s' of network host '%s' must be one of: %s.", iface.Name, hostname, strings.Join(bondModes, ", "))
+		failures = append(failures, FailedValidation{
+			UserMessage: msg,
+			Code:        CodeUnsupported,
+			Field:       field + ".bondMode",
+		})
+	}
+
+	if len(iface.Ports) == 0 {
+		msg := fmt.Sprintf("Bond '%s' of network host '%s' must contain at least one port.", iface.Name, hostname)
+		failures = append(failures, FailedValidation{
+			UserMessage: msg,
+			Code:        CodeRequired,
+			Field:       field + ".ports",
+		})
+	}
+
+	for i, port := range iface.Ports {
+		portField := fmt.Sprintf("%s.ports[%d]", field, i)
+
+		if interfaceTypes[port] != image.NetworkInterfaceTypeEthernet {
+			msg := fmt.Sprintf("Port '%s' of bond '%s' of network host '%s' must be an ethernet interface of the host.", port, iface.Name, hostname)
+			failures = append(failures, FailedValidation{
+				UserMessage: msg,
+				Code:        CodeNotFound,
+				Field:       portField,
+			})
+			continue
+		}
+
+		if bond, exists := bondPorts[port]; exists {
+			msg := fmt.Sprintf("Interface '%s' of network host '%s' is a port of both bond '%s' and bond '%s'.", port, hostname, bond, iface.Name)
+			failures = append(failures, FailedValidation{
+				UserMessage: msg,
+				Code:        CodeConflict,
+				Field:       portField,
+			})
+			continue
+		}
+		bondPorts[port] = iface.Name
+	}
+
+	return failures
+}
+
+func validateNetworkGateway(host *image.NetworkHost, hostField string) []FailedValidation {
+	if host.Gateway == "" {
+		return nil
+	}
+
+	if _, err := netip.ParseAddr(host.Gateway); err != nil {
+		msg := fmt.Sprintf("The 'gateway' value '%s' of network host '%s' is not a valid IP address.", host.Gateway, host.Hostname)
+		return []FailedValidation{
+			{
+				UserMessage: msg,
+				Error:       err,
+				Code:        CodeInvalid,
+				Field:       hostField + ".gateway",
+			},
+		}
+	}
+
+	if host.GatewayInterface() == "" {
+		msg := fmt.Sprintf("The 'gateway' %s of network host '%s' is not contained in the subnet of any static interface address.",
+			host.Gateway, host.Hostname)
+		return []FailedValidation{
+			{
+				UserMessage: msg,
+				Code:        CodeMismatch,
+				Field:       hostField + ".gateway",
+			},
+		}
+	}
+
+	return nil
+}
+
+// validateDNS checks the DNS servers and search domains of the network configuration identified by the source.
+// The failures are reported against the given fields of the servers and search domains.
+func validateDNS(source, serversField, searchField string, servers, searchDomains []string) []FailedValidation {
+	var failures []FailedValidation
+
+	for _, server := range servers {
+		if _, err := netip.ParseAddr(server); err != nil {
+			msg := fmt.Sprintf("DNS server '%s' of %s is not a valid IP address.", server, source)
+			failures = append(failures, FailedValidation{
+				UserMessage: msg,
+				Error:       err,
+				Code:        CodeInvalid,
+				Field:       serversField,
+			})
+		}
+	}
+
+	for _, domain := range searchDomains {
+		// Fully qualified domains may be specified with a trailing dot
+		if !isValidHostname(strings.TrimSuffix(domain, ".")) {
+			msg := fmt.Sprintf("DNS search domain '%s' of %s is not a valid domain name.", domain, source)
+			failures = append(failures, FailedValidation{
+				UserMessage: msg,
+				Code:        CodeInvalid,
+				Field:       searchField,
+			})
+		}
+	}
+
+	return failures
+}
+
+// validateNetworkDirectoryDNS checks the DNS configuration of the NMState files in the network directory.
+// Files which cannot be parsed are left to be reported by the network config generator.
+func validateNetworkDirectoryDNS(networkDir string) []FailedValidation {
+	entries, err := os.ReadDir(networkDir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			zap.S().Warnf("Reading the network directory failed: %s", err)
+		}
+		return nil
+	}
+
+	var failures []FailedValidation
+
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+
+		data, readErr := os.ReadFile(filepath.Join(networkDir, entry.Name()))
+		if readErr != nil {
+			zap.S().Warnf("Reading network config file '%s' failed: %s", entry.Name(), readErr)
+			continue
+		}
+
+		var config struct {
+			DNSResolver struct {
+				Config struct {
+					Server []string `yaml:"server"`
+					Search []string `yaml:"search"`
+				} `yaml:"config"`
+			} `yaml:"dns-resolver"`
+		}
+		if err = yaml.Unmarshal(data, &config); err != nil {
+			zap.S().Warnf("Parsing network config file '%s' failed: %s", entry.Name(), err)
+			continue
+		}
+
+		source := fmt.Sprintf("network config file '%s'", entry.Name())
+		field := path.Join(filepath.Base(networkDir), entry.Name())
+		failures = append(failures, validateDNS(source, field, field, config.DNSResolver.Config.Server, config.DNSResolver.Config.Search)...)
+	}
+
+	return failures
+}
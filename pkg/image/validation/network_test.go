@@ -0,0 +1,281 @@
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestValidateNetwork(t *testing.T) {
+	validHost := image.NetworkHost{
+		Hostname: "node1.suse.com",
+		Interfaces: []image.NetworkInterface{
+			{
+				Name:       "eth0",
+				MACAddress: "34:8A:B1:4B:16:E1",
+				Addresses:  []string{"192.168.122.50/24"},
+			},
+			{
+				Name:     "bond0",
+				Type:     image.NetworkInterfaceTypeBond,
+				BondMode: "active-backup",
+				Ports:    []string{"eth1", "eth2"},
+			},
+			{
+				Name:       "eth1",
+				MACAddress: "34:8A:B1:4B:16:E2",
+			},
+			{
+				Name:       "eth2",
+				MACAddress: "34:8A:B1:4B:16:E3",
+			},
+		},
+		Gateway: "192.168.122.1",
+	}
+
+	tests := map[string]struct {
+		Hosts                  []image.NetworkHost
+		NetworkDir             bool
+		ExpectedFailedMessages []string
+	}{
+		`not configured`: {},
+		`valid`: {
+			Hosts: []image.NetworkHost{validHost},
+		},
+		`network directory conflict`: {
+			Hosts:      []image.NetworkHost{validHost},
+			NetworkDir: true,
+			ExpectedFailedMessages: []string{
+				"The 'network' section of the operating system cannot be combined with the 'network' configuration directory.",
+			},
+		},
+		`invalid hosts`: {
+			Hosts: []image.NetworkHost{
+				{
+					Interfaces: []image.NetworkInterface{{Name: "eth0", MACAddress: "34:8A:B1:4B:16:E1"}},
+				},
+				{
+					Hostname:   "node_1",
+					Interfaces: []image.NetworkInterface{{Name: "eth0", MACAddress: "34:8A:B1:4B:16:E1"}},
+				},
+				{
+					Hostname: "node2",
+				},
+				{
+					Hostname: "node2",
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'hostname' field is required for all entries under 'network.hosts'.",
+				"The network host hostname 'node_1' is not a valid RFC 1123 hostname.",
+				"Network host 'node2' must contain at least one interface.",
+				"Duplicate network host hostname found: node2",
+				"Network host 'node2' must contain at least one interface.",
+			},
+		},
+		`invalid interfaces`: {
+			Hosts: []image.NetworkHost{
+				{
+					Hostname: "node1",
+					Interfaces: []image.NetworkInterface{
+						{MACAddress: "34:8A:B1:4B:16:E1"},
+						{Name: "eth0"},
+						{Name: "eth0", MACAddress: "not-a-mac"},
+						{Name: "eth1", MACAddress: "34:8A:B1:4B:16:E2", BondMode: "active-backup"},
+						{Name: "eth2", MACAddress: "34:8A:B1:4B:16:E3", Addresses: []string{"192.168.122.50"}},
+						{Name: "wlan0", Type: "wifi"},
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'name' field is required for all interfaces of network host 'node1'.",
+				"Duplicate interface name 'eth0' found for network host 'node1'.",
+				"The 'macAddress' field is required for ethernet interface 'eth0' of network host 'node1'.",
+				"The 'macAddress' value 'not-a-mac' of interface 'eth0' of network host 'node1' is not a valid MAC address.",
+				"The 'bondMode' and 'ports' fields can only be specified for bond interfaces, found for interface 'eth1' of network host 'node1'.",
+				"Address '192.168.122.50' of interface 'eth2' of network host 'node1' must be an IP address in CIDR notation (e.g. '192.168.122.50/24').",
+				"Interface 'wlan0' of network host 'node1' has an invalid type 'wifi'. It must be one of: ethernet, bond.",
+			},
+		},
+		`invalid bonds`: {
+			Hosts: []image.NetworkHost{
+				{
+					Hostname: "node1",
+					Interfaces: []image.NetworkInterface{
+						{Name: "bond0", Type: image.NetworkInterfaceTypeBond, BondMode: "round-robin"},
+						{Name: "bond1", Type: image.NetworkInterfaceTypeBond, BondMode: "802.3ad", Ports: []string{"eth0", "eth9", "bond0"}},
+						{Name: "bond2", Type: image.NetworkInterfaceTypeBond, BondMode: "802.3ad", Ports: []string{"eth0"}},
+						{Name: "eth0", MACAddress: "34:8A:B1:4B:16:E1", Addresses: []string{"192.168.122.50/24"}},
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'bondMode' of bond 'bond0' of network host 'node1' must be one of: balance-rr, active-backup, balance-xor, broadcast, 802.3ad, balance-tlb, balance-alb.",
+				"Bond 'bond0' of network host 'node1' must contain at least one port.",
+				"Port 'eth9' of bond 'bond1' of network host 'node1' must be an ethernet interface of the host.",
+				"Port 'bond0' of bond 'bond1' of network host 'node1' must be an ethernet interface of the host.",
+				"Interface 'eth0' of network host 'node1' is a port of both bond 'bond1' and bond 'bond2'.",
+				"Interface 'eth0' of network host 'node1' cannot have addresses since it is a port of bond 'bond1'.",
+			},
+		},
+		`invalid gateways`: {
+			Hosts: []image.NetworkHost{
+				{
+					Hostname:   "node1",
+					Interfaces: []image.NetworkInterface{{Name: "eth0", MACAddress: "34:8A:B1:4B:16:E1", Addresses: []string{"192.168.122.50/24"}}},
+					Gateway:    "192.168.122.256",
+				},
+				{
+					Hostname:   "node2",
+					Interfaces: []image.NetworkInterface{{Name: "eth0", MACAddress: "34:8A:B1:4B:16:E1", Addresses: []string{"192.168.122.50/24"}}},
+					Gateway:    "192.168.123.1",
+				},
+				{
+					Hostname:   "node3",
+					Interfaces: []image.NetworkInterface{{Name: "eth0", MACAddress: "34:8A:B1:4B:16:E1"}},
+					Gateway:    "192.168.122.1",
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'gateway' value '192.168.122.256' of network host 'node1' is not a valid IP address.",
+				"The 'gateway' 192.168.123.1 of network host 'node2' is not contained in the subnet of any static interface address.",
+				"The 'gateway' 192.168.122.1 of network host 'node3' is not contained in the subnet of any static interface address.",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			configDir := t.TempDir()
+			if test.NetworkDir {
+				require.NoError(t, os.Mkdir(filepath.Join(configDir, "network"), 0o755))
+			}
+
+			ctx := image.Context{
+				ImageConfigDir: configDir,
+				ImageDefinition: &image.Definition{
+					OperatingSystem: image.OperatingSystem{
+						Network: image.OperatingSystemNetwork{
+							Hosts: test.Hosts,
+						},
+					},
+				},
+			}
+			failures := validateNetwork(&ctx)
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+		})
+	}
+}
+
+func TestValidateNetworkDNS(t *testing.T) {
+	tests := map[string]struct {
+		DNSServers             []string
+		DNSSearch              []string
+		ExpectedFailedMessages []string
+	}{
+		`valid`: {
+			DNSServers: []string{"192.168.122.1", "fd00::1"},
+			DNSSearch:  []string{"suse.com", "edge.suse.com.", "local"},
+		},
+		`invalid servers`: {
+			DNSServers: []string{"192.168.122.256", "dns.suse.com", "192.168.122.0/24"},
+			ExpectedFailedMessages: []string{
+				"DNS server '192.168.122.256' of network host 'node1' is not a valid IP address.",
+				"DNS server 'dns.suse.com' of network host 'node1' is not a valid IP address.",
+				"DNS server '192.168.122.0/24' of network host 'node1' is not a valid IP address.",
+			},
+		},
+		`invalid search domains`: {
+			DNSSearch: []string{"", "suse..com", "-suse.com", "suse_edge.com", "*.suse.com"},
+			ExpectedFailedMessages: []string{
+				"DNS search domain '' of network host 'node1' is not a valid domain name.",
+				"DNS search domain 'suse..com' of network host 'node1' is not a valid domain name.",
+				"DNS search domain '-suse.com' of network host 'node1' is not a valid domain name.",
+				"DNS search domain 'suse_edge.com' of network host 'node1' is not a valid domain name.",
+				"DNS search domain '*.suse.com' of network host 'node1' is not a valid domain name.",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := image.Context{
+				ImageConfigDir: t.TempDir(),
+				ImageDefinition: &image.Definition{
+					OperatingSystem: image.OperatingSystem{
+						Network: image.OperatingSystemNetwork{
+							Hosts: []image.NetworkHost{
+								{
+									Hostname:   "node1",
+									Interfaces: []image.NetworkInterface{{Name: "eth0", MACAddress: "34:8A:B1:4B:16:E1"}},
+									DNSServers: test.DNSServers,
+									DNSSearch:  test.DNSSearch,
+								},
+							},
+						},
+					},
+				},
+			}
+			failures := validateNetwork(&ctx)
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+		})
+	}
+}
+
+func TestValidateNetworkDirectoryDNS(t *testing.T) {
+	networkDir := filepath.Join(t.TempDir(), "network")
+	require.NoError(t, os.Mkdir(networkDir, 0o755))
+
+	files := map[string]string{
+		"node1.suse.com.yaml": `dns-resolver:
+  config:
+    server:
+      - 192.168.122.1
+    search:
+      - suse.com
+`,
+		"node2.suse.com.yaml": `dns-resolver:
+  config:
+    server:
+      - 192.168.122
+    search:
+      - suse_edge.com
+`,
+		"node3.suse.com.yaml":  `interfaces: [`,
+		"configure-network.sh": `#!/bin/bash`,
+	}
+	for filename, contents := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(networkDir, filename), []byte(contents), 0o600))
+	}
+
+	failures := validateNetworkDirectoryDNS(networkDir)
+
+	var foundMessages []string
+	for _, foundValidation := range failures {
+		foundMessages = append(foundMessages, foundValidation.UserMessage)
+	}
+
+	expectedMessages := []string{
+		"DNS server '192.168.122' of network config file 'node2.suse.com.yaml' is not a valid IP address.",
+		"DNS search domain 'suse_edge.com' of network config file 'node2.suse.com.yaml' is not a valid domain name.",
+	}
+	assert.Equal(t, expectedMessages, foundMessages)
+
+	assert.Empty(t, validateNetworkDirectoryDNS(filepath.Join(t.TempDir(), "missing")))
+}
@@ -1,17 +1,60 @@
 package validation
 
 import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
 	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
 	"slices"
 	"strings"
+	"unicode"
 
+	"github.com/suse-edge/edge-image-builder/pkg/combustion"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/rpm"
+	"go.uber.org/zap"
+	"golang.org/x/text/language"
 )
 
 const (
 	osComponent = "Operating System"
+
+	// minRegularUID is the first UID assigned to regular (non-system) users.
+	minRegularUID = 1000
 )
 
+// hostnameLabelRegex matches a single RFC 1123 hostname label.
+var hostnameLabelRegex = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?$`)
+
+// sysctlKeyRegex matches kernel parameter paths using either '.' or '/' as a separator.
+var sysctlKeyRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]+([./][a-zA-Z0-9_-]+)+$`)
+
+// sysctlShellCharacters are rejected in sysctl values as they carry special meaning to the shell.
+const sysctlShellCharacters = "$`\\"
+
+// localeRegex matches locales in the 'language_TERRITORY[.codeset][@modifier]' format.
+var localeRegex = regexp.MustCompile(`^([a-z]{2,3})_([A-Z]{2})(\.([A-Za-z0-9-]+))?(@[a-z]+)?$`)
+
+// rpmLeadMagic is the magic number found at the start of the lead of every RPM package.
+var rpmLeadMagic = []byte{0xed, 0xab, 0xee, 0xdb}
+
+// rpmArches are the architecture suffixes of RPM file names which are checked against the image architecture.
+var rpmArches = []string{string(image.ArchTypeX86), string(image.ArchTypeARM), "i586", "i686", "ppc64le", "s390x"}
+
+// zypperInstallOptions are the options which may be passed to the zypper install command through 'installOptions'.
+var zypperInstallOptions = []string{"--no-recommends", "--recommends", "--oldpackage", "--replacefiles"}
+
+// standardLocales are the locales available independently of any language or territory.
+var standardLocales = []string{"C", "C.UTF-8", "C.utf8", "POSIX"}
+
 func validateOperatingSystem(ctx *image.Context) []FailedValidation {
 	def := ctx.ImageDefinition
 
@@ -21,11 +64,20 @@ func validateOperatingSystem(ctx *image.Context) []FailedValidation {
 	failures = append(failures, validateSystemd(&def.OperatingSystem)...)
 	failures = append(failures, validateGroups(&def.OperatingSystem)...)
 	failures = append(failures, validateUsers(&def.OperatingSystem)...)
-	failures = append(failures, validateSuma(&def.OperatingSystem)...)
+	failures = append(failures, validateSuma(&def.OperatingSystem, ctx.ImageConfigDir)...)
 	failures = append(failures, validatePackages(&def.OperatingSystem)...)
+	failures = append(failures, validateRegistrationCode(ctx)...)
+	failures = append(failures, validateSideLoadedRPMs(combustion.RPMsPath(ctx), def.Image.Arch)...)
 	failures = append(failures, validateTimeSync(&def.OperatingSystem)...)
+	failures = append(failures, validateImageTypeFields(def)...)
 	failures = append(failures, validateIsoConfig(def)...)
 	failures = append(failures, validateRawConfig(def)...)
+	failures = append(failures, validateHostEntries(&def.OperatingSystem)...)
+	failures = append(failures, validateSysctl(&def.OperatingSystem)...)
+	failures = append(failures, validateHostname(def)...)
+	failures = append(failures, validateLocale(&def.OperatingSystem)...)
+	failures = append(failures, validateProxy(&def.OperatingSystem)...)
+	failures = append(failures, validateNetwork(ctx)...)
 
 	return failures
 }
@@ -34,7 +86,8 @@ func validateKernelArgs(os *image.OperatingSystem) []FailedValidation {
 	var failures []FailedValidation
 
 	seenKeys := make(map[string]bool)
-	for _, arg := range os.KernelArgs {
+	for i, arg := range os.KernelArgs {
+		field := fmt.Sprintf("operatingSystem.kernelArgs[%d]", i)
 		key := arg
 
 		parts := strings.SplitN(arg, "=", 2)
@@ -44,6 +97,8 @@ func validateKernelArgs(os *image.OperatingSystem) []FailedValidation {
 			if key == "" || value == "" {
 				failures = append(failures, FailedValidation{
 					UserMessage: "Kernel arguments must be specified as 'key=value'.",
+					Code:        CodeInvalid,
+					Field:       field,
 				})
 			}
 		}
@@ -51,6 +106,8 @@ func validateKernelArgs(os *image.OperatingSystem) []FailedValidation {
 		if _, exists := seenKeys[key]; exists {
 			failures = append(failures, FailedValidation{
 				UserMessage: fmt.Sprintf("Duplicate kernel argument found: %s", key),
+				Code:        CodeDuplicate,
+				Field:       field,
 			})
 		}
 		seenKeys[key] = true
@@ -67,6 +124,8 @@ func validateSystemd(os *image.OperatingSystem) []FailedValidation {
 		msg := fmt.Sprintf("Systemd enable list contains duplicate entries: %s", duplicateValues)
 		failures = append(failures, FailedValidation{
 			UserMessage: msg,
+			Code:        CodeDuplicate,
+			Field:       "operatingSystem.systemd.enable",
 		})
 	}
 
@@ -75,6 +134,8 @@ func validateSystemd(os *image.OperatingSystem) []FailedValidation {
 		msg := fmt.Sprintf("Systemd disable list contains duplicate entries: %s", duplicateValues)
 		failures = append(failures, FailedValidation{
 			UserMessage: msg,
+			Code:        CodeDuplicate,
+			Field:       "operatingSystem.systemd.disable",
 		})
 	}
 
@@ -84,6 +145,8 @@ func validateSystemd(os *image.OperatingSystem) []FailedValidation {
 				msg := fmt.Sprintf("Systemd conflict found, '%s' is both enabled and disabled.", enableItem)
 				failures = append(failures, FailedValidation{
 					UserMessage: msg,
+					Code:        CodeConflict,
+					Field:       "operatingSystem.systemd.disable",
 				})
 			}
 		}
@@ -98,10 +161,14 @@ func validateGroups(os *image.OperatingSystem) []FailedValidation {
 	// The script is idempotent and will not fail on creating a duplicate group,
 	// but for consistency validate that duplicates aren't in the definition.
 	seenGroupNames := make(map[string]bool)
-	for _, group := range os.Groups {
+	for i, group := range os.Groups {
+		field := fmt.Sprintf("operatingSystem.groups[%d]", i)
+
 		if group.Name == "" {
 			failures = append(failures, FailedValidation{
 				UserMessage: "The 'name' field is required for all entries under 'groups'.",
+				Code:        CodeRequired,
+				Field:       field + ".name",
 			})
 		}
 
@@ -109,11 +176,31 @@ func validateGroups(os *image.OperatingSystem) []FailedValidation {
 			msg := fmt.Sprintf("Duplicate group name found: %s", group.Name)
 			failures = append(failures, FailedValidation{
 				UserMessage: msg,
+				Code:        CodeDuplicate,
+				Field:       field + ".name",
 			})
 		}
 		seenGroupNames[group.Name] = true
 	}
 
+	// Explicit GIDs must be unique, otherwise creating the latter group fails
+	seenGIDs := make(map[int]bool)
+	for i, group := range os.Groups {
+		if group.GID == 0 {
+			continue
+		}
+
+		if seenGIDs[group.GID] {
+			msg := fmt.Sprintf("Duplicate GID %d found across groups.", group.GID)
+			failures = append(failures, FailedValidation{
+				UserMessage: msg,
+				Code:        CodeDuplicate,
+				Field:       fmt.Sprintf("operatingSystem.groups[%d].gid", i),
+			})
+		}
+		seenGIDs[group.GID] = true
+	}
+
 	return failures
 }
 
@@ -121,39 +208,147 @@ func validateUsers(os *image.OperatingSystem) []FailedValidation {
 	var failures []FailedValidation
 
 	seenUsernames := make(map[string]bool)
-	for _, user := range os.Users {
+	for i, user := range os.Users {
+		field := fmt.Sprintf("operatingSystem.users[%d]", i)
+
 		if user.Username == "" {
 			failures = append(failures, FailedValidation{
 				UserMessage: "The 'username' field is required for all entries under 'users'.",
+				Code:        CodeRequired,
+				Field:       field + ".username",
 			})
 		}
 
-		if user.EncryptedPassword == "" && len(user.SSHKeys) == 0 {
+		if user.DisablePasswordAuth {
+			failures = append(failures, validateUserPasswordAuth(&user, field)...)
+		} else if user.EncryptedPassword == "" && len(user.SSHKeys) == 0 {
 			msg := fmt.Sprintf("User '%s' must have either a password or at least one SSH key.", user.Username)
 			failures = append(failures, FailedValidation{
 				UserMessage: msg,
+				Code:        CodeRequired,
+				Field:       field + ".encryptedPassword",
 			})
 		}
 
-		if !user.CreateHomeDir && len(user.SSHKeys) > 0 {
-			failures = append(failures, FailedValidation{
-				UserMessage: "The 'createHomeDir' attribute must be set to 'true' if at least one SSH key is specified.",
-			})
-		}
+		failures = append(failures, validateUserHomeDir(&user, field)...)
+		failures = append(failures, validateUserGroups(&user, field)...)
 
 		if seenUsernames[user.Username] {
 			msg := fmt.Sprintf("Duplicate username found: %s", user.Username)
 			failures = append(failures, FailedValidation{
 				UserMessage: msg,
+				Code:        CodeDuplicate,
+				Field:       field + ".username",
 			})
 		}
 		seenUsernames[user.Username] = true
 	}
 
+	// Explicit UIDs must be unique, otherwise creating the latter user fails
+	seenUIDs := make(map[int]bool)
+	for i, user := range os.Users {
+		if user.UID == 0 {
+			continue
+		}
+
+		if seenUIDs[user.UID] {
+			msg := fmt.Sprintf("Duplicate UID %d found across users.", user.UID)
+			failures = append(failures, FailedValidation{
+				UserMessage: msg,
+				Code:        CodeDuplicate,
+				Field:       fmt.Sprintf("operatingSystem.users[%d].uid", i),
+			})
+		}
+		seenUIDs[user.UID] = true
+	}
+
+	return failures
+}
+
+// validateUserHomeDir checks the home directory requirements of a user:
+// SSH keys are stored in the home directory, so one must be created for them,
+// while password-only users may skip it. System users rarely need one.
+func validateUserHomeDir(user *image.OperatingSystemUser, field string) []FailedValidation {
+	if len(user.SSHKeys) > 0 {
+		if user.CreateHomeDir {
+			return nil
+		}
+
+		msg := fmt.Sprintf("User '%s' must have 'createHomeDir' set to 'true' since SSH keys are stored in the home directory.", user.Username)
+		return []FailedValidation{
+			{
+				UserMessage: msg,
+				Code:        CodeRequired,
+				Field:       field + ".createHomeDir",
+			},
+		}
+	}
+
+	if user.CreateHomeDir && user.UID > 0 && user.UID < minRegularUID {
+		msg := fmt.Sprintf("User '%s' is a system user (UID %d < %d) but 'createHomeDir' is set to 'true'.", user.Username, user.UID, minRegularUID)
+		return []FailedValidation{
+			{
+				UserMessage: msg,
+				Severity:    SeverityWarning,
+				Code:        CodeInvalid,
+				Field:       field + ".createHomeDir",
+			},
+		}
+	}
+
+	return nil
+}
+
+// validateUserPasswordAuth checks the credentials of a user restricted to SSH key authentication.
+func validateUserPasswordAuth(user *image.OperatingSystemUser, field string) []FailedValidation {
+	var failures []FailedValidation
+
+	if user.EncryptedPassword != "" {
+		msg := fmt.Sprintf("User '%s' cannot set an 'encryptedPassword' when 'disablePasswordAuth' is set to 'true'.", user.Username)
+		failures = append(failures, FailedValidation{
+			UserMessage: msg,
+			Code:        CodeConflict,
+			Field:       field + ".encryptedPassword",
+		})
+	}
+
+	if len(user.SSHKeys) == 0 {
+		msg := fmt.Sprintf("User '%s' must have at least one SSH key when 'disablePasswordAuth' is set to 'true'.", user.Username)
+		failures = append(failures, FailedValidation{
+			UserMessage: msg,
+			Code:        CodeRequired,
+			Field:       field + ".sshKeys",
+		})
+	}
+
 	return failures
 }
 
-func validateSuma(os *image.OperatingSystem) []FailedValidation {
+func validateUserGroups(user *image.OperatingSystemUser, field string) []FailedValidation {
+	var failures []FailedValidation
+
+	if user.PrimaryGroup != "" && slices.Contains(user.SecondaryGroups, user.PrimaryGroup) {
+		msg := fmt.Sprintf("User '%s' lists its primaryGroup '%s' in secondaryGroups.", user.Username, user.PrimaryGroup)
+		failures = append(failures, FailedValidation{
+			UserMessage: msg,
+			Code:        CodeConflict,
+			Field:       field + ".secondaryGroups",
+		})
+	}
+
+	for _, group := range findDuplicates(user.SecondaryGroups) {
+		msg := fmt.Sprintf("User '%s' lists the group '%s' multiple times in secondaryGroups.", user.Username, group)
+		failures = append(failures, FailedValidation{
+			UserMessage: msg,
+			Code:        CodeDuplicate,
+			Field:       field + ".secondaryGroups",
+		})
+	}
+
+	return failures
+}
+
+func validateSuma(os *image.OperatingSystem, imageConfigDir string) []FailedValidation {
 	var failures []FailedValidation
 
 	if os.Suma == (image.Suma{}) {
@@ -162,49 +357,204 @@ func validateSuma(os *image.OperatingSystem) []FailedValidation {
 	if os.Suma.Host == "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: "The 'host' field is required for the 'suma' section.",
+			Code:        CodeRequired,
+			Field:       "operatingSystem.suma.host",
 		})
 	}
 	if strings.HasPrefix(os.Suma.Host, "http") {
 		failures = append(failures, FailedValidation{
 			UserMessage: "The suma 'host' field may not contain 'http://' or 'https://'",
+			Code:        CodeInvalid,
+			Field:       "operatingSystem.suma.host",
+		})
+	} else if strings.ContainsAny(os.Suma.Host, ":/") {
+		failures = append(failures, FailedValidation{
+			UserMessage: "The suma 'host' field must be a bare hostname without a port or path.",
+			Code:        CodeInvalid,
+			Field:       "operatingSystem.suma.host",
 		})
 	}
 	if os.Suma.ActivationKey == "" {
 		failures = append(failures, FailedValidation{
 			UserMessage: "The 'activationKey' field is required for the 'suma' section.",
+			Code:        CodeRequired,
+			Field:       "operatingSystem.suma.activationKey",
+		})
+	}
+	if msg := validateSumaProxy(os.Suma.Proxy); msg != "" {
+		failures = append(failures, FailedValidation{
+			UserMessage: msg,
+			Code:        CodeInvalid,
+			Field:       "operatingSystem.suma.proxy",
+		})
+	}
+	if msg := validateSumaCACert(os.Suma.CACert, imageConfigDir); msg != "" {
+		failures = append(failures, FailedValidation{
+			UserMessage: msg,
+			Code:        CodeInvalid,
+			Field:       "operatingSystem.suma.caCert",
 		})
 	}
 
 	return failures
 }
 
+func validateSumaProxy(proxy string) string {
+	if proxy == "" {
+		return ""
+	}
+
+	proxyURL, err := url.Parse(proxy)
+	if err != nil || (proxyURL.Scheme != "http" && proxyURL.Scheme != "https") ||
+		proxyURL.Hostname() == "" || proxyURL.Port() == "" {
+		return "The suma 'proxy' field must be a valid URL in the format 'http://<host>:<port>'."
+	}
+
+	return ""
+}
+
+func validateSumaCACert(certFile, imageConfigDir string) string {
+	if certFile == "" {
+		return ""
+	}
+
+	certFilePath := filepath.Join(imageConfigDir, combustion.SumaDir, certFile)
+	data, err := os.ReadFile(certFilePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Sprintf("The suma CA certificate '%s' could not be found at '%s'.", certFile, certFilePath)
+		}
+
+		zap.S().Errorf("The suma CA certificate '%s' could not be read: %s", certFile, err)
+		return fmt.Sprintf("The suma CA certificate '%s' could not be read.", certFile)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return fmt.Sprintf("The suma CA certificate '%s' must be a PEM encoded certificate.", certFile)
+	}
+
+	if _, err = x509.ParseCertificate(block.Bytes); err != nil {
+		zap.S().Errorf("Parsing suma CA certificate '%s' failed: %s", certFile, err)
+		return fmt.Sprintf("The suma CA certificate '%s' could not be parsed.", certFile)
+	}
+
+	return ""
+}
+
+func validateRegistrationCode(ctx *image.Context) []FailedValidation {
+	packages := ctx.ImageDefinition.OperatingSystem.Packages
+
+	if packages.RegCode != "" && packages.RegCodeFile != "" {
+		return []FailedValidation{
+			{
+				UserMessage: "Only one of the 'sccRegistrationCode' and 'sccRegistrationCodeFile' fields may be specified.",
+				Code:        CodeConflict,
+				Field:       "operatingSystem.packages.sccRegistrationCode",
+			},
+		}
+	}
+
+	if _, err := combustion.ResolveRegistrationCode(ctx); err != nil {
+		var msg string
+		regCodeField := "operatingSystem.packages.sccRegistrationCode"
+		if packages.RegCodeFile != "" {
+			regCodeField = "operatingSystem.packages.sccRegistrationCodeFile"
+			msg = fmt.Sprintf("The registration code file '%s' specified in the 'sccRegistrationCodeFile' field could not be read or is empty.", packages.RegCodeFile)
+		} else {
+			msg = fmt.Sprintf("The environment variable referenced by the 'sccRegistrationCode' field ('%s') is not set.", packages.RegCode)
+		}
+
+		return []FailedValidation{
+			{
+				UserMessage: msg,
+				Error:       err,
+				Code:        CodeNotFound,
+				Field:       regCodeField,
+			},
+		}
+	}
+
+	return nil
+}
+
 func validatePackages(os *image.OperatingSystem) []FailedValidation {
 	var failures []FailedValidation
 
+	if os.Packages.NoGPGCheck {
+		failures = append(failures, FailedValidation{
+			UserMessage: "GPG signature checking is disabled for package installation; this is insecure.",
+			Severity:    SeverityWarning,
+			Code:        CodeInsecure,
+			Field:       "operatingSystem.packages.noGPGCheck",
+		})
+	}
+
 	if slices.Contains(os.Packages.PKGList, "") {
 		failures = append(failures, FailedValidation{
 			UserMessage: "The 'packageList' field cannot contain empty values.",
+			Code:        CodeRequired,
+			Field:       "operatingSystem.packages.packageList",
 		})
 	}
 
-	if duplicates := findDuplicates(os.Packages.PKGList); len(duplicates) > 0 {
+	var packageNames []string
+	for i, pkg := range os.Packages.PKGList {
+		if pkg == "" {
+			continue
+		}
+
+		spec, err := rpm.ParsePackageSpec(pkg)
+		if err != nil {
+			msg := fmt.Sprintf("The 'packageList' entry '%s' is invalid. Entries must be a package name, optionally followed "+
+				"by a version constraint such as 'foo=1.2.3' or 'foo>=1.2'.", pkg)
+			failures = append(failures, FailedValidation{
+				UserMessage: msg,
+				Error:       err,
+				Code:        CodeInvalid,
+				Field:       fmt.Sprintf("operatingSystem.packages.packageList[%d]", i),
+			})
+			continue
+		}
+
+		// Duplicates are detected by name, so that conflicting pins of the same package are reported as well
+		packageNames = append(packageNames, spec.Name)
+	}
+
+	if duplicates := findDuplicates(packageNames); len(duplicates) > 0 {
 		duplicateValues := strings.Join(duplicates, ", ")
 		msg := fmt.Sprintf("The 'packageList' field contains duplicate packages: %s", duplicateValues)
 		failures = append(failures, FailedValidation{
 			UserMessage: msg,
+			Code:        CodeDuplicate,
+			Field:       "operatingSystem.packages.packageList",
 		})
 	}
 
+	for i, option := range os.Packages.InstallOptions {
+		if !slices.Contains(zypperInstallOptions, option) {
+			msg := fmt.Sprintf("The 'installOptions' field contains the unsupported option '%s'. Supported options are: %s.",
+				option, strings.Join(zypperInstallOptions, ", "))
+			failures = append(failures, FailedValidation{
+				UserMessage: msg,
+				Code:        CodeUnsupported,
+				Field:       fmt.Sprintf("operatingSystem.packages.installOptions[%d]", i),
+			})
+		}
+	}
+
 	// It is possible to only provide `additionalRepos` without listing any packages
 	// under `packageList` in the cases where RPMs are side-loaded under the `/rpms` directory.
 	if len(os.Packages.AdditionalRepos) > 0 {
 		var repoURLs []string
 
-		for _, repo := range os.Packages.AdditionalRepos {
+		for i, repo := range os.Packages.AdditionalRepos {
 			if repo.URL == "" {
 				msg := "The 'url' field is required for all entries under 'additionalRepos'."
 				failures = append(failures, FailedValidation{
 					UserMessage: msg,
+					Code:        CodeRequired,
+					Field:       fmt.Sprintf("operatingSystem.packages.additionalRepos[%d].url", i),
 				})
 			}
 
@@ -216,6 +566,8 @@ func validatePackages(os *image.OperatingSystem) []FailedValidation {
 			msg := fmt.Sprintf("The 'additionalRepos' field contains duplicate repos: %s", duplicateValues)
 			failures = append(failures, FailedValidation{
 				UserMessage: msg,
+				Code:        CodeDuplicate,
+				Field:       "operatingSystem.packages.additionalRepos",
 			})
 		}
 	}
@@ -223,37 +575,247 @@ func validatePackages(os *image.OperatingSystem) []FailedValidation {
 	return failures
 }
 
-func validateIsoConfig(def *image.Definition) []FailedValidation {
+func validateSideLoadedRPMs(rpmsDir string, arch image.Arch) []FailedValidation {
 	var failures []FailedValidation
 
-	if def.Image.ImageType != image.TypeISO && def.OperatingSystem.IsoConfiguration.InstallDevice != "" {
-		msg := fmt.Sprintf("The 'isoConfiguration/installDevice' field can only be used when 'imageType' is '%s'.", image.TypeISO)
+	dirField := filepath.Base(rpmsDir)
+
+	entries, err := os.ReadDir(rpmsDir)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			failures = append(failures, FailedValidation{
+				UserMessage: "The 'rpms' directory could not be read.",
+				Error:       err,
+				Code:        CodeUnreadable,
+				Field:       dirField,
+			})
+		}
+
+		return failures
+	}
+
+	var rpmFiles []string
+
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".rpm" {
+			continue
+		}
+		rpmFiles = append(rpmFiles, entry.Name())
+		rpmField := path.Join(dirField, entry.Name())
+
+		isRPM, err := hasRPMLead(filepath.Join(rpmsDir, entry.Name()))
+		if err != nil {
+			failures = append(failures, FailedValidation{
+				UserMessage: fmt.Sprintf("The RPM file '%s' could not be read.", entry.Name()),
+				Error:       err,
+				Code:        CodeUnreadable,
+				Field:       rpmField,
+			})
+		} else if !isRPM {
+			failures = append(failures, FailedValidation{
+				UserMessage: fmt.Sprintf("The file '%s' in the 'rpms' directory is not a valid RPM package.", entry.Name()),
+				Code:        CodeInvalid,
+				Field:       rpmField,
+			})
+		}
+
+		if rpmArch := rpmFileArch(entry.Name()); rpmArch != "" && rpmArch != string(arch) {
+			failures = append(failures, FailedValidation{
+				UserMessage: fmt.Sprintf("RPM '%s' targets a different architecture than the image (%s).", entry.Name(), arch),
+				Severity:    SeverityWarning,
+				Code:        CodeMismatch,
+				Field:       rpmField,
+			})
+		}
+	}
+
+	supersededRPMs := rpm.Deduplicate(rpmFiles)
+
+	var supersededNames []string
+	for superseded := range supersededRPMs {
+		supersededNames = append(supersededNames, superseded)
+	}
+	slices.Sort(supersededNames)
+
+	for _, superseded := range supersededNames {
+		failures = append(failures, FailedValidation{
+			UserMessage: fmt.Sprintf("RPM '%s' is superseded by a higher version of the same package ('%s') and will not be installed.",
+				superseded, supersededRPMs[superseded]),
+			Severity: SeverityWarning,
+			Code:     CodeConflict,
+			Field:    path.Join(dirField, superseded),
+		})
+	}
+
+	return failures
+}
+
+// rpmFileArch returns the architecture from an RPM file name in the '<name>-<version>-<release>.<arch>.rpm'
+// format. Architecture independent packages and unrecognized suffixes result in an empty string.
+func rpmFileArch(filename string) string {
+	arch := filepath.Ext(strings.TrimSuffix(filename, ".rpm"))
+	arch = strings.TrimPrefix(arch, ".")
+
+	if !slices.Contains(rpmArches, arch) {
+		return ""
+	}
+
+	return arch
+}
+
+func hasRPMLead(path string) (bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return false, fmt.Errorf("opening file: %w", err)
+	}
+	defer file.Close()
+
+	magic := make([]byte, len(rpmLeadMagic))
+	if _, err = io.ReadFull(file, magic); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("reading file: %w", err)
+	}
+
+	return bytes.Equal(magic, rpmLeadMagic), nil
+}
+
+// imageTypeField describes a definition field which is only applicable to a single image type.
+type imageTypeField struct {
+	name      string
+	imageType string
+	isSet     func(def *image.Definition) bool
+}
+
+var imageTypeFields = []imageTypeField{
+	{
+		name:      "isoConfiguration/installDevice",
+		imageType: image.TypeISO,
+		isSet: func(def *image.Definition) bool {
+			return def.OperatingSystem.IsoConfiguration.InstallDevice != ""
+		},
+	},
+	{
+		name:      "isoConfiguration/rebootAfterInstall",
+		imageType: image.TypeISO,
+		isSet: func(def *image.Definition) bool {
+			return def.OperatingSystem.IsoConfiguration.RebootAfterInstall
+		},
+	},
+	{
+		name:      "isoConfiguration/maxSize",
+		imageType: image.TypeISO,
+		isSet: func(def *image.Definition) bool {
+			return def.OperatingSystem.IsoConfiguration.MaxSize != ""
+		},
+	},
+	{
+		name:      "rawConfiguration/diskSize",
+		imageType: image.TypeRAW,
+		isSet: func(def *image.Definition) bool {
+			return def.OperatingSystem.RawConfiguration.DiskSize != ""
+		},
+	},
+}
+
+// validateImageTypeFields reports all fields specific to an image type which are set for an image of a different type.
+func validateImageTypeFields(def *image.Definition) []FailedValidation {
+	var failures []FailedValidation
+
+	for _, field := range imageTypeFields {
+		if def.Image.ImageType == field.imageType || !field.isSet(def) {
+			continue
+		}
+
+		msg := fmt.Sprintf("The '%s' field can only be used when 'imageType' is '%s'.", field.name, field.imageType)
 		failures = append(failures, FailedValidation{
 			UserMessage: msg,
+			Code:        CodeUnsupported,
+			Field:       "operatingSystem." + strings.ReplaceAll(field.name, "/", "."),
 		})
 	}
 
 	return failures
 }
 
-func validateRawConfig(def *image.Definition) []FailedValidation {
+func validateIsoConfig(def *image.Definition) []FailedValidation {
 	var failures []FailedValidation
 
-	if def.OperatingSystem.RawConfiguration.DiskSize == "" {
-		return nil
+	isoConfig := def.OperatingSystem.IsoConfiguration
+
+	devices := strings.FieldsFunc(isoConfig.InstallDevice, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+	switch {
+	case len(devices) > 1 && slices.Contains(devices, image.InstallDeviceAuto):
+		msg := fmt.Sprintf("The 'isoConfiguration/installDevice' field cannot combine '%s' with an explicit device.", image.InstallDeviceAuto)
+		failures = append(failures, FailedValidation{
+			UserMessage: msg,
+			Code:        CodeConflict,
+			Field:       "operatingSystem.isoConfiguration.installDevice",
+		})
+	case isoConfig.InstallDevice != "" && !isValidInstallDevice(isoConfig.InstallDevice):
+		msg := "The 'installDevice' field must be either 'auto' or a single absolute device path (e.g. /dev/sda)."
+		failures = append(failures, FailedValidation{
+			UserMessage: msg,
+			Code:        CodeInvalid,
+			Field:       "operatingSystem.isoConfiguration.installDevice",
+		})
 	}
 
-	if def.Image.ImageType != image.TypeRAW {
-		msg := fmt.Sprintf("The 'rawConfiguration/diskSize' field can only be used when 'imageType' is '%s'.", image.TypeRAW)
+	if isoConfig.MaxSize != "" && !isoConfig.MaxSize.IsValid() {
+		msg := "The 'isoConfiguration/maxSize' field must be an integer followed by a suffix of either 'M', 'G', or 'T'."
 		failures = append(failures, FailedValidation{
 			UserMessage: msg,
+			Code:        CodeInvalid,
+			Field:       "operatingSystem.isoConfiguration.maxSize",
 		})
 	}
 
+	if isoConfig.RebootAfterInstall && isoConfig.InstallDevice == "" {
+		msg := "The 'isoConfiguration/rebootAfterInstall' field requires 'isoConfiguration/installDevice' to be set for an unattended installation."
+		failures = append(failures, FailedValidation{
+			UserMessage: msg,
+			Code:        CodeRequired,
+			Field:       "operatingSystem.isoConfiguration.installDevice",
+		})
+	}
+
+	return failures
+}
+
+// isValidInstallDevice checks that the install device is either exactly the auto token or a single
+// normalized absolute path to a device under /dev. Lists of devices are rejected since the value
+// is passed to the installer as is.
+func isValidInstallDevice(device string) bool {
+	if device == image.InstallDeviceAuto {
+		return true
+	}
+
+	if strings.ContainsFunc(device, func(r rune) bool {
+		return r == ',' || r == '|' || unicode.IsSpace(r)
+	}) {
+		return false
+	}
+
+	return filepath.Clean(device) == device && strings.HasPrefix(device, "/dev/")
+}
+
+func validateRawConfig(def *image.Definition) []FailedValidation {
+	var failures []FailedValidation
+
+	if def.OperatingSystem.RawConfiguration.DiskSize == "" {
+		return nil
+	}
+
 	if def.OperatingSystem.IsoConfiguration.InstallDevice != "" {
 		msg := "You cannot simultaneously configure rawConfiguration and isoConfiguration, regardless of image type."
 		failures = append(failures, FailedValidation{
 			UserMessage: msg,
+			Code:        CodeConflict,
+			Field:       "operatingSystem.rawConfiguration",
 		})
 	}
 
@@ -261,6 +823,8 @@ func validateRawConfig(def *image.Definition) []FailedValidation {
 		msg := "The 'rawConfiguration/diskSize' field must be an integer followed by a suffix of either 'M', 'G', or 'T'."
 		failures = append(failures, FailedValidation{
 			UserMessage: msg,
+			Code:        CodeInvalid,
+			Field:       "operatingSystem.rawConfiguration.diskSize",
 		})
 	}
 
@@ -278,8 +842,275 @@ func validateTimeSync(os *image.OperatingSystem) []FailedValidation {
 		msg := "If you're wanting to wait for NTP synchronization at boot, please ensure that you provide at least one NTP time source."
 		failures = append(failures, FailedValidation{
 			UserMessage: msg,
+			Code:        CodeRequired,
+			Field:       "operatingSystem.time.ntp",
 		})
 	}
 
 	return failures
 }
+
+func validateHostEntries(os *image.OperatingSystem) []FailedValidation {
+	var failures []FailedValidation
+
+	for i, entry := range os.HostEntries {
+		field := fmt.Sprintf("operatingSystem.hostEntries[%d]", i)
+
+		if net.ParseIP(entry.IP) == nil {
+			failures = append(failures, FailedValidation{
+				UserMessage: fmt.Sprintf("Host entry IP '%s' is not a valid IP address.", entry.IP),
+				Code:        CodeInvalid,
+				Field:       field + ".ip",
+			})
+		}
+
+		if len(entry.Hostnames) == 0 {
+			failures = append(failures, FailedValidation{
+				UserMessage: fmt.Sprintf("Host entry for IP '%s' must contain at least one hostname.", entry.IP),
+				Code:        CodeRequired,
+				Field:       field + ".hostnames",
+			})
+		}
+
+		for j, hostname := range entry.Hostnames {
+			if !isValidHostname(hostname) {
+				msg := fmt.Sprintf("Hostname '%s' in the host entry for IP '%s' is not a valid RFC 1123 hostname.", hostname, entry.IP)
+				failures = append(failures, FailedValidation{
+					UserMessage: msg,
+					Code:        CodeInvalid,
+					Field:       fmt.Sprintf("%s.hostnames[%d]", field, j),
+				})
+			}
+		}
+	}
+
+	return failures
+}
+
+func isValidHostname(hostname string) bool {
+	if hostname == "" || len(hostname) > 253 {
+		return false
+	}
+
+	for _, label := range strings.Split(hostname, ".") {
+		if !hostnameLabelRegex.MatchString(label) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func validateSysctl(os *image.OperatingSystem) []FailedValidation {
+	var failures []FailedValidation
+
+	keys := make([]string, 0, len(os.Sysctl))
+	for key := range os.Sysctl {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		field := fmt.Sprintf("operatingSystem.sysctl[%s]", key)
+
+		if !sysctlKeyRegex.MatchString(key) {
+			msg := fmt.Sprintf("Sysctl key '%s' is not a valid kernel parameter path (e.g. 'net.ipv4.ip_forward').", key)
+			failures = append(failures, FailedValidation{
+				UserMessage: msg,
+				Code:        CodeInvalid,
+				Field:       field,
+			})
+		}
+
+		value := os.Sysctl[key]
+		if strings.TrimSpace(value) == "" || strings.ContainsAny(value, "\r\n") {
+			msg := fmt.Sprintf("Sysctl value for key '%s' must be a non-empty single line.", key)
+			failures = append(failures, FailedValidation{
+				UserMessage: msg,
+				Code:        CodeInvalid,
+				Field:       field,
+			})
+		} else if strings.ContainsAny(value, sysctlShellCharacters) {
+			msg := fmt.Sprintf("Sysctl value for key '%s' must not contain any of the characters: %s", key, sysctlShellCharacters)
+			failures = append(failures, FailedValidation{
+				UserMessage: msg,
+				Code:        CodeInsecure,
+				Field:       field,
+			})
+		}
+	}
+
+	return failures
+}
+
+func validateHostname(def *image.Definition) []FailedValidation {
+	var failures []FailedValidation
+
+	hostname := def.OperatingSystem.Hostname
+	if hostname == "" {
+		return nil
+	}
+
+	if !isValidHostname(hostname) {
+		failures = append(failures, FailedValidation{
+			UserMessage: fmt.Sprintf("The 'hostname' field value '%s' is not a valid RFC 1123 hostname.", hostname),
+			Code:        CodeInvalid,
+			Field:       "operatingSystem.hostname",
+		})
+	}
+
+	// Multi-node clusters identify the role of each node through its hostname
+	if len(def.Kubernetes.Nodes) > 1 {
+		msg := "The 'hostname' field cannot be used when multiple Kubernetes nodes are configured, " +
+			"as each node must be assigned its own hostname (e.g. through the network configuration)."
+		failures = append(failures, FailedValidation{
+			UserMessage: msg,
+			Code:        CodeConflict,
+			Field:       "operatingSystem.hostname",
+		})
+	}
+
+	return failures
+}
+
+func validateLocale(os *image.OperatingSystem) []FailedValidation {
+	if os.Locale == "" || slices.Contains(standardLocales, os.Locale) {
+		return nil
+	}
+
+	if !isKnownLocale(os.Locale) {
+		msg := fmt.Sprintf("The 'locale' field value '%s' is not a known locale. "+
+			"Locales must be in the format 'language_TERRITORY.UTF-8' (e.g. 'en_US.UTF-8').", os.Locale)
+		return []FailedValidation{
+			{
+				UserMessage: msg,
+				Code:        CodeInvalid,
+				Field:       "operatingSystem.locale",
+			},
+		}
+	}
+
+	return nil
+}
+
+// isKnownLocale checks that the language and territory of a locale are valid
+// ISO 639 and ISO 3166-1 codes and that it uses the UTF-8 codeset, if one is specified.
+func isKnownLocale(locale string) bool {
+	matches := localeRegex.FindStringSubmatch(locale)
+	if matches == nil {
+		return false
+	}
+
+	if _, err := language.ParseBase(matches[1]); err != nil {
+		return false
+	}
+
+	region, err := language.ParseRegion(matches[2])
+	if err != nil || !region.IsCountry() {
+		return false
+	}
+
+	codeset := matches[4]
+	return codeset == "" || strings.EqualFold(codeset, "UTF-8") || strings.EqualFold(codeset, "utf8")
+}
+
+// validateProxy warns about proxy configurations which are effectively broken,
+// i.e. proxies pointing at the node itself or bypassed through a 'noProxy' entry.
+func validateProxy(os *image.OperatingSystem) []FailedValidation {
+	var failures []FailedValidation
+
+	proxies := []struct {
+		field string
+		value string
+	}{
+		{field: "httpProxy", value: os.Proxy.HTTPProxy},
+		{field: "httpsProxy", value: os.Proxy.HTTPSProxy},
+	}
+
+	for _, proxy := range proxies {
+		host := proxyHost(proxy.value)
+		if host == "" {
+			continue
+		}
+
+		if isLocalHost(host) {
+			msg := fmt.Sprintf("The proxy '%s' field points at the local host '%s', which is unlikely to serve as a proxy for the node.", proxy.field, host)
+			failures = append(failures, FailedValidation{
+				UserMessage: msg,
+				Severity:    SeverityWarning,
+				Code:        CodeConflict,
+				Field:       "operatingSystem.proxy." + proxy.field,
+			})
+			continue
+		}
+
+		if entry := matchingNoProxyEntry(host, os.Proxy.NoProxy); entry != "" {
+			msg := fmt.Sprintf("The proxy '%s' host '%s' is covered by the 'noProxy' entry '%s'.", proxy.field, host, entry)
+			failures = append(failures, FailedValidation{
+				UserMessage: msg,
+				Severity:    SeverityWarning,
+				Code:        CodeConflict,
+				Field:       "operatingSystem.proxy." + proxy.field,
+			})
+		}
+	}
+
+	return failures
+}
+
+func proxyHost(proxy string) string {
+	if proxy == "" {
+		return ""
+	}
+
+	if !strings.Contains(proxy, "://") {
+		proxy = "http://" + proxy
+	}
+
+	proxyURL, err := url.Parse(proxy)
+	if err != nil {
+		return ""
+	}
+
+	return strings.ToLower(proxyURL.Hostname())
+}
+
+func isLocalHost(host string) bool {
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// matchingNoProxyEntry returns the 'noProxy' entry covering the given host, if any.
+// Entries match the host itself and its subdomains, while IP addresses are also matched against CIDRs.
+func matchingNoProxyEntry(host string, noProxy []string) string {
+	ip := net.ParseIP(host)
+
+	for _, entry := range noProxy {
+		normalised := strings.ToLower(strings.TrimSpace(entry))
+
+		switch {
+		case normalised == "":
+			continue
+		case normalised == "*":
+			return entry
+		case ip != nil:
+			if _, cidr, err := net.ParseCIDR(normalised); err == nil && cidr.Contains(ip) {
+				return entry
+			}
+			if entryIP := net.ParseIP(normalised); entryIP != nil && entryIP.Equal(ip) {
+				return entry
+			}
+		default:
+			domain := strings.TrimPrefix(strings.TrimPrefix(normalised, "*"), ".")
+			if host == domain || strings.HasSuffix(host, "."+domain) {
+				return entry
+			}
+		}
+	}
+
+	return ""
+}
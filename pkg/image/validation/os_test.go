@@ -1,10 +1,23 @@
 package validation
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/combustion"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 )
 
@@ -108,6 +121,40 @@ func TestValidateOperatingSystem(t *testing.T) {
 				"You cannot simultaneously configure rawConfiguration and isoConfiguration, regardless of image type.",
 			},
 		},
+		`duplicate IDs`: {
+			Definition: image.Definition{
+				OperatingSystem: image.OperatingSystem{
+					Groups: []image.OperatingSystemGroup{
+						{
+							Name: "developers",
+							GID:  3000,
+						},
+						{
+							Name: "operators",
+							GID:  3000,
+						},
+					},
+					Users: []image.OperatingSystemUser{
+						{
+							Username:          "alpha",
+							UID:               3000,
+							EncryptedPassword: "foo",
+							PrimaryGroup:      "developers",
+						},
+						{
+							Username:          "beta",
+							UID:               3000,
+							EncryptedPassword: "foo",
+							PrimaryGroup:      "operators",
+						},
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"Duplicate GID 3000 found across groups.",
+				"Duplicate UID 3000 found across users.",
+			},
+		},
 	}
 
 	for name, test := range tests {
@@ -290,6 +337,49 @@ func TestValidateGroups(t *testing.T) {
 				"Duplicate group name found: group2",
 			},
 		},
+		`unique GIDs`: {
+			Groups: []image.OperatingSystemGroup{
+				{
+					Name: "group1",
+					GID:  2000,
+				},
+				{
+					Name: "group2",
+				},
+				{
+					Name: "group3",
+				},
+				{
+					Name: "group4",
+					GID:  2001,
+				},
+			},
+		},
+		`duplicate GIDs`: {
+			Groups: []image.OperatingSystemGroup{
+				{
+					Name: "group1",
+					GID:  2000,
+				},
+				{
+					Name: "group2",
+					GID:  2000,
+				},
+				{
+					Name: "group3",
+					GID:  2001,
+				},
+				{
+					Name: "group1",
+					GID:  2001,
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"Duplicate group name found: group1",
+				"Duplicate GID 2000 found across groups.",
+				"Duplicate GID 2001 found across groups.",
+			},
+		},
 	}
 
 	for name, test := range tests {
@@ -373,7 +463,136 @@ func TestValidateUsers(t *testing.T) {
 				},
 			},
 			ExpectedFailedMessages: []string{
-				"The 'createHomeDir' attribute must be set to 'true' if at least one SSH key is specified.",
+				"User 'edu' must have 'createHomeDir' set to 'true' since SSH keys are stored in the home directory.",
+			},
+		},
+		`password only and no create home`: {
+			Users: []image.OperatingSystemUser{
+				{
+					Username:          "nick",
+					EncryptedPassword: "foo",
+				},
+			},
+		},
+		`system user with create home`: {
+			Users: []image.OperatingSystemUser{
+				{
+					Username:          "svc",
+					UID:               999,
+					EncryptedPassword: "foo",
+					CreateHomeDir:     true,
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"User 'svc' is a system user (UID 999 < 1000) but 'createHomeDir' is set to 'true'.",
+			},
+		},
+		`primary group in secondary groups`: {
+			Users: []image.OperatingSystemUser{
+				{
+					Username:          "alex",
+					EncryptedPassword: "foo",
+					PrimaryGroup:      "developers",
+					SecondaryGroups:   []string{"wheel", "developers"},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"User 'alex' lists its primaryGroup 'developers' in secondaryGroups.",
+			},
+		},
+		`duplicate secondary groups`: {
+			Users: []image.OperatingSystemUser{
+				{
+					Username:          "alex",
+					EncryptedPassword: "foo",
+					PrimaryGroup:      "developers",
+					SecondaryGroups:   []string{"wheel", "docker", "wheel", "developers"},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"User 'alex' lists its primaryGroup 'developers' in secondaryGroups.",
+				"User 'alex' lists the group 'wheel' multiple times in secondaryGroups.",
+			},
+		},
+		`secondary groups without primary group`: {
+			Users: []image.OperatingSystemUser{
+				{
+					Username:          "alex",
+					EncryptedPassword: "foo",
+					SecondaryGroups:   []string{"wheel", "docker"},
+				},
+			},
+		},
+		`password authentication disabled`: {
+			Users: []image.OperatingSystemUser{
+				{
+					Username:            "kai",
+					CreateHomeDir:       true,
+					SSHKeys:             []string{"key1"},
+					DisablePasswordAuth: true,
+				},
+			},
+		},
+		`password authentication disabled with password`: {
+			Users: []image.OperatingSystemUser{
+				{
+					Username:            "kai",
+					EncryptedPassword:   "foo",
+					CreateHomeDir:       true,
+					SSHKeys:             []string{"key1"},
+					DisablePasswordAuth: true,
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"User 'kai' cannot set an 'encryptedPassword' when 'disablePasswordAuth' is set to 'true'.",
+			},
+		},
+		`password authentication disabled without ssh keys`: {
+			Users: []image.OperatingSystemUser{
+				{
+					Username:            "kai",
+					EncryptedPassword:   "foo",
+					DisablePasswordAuth: true,
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"User 'kai' cannot set an 'encryptedPassword' when 'disablePasswordAuth' is set to 'true'.",
+				"User 'kai' must have at least one SSH key when 'disablePasswordAuth' is set to 'true'.",
+			},
+		},
+		`duplicate UIDs`: {
+			Users: []image.OperatingSystemUser{
+				{
+					Username:          "alpha",
+					UID:               2000,
+					EncryptedPassword: "foo",
+				},
+				{
+					Username:          "beta",
+					EncryptedPassword: "foo",
+				},
+				{
+					Username:          "gamma",
+					EncryptedPassword: "foo",
+				},
+				{
+					Username:          "delta",
+					UID:               2000,
+					EncryptedPassword: "foo",
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"Duplicate UID 2000 found across users.",
+			},
+		},
+		`system user with ssh keys`: {
+			Users: []image.OperatingSystemUser{
+				{
+					Username:      "svc",
+					UID:           500,
+					SSHKeys:       []string{"key1"},
+					CreateHomeDir: true,
+				},
 			},
 		},
 	}
@@ -386,6 +605,14 @@ func TestValidateUsers(t *testing.T) {
 			failures := validateUsers(&os)
 			assert.Len(t, failures, len(test.ExpectedFailedMessages))
 
+			for _, failure := range failures {
+				if strings.Contains(failure.UserMessage, "is a system user") {
+					assert.Equal(t, SeverityWarning, failure.Severity)
+				} else {
+					assert.Equal(t, SeverityError, failure.Severity)
+				}
+			}
+
 			var foundMessages []string
 			for _, foundValidation := range failures {
 				foundMessages = append(foundMessages, foundValidation.UserMessage)
@@ -399,6 +626,15 @@ func TestValidateUsers(t *testing.T) {
 }
 
 func TestValidateSuma(t *testing.T) {
+	configDir := t.TempDir()
+	sumaDir := filepath.Join(configDir, combustion.SumaDir)
+	require.NoError(t, os.Mkdir(sumaDir, 0o755))
+
+	invalidCert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("invalid")})
+	require.NoError(t, os.WriteFile(filepath.Join(sumaDir, "ca.crt"), generateCertificate(t), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(sumaDir, "invalid.crt"), invalidCert, 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(sumaDir, "plain.crt"), []byte("not a certificate"), 0o600))
+
 	tests := map[string]struct {
 		Suma                   image.Suma
 		ExpectedFailedMessages []string
@@ -429,6 +665,39 @@ func TestValidateSuma(t *testing.T) {
 				"The suma 'host' field may not contain 'http://' or 'https://'",
 			},
 		},
+		`FQDN host`: {
+			Suma: image.Suma{
+				Host:          "suma.example.com",
+				ActivationKey: "foo",
+			},
+		},
+		`host with port`: {
+			Suma: image.Suma{
+				Host:          "example.com:8080",
+				ActivationKey: "foo",
+			},
+			ExpectedFailedMessages: []string{
+				"The suma 'host' field must be a bare hostname without a port or path.",
+			},
+		},
+		`host with path`: {
+			Suma: image.Suma{
+				Host:          "example.com/rhn",
+				ActivationKey: "foo",
+			},
+			ExpectedFailedMessages: []string{
+				"The suma 'host' field must be a bare hostname without a port or path.",
+			},
+		},
+		`host with port and path`: {
+			Suma: image.Suma{
+				Host:          "example.com:8080/path",
+				ActivationKey: "foo",
+			},
+			ExpectedFailedMessages: []string{
+				"The suma 'host' field must be a bare hostname without a port or path.",
+			},
+		},
 		`no activation key`: {
 			Suma: image.Suma{
 				Host: "valid",
@@ -437,6 +706,65 @@ func TestValidateSuma(t *testing.T) {
 				"The 'activationKey' field is required for the 'suma' section.",
 			},
 		},
+		`valid proxy and CA certificate`: {
+			Suma: image.Suma{
+				Host:          "suma.example.com",
+				ActivationKey: "foo",
+				Proxy:         "http://proxy.example.com:3128",
+				CACert:        "ca.crt",
+			},
+		},
+		`proxy without port`: {
+			Suma: image.Suma{
+				Host:          "suma.example.com",
+				ActivationKey: "foo",
+				Proxy:         "http://proxy.example.com",
+			},
+			ExpectedFailedMessages: []string{
+				"The suma 'proxy' field must be a valid URL in the format 'http://<host>:<port>'.",
+			},
+		},
+		`proxy without scheme`: {
+			Suma: image.Suma{
+				Host:          "suma.example.com",
+				ActivationKey: "foo",
+				Proxy:         "proxy.example.com:3128",
+			},
+			ExpectedFailedMessages: []string{
+				"The suma 'proxy' field must be a valid URL in the format 'http://<host>:<port>'.",
+			},
+		},
+		`missing CA certificate`: {
+			Suma: image.Suma{
+				Host:          "suma.example.com",
+				ActivationKey: "foo",
+				CACert:        "missing.crt",
+			},
+			ExpectedFailedMessages: []string{
+				fmt.Sprintf("The suma CA certificate 'missing.crt' could not be found at '%s'.",
+					filepath.Join(sumaDir, "missing.crt")),
+			},
+		},
+		`CA certificate not PEM encoded`: {
+			Suma: image.Suma{
+				Host:          "suma.example.com",
+				ActivationKey: "foo",
+				CACert:        "plain.crt",
+			},
+			ExpectedFailedMessages: []string{
+				"The suma CA certificate 'plain.crt' must be a PEM encoded certificate.",
+			},
+		},
+		`invalid CA certificate`: {
+			Suma: image.Suma{
+				Host:          "suma.example.com",
+				ActivationKey: "foo",
+				CACert:        "invalid.crt",
+			},
+			ExpectedFailedMessages: []string{
+				"The suma CA certificate 'invalid.crt' could not be parsed.",
+			},
+		},
 	}
 
 	for name, test := range tests {
@@ -444,7 +772,7 @@ func TestValidateSuma(t *testing.T) {
 			os := image.OperatingSystem{
 				Suma: test.Suma,
 			}
-			failures := validateSuma(&os)
+			failures := validateSuma(&os, configDir)
 			assert.Len(t, failures, len(test.ExpectedFailedMessages))
 
 			var foundMessages []string
@@ -478,6 +806,16 @@ func TestPackages(t *testing.T) {
 				RegCode: "regcode",
 			},
 		},
+		`gpg check disabled`: {
+			Packages: image.Packages{
+				PKGList:    []string{"foo"},
+				RegCode:    "regcode",
+				NoGPGCheck: true,
+			},
+			ExpectedFailedMessages: []string{
+				"GPG signature checking is disabled for package installation; this is insecure.",
+			},
+		},
 		`empty package`: {
 			Packages: image.Packages{
 				PKGList: []string{"foo", "bar", ""},
@@ -495,6 +833,50 @@ func TestPackages(t *testing.T) {
 				"The 'packageList' field contains duplicate packages: foo, bar",
 			},
 		},
+		`version constraints`: {
+			Packages: image.Packages{
+				PKGList: []string{"foo=1.2.3", "bar>=1.2", "baz<1:2.0-150500.1.1"},
+				RegCode: "regcode",
+			},
+		},
+		`invalid version constraints`: {
+			Packages: image.Packages{
+				PKGList: []string{"foo=>1.2.3", "bar>=", "baz 1.2"},
+				RegCode: "regcode",
+			},
+			ExpectedFailedMessages: []string{
+				"The 'packageList' entry 'foo=>1.2.3' is invalid. Entries must be a package name, optionally followed by a version constraint such as 'foo=1.2.3' or 'foo>=1.2'.",
+				"The 'packageList' entry 'bar>=' is invalid. Entries must be a package name, optionally followed by a version constraint such as 'foo=1.2.3' or 'foo>=1.2'.",
+				"The 'packageList' entry 'baz 1.2' is invalid. Entries must be a package name, optionally followed by a version constraint such as 'foo=1.2.3' or 'foo>=1.2'.",
+			},
+		},
+		`conflicting version pins`: {
+			Packages: image.Packages{
+				PKGList: []string{"foo=1", "foo=2", "bar", "bar>=1.2"},
+				RegCode: "regcode",
+			},
+			ExpectedFailedMessages: []string{
+				"The 'packageList' field contains duplicate packages: foo, bar",
+			},
+		},
+		`install options`: {
+			Packages: image.Packages{
+				PKGList:        []string{"foo"},
+				RegCode:        "regcode",
+				InstallOptions: []string{"--no-recommends", "--replacefiles"},
+			},
+		},
+		`unsupported install options`: {
+			Packages: image.Packages{
+				PKGList:        []string{"foo"},
+				RegCode:        "regcode",
+				InstallOptions: []string{"--no-recommends", "--no-gpg-checks", "-y; reboot"},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'installOptions' field contains the unsupported option '--no-gpg-checks'. Supported options are: --no-recommends, --recommends, --oldpackage, --replacefiles.",
+				"The 'installOptions' field contains the unsupported option '-y; reboot'. Supported options are: --no-recommends, --recommends, --oldpackage, --replacefiles.",
+			},
+		},
 		`duplicate repos`: {
 			Packages: image.Packages{
 				AdditionalRepos: []image.AddRepo{
@@ -550,39 +932,586 @@ func TestPackages(t *testing.T) {
 	}
 }
 
-func TestValidateUnattended(t *testing.T) {
+func TestValidateRegistrationCode(t *testing.T) {
+	configDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "reg-code"), []byte("code"), 0o600))
+
+	t.Setenv("EIB_TEST_REG_CODE", "code")
+
 	tests := map[string]struct {
-		Definition             image.Definition
+		Packages               image.Packages
 		ExpectedFailedMessages []string
 	}{
-		`not included`: {
-			Definition: image.Definition{},
+		`not specified`: {},
+		`plain text`: {
+			Packages: image.Packages{
+				RegCode: "code",
+			},
 		},
-		`iso install device specified`: {
-			Definition: image.Definition{
-				Image: image.Image{
-					ImageType: image.TypeISO,
-				},
-				OperatingSystem: image.OperatingSystem{
-					IsoConfiguration: image.IsoConfiguration{
-						InstallDevice: "/dev/sda",
-					},
-				},
+		`environment variable`: {
+			Packages: image.Packages{
+				RegCode: "env:EIB_TEST_REG_CODE",
 			},
 		},
-		`not iso install device`: {
-			Definition: image.Definition{
-				Image: image.Image{
-					ImageType: image.TypeRAW,
-				},
-				OperatingSystem: image.OperatingSystem{
-					IsoConfiguration: image.IsoConfiguration{
-						InstallDevice: "/dev/sda",
-					},
-				},
+		`file`: {
+			Packages: image.Packages{
+				RegCodeFile: "reg-code",
+			},
+		},
+		`both forms`: {
+			Packages: image.Packages{
+				RegCode:     "env:EIB_TEST_REG_CODE",
+				RegCodeFile: "reg-code",
 			},
 			ExpectedFailedMessages: []string{
-				fmt.Sprintf("The 'isoConfiguration/installDevice' field can only be used when 'imageType' is '%s'.", image.TypeISO),
+				"Only one of the 'sccRegistrationCode' and 'sccRegistrationCodeFile' fields may be specified.",
+			},
+		},
+		`missing environment variable`: {
+			Packages: image.Packages{
+				RegCode: "env:EIB_TEST_MISSING_REG_CODE",
+			},
+			ExpectedFailedMessages: []string{
+				"The environment variable referenced by the 'sccRegistrationCode' field ('env:EIB_TEST_MISSING_REG_CODE') is not set.",
+			},
+		},
+		`missing file`: {
+			Packages: image.Packages{
+				RegCodeFile: "missing",
+			},
+			ExpectedFailedMessages: []string{
+				"The registration code file 'missing' specified in the 'sccRegistrationCodeFile' field could not be read or is empty.",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := image.Context{
+				ImageConfigDir: configDir,
+				ImageDefinition: &image.Definition{
+					OperatingSystem: image.OperatingSystem{
+						Packages: test.Packages,
+					},
+				},
+			}
+			failures := validateRegistrationCode(&ctx)
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+		})
+	}
+}
+
+func TestValidateSideLoadedRPMs(t *testing.T) {
+	// Setup
+	rpmsDir := filepath.Join(t.TempDir(), "rpms")
+	require.NoError(t, os.MkdirAll(filepath.Join(rpmsDir, "gpg-keys"), 0o755))
+
+	// RPM lead: magic, version 3.0, binary type, followed by padding up to the 96 bytes lead size
+	validRPM := append([]byte{0xed, 0xab, 0xee, 0xdb, 0x03, 0x00, 0x00, 0x00}, make([]byte, 88)...)
+	require.NoError(t, os.WriteFile(filepath.Join(rpmsDir, "valid.rpm"), validRPM, 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(rpmsDir, "fake.rpm"), []byte("this is not an RPM"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(rpmsDir, "empty.rpm"), []byte{}, 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(rpmsDir, "README.txt"), []byte("notes"), 0o600))
+
+	// Test
+	failures := validateSideLoadedRPMs(rpmsDir, image.ArchTypeX86)
+
+	// Verify
+	var foundMessages []string
+	for _, foundValidation := range failures {
+		foundMessages = append(foundMessages, foundValidation.UserMessage)
+	}
+
+	expectedMessages := []string{
+		"The file 'empty.rpm' in the 'rpms' directory is not a valid RPM package.",
+		"The file 'fake.rpm' in the 'rpms' directory is not a valid RPM package.",
+	}
+	assert.ElementsMatch(t, expectedMessages, foundMessages)
+}
+
+func TestValidateSideLoadedRPMs_MultipleVersions(t *testing.T) {
+	// Setup
+	rpmsDir := t.TempDir()
+
+	validRPM := append([]byte{0xed, 0xab, 0xee, 0xdb, 0x03, 0x00, 0x00, 0x00}, make([]byte, 88)...)
+	for _, rpmFile := range []string{"cowsay-3.10-1.1.noarch.rpm", "cowsay-3.9-1.1.noarch.rpm", "fortune-1.0-1.x86_64.rpm"} {
+		require.NoError(t, os.WriteFile(filepath.Join(rpmsDir, rpmFile), validRPM, 0o600))
+	}
+
+	// Test
+	failures := validateSideLoadedRPMs(rpmsDir, image.ArchTypeX86)
+
+	// Verify
+	require.Len(t, failures, 1)
+	assert.Equal(t, "RPM 'cowsay-3.9-1.1.noarch.rpm' is superseded by a higher version of the same package "+
+		"('cowsay-3.10-1.1.noarch.rpm') and will not be installed.", failures[0].UserMessage)
+	assert.Equal(t, SeverityWarning, failures[0].Severity)
+}
+
+func TestValidateSideLoadedRPMs_NoDirectory(t *testing.T) {
+	failures := validateSideLoadedRPMs(filepath.Join(t.TempDir(), "rpms"), image.ArchTypeX86)
+	assert.Empty(t, failures)
+}
+
+func TestValidateSideLoadedRPMs_Architecture(t *testing.T) {
+	validRPM := append([]byte{0xed, 0xab, 0xee, 0xdb, 0x03, 0x00, 0x00, 0x00}, make([]byte, 88)...)
+
+	tests := map[string]struct {
+		RPMFile                string
+		Arch                   image.Arch
+		ExpectedFailedMessages []string
+	}{
+		`matching x86_64`: {
+			RPMFile: "cowsay-3.03-150500.1.1.x86_64.rpm",
+			Arch:    image.ArchTypeX86,
+		},
+		`matching aarch64`: {
+			RPMFile: "cowsay-3.03-150500.1.1.aarch64.rpm",
+			Arch:    image.ArchTypeARM,
+		},
+		`mismatching x86_64`: {
+			RPMFile: "cowsay-3.03-150500.1.1.x86_64.rpm",
+			Arch:    image.ArchTypeARM,
+			ExpectedFailedMessages: []string{
+				"RPM 'cowsay-3.03-150500.1.1.x86_64.rpm' targets a different architecture than the image (aarch64).",
+			},
+		},
+		`mismatching aarch64`: {
+			RPMFile: "cowsay-3.03-150500.1.1.aarch64.rpm",
+			Arch:    image.ArchTypeX86,
+			ExpectedFailedMessages: []string{
+				"RPM 'cowsay-3.03-150500.1.1.aarch64.rpm' targets a different architecture than the image (x86_64).",
+			},
+		},
+		`noarch`: {
+			RPMFile: "cowsay-3.03-150500.1.1.noarch.rpm",
+			Arch:    image.ArchTypeARM,
+		},
+		`no architecture suffix`: {
+			RPMFile: "my-policy.rpm",
+			Arch:    image.ArchTypeARM,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			rpmsDir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(rpmsDir, test.RPMFile), validRPM, 0o600))
+
+			failures := validateSideLoadedRPMs(rpmsDir, test.Arch)
+			assert.Len(t, failures, len(test.ExpectedFailedMessages))
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+				assert.Equal(t, SeverityWarning, foundValidation.Severity)
+			}
+
+			for _, expectedMessage := range test.ExpectedFailedMessages {
+				assert.Contains(t, foundMessages, expectedMessage)
+			}
+		})
+	}
+}
+
+func TestValidateUnattended(t *testing.T) {
+	tests := map[string]struct {
+		Definition             image.Definition
+		ExpectedFailedMessages []string
+	}{
+		`not included`: {
+			Definition: image.Definition{},
+		},
+		`iso install device specified`: {
+			Definition: image.Definition{
+				Image: image.Image{
+					ImageType: image.TypeISO,
+				},
+				OperatingSystem: image.OperatingSystem{
+					IsoConfiguration: image.IsoConfiguration{
+						InstallDevice: "/dev/sda",
+					},
+				},
+			},
+		},
+		`iso install device auto`: {
+			Definition: image.Definition{
+				Image: image.Image{
+					ImageType: image.TypeISO,
+				},
+				OperatingSystem: image.OperatingSystem{
+					IsoConfiguration: image.IsoConfiguration{
+						InstallDevice: "auto",
+					},
+				},
+			},
+		},
+		`iso install device by id`: {
+			Definition: image.Definition{
+				Image: image.Image{
+					ImageType: image.TypeISO,
+				},
+				OperatingSystem: image.OperatingSystem{
+					IsoConfiguration: image.IsoConfiguration{
+						InstallDevice: "/dev/disk/by-id/nvme-eui.0025388b71b0a1b2",
+					},
+				},
+			},
+		},
+		`iso install device missing dev prefix`: {
+			Definition: image.Definition{
+				Image: image.Image{
+					ImageType: image.TypeISO,
+				},
+				OperatingSystem: image.OperatingSystem{
+					IsoConfiguration: image.IsoConfiguration{
+						InstallDevice: "sda",
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'installDevice' field must be either 'auto' or a single absolute device path (e.g. /dev/sda).",
+			},
+		},
+		`iso install device outside dev`: {
+			Definition: image.Definition{
+				Image: image.Image{
+					ImageType: image.TypeISO,
+				},
+				OperatingSystem: image.OperatingSystem{
+					IsoConfiguration: image.IsoConfiguration{
+						InstallDevice: "/dev/../tmp/disk.img",
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'installDevice' field must be either 'auto' or a single absolute device path (e.g. /dev/sda).",
+			},
+		},
+		`iso install device unknown token`: {
+			Definition: image.Definition{
+				Image: image.Image{
+					ImageType: image.TypeISO,
+				},
+				OperatingSystem: image.OperatingSystem{
+					IsoConfiguration: image.IsoConfiguration{
+						InstallDevice: "first",
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'installDevice' field must be either 'auto' or a single absolute device path (e.g. /dev/sda).",
+			},
+		},
+		`iso install device list`: {
+			Definition: image.Definition{
+				Image: image.Image{
+					ImageType: image.TypeISO,
+				},
+				OperatingSystem: image.OperatingSystem{
+					IsoConfiguration: image.IsoConfiguration{
+						InstallDevice: "/dev/sda,/dev/sdb",
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'installDevice' field must be either 'auto' or a single absolute device path (e.g. /dev/sda).",
+			},
+		},
+		`iso install device whitespace`: {
+			Definition: image.Definition{
+				Image: image.Image{
+					ImageType: image.TypeISO,
+				},
+				OperatingSystem: image.OperatingSystem{
+					IsoConfiguration: image.IsoConfiguration{
+						InstallDevice: "/dev/sda /dev/sdb",
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'installDevice' field must be either 'auto' or a single absolute device path (e.g. /dev/sda).",
+			},
+		},
+		`iso install device pipe`: {
+			Definition: image.Definition{
+				Image: image.Image{
+					ImageType: image.TypeISO,
+				},
+				OperatingSystem: image.OperatingSystem{
+					IsoConfiguration: image.IsoConfiguration{
+						InstallDevice: "/dev/sda|/dev/sdb",
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'installDevice' field must be either 'auto' or a single absolute device path (e.g. /dev/sda).",
+			},
+		},
+		`iso install device trailing newline`: {
+			Definition: image.Definition{
+				Image: image.Image{
+					ImageType: image.TypeISO,
+				},
+				OperatingSystem: image.OperatingSystem{
+					IsoConfiguration: image.IsoConfiguration{
+						InstallDevice: "/dev/sda\n",
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'installDevice' field must be either 'auto' or a single absolute device path (e.g. /dev/sda).",
+			},
+		},
+		`iso install device padded auto`: {
+			Definition: image.Definition{
+				Image: image.Image{
+					ImageType: image.TypeISO,
+				},
+				OperatingSystem: image.OperatingSystem{
+					IsoConfiguration: image.IsoConfiguration{
+						InstallDevice: " auto",
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'installDevice' field must be either 'auto' or a single absolute device path (e.g. /dev/sda).",
+			},
+		},
+		`not iso install device`: {
+			Definition: image.Definition{
+				Image: image.Image{
+					ImageType: image.TypeRAW,
+				},
+				OperatingSystem: image.OperatingSystem{
+					IsoConfiguration: image.IsoConfiguration{
+						InstallDevice: "/dev/sda",
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				fmt.Sprintf("The 'isoConfiguration/installDevice' field can only be used when 'imageType' is '%s'.", image.TypeISO),
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			def := test.Definition
+			failures := validateImageTypeFields(&def)
+			failures = append(failures, validateIsoConfig(&def)...)
+			assert.Len(t, failures, len(test.ExpectedFailedMessages))
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+			}
+
+			for _, expectedMessage := range test.ExpectedFailedMessages {
+				assert.Contains(t, foundMessages, expectedMessage)
+			}
+		})
+	}
+}
+
+func TestValidateImageTypeFields(t *testing.T) {
+	tests := map[string]struct {
+		Definition             image.Definition
+		ExpectedFailedMessages []string
+	}{
+		`raw fields on raw image`: {
+			Definition: image.Definition{
+				Image: image.Image{
+					ImageType: image.TypeRAW,
+				},
+				OperatingSystem: image.OperatingSystem{
+					RawConfiguration: image.RawConfiguration{
+						DiskSize: "64G",
+					},
+				},
+			},
+		},
+		`raw fields on iso image`: {
+			Definition: image.Definition{
+				Image: image.Image{
+					ImageType: image.TypeISO,
+				},
+				OperatingSystem: image.OperatingSystem{
+					RawConfiguration: image.RawConfiguration{
+						DiskSize: "64G",
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'rawConfiguration/diskSize' field can only be used when 'imageType' is 'raw'.",
+			},
+		},
+		`iso and raw fields on raw image`: {
+			Definition: image.Definition{
+				Image: image.Image{
+					ImageType: image.TypeRAW,
+				},
+				OperatingSystem: image.OperatingSystem{
+					IsoConfiguration: image.IsoConfiguration{
+						InstallDevice: "/dev/sda",
+					},
+					RawConfiguration: image.RawConfiguration{
+						DiskSize: "64G",
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'isoConfiguration/installDevice' field can only be used when 'imageType' is 'iso'.",
+			},
+		},
+		`iso and raw fields on iso image`: {
+			Definition: image.Definition{
+				Image: image.Image{
+					ImageType: image.TypeISO,
+				},
+				OperatingSystem: image.OperatingSystem{
+					IsoConfiguration: image.IsoConfiguration{
+						InstallDevice: "/dev/sda",
+					},
+					RawConfiguration: image.RawConfiguration{
+						DiskSize: "64G",
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'rawConfiguration/diskSize' field can only be used when 'imageType' is 'raw'.",
+			},
+		},
+		`iso reboot on raw image`: {
+			Definition: image.Definition{
+				Image: image.Image{
+					ImageType: image.TypeRAW,
+				},
+				OperatingSystem: image.OperatingSystem{
+					IsoConfiguration: image.IsoConfiguration{
+						RebootAfterInstall: true,
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'isoConfiguration/rebootAfterInstall' field can only be used when 'imageType' is 'iso'.",
+			},
+		},
+		`iso and raw fields without image type`: {
+			Definition: image.Definition{
+				OperatingSystem: image.OperatingSystem{
+					IsoConfiguration: image.IsoConfiguration{
+						InstallDevice: "/dev/sda",
+					},
+					RawConfiguration: image.RawConfiguration{
+						DiskSize: "64G",
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'isoConfiguration/installDevice' field can only be used when 'imageType' is 'iso'.",
+				"The 'rawConfiguration/diskSize' field can only be used when 'imageType' is 'raw'.",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			def := test.Definition
+			failures := validateImageTypeFields(&def)
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+		})
+	}
+}
+
+func TestValidateIsoConfiguration(t *testing.T) {
+	tests := map[string]struct {
+		Definition             image.Definition
+		ExpectedFailedMessages []string
+	}{
+		`not included`: {
+			Definition: image.Definition{},
+		},
+		`explicit install device with reboot`: {
+			Definition: image.Definition{
+				Image: image.Image{
+					ImageType: image.TypeISO,
+				},
+				OperatingSystem: image.OperatingSystem{
+					IsoConfiguration: image.IsoConfiguration{
+						InstallDevice:      "/dev/sda",
+						RebootAfterInstall: true,
+					},
+				},
+			},
+		},
+		`auto install device`: {
+			Definition: image.Definition{
+				Image: image.Image{
+					ImageType: image.TypeISO,
+				},
+				OperatingSystem: image.OperatingSystem{
+					IsoConfiguration: image.IsoConfiguration{
+						InstallDevice: "auto",
+					},
+				},
+			},
+		},
+		`auto combined with explicit device`: {
+			Definition: image.Definition{
+				Image: image.Image{
+					ImageType: image.TypeISO,
+				},
+				OperatingSystem: image.OperatingSystem{
+					IsoConfiguration: image.IsoConfiguration{
+						InstallDevice: "auto, /dev/sda",
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'isoConfiguration/installDevice' field cannot combine 'auto' with an explicit device.",
+			},
+		},
+		`invalid max size`: {
+			Definition: image.Definition{
+				Image: image.Image{
+					ImageType: image.TypeISO,
+				},
+				OperatingSystem: image.OperatingSystem{
+					IsoConfiguration: image.IsoConfiguration{
+						MaxSize: "4.7G",
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'isoConfiguration/maxSize' field must be an integer followed by a suffix of either 'M', 'G', or 'T'.",
+			},
+		},
+		`reboot without install device`: {
+			Definition: image.Definition{
+				Image: image.Image{
+					ImageType: image.TypeISO,
+				},
+				OperatingSystem: image.OperatingSystem{
+					IsoConfiguration: image.IsoConfiguration{
+						RebootAfterInstall: true,
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'isoConfiguration/rebootAfterInstall' field requires 'isoConfiguration/installDevice' to be set for an unattended installation.",
 			},
 		},
 	}
@@ -591,16 +1520,13 @@ func TestValidateUnattended(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			def := test.Definition
 			failures := validateIsoConfig(&def)
-			assert.Len(t, failures, len(test.ExpectedFailedMessages))
 
 			var foundMessages []string
 			for _, foundValidation := range failures {
 				foundMessages = append(foundMessages, foundValidation.UserMessage)
 			}
 
-			for _, expectedMessage := range test.ExpectedFailedMessages {
-				assert.Contains(t, foundMessages, expectedMessage)
-			}
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
 		})
 	}
 }
@@ -778,3 +1704,406 @@ func TestValidateTimeSync(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateHostEntries(t *testing.T) {
+	tests := map[string]struct {
+		HostEntries            []image.HostEntry
+		ExpectedFailedMessages []string
+	}{
+		`no entries`: {},
+		`valid entries`: {
+			HostEntries: []image.HostEntry{
+				{
+					IP:        "192.168.122.100",
+					Hostnames: []string{"api.cluster01.edge.suse.com", "node1"},
+				},
+				{
+					IP:        "fd12:3456::1",
+					Hostnames: []string{"node2.edge.suse.com"},
+				},
+			},
+		},
+		`invalid IP`: {
+			HostEntries: []image.HostEntry{
+				{
+					IP:        "192.168.122.300",
+					Hostnames: []string{"node1"},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"Host entry IP '192.168.122.300' is not a valid IP address.",
+			},
+		},
+		`missing hostnames`: {
+			HostEntries: []image.HostEntry{
+				{
+					IP: "192.168.122.100",
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"Host entry for IP '192.168.122.100' must contain at least one hostname.",
+			},
+		},
+		`invalid hostnames`: {
+			HostEntries: []image.HostEntry{
+				{
+					IP:        "192.168.122.100",
+					Hostnames: []string{"-node1", "node_2", "node3..suse.com"},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"Hostname '-node1' in the host entry for IP '192.168.122.100' is not a valid RFC 1123 hostname.",
+				"Hostname 'node_2' in the host entry for IP '192.168.122.100' is not a valid RFC 1123 hostname.",
+				"Hostname 'node3..suse.com' in the host entry for IP '192.168.122.100' is not a valid RFC 1123 hostname.",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			os := image.OperatingSystem{
+				HostEntries: test.HostEntries,
+			}
+			failures := validateHostEntries(&os)
+			assert.Len(t, failures, len(test.ExpectedFailedMessages))
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+			}
+
+			for _, expectedMessage := range test.ExpectedFailedMessages {
+				assert.Contains(t, foundMessages, expectedMessage)
+			}
+		})
+	}
+}
+
+func TestValidateSysctl(t *testing.T) {
+	tests := map[string]struct {
+		Sysctl                 map[string]string
+		ExpectedFailedMessages []string
+	}{
+		`no sysctl`: {},
+		`valid sysctl`: {
+			Sysctl: map[string]string{
+				"vm.max_map_count":                 "262144",
+				"net.ipv4.ip_forward":              "1",
+				"net/ipv4/conf/eth0.100/rp_filter": "2",
+			},
+		},
+		`invalid keys`: {
+			Sysctl: map[string]string{
+				"max_map_count":        "262144",
+				"net.ipv4..forward":    "1",
+				"kernel.panic; reboot": "1",
+			},
+			ExpectedFailedMessages: []string{
+				"Sysctl key 'max_map_count' is not a valid kernel parameter path (e.g. 'net.ipv4.ip_forward').",
+				"Sysctl key 'net.ipv4..forward' is not a valid kernel parameter path (e.g. 'net.ipv4.ip_forward').",
+				"Sysctl key 'kernel.panic; reboot' is not a valid kernel parameter path (e.g. 'net.ipv4.ip_forward').",
+			},
+		},
+		`invalid values`: {
+			Sysctl: map[string]string{
+				"vm.swappiness":       " ",
+				"net.ipv4.ip_forward": "1\nkernel.panic = 0",
+			},
+			ExpectedFailedMessages: []string{
+				"Sysctl value for key 'vm.swappiness' must be a non-empty single line.",
+				"Sysctl value for key 'net.ipv4.ip_forward' must be a non-empty single line.",
+			},
+		},
+		`shell characters in values`: {
+			Sysctl: map[string]string{
+				"kernel.hostname":     "$(reboot)",
+				"kernel.domainname":   "`reboot`",
+				"kernel.core_pattern": "|/usr/lib/systemd/systemd-coredump %P",
+			},
+			ExpectedFailedMessages: []string{
+				"Sysctl value for key 'kernel.hostname' must not contain any of the characters: $`\\",
+				"Sysctl value for key 'kernel.domainname' must not contain any of the characters: $`\\",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			os := image.OperatingSystem{
+				Sysctl: test.Sysctl,
+			}
+			failures := validateSysctl(&os)
+			assert.Len(t, failures, len(test.ExpectedFailedMessages))
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+			}
+
+			for _, expectedMessage := range test.ExpectedFailedMessages {
+				assert.Contains(t, foundMessages, expectedMessage)
+			}
+		})
+	}
+}
+
+func TestValidateHostname(t *testing.T) {
+	tests := map[string]struct {
+		Definition             image.Definition
+		ExpectedFailedMessages []string
+	}{
+		`no hostname`: {},
+		`valid hostname`: {
+			Definition: image.Definition{
+				OperatingSystem: image.OperatingSystem{
+					Hostname: "edge-node.suse.com",
+				},
+			},
+		},
+		`valid hostname with single node`: {
+			Definition: image.Definition{
+				OperatingSystem: image.OperatingSystem{
+					Hostname: "node1",
+				},
+				Kubernetes: image.Kubernetes{
+					Nodes: []image.Node{
+						{Hostname: "node1", Type: image.KubernetesNodeTypeServer},
+					},
+				},
+			},
+		},
+		`invalid hostname`: {
+			Definition: image.Definition{
+				OperatingSystem: image.OperatingSystem{
+					Hostname: "edge_node",
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'hostname' field value 'edge_node' is not a valid RFC 1123 hostname.",
+			},
+		},
+		`hostname with multiple nodes`: {
+			Definition: image.Definition{
+				OperatingSystem: image.OperatingSystem{
+					Hostname: "node1",
+				},
+				Kubernetes: image.Kubernetes{
+					Nodes: []image.Node{
+						{Hostname: "node1", Type: image.KubernetesNodeTypeServer},
+						{Hostname: "node2", Type: image.KubernetesNodeTypeAgent},
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'hostname' field cannot be used when multiple Kubernetes nodes are configured, " +
+					"as each node must be assigned its own hostname (e.g. through the network configuration).",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			def := test.Definition
+			failures := validateHostname(&def)
+			assert.Len(t, failures, len(test.ExpectedFailedMessages))
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+			}
+
+			for _, expectedMessage := range test.ExpectedFailedMessages {
+				assert.Contains(t, foundMessages, expectedMessage)
+			}
+		})
+	}
+}
+
+func TestValidateLocale(t *testing.T) {
+	unknownLocaleMessage := func(locale string) string {
+		return fmt.Sprintf("The 'locale' field value '%s' is not a known locale. "+
+			"Locales must be in the format 'language_TERRITORY.UTF-8' (e.g. 'en_US.UTF-8').", locale)
+	}
+
+	tests := map[string]struct {
+		OS                     image.OperatingSystem
+		ExpectedFailedMessages []string
+	}{
+		`no locale`: {},
+		`valid locale`: {
+			OS: image.OperatingSystem{
+				Locale: "en_US.UTF-8",
+			},
+		},
+		`valid locale without codeset`: {
+			OS: image.OperatingSystem{
+				Locale: "de_DE",
+			},
+		},
+		`valid locale with modifier`: {
+			OS: image.OperatingSystem{
+				Locale: "sr_RS.utf8@latin",
+			},
+		},
+		`standard locale`: {
+			OS: image.OperatingSystem{
+				Locale: "C.UTF-8",
+			},
+		},
+		`invalid format`: {
+			OS: image.OperatingSystem{
+				Locale: "english",
+			},
+			ExpectedFailedMessages: []string{
+				unknownLocaleMessage("english"),
+			},
+		},
+		`unknown language`: {
+			OS: image.OperatingSystem{
+				Locale: "zz_US.UTF-8",
+			},
+			ExpectedFailedMessages: []string{
+				unknownLocaleMessage("zz_US.UTF-8"),
+			},
+		},
+		`unknown territory`: {
+			OS: image.OperatingSystem{
+				Locale: "en_XY.UTF-8",
+			},
+			ExpectedFailedMessages: []string{
+				unknownLocaleMessage("en_XY.UTF-8"),
+			},
+		},
+		`unsupported codeset`: {
+			OS: image.OperatingSystem{
+				Locale: "en_US.ISO-8859-1",
+			},
+			ExpectedFailedMessages: []string{
+				unknownLocaleMessage("en_US.ISO-8859-1"),
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			os := test.OS
+			failures := validateLocale(&os)
+			assert.Len(t, failures, len(test.ExpectedFailedMessages))
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+			}
+
+			for _, expectedMessage := range test.ExpectedFailedMessages {
+				assert.Contains(t, foundMessages, expectedMessage)
+			}
+		})
+	}
+}
+
+func generateCertificate(t *testing.T) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "suma.example.com"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+		IsCA:         true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestValidateProxy(t *testing.T) {
+	tests := map[string]struct {
+		Proxy                  image.Proxy
+		ExpectedFailedMessages []string
+	}{
+		`no proxy`: {},
+		`valid`: {
+			Proxy: image.Proxy{
+				HTTPProxy:  "http://10.0.0.1:3128",
+				HTTPSProxy: "http://proxy.corp.example.com:3128",
+				NoProxy:    []string{"localhost", "127.0.0.1", "edge.suse.com", ".svc", "192.168.100.0/24"},
+			},
+		},
+		`self-referential`: {
+			Proxy: image.Proxy{
+				HTTPProxy:  "http://10.0.0.1:3128",
+				HTTPSProxy: "proxy.corp.example.com:3128",
+				NoProxy:    []string{"localhost", "10.0.0.0/8", ".example.com"},
+			},
+			ExpectedFailedMessages: []string{
+				"The proxy 'httpProxy' host '10.0.0.1' is covered by the 'noProxy' entry '10.0.0.0/8'.",
+				"The proxy 'httpsProxy' host 'proxy.corp.example.com' is covered by the 'noProxy' entry '.example.com'.",
+			},
+		},
+		`covered by wildcard`: {
+			Proxy: image.Proxy{
+				HTTPProxy: "http://proxy.corp.example.com:3128",
+				NoProxy:   []string{"*"},
+			},
+			ExpectedFailedMessages: []string{
+				"The proxy 'httpProxy' host 'proxy.corp.example.com' is covered by the 'noProxy' entry '*'.",
+			},
+		},
+		`covered by exact entry`: {
+			Proxy: image.Proxy{
+				HTTPSProxy: "https://Proxy.Corp.Example.com:3129",
+				NoProxy:    []string{"proxy.corp.example.com"},
+			},
+			ExpectedFailedMessages: []string{
+				"The proxy 'httpsProxy' host 'proxy.corp.example.com' is covered by the 'noProxy' entry 'proxy.corp.example.com'.",
+			},
+		},
+		`local host`: {
+			Proxy: image.Proxy{
+				HTTPProxy:  "http://127.0.0.1:3128",
+				HTTPSProxy: "http://localhost:3128",
+				NoProxy:    []string{"localhost", "127.0.0.1"},
+			},
+			ExpectedFailedMessages: []string{
+				"The proxy 'httpProxy' field points at the local host '127.0.0.1', which is unlikely to serve as a proxy for the node.",
+				"The proxy 'httpsProxy' field points at the local host 'localhost', which is unlikely to serve as a proxy for the node.",
+			},
+		},
+		`IPv6 local host`: {
+			Proxy: image.Proxy{
+				HTTPProxy: "http://[::1]:3128",
+			},
+			ExpectedFailedMessages: []string{
+				"The proxy 'httpProxy' field points at the local host '::1', which is unlikely to serve as a proxy for the node.",
+			},
+		},
+		`similar domain is not covered`: {
+			Proxy: image.Proxy{
+				HTTPProxy: "http://proxy.notexample.com:3128",
+				NoProxy:   []string{"example.com"},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			os := image.OperatingSystem{
+				Proxy: test.Proxy,
+			}
+
+			failures := validateProxy(&os)
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+				assert.Equal(t, SeverityWarning, foundValidation.Severity)
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+		})
+	}
+}
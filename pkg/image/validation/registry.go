@@ -1,9 +1,17 @@
 package validation
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
+	"slices"
+	"strings"
 
+	"github.com/suse-edge/edge-image-builder/pkg/combustion"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"github.com/suse-edge/edge-image-builder/pkg/registry"
+	"go.uber.org/zap"
 )
 
 const (
@@ -14,18 +22,40 @@ func validateEmbeddedArtifactRegistry(ctx *image.Context) []FailedValidation {
 	var failures []FailedValidation
 
 	failures = append(failures, validateContainerImages(&ctx.ImageDefinition.EmbeddedArtifactRegistry)...)
+	failures = append(failures, validateSizeBudget(&ctx.ImageDefinition.EmbeddedArtifactRegistry)...)
+	failures = append(failures, validateRegistries(&ctx.ImageDefinition.EmbeddedArtifactRegistry)...)
+	failures = append(failures, validateManifestImageTags(ctx)...)
+	failures = append(failures, validateRegistryReferences(ctx)...)
 
 	return failures
 }
 
+func validateSizeBudget(ear *image.EmbeddedArtifactRegistry) []FailedValidation {
+	if ear.SizeBudget == "" || ear.SizeBudget.IsValid() {
+		return nil
+	}
+
+	return []FailedValidation{
+		{
+			UserMessage: "The 'sizeBudget' field must be an integer followed by a suffix of either 'M', 'G', or 'T'.",
+			Code:        CodeInvalid,
+			Field:       "embeddedArtifactRegistry.sizeBudget",
+		},
+	}
+}
+
 func validateContainerImages(ear *image.EmbeddedArtifactRegistry) []FailedValidation {
 	var failures []FailedValidation
 
 	seenContainerImages := make(map[string]bool)
-	for _, cImage := range ear.ContainerImages {
+	for i, cImage := range ear.ContainerImages {
+		field := fmt.Sprintf("embeddedArtifactRegistry.images[%d].name", i)
+
 		if cImage.Name == "" {
 			failures = append(failures, FailedValidation{
 				UserMessage: "The 'name' field is required for each entry in 'images'.",
+				Code:        CodeRequired,
+				Field:       field,
 			})
 		}
 
@@ -33,6 +63,8 @@ func validateContainerImages(ear *image.EmbeddedArtifactRegistry) []FailedValida
 			msg := fmt.Sprintf("Duplicate image name '%s' found in the 'images' section.", cImage.Name)
 			failures = append(failures, FailedValidation{
 				UserMessage: msg,
+				Code:        CodeDuplicate,
+				Field:       field,
 			})
 		}
 		seenContainerImages[cImage.Name] = true
@@ -40,3 +72,181 @@ func validateContainerImages(ear *image.EmbeddedArtifactRegistry) []FailedValida
 
 	return failures
 }
+
+func validateRegistries(ear *image.EmbeddedArtifactRegistry) []FailedValidation {
+	var failures []FailedValidation
+
+	seenRegistries := make(map[string]bool)
+	for i, r := range ear.Registries {
+		field := fmt.Sprintf("embeddedArtifactRegistry.registries[%d]", i)
+
+		if r.URI == "" {
+			failures = append(failures, FailedValidation{
+				UserMessage: "The 'uri' field is required for each entry in 'registries'.",
+				Code:        CodeRequired,
+				Field:       field + ".uri",
+			})
+			continue
+		}
+
+		if strings.Contains(r.URI, "/") {
+			msg := fmt.Sprintf("The registry URI '%s' must be a hostname, optionally followed by a port, without a scheme or path.", r.URI)
+			failures = append(failures, FailedValidation{
+				UserMessage: msg,
+				Code:        CodeInvalid,
+				Field:       field + ".uri",
+			})
+		}
+
+		if seenRegistries[r.URI] {
+			msg := fmt.Sprintf("Duplicate registry URI '%s' found in the 'registries' section.", r.URI)
+			failures = append(failures, FailedValidation{
+				UserMessage: msg,
+				Code:        CodeDuplicate,
+				Field:       field + ".uri",
+			})
+		}
+		seenRegistries[r.URI] = true
+
+		if r.Username == "" || r.PasswordEnv == "" {
+			missingField := field + ".passwordEnv"
+			if r.Username == "" {
+				missingField = field + ".username"
+			}
+
+			msg := fmt.Sprintf("Registry credentials for '%s' must define both 'username' and 'passwordEnv'.", r.URI)
+			failures = append(failures, FailedValidation{
+				UserMessage: msg,
+				Code:        CodeRequired,
+				Field:       missingField,
+			})
+			continue
+		}
+
+		if !envVarRegex.MatchString(r.PasswordEnv) {
+			msg := fmt.Sprintf("The 'passwordEnv' field in the registry credentials for '%s' must be the name of an environment variable; "+
+				"secrets must not be stored in the definition.", r.URI)
+			failures = append(failures, FailedValidation{
+				UserMessage: msg,
+				Code:        CodeInvalid,
+				Field:       field + ".passwordEnv",
+			})
+		} else if os.Getenv(r.PasswordEnv) == "" {
+			msg := fmt.Sprintf("The environment variable '%s' referenced in the registry credentials for '%s' is not set.", r.PasswordEnv, r.URI)
+			failures = append(failures, FailedValidation{
+				UserMessage: msg,
+				Code:        CodeNotFound,
+				Field:       field + ".passwordEnv",
+			})
+		}
+	}
+
+	return failures
+}
+
+// validateManifestImageTags warns about container images which are referenced by the local Kubernetes manifests
+// with a different tag than the one listed in the 'images' section, as both tags end up in the registry.
+func validateManifestImageTags(ctx *image.Context) []FailedValidation {
+	var failures []FailedValidation
+
+	ear := &ctx.ImageDefinition.EmbeddedArtifactRegistry
+	if len(ear.ContainerImages) == 0 {
+		return failures
+	}
+
+	manifestsDir := combustion.KubernetesManifestsPath(ctx)
+	if _, err := os.Stat(manifestsDir); err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			zap.S().Warnf("Reading the manifests directory failed: %s", err)
+		}
+		return failures
+	}
+
+	manifestImages, err := registry.ManifestImages(context.Background(), ctx.HTTPClient, &image.Manifests{}, manifestsDir, 0)
+	if err != nil {
+		zap.S().Warnf("Parsing the container images of the local manifests failed: %s", err)
+		return failures
+	}
+	slices.Sort(manifestImages)
+
+	embeddedImages := map[string][]string{}
+	embeddedReferences := map[string][]string{}
+	for _, cImage := range ear.ContainerImages {
+		repository, reference := registry.NormalizeImageName(cImage.Name)
+		embeddedImages[repository] = append(embeddedImages[repository], cImage.Name)
+		embeddedReferences[repository] = append(embeddedReferences[repository], reference)
+	}
+
+	for _, manifestImage := range manifestImages {
+		repository, reference := registry.NormalizeImageName(manifestImage)
+
+		names, found := embeddedImages[repository]
+		if !found || slices.Contains(embeddedReferences[repository], reference) {
+			continue
+		}
+
+		for _, name := range names {
+			msg := fmt.Sprintf("Container image '%s' referenced by a Kubernetes manifest conflicts with '%s' in the 'images' section. "+
+				"Both tags will be stored in the embedded artifact registry.", manifestImage, name)
+			failures = append(failures, FailedValidation{
+				UserMessage: msg,
+				Severity:    SeverityWarning,
+				Code:        CodeConflict,
+				Field:       "embeddedArtifactRegistry.images",
+			})
+		}
+	}
+
+	return failures
+}
+
+// validateRegistryReferences warns about registry credentials for hosts which none of the container images
+// in the 'images' section or the local Kubernetes manifests is pulled from, as these are usually caused by a typo.
+// The images of Helm charts and remote manifests are only known during the build, so the check is skipped if any are configured.
+func validateRegistryReferences(ctx *image.Context) []FailedValidation {
+	var failures []FailedValidation
+
+	def := ctx.ImageDefinition
+	if len(def.EmbeddedArtifactRegistry.Registries) == 0 ||
+		len(def.Kubernetes.Helm.Charts) != 0 || len(def.Kubernetes.Manifests.URLs) != 0 {
+		return failures
+	}
+
+	var images []string
+	for _, cImage := range def.EmbeddedArtifactRegistry.ContainerImages {
+		images = append(images, cImage.Name)
+	}
+
+	manifestsDir := combustion.KubernetesManifestsPath(ctx)
+	if _, err := os.Stat(manifestsDir); err == nil {
+		var manifestImages []string
+		if manifestImages, err = registry.ManifestImages(context.Background(), ctx.HTTPClient, &image.Manifests{}, manifestsDir, 0); err != nil {
+			zap.S().Warnf("Parsing the container images of the local manifests failed: %s", err)
+			return failures
+		}
+		images = append(images, manifestImages...)
+	}
+
+	referencedHosts := map[string]bool{}
+	for _, img := range images {
+		repository, _ := registry.NormalizeImageName(img)
+		host, _, _ := strings.Cut(repository, "/")
+		referencedHosts[host] = true
+	}
+
+	for i, r := range def.EmbeddedArtifactRegistry.Registries {
+		if r.URI == "" || referencedHosts[r.URI] {
+			continue
+		}
+
+		msg := fmt.Sprintf("Registry credentials declared for '%s' but no image references that host.", r.URI)
+		failures = append(failures, FailedValidation{
+			UserMessage: msg,
+			Severity:    SeverityWarning,
+			Code:        CodeUnreferenced,
+			Field:       fmt.Sprintf("embeddedArtifactRegistry.registries[%d].uri", i),
+		})
+	}
+
+	return failures
+}
@@ -1,9 +1,12 @@
 package validation
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 )
 
@@ -22,6 +25,20 @@ func TestValidateEmbeddedArtifactRegistry(t *testing.T) {
 						Name: "foo",
 					},
 				},
+				SizeBudget: "10G",
+			},
+		},
+		`invalid size budget`: {
+			Registry: image.EmbeddedArtifactRegistry{
+				ContainerImages: []image.ContainerImage{
+					{
+						Name: "foo",
+					},
+				},
+				SizeBudget: "10K",
+			},
+			ExpectedFailedMessages: []string{
+				"The 'sizeBudget' field must be an integer followed by a suffix of either 'M', 'G', or 'T'.",
 			},
 		},
 		`image definition failure`: {
@@ -128,3 +145,264 @@ func TestValidateContainerImages(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateRegistries(t *testing.T) {
+	t.Setenv("PRIVATE_REGISTRY_PASSWORD", "s3cr3t")
+
+	tests := map[string]struct {
+		Registries             []image.Registry
+		ExpectedFailedMessages []string
+		ExpectedCodes          []failureCode
+	}{
+		`valid`: {
+			Registries: []image.Registry{
+				{
+					URI:         "private.example.com:5000",
+					Username:    "eib",
+					PasswordEnv: "PRIVATE_REGISTRY_PASSWORD",
+				},
+			},
+		},
+		`missing uri`: {
+			Registries: []image.Registry{
+				{
+					Username:    "eib",
+					PasswordEnv: "PRIVATE_REGISTRY_PASSWORD",
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'uri' field is required for each entry in 'registries'.",
+			},
+			ExpectedCodes: []failureCode{
+				{Code: CodeRequired, Field: "embeddedArtifactRegistry.registries[0].uri"},
+			},
+		},
+		`uri with scheme and duplicate`: {
+			Registries: []image.Registry{
+				{
+					URI:         "https://private.example.com",
+					Username:    "eib",
+					PasswordEnv: "PRIVATE_REGISTRY_PASSWORD",
+				},
+				{
+					URI:         "https://private.example.com",
+					Username:    "eib",
+					PasswordEnv: "PRIVATE_REGISTRY_PASSWORD",
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The registry URI 'https://private.example.com' must be a hostname, optionally followed by a port, without a scheme or path.",
+				"The registry URI 'https://private.example.com' must be a hostname, optionally followed by a port, without a scheme or path.",
+				"Duplicate registry URI 'https://private.example.com' found in the 'registries' section.",
+			},
+			ExpectedCodes: []failureCode{
+				{Code: CodeInvalid, Field: "embeddedArtifactRegistry.registries[0].uri"},
+				{Code: CodeInvalid, Field: "embeddedArtifactRegistry.registries[1].uri"},
+				{Code: CodeDuplicate, Field: "embeddedArtifactRegistry.registries[1].uri"},
+			},
+		},
+		`missing credentials`: {
+			Registries: []image.Registry{
+				{
+					URI:      "private.example.com",
+					Username: "eib",
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"Registry credentials for 'private.example.com' must define both 'username' and 'passwordEnv'.",
+			},
+			ExpectedCodes: []failureCode{
+				{Code: CodeRequired, Field: "embeddedArtifactRegistry.registries[0].passwordEnv"},
+			},
+		},
+		`invalid and unset password variables`: {
+			Registries: []image.Registry{
+				{
+					URI:         "private.example.com",
+					Username:    "eib",
+					PasswordEnv: "s3cr3t!",
+				},
+				{
+					URI:         "other.example.com",
+					Username:    "eib",
+					PasswordEnv: "EIB_UNSET_REGISTRY_PASSWORD",
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'passwordEnv' field in the registry credentials for 'private.example.com' must be the name of an environment variable; " +
+					"secrets must not be stored in the definition.",
+				"The environment variable 'EIB_UNSET_REGISTRY_PASSWORD' referenced in the registry credentials for 'other.example.com' is not set.",
+			},
+			ExpectedCodes: []failureCode{
+				{Code: CodeInvalid, Field: "embeddedArtifactRegistry.registries[0].passwordEnv"},
+				{Code: CodeNotFound, Field: "embeddedArtifactRegistry.registries[1].passwordEnv"},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ear := image.EmbeddedArtifactRegistry{
+				Registries: test.Registries,
+			}
+			failures := validateRegistries(&ear)
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+			assert.Equal(t, test.ExpectedCodes, failureCodes(failures))
+		})
+	}
+}
+
+func TestValidateManifestImageTags(t *testing.T) {
+	configDir := t.TempDir()
+	manifestsDir := filepath.Join(configDir, "kubernetes", "manifests")
+	require.NoError(t, os.MkdirAll(manifestsDir, 0o755))
+
+	manifest := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+spec:
+  template:
+    spec:
+      containers:
+        - name: nginx
+          image: docker.io/library/nginx:1.26
+        - name: hello-world
+          image: hello-world:latest
+`
+	require.NoError(t, os.WriteFile(filepath.Join(manifestsDir, "deployment.yaml"), []byte(manifest), 0o600))
+
+	tests := map[string]struct {
+		ConfigDir              string
+		ContainerImages        []image.ContainerImage
+		ExpectedFailedMessages []string
+	}{
+		`no manifests`: {
+			ConfigDir: t.TempDir(),
+			ContainerImages: []image.ContainerImage{
+				{Name: "nginx:1.25"},
+			},
+		},
+		`matching tags`: {
+			ConfigDir: configDir,
+			ContainerImages: []image.ContainerImage{
+				{Name: "nginx:1.26"},
+				{Name: "docker.io/library/hello-world"},
+			},
+		},
+		`conflicting tag`: {
+			ConfigDir: configDir,
+			ContainerImages: []image.ContainerImage{
+				{Name: "nginx:1.25"},
+				{Name: "hello-world:latest"},
+			},
+			ExpectedFailedMessages: []string{
+				"Container image 'docker.io/library/nginx:1.26' referenced by a Kubernetes manifest conflicts with 'nginx:1.25' in the 'images' section. " +
+					"Both tags will be stored in the embedded artifact registry.",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := image.Context{
+				ImageConfigDir: test.ConfigDir,
+				ImageDefinition: &image.Definition{
+					EmbeddedArtifactRegistry: image.EmbeddedArtifactRegistry{
+						ContainerImages: test.ContainerImages,
+					},
+				},
+			}
+			failures := validateManifestImageTags(&ctx)
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+				assert.Equal(t, SeverityWarning, foundValidation.Severity)
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+		})
+	}
+}
+
+func TestValidateRegistryReferences(t *testing.T) {
+	configDir := t.TempDir()
+	manifestsDir := filepath.Join(configDir, "kubernetes", "manifests")
+	require.NoError(t, os.MkdirAll(manifestsDir, 0o755))
+
+	manifest := `apiVersion: v1
+kind: Pod
+metadata:
+  name: app
+spec:
+  containers:
+    - name: app
+      image: manifests.example.com/app:1.0
+`
+	require.NoError(t, os.WriteFile(filepath.Join(manifestsDir, "pod.yaml"), []byte(manifest), 0o600))
+
+	registries := []image.Registry{
+		{URI: "private.example.com"},
+		{URI: "manifests.example.com"},
+	}
+
+	tests := map[string]struct {
+		ContainerImages        []image.ContainerImage
+		Kubernetes             image.Kubernetes
+		ExpectedFailedMessages []string
+	}{
+		`all hosts referenced`: {
+			ContainerImages: []image.ContainerImage{
+				{Name: "private.example.com/app:1.0"},
+			},
+		},
+		`unreferenced registry`: {
+			ContainerImages: []image.ContainerImage{
+				{Name: "privat.example.com/app:1.0"},
+			},
+			ExpectedFailedMessages: []string{
+				"Registry credentials declared for 'private.example.com' but no image references that host.",
+			},
+		},
+		`helm charts configured`: {
+			Kubernetes: image.Kubernetes{
+				Helm: image.Helm{
+					Charts: []image.HelmChart{
+						{Name: "app"},
+					},
+				},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := image.Context{
+				ImageConfigDir: configDir,
+				ImageDefinition: &image.Definition{
+					EmbeddedArtifactRegistry: image.EmbeddedArtifactRegistry{
+						ContainerImages: test.ContainerImages,
+						Registries:      registries,
+					},
+					Kubernetes: test.Kubernetes,
+				},
+			}
+			failures := validateRegistryReferences(&ctx)
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+				assert.Equal(t, SeverityWarning, foundValidation.Severity)
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package validation
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"go.uber.org/zap"
+)
+
+const (
+	requirementsComponent = "Requirements"
+)
+
+func validateRequirements(ctx *image.Context) []FailedValidation {
+	var failures []FailedValidation
+
+	requirements := ctx.ImageDefinition.Requirements
+
+	if requirements.MinMemory != "" && !requirements.MinMemory.IsValid() {
+		failures = append(failures, FailedValidation{
+			UserMessage: "The 'minMemory' field must be an integer followed by a suffix of either 'M', 'G', or 'T'.",
+			Code:        CodeInvalid,
+			Field:       "requirements.minMemory",
+		})
+	}
+
+	if requirements.MinDisk == "" {
+		return failures
+	}
+
+	if !requirements.MinDisk.IsValid() {
+		failures = append(failures, FailedValidation{
+			UserMessage: "The 'minDisk' field must be an integer followed by a suffix of either 'M', 'G', or 'T'.",
+			Code:        CodeInvalid,
+			Field:       "requirements.minDisk",
+		})
+		return failures
+	}
+
+	if required := estimateRequiredDisk(ctx); requirements.MinDisk.ToMB() < required {
+		msg := fmt.Sprintf("The 'minDisk' field (%s) is smaller than the %d MB required by the image.", requirements.MinDisk, required)
+		failures = append(failures, FailedValidation{
+			UserMessage: msg,
+			Code:        CodeInsufficient,
+			Field:       "requirements.minDisk",
+		})
+	}
+
+	return failures
+}
+
+// estimateRequiredDisk returns the disk space in MB the deployed image occupies at the very least.
+// Raw images are expanded to the configured disk size, otherwise the size of the base image and
+// the budget of the embedded artifact registry are used as the estimate.
+func estimateRequiredDisk(ctx *image.Context) int64 {
+	def := ctx.ImageDefinition
+
+	if def.OperatingSystem.RawConfiguration.DiskSize.IsValid() {
+		return def.OperatingSystem.RawConfiguration.DiskSize.ToMB()
+	}
+
+	var required int64
+
+	baseImageFilename := filepath.Join(ctx.ImageConfigDir, "base-images", def.Image.BaseImage)
+	if info, err := os.Stat(baseImageFilename); err != nil {
+		zap.S().Warnf("Determining the size of base image '%s' failed: %s", def.Image.BaseImage, err)
+	} else {
+		required += info.Size() >> 20
+	}
+
+	if def.EmbeddedArtifactRegistry.SizeBudget.IsValid() {
+		required += def.EmbeddedArtifactRegistry.SizeBudget.ToMB()
+	}
+
+	return required
+}
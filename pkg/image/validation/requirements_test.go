@@ -0,0 +1,112 @@
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestValidateRequirements(t *testing.T) {
+	configDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(configDir, "base-images"), 0o755))
+
+	baseImage, err := os.Create(filepath.Join(configDir, "base-images", "base.iso"))
+	require.NoError(t, err)
+	require.NoError(t, baseImage.Truncate(2048<<20))
+	require.NoError(t, baseImage.Close())
+
+	tests := map[string]struct {
+		Definition             image.Definition
+		ExpectedFailedMessages []string
+		ExpectedCodes          []failureCode
+	}{
+		`not defined`: {},
+		`valid`: {
+			Definition: image.Definition{
+				Image: image.Image{
+					BaseImage: "base.iso",
+				},
+				Requirements: image.Requirements{
+					MinMemory: "8G",
+					MinDisk:   "64G",
+				},
+			},
+		},
+		`invalid sizes`: {
+			Definition: image.Definition{
+				Requirements: image.Requirements{
+					MinMemory: "eight",
+					MinDisk:   "64",
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'minMemory' field must be an integer followed by a suffix of either 'M', 'G', or 'T'.",
+				"The 'minDisk' field must be an integer followed by a suffix of either 'M', 'G', or 'T'.",
+			},
+			ExpectedCodes: []failureCode{
+				{Code: CodeInvalid, Field: "requirements.minMemory"},
+				{Code: CodeInvalid, Field: "requirements.minDisk"},
+			},
+		},
+		`smaller than raw disk size`: {
+			Definition: image.Definition{
+				OperatingSystem: image.OperatingSystem{
+					RawConfiguration: image.RawConfiguration{
+						DiskSize: "32G",
+					},
+				},
+				Requirements: image.Requirements{
+					MinDisk: "16G",
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'minDisk' field (16G) is smaller than the 32768 MB required by the image.",
+			},
+			ExpectedCodes: []failureCode{
+				{Code: CodeInsufficient, Field: "requirements.minDisk"},
+			},
+		},
+		`smaller than base image and registry budget`: {
+			Definition: image.Definition{
+				Image: image.Image{
+					BaseImage: "base.iso",
+				},
+				EmbeddedArtifactRegistry: image.EmbeddedArtifactRegistry{
+					SizeBudget: "2G",
+				},
+				Requirements: image.Requirements{
+					MinDisk: "3G",
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"The 'minDisk' field (3G) is smaller than the 4096 MB required by the image.",
+			},
+			ExpectedCodes: []failureCode{
+				{Code: CodeInsufficient, Field: "requirements.minDisk"},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			def := test.Definition
+			ctx := image.Context{
+				ImageConfigDir:  configDir,
+				ImageDefinition: &def,
+			}
+			failures := validateRequirements(&ctx)
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+			assert.Equal(t, test.ExpectedCodes, failureCodes(failures))
+		})
+	}
+}
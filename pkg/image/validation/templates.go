@@ -0,0 +1,38 @@
+package validation
+
+import (
+	"fmt"
+
+	"github.com/suse-edge/edge-image-builder/pkg/combustion"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+const (
+	templatesComponent = "Templates"
+)
+
+func validateTemplates(ctx *image.Context) []FailedValidation {
+	templateFailures, err := combustion.ValidateTemplates(ctx)
+	if err != nil {
+		return []FailedValidation{
+			{
+				UserMessage: "The combustion script templates could not be validated. See the logs for more information.",
+				Error:       err,
+				Code:        CodeUnreadable,
+			},
+		}
+	}
+
+	var failures []FailedValidation
+
+	for _, tf := range templateFailures {
+		msg := fmt.Sprintf("Generating the combustion script of the '%s' component failed. See the logs for more information.", tf.Component)
+		failures = append(failures, FailedValidation{
+			UserMessage: msg,
+			Error:       tf.Error,
+			Code:        CodeInvalid,
+		})
+	}
+
+	return failures
+}
@@ -0,0 +1,58 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestValidateTemplates(t *testing.T) {
+	tests := map[string]struct {
+		Definition             image.Definition
+		ExpectedFailedMessages []string
+	}{
+		`valid`: {
+			Definition: image.Definition{
+				OperatingSystem: image.OperatingSystem{
+					Hostname: "node1.suse.com",
+					Suma: image.Suma{
+						Host:  "suma.suse.com",
+						Proxy: "http://proxy.suse.com:3128",
+					},
+				},
+			},
+		},
+		`invalid suma proxy`: {
+			Definition: image.Definition{
+				OperatingSystem: image.OperatingSystem{
+					Suma: image.Suma{
+						Host:  "suma.suse.com",
+						Proxy: "http://proxy:port",
+					},
+				},
+			},
+			ExpectedFailedMessages: []string{
+				"Generating the combustion script of the 'suma' component failed. See the logs for more information.",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			def := test.Definition
+			ctx := image.Context{
+				ImageConfigDir:  t.TempDir(),
+				ImageDefinition: &def,
+			}
+			failures := validateTemplates(&ctx)
+
+			var foundMessages []string
+			for _, foundValidation := range failures {
+				foundMessages = append(foundMessages, foundValidation.UserMessage)
+			}
+
+			assert.Equal(t, test.ExpectedFailedMessages, foundMessages)
+		})
+	}
+}
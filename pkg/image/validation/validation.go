@@ -1,12 +1,47 @@
 package validation
 
 import (
+	"path/filepath"
+
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 )
 
+type Severity int
+
+const (
+	// SeverityError marks a failure which prevents the image from being built.
+	SeverityError Severity = iota
+	// SeverityWarning marks a potential misconfiguration which does not prevent the image from being built.
+	SeverityWarning
+)
+
+// Codes identify the kind of a failed check independently of its user message,
+// so that callers can inspect failures programmatically.
+const (
+	CodeUnsupported  = "unsupported"
+	CodeRequired     = "required"
+	CodeInvalid      = "invalid"
+	CodeNotFound     = "not-found"
+	CodeUnreadable   = "unreadable"
+	CodeDuplicate    = "duplicate"
+	CodeMismatch     = "mismatch"
+	CodeConflict     = "conflict"
+	CodeInsecure     = "insecure"
+	CodeInsufficient = "insufficient"
+	CodeUnreferenced = "unreferenced"
+)
+
 type FailedValidation struct {
 	UserMessage string
 	Error       error
+	Severity    Severity
+	// Code identifies the kind of the failed check (e.g. CodeRequired).
+	// Together with Field, it identifies the check independently of its user message.
+	Code string
+	// Field is the path of the offending definition field (e.g. "image.arch") or, for checks of the
+	// image configuration directory, the path relative to it (e.g. "firstBoot/scripts/10-setup.sh").
+	// It is empty for checks which do not concern the image definition or configuration directory.
+	Field string
 }
 
 type validateComponent func(ctx *image.Context) []FailedValidation
@@ -23,10 +58,16 @@ func ValidateDefinition(ctx *image.Context) map[string][]FailedValidation {
 	}
 
 	validations := map[string]validateComponent{
-		imageComponent:    validateImage,
-		osComponent:       validateOperatingSystem,
-		registryComponent: validateEmbeddedArtifactRegistry,
-		k8sComponent:      validateKubernetes,
+		imageComponent:        validateImage,
+		osComponent:           validateOperatingSystem,
+		registryComponent:     validateEmbeddedArtifactRegistry,
+		k8sComponent:          validateKubernetes,
+		elementalComponent:    validateElemental,
+		layoutComponent:       validateLayout,
+		buildComponent:        validateBuild,
+		requirementsComponent: validateRequirements,
+		templatesComponent:    validateTemplates,
+		firstBootComponent:    validateFirstBoot,
 	}
 	for componentName, v := range validations {
 		componentFailures := v(ctx)
@@ -52,3 +93,14 @@ func findDuplicates(items []string) []string {
 
 	return duplicates
 }
+
+// configRelativePath returns the slash separated path of the file relative to the image configuration directory,
+// as used in the Field of failures concerning files rather than definition fields.
+func configRelativePath(ctx *image.Context, file string) string {
+	rel, err := filepath.Rel(ctx.ImageConfigDir, file)
+	if err != nil {
+		return file
+	}
+
+	return filepath.ToSlash(rel)
+}
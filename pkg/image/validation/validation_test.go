@@ -1,8 +1,12 @@
 package validation
 
 import (
+	"go/ast"
+	"go/parser"
+	"go/token"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -88,6 +92,50 @@ func TestValidateDefinition(t *testing.T) {
 				},
 			},
 		},
+		`invalid arch`: {
+			Definition: image.Definition{
+				APIVersion: "1.0",
+				Image: image.Image{
+					ImageType:       "iso",
+					Arch:            "amd64",
+					BaseImage:       fakeBaseImageName,
+					OutputImageName: "output.iso",
+				},
+			},
+			Expected: map[string][]string{
+				imageComponent: {
+					"The 'arch' field must be one of: x86_64, aarch64.",
+				},
+			},
+		},
+		`mismatching image extensions`: {
+			Definition: image.Definition{
+				APIVersion: "1.0",
+				Image: image.Image{
+					ImageType:       "raw",
+					Arch:            image.ArchTypeX86,
+					BaseImage:       fakeBaseImageName,
+					OutputImageName: "output.iso",
+				},
+			},
+			Expected: map[string][]string{
+				imageComponent: {
+					"The 'baseImage' field 'fake-base.iso' has an extension indicating the 'iso' image type, which does not match the 'raw' 'imageType' field.",
+					"The 'outputImageName' field 'output.iso' has an extension indicating the 'iso' image type, which does not match the 'raw' 'imageType' field.",
+				},
+			},
+		},
+		`unknown output image extension`: {
+			Definition: image.Definition{
+				APIVersion: "1.0",
+				Image: image.Image{
+					ImageType:       "iso",
+					Arch:            image.ArchTypeX86,
+					BaseImage:       fakeBaseImageName,
+					OutputImageName: "output",
+				},
+			},
+		},
 	}
 
 	for name, test := range tests {
@@ -106,6 +154,7 @@ func TestValidateDefinition(t *testing.T) {
 				var foundMessages []string
 				for _, foundValidation := range foundComponentFailures {
 					foundMessages = append(foundMessages, foundValidation.UserMessage)
+					assert.NotEmpty(t, foundValidation.Code, foundValidation.UserMessage)
 				}
 
 				for _, expectedMessage := range test.Expected[foundComponent] {
@@ -115,3 +164,81 @@ func TestValidateDefinition(t *testing.T) {
 		})
 	}
 }
+
+// failureCode pairs the code and field of a failed validation, so that tests can assert them together.
+type failureCode struct {
+	Code  string
+	Field string
+}
+
+func failureCodes(failures []FailedValidation) []failureCode {
+	var codes []failureCode
+	for _, failure := range failures {
+		codes = append(codes, failureCode{Code: failure.Code, Field: failure.Field})
+	}
+
+	return codes
+}
+
+// TestFailedValidationCodes ensures that every check of the package assigns a code to its failures.
+func TestFailedValidationCodes(t *testing.T) {
+	sources, err := filepath.Glob("*.go")
+	require.NoError(t, err)
+
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, source := range sources {
+		if strings.HasSuffix(source, "_test.go") {
+			continue
+		}
+
+		file, parseErr := parser.ParseFile(fset, source, nil, 0)
+		require.NoError(t, parseErr)
+		files = append(files, file)
+	}
+
+	isFailedValidation := func(expr ast.Expr) bool {
+		ident, ok := expr.(*ast.Ident)
+		return ok && ident.Name == "FailedValidation"
+	}
+
+	hasCode := func(lit *ast.CompositeLit) bool {
+		for _, elt := range lit.Elts {
+			if kv, ok := elt.(*ast.KeyValueExpr); ok {
+				if key, ok := kv.Key.(*ast.Ident); ok && key.Name == "Code" {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	for _, file := range files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			lit, ok := node.(*ast.CompositeLit)
+			if !ok {
+				return true
+			}
+
+			var literals []*ast.CompositeLit
+			switch {
+			case isFailedValidation(lit.Type):
+				literals = append(literals, lit)
+			case lit.Type != nil:
+				if array, isArray := lit.Type.(*ast.ArrayType); isArray && isFailedValidation(array.Elt) {
+					for _, elt := range lit.Elts {
+						if element, isLiteral := elt.(*ast.CompositeLit); isLiteral && element.Type == nil {
+							literals = append(literals, element)
+						}
+					}
+				}
+			}
+
+			for _, l := range literals {
+				assert.True(t, hasCode(l), "failed validation without a code at %s", fset.Position(l.Pos()))
+			}
+
+			return true
+		})
+	}
+}
@@ -41,9 +41,13 @@ type cache interface {
 
 type ArtefactDownloader struct {
 	Cache cache
+	// ReleaseURL optionally overrides the upstream location of the release artefacts (e.g. with a mirror).
+	// Artefacts are expected under '<ReleaseURL>/<version>/<artefact>', following the layout of the upstream releases.
+	ReleaseURL string
+	Client     *http.Client
 }
 
-func (d ArtefactDownloader) DownloadRKE2Artefacts(arch image.Arch, version, cni string, multusEnabled bool, installPath, imagesPath string) error {
+func (d ArtefactDownloader) DownloadRKE2Artefacts(ctx context.Context, arch image.Arch, version, cni string, multusEnabled bool, installPath, imagesPath string) error {
 	if !strings.Contains(version, image.KubernetesDistroRKE2) {
 		return fmt.Errorf("invalid RKE2 version: '%s'", version)
 	}
@@ -57,12 +61,12 @@ func (d ArtefactDownloader) DownloadRKE2Artefacts(arch image.Arch, version, cni
 		return fmt.Errorf("gathering RKE2 image artefacts: %w", err)
 	}
 
-	if err = d.downloadArtefacts(artefacts, rke2ReleaseURL, version, imagesPath); err != nil {
+	if err = d.downloadArtefacts(ctx, artefacts, d.releaseURL(rke2ReleaseURL), version, imagesPath); err != nil {
 		return fmt.Errorf("downloading RKE2 image artefacts: %w", err)
 	}
 
 	artefacts = rke2InstallerArtefacts(arch)
-	if err = d.downloadArtefacts(artefacts, rke2ReleaseURL, version, installPath); err != nil {
+	if err = d.downloadArtefacts(ctx, artefacts, d.releaseURL(rke2ReleaseURL), version, installPath); err != nil {
 		return fmt.Errorf("downloading RKE2 install artefacts: %w", err)
 	}
 
@@ -79,7 +83,10 @@ func rke2InstallerArtefacts(arch image.Arch) []string {
 }
 
 func rke2ImageArtefacts(cni string, multusEnabled bool, arch image.Arch) ([]string, error) {
-	artefactArch := arch.Short()
+	artefactArch, err := arch.ShortName()
+	if err != nil {
+		return nil, err
+	}
 
 	var artefacts []string
 
@@ -115,18 +122,18 @@ func rke2ImageArtefacts(cni string, multusEnabled bool, arch image.Arch) ([]stri
 	return artefacts, nil
 }
 
-func (d ArtefactDownloader) DownloadK3sArtefacts(arch image.Arch, version, installPath, imagesPath string) error {
+func (d ArtefactDownloader) DownloadK3sArtefacts(ctx context.Context, arch image.Arch, version, installPath, imagesPath string) error {
 	if !strings.Contains(version, image.KubernetesDistroK3S) {
 		return fmt.Errorf("invalid k3s version: '%s'", version)
 	}
 
 	artefacts := k3sImageArtefacts(arch)
-	if err := d.downloadArtefacts(artefacts, k3sReleaseURL, version, imagesPath); err != nil {
+	if err := d.downloadArtefacts(ctx, artefacts, d.releaseURL(k3sReleaseURL), version, imagesPath); err != nil {
 		return fmt.Errorf("downloading k3s image artefacts: %w", err)
 	}
 
 	artefacts = k3sInstallerArtefacts(arch)
-	if err := d.downloadArtefacts(artefacts, k3sReleaseURL, version, installPath); err != nil {
+	if err := d.downloadArtefacts(ctx, artefacts, d.releaseURL(k3sReleaseURL), version, installPath); err != nil {
 		return fmt.Errorf("downloading k3s install artefacts: %w", err)
 	}
 
@@ -154,7 +161,17 @@ func k3sImageArtefacts(arch image.Arch) []string {
 	}
 }
 
-func (d ArtefactDownloader) downloadArtefacts(artefacts []string, releaseURL, version, destinationPath string) error {
+// releaseURL returns the format of the artefact URLs, preferring the configured override over the upstream default.
+func (d ArtefactDownloader) releaseURL(defaultURL string) string {
+	if d.ReleaseURL == "" {
+		return defaultURL
+	}
+
+	baseURL := strings.ReplaceAll(strings.TrimSuffix(d.ReleaseURL, "/"), "%", "%%")
+	return baseURL + "/%s/%s"
+}
+
+func (d ArtefactDownloader) downloadArtefacts(ctx context.Context, artefacts []string, releaseURL, version, destinationPath string) error {
 	for _, artefact := range artefacts {
 		url := fmt.Sprintf(releaseURL, version, artefact)
 		path := filepath.Join(destinationPath, artefact)
@@ -166,7 +183,12 @@ func (d ArtefactDownloader) downloadArtefacts(artefacts []string, releaseURL, ve
 		}
 
 		if !copied {
-			if err = d.downloadArtefact(url, path, cacheKey); err != nil {
+			// Fail before the download is streamed to the cache so that no empty entries are stored
+			if err = d.Client.CheckOnline(url); err != nil {
+				return fmt.Errorf("downloading artefact '%s': %w", artefact, err)
+			}
+
+			if err = d.downloadArtefact(ctx, url, path, cacheKey); err != nil {
 				return fmt.Errorf("downloading artefact '%s': %w", artefact, err)
 			}
 		}
@@ -194,10 +216,10 @@ func (d ArtefactDownloader) copyArtefactFromCache(cacheKey, destPath string) (bo
 	return true, nil
 }
 
-func (d ArtefactDownloader) downloadArtefact(url, path, cacheKey string) error {
+func (d ArtefactDownloader) downloadArtefact(ctx context.Context, url, path, cacheKey string) error {
 	reader, writer := io.Pipe()
 
-	errGroup, ctx := errgroup.WithContext(context.Background())
+	errGroup, ctx := errgroup.WithContext(ctx)
 
 	errGroup.Go(func() error {
 		defer func() {
@@ -206,7 +228,7 @@ func (d ArtefactDownloader) downloadArtefact(url, path, cacheKey string) error {
 			}
 		}()
 
-		if err := http.DownloadFile(ctx, url, path, writer); err != nil {
+		if err := d.Client.DownloadFile(ctx, url, path, nil, writer); err != nil {
 			return fmt.Errorf("downloading artefact: %w", err)
 		}
 		return nil
@@ -1,10 +1,12 @@
 package kubernetes
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/http"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 )
 
@@ -150,3 +152,54 @@ func TestK3sImageArtefacts(t *testing.T) {
 	armArtefacts := []string{"k3s-airgap-images-arm64.tar.zst"}
 	assert.Equal(t, armArtefacts, k3sImageArtefacts(image.ArchTypeARM))
 }
+
+func TestReleaseURL(t *testing.T) {
+	tests := map[string]struct {
+		releaseURL  string
+		expectedURL string
+	}{
+		`default`: {
+			expectedURL: rke2ReleaseURL,
+		},
+		`override`: {
+			releaseURL:  "https://mirror.example.com/rke2/releases",
+			expectedURL: "https://mirror.example.com/rke2/releases/%s/%s",
+		},
+		`override with trailing slash`: {
+			releaseURL:  "https://mirror.example.com/rke2/",
+			expectedURL: "https://mirror.example.com/rke2/%s/%s",
+		},
+		`override with escaped characters`: {
+			releaseURL:  "https://mirror.example.com/rke2%20releases",
+			expectedURL: "https://mirror.example.com/rke2%%20releases/%s/%s",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			downloader := ArtefactDownloader{
+				ReleaseURL: test.releaseURL,
+			}
+
+			assert.Equal(t, test.expectedURL, downloader.releaseURL(rke2ReleaseURL))
+		})
+	}
+}
+
+func TestDownloadK3sArtefacts_ReleaseURLOverride(t *testing.T) {
+	// Setup
+	downloader := ArtefactDownloader{
+		Cache:      mockCache{},
+		ReleaseURL: "https://mirror.example.com/k3s",
+		Client:     offlineClient(t),
+	}
+
+	// Offline mode reports the URL which would have been downloaded without accessing the network
+	// Test
+	err := downloader.DownloadK3sArtefacts(context.Background(), image.ArchTypeX86, "v1.30.3+k3s1", t.TempDir(), t.TempDir())
+
+	// Verify
+	require.ErrorIs(t, err, http.ErrOffline)
+	assert.ErrorContains(t, err,
+		"'https://mirror.example.com/k3s/v1.30.3+k3s1/k3s-airgap-images-amd64.tar.zst' is not available locally")
+}
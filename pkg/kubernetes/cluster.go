@@ -6,6 +6,7 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/google/uuid"
@@ -27,6 +28,7 @@ const (
 	disableKey      = "disable"
 	clusterInitKey  = "cluster-init"
 	selinuxKey      = "selinux"
+	nodeIPKey       = "node-ip"
 )
 
 type Cluster struct {
@@ -147,6 +149,7 @@ func setSingleNodeConfigDefaults(kubernetes *image.Kubernetes, config map[string
 	if kubernetes.Network.APIHost != "" {
 		appendClusterTLSSAN(config, kubernetes.Network.APIHost)
 	}
+	appendAdditionalTLSSANs(config, kubernetes.Network.AdditionalSANs)
 	delete(config, serverKey)
 }
 
@@ -170,6 +173,7 @@ func setMultiNodeConfigDefaults(kubernetes *image.Kubernetes, config map[string]
 	if kubernetes.Network.APIHost != "" {
 		appendClusterTLSSAN(config, kubernetes.Network.APIHost)
 	}
+	appendAdditionalTLSSANs(config, kubernetes.Network.AdditionalSANs)
 }
 
 func setClusterToken(config map[string]any) {
@@ -245,6 +249,51 @@ func appendClusterTLSSAN(config map[string]any, address string) {
 	}
 }
 
+// appendAdditionalTLSSANs appends the given addresses to the 'tls-san' list,
+// skipping the ones which are already present (e.g. the apiVIP and apiHost).
+func appendAdditionalTLSSANs(config map[string]any, addresses []string) {
+	for _, address := range addresses {
+		if slices.Contains(TLSSANs(config), address) {
+			continue
+		}
+
+		appendClusterTLSSAN(config, address)
+	}
+}
+
+// TLSSANs returns the 'tls-san' entries of the given Kubernetes config.
+// Both the comma-separated and the list representations are supported.
+func TLSSANs(config map[string]any) []string {
+	return listValues(config, tlsSANKey)
+}
+
+// NodeIPs returns the 'node-ip' addresses of the given Kubernetes config.
+// Both the comma-separated (e.g. dual-stack) and the list representations are supported.
+func NodeIPs(config map[string]any) []string {
+	return listValues(config, nodeIPKey)
+}
+
+func listValues(config map[string]any, key string) []string {
+	var values []string
+
+	switch v := config[key].(type) {
+	case string:
+		for _, value := range strings.Split(v, ",") {
+			values = append(values, strings.TrimSpace(value))
+		}
+	case []string:
+		values = append(values, v...)
+	case []any:
+		for _, value := range v {
+			if s, ok := value.(string); ok {
+				values = append(values, s)
+			}
+		}
+	}
+
+	return values
+}
+
 func appendDisabledServices(config map[string]any, service string) {
 	if service == "" {
 		zap.S().Warn("Attempted to disable an empty service")
@@ -82,6 +82,23 @@ func TestNewCluster_SingleNode_ExistingConfig(t *testing.T) {
 	assert.Nil(t, cluster.AgentConfig)
 }
 
+func TestNewCluster_SingleNode_AdditionalSANs(t *testing.T) {
+	kubernetes := &image.Kubernetes{
+		Version: "v1.29.0+rke2r1",
+		Network: image.Network{
+			APIHost:        "api.suse.edge.com",
+			APIVIP:         "192.168.122.50",
+			AdditionalSANs: []string{"api.suse.edge.com", "192.168.122.51", "rancher.suse.edge.com", "192.168.122.51"},
+		},
+	}
+
+	cluster, err := NewCluster(kubernetes, "")
+	require.NoError(t, err)
+
+	require.NotNil(t, cluster.ServerConfig)
+	assert.Equal(t, []string{"192.168.122.50", "api.suse.edge.com", "192.168.122.51", "rancher.suse.edge.com"}, cluster.ServerConfig["tls-san"])
+}
+
 func TestNewCluster_MultiNodeRKE2_MissingConfig(t *testing.T) {
 	kubernetes := &image.Kubernetes{
 		Version: "v1.29.0+rke2r1",
@@ -344,6 +361,50 @@ func TestAppendClusterTLSSAN(t *testing.T) {
 	}
 }
 
+func TestAppendAdditionalTLSSANs(t *testing.T) {
+	tests := []struct {
+		name           string
+		config         map[string]any
+		addresses      []string
+		expectedTLSSAN any
+	}{
+		{
+			name:           "No addresses",
+			config:         map[string]any{},
+			expectedTLSSAN: nil,
+		},
+		{
+			name:           "Missing TLS SAN",
+			config:         map[string]any{},
+			addresses:      []string{"api.edge1.com", "192.168.122.50"},
+			expectedTLSSAN: []string{"api.edge1.com", "192.168.122.50"},
+		},
+		{
+			name: "Existing TLS SAN string",
+			config: map[string]any{
+				"tls-san": "api.edge1.com, api.edge2.com",
+			},
+			addresses:      []string{"api.edge2.com", "api.edge3.com"},
+			expectedTLSSAN: []string{"api.edge1.com", "api.edge2.com", "api.edge3.com"},
+		},
+		{
+			name: "Existing TLS SAN list",
+			config: map[string]any{
+				"tls-san": []any{"api.edge1.com", "api.edge2.com"},
+			},
+			addresses:      []string{"api.edge1.com", "api.edge3.com", "api.edge3.com"},
+			expectedTLSSAN: []any{"api.edge1.com", "api.edge2.com", "api.edge3.com"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			appendAdditionalTLSSANs(test.config, test.addresses)
+			assert.Equal(t, test.expectedTLSSAN, test.config["tls-san"])
+		})
+	}
+}
+
 func TestAppendClusterDisabledServices(t *testing.T) {
 	tests := []struct {
 		name             string
@@ -424,3 +485,92 @@ func TestServersCount(t *testing.T) {
 	assert.Equal(t, 2, ServersCount(nodes))
 	assert.Equal(t, 0, ServersCount([]image.Node{}))
 }
+
+func TestTLSSANs(t *testing.T) {
+	tests := []struct {
+		name            string
+		config          map[string]any
+		expectedTLSSANs []string
+	}{
+		{
+			name:            "Missing TLS SAN",
+			config:          map[string]any{},
+			expectedTLSSANs: nil,
+		},
+		{
+			name: "Invalid TLS SAN",
+			config: map[string]any{
+				"tls-san": 5,
+			},
+			expectedTLSSANs: nil,
+		},
+		{
+			name: "TLS SAN string",
+			config: map[string]any{
+				"tls-san": "api.edge1.com, api.edge2.com",
+			},
+			expectedTLSSANs: []string{"api.edge1.com", "api.edge2.com"},
+		},
+		{
+			name: "TLS SAN string list",
+			config: map[string]any{
+				"tls-san": []string{"api.edge1.com", "api.edge2.com"},
+			},
+			expectedTLSSANs: []string{"api.edge1.com", "api.edge2.com"},
+		},
+		{
+			name: "TLS SAN list",
+			config: map[string]any{
+				"tls-san": []any{"api.edge1.com", "api.edge2.com"},
+			},
+			expectedTLSSANs: []string{"api.edge1.com", "api.edge2.com"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expectedTLSSANs, TLSSANs(test.config))
+		})
+	}
+}
+
+func TestNodeIPs(t *testing.T) {
+	tests := []struct {
+		name            string
+		config          map[string]any
+		expectedNodeIPs []string
+	}{
+		{
+			name:            "Missing node IP",
+			config:          map[string]any{},
+			expectedNodeIPs: nil,
+		},
+		{
+			name: "Single node IP",
+			config: map[string]any{
+				"node-ip": "192.168.122.50",
+			},
+			expectedNodeIPs: []string{"192.168.122.50"},
+		},
+		{
+			name: "Dual-stack node IPs",
+			config: map[string]any{
+				"node-ip": "192.168.122.50, fd00::50",
+			},
+			expectedNodeIPs: []string{"192.168.122.50", "fd00::50"},
+		},
+		{
+			name: "Node IP list",
+			config: map[string]any{
+				"node-ip": []any{"192.168.122.50", "fd00::50"},
+			},
+			expectedNodeIPs: []string{"192.168.122.50", "fd00::50"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expectedNodeIPs, NodeIPs(test.config))
+		})
+	}
+}
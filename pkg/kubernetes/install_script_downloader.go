@@ -16,9 +16,11 @@ const (
 	k3sInstallScriptURL  = "https://get.k3s.io"
 )
 
-type ScriptDownloader struct{}
+type ScriptDownloader struct {
+	Client *http.Client
+}
 
-func (d ScriptDownloader) DownloadInstallScript(distribution, destinationPath string) (string, error) {
+func (d ScriptDownloader) DownloadInstallScript(ctx context.Context, distribution, destinationPath string) (string, error) {
 	var scriptURL string
 
 	switch distribution {
@@ -33,7 +35,7 @@ func (d ScriptDownloader) DownloadInstallScript(distribution, destinationPath st
 	installer := fmt.Sprintf("%s_installer.sh", distribution)
 	destinationPath = filepath.Join(destinationPath, installer)
 
-	if err := http.DownloadFile(context.Background(), scriptURL, destinationPath, nil); err != nil {
+	if err := d.Client.DownloadFile(ctx, scriptURL, destinationPath, nil, nil); err != nil {
 		return "", fmt.Errorf("downloading script: %w", err)
 	}
 
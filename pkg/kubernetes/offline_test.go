@@ -0,0 +1,105 @@
+package kubernetes
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/http"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+type mockCache struct {
+	artefacts map[string]string
+}
+
+func (m mockCache) Get(artefact string) (string, error) {
+	path, ok := m.artefacts[artefact]
+	if !ok {
+		return "", fs.ErrNotExist
+	}
+
+	return path, nil
+}
+
+func (m mockCache) Put(string, io.Reader) error {
+	panic("not implemented")
+}
+
+func offlineClient(t *testing.T) *http.Client {
+	client, err := http.NewClient(http.ClientConfig{Offline: true})
+	require.NoError(t, err)
+
+	return client
+}
+
+func TestDownloadInstallScript_Offline(t *testing.T) {
+	_, err := ScriptDownloader{Client: offlineClient(t)}.DownloadInstallScript(context.Background(), image.KubernetesDistroRKE2, t.TempDir())
+	require.ErrorIs(t, err, http.ErrOffline)
+	assert.ErrorContains(t, err, "'https://get.rke2.io' is not available locally")
+}
+
+func TestStoreSELinuxRPMsSigningKey_Offline(t *testing.T) {
+	err := StoreSELinuxRPMsSigningKey(context.Background(), offlineClient(t), "", t.TempDir())
+	require.ErrorIs(t, err, http.ErrOffline)
+	assert.ErrorContains(t, err, "'https://rpm.rancher.io/public.key' is not available locally")
+}
+
+func TestStoreSELinuxRPMsSigningKey_OfflineLocalKey(t *testing.T) {
+	// Setup
+	localKey := filepath.Join(t.TempDir(), "signing.key")
+	require.NoError(t, os.WriteFile(localKey, []byte("local key"), 0o600))
+
+	gpgKeysDir := t.TempDir()
+
+	// Test
+	err := StoreSELinuxRPMsSigningKey(context.Background(), offlineClient(t), localKey, gpgKeysDir)
+
+	// Verify
+	require.NoError(t, err)
+
+	contents, err := os.ReadFile(filepath.Join(gpgKeysDir, "rancher-public.key"))
+	require.NoError(t, err)
+	assert.Equal(t, "local key", string(contents))
+}
+
+func TestStoreSELinuxRPMsSigningKey_OfflineCustomURL(t *testing.T) {
+	err := StoreSELinuxRPMsSigningKey(context.Background(), offlineClient(t), "https://mirror.example.com/rancher.key", t.TempDir())
+	require.ErrorIs(t, err, http.ErrOffline)
+	assert.ErrorContains(t, err, "'https://mirror.example.com/rancher.key' is not available locally")
+}
+
+func TestDownloadArtefacts_Offline(t *testing.T) {
+	// Setup
+	cachedArtefact := filepath.Join(t.TempDir(), "cached")
+	require.NoError(t, os.WriteFile(cachedArtefact, []byte("cached"), 0o600))
+
+	downloader := ArtefactDownloader{
+		Cache: mockCache{
+			artefacts: map[string]string{
+				"v1.30.3+k3s1/k3s": cachedArtefact,
+			},
+		},
+		Client: offlineClient(t),
+	}
+
+	destDir := t.TempDir()
+	releaseURL := "https://github.com/k3s-io/k3s/releases/download/%s/%s"
+
+	// Test
+	errCached := downloader.downloadArtefacts(context.Background(), []string{"k3s"}, releaseURL, "v1.30.3+k3s1", destDir)
+	errMissing := downloader.downloadArtefacts(context.Background(), []string{"sha256sum-amd64.txt"}, releaseURL, "v1.30.3+k3s1", destDir)
+
+	// Verify
+	require.NoError(t, errCached)
+	assert.FileExists(t, filepath.Join(destDir, "k3s"))
+
+	require.ErrorIs(t, errMissing, http.ErrOffline)
+	assert.ErrorContains(t, errMissing,
+		"'https://github.com/k3s-io/k3s/releases/download/v1.30.3+k3s1/sha256sum-amd64.txt' is not available locally")
+}
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/suse-edge/edge-image-builder/pkg/fileio"
 	"github.com/suse-edge/edge-image-builder/pkg/http"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 )
@@ -49,9 +50,30 @@ func SELinuxRepository(version string) (image.AddRepo, error) {
 	}, nil
 }
 
-func DownloadSELinuxRPMsSigningKey(gpgKeysDir string) error {
+// IsRemoteSELinuxSigningKey reports whether the SELinux RPMs signing key is downloaded rather than provided locally.
+// An empty source refers to the default Rancher signing key.
+func IsRemoteSELinuxSigningKey(source string) bool {
+	return source == "" || strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
+
+// StoreSELinuxRPMsSigningKey places the key the SELinux RPMs are signed with in the GPG keys directory.
+// The source is either a URL to download the key from or the path to a local key file.
+// The Rancher signing key is downloaded if the source is empty.
+func StoreSELinuxRPMsSigningKey(ctx context.Context, client *http.Client, source, gpgKeysDir string) error {
 	const rancherSigningKeyURL = "https://rpm.rancher.io/public.key"
 	var signingKeyPath = filepath.Join(gpgKeysDir, "rancher-public.key")
 
-	return http.DownloadFile(context.Background(), rancherSigningKeyURL, signingKeyPath, nil)
+	if !IsRemoteSELinuxSigningKey(source) {
+		if err := fileio.CopyFile(source, signingKeyPath, fileio.NonExecutablePerms); err != nil {
+			return fmt.Errorf("copying local signing key: %w", err)
+		}
+
+		return nil
+	}
+
+	if source == "" {
+		source = rancherSigningKeyURL
+	}
+
+	return client.DownloadFile(ctx, source, signingKeyPath, nil, nil)
 }
@@ -0,0 +1,10 @@
+package kubernetes
+
+import "strings"
+
+// UpstreamVersion returns the upstream Kubernetes version (e.g. 'v1.30.3') of the given
+// k3s or RKE2 release version (e.g. 'v1.30.3+k3s1'), stripping its build metadata.
+func UpstreamVersion(version string) string {
+	upstreamVersion, _, _ := strings.Cut(version, "+")
+	return upstreamVersion
+}
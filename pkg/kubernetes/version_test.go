@@ -0,0 +1,38 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpstreamVersion(t *testing.T) {
+	tests := map[string]struct {
+		version  string
+		expected string
+	}{
+		`k3s`: {
+			version:  "v1.30.3+k3s1",
+			expected: "v1.30.3",
+		},
+		`rke2`: {
+			version:  "v1.29.0+rke2r1",
+			expected: "v1.29.0",
+		},
+		`rke2 multi-digit revision`: {
+			version:  "v1.28.12+rke2r12",
+			expected: "v1.28.12",
+		},
+		`without build metadata`: {
+			version:  "v1.30.3",
+			expected: "v1.30.3",
+		},
+		`empty`: {},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, UpstreamVersion(test.version))
+		})
+	}
+}
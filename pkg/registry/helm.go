@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/suse-edge/edge-image-builder/pkg/image"
+	"gopkg.in/yaml.v3"
 )
 
 type HelmChart struct {
@@ -37,16 +38,47 @@ func HelmCharts(helm *image.Helm, valuesDir, buildDir, kubeVersion string, helmC
 	return charts, nil
 }
 
+// RenderHelmChart downloads and templates the given chart without collecting its contents,
+// surfacing any rendering failures (e.g. invalid values) ahead of the build.
+func RenderHelmChart(chart *image.HelmChart, repo *image.HelmRepository, valuesDir, destDir, kubeVersion string, helmClient image.HelmClient) error {
+	chartPath, err := downloadChart(chart, repo, helmClient, destDir)
+	if err != nil {
+		return fmt.Errorf("downloading chart: %w", err)
+	}
+
+	_, err = helmClient.Template(chart.Name, chartPath, chart.Version, valuesFilePaths(chart, valuesDir), kubeVersion, chart.TargetNamespace, chart.IncludeCRDs)
+	if err != nil {
+		return fmt.Errorf("templating chart: %w", err)
+	}
+
+	return nil
+}
+
+func valuesFilePaths(chart *image.HelmChart, valuesDir string) []string {
+	var valuesPaths []string
+	for _, valuesFile := range chart.AllValuesFiles() {
+		valuesPaths = append(valuesPaths, filepath.Join(valuesDir, valuesFile))
+	}
+
+	return valuesPaths
+}
+
 func handleChart(chart *image.HelmChart, repo *image.HelmRepository, valuesDir, buildDir, kubeVersion string, helmClient image.HelmClient) (*HelmChart, error) {
-	var valuesPath string
-	var valuesContent []byte
-	if chart.ValuesFile != "" {
-		var err error
-		valuesPath = filepath.Join(valuesDir, chart.ValuesFile)
-		valuesContent, err = os.ReadFile(valuesPath)
+	valuesPaths := valuesFilePaths(chart, valuesDir)
+
+	var valuesContents [][]byte
+	for _, valuesPath := range valuesPaths {
+		valuesContent, err := os.ReadFile(valuesPath)
 		if err != nil {
 			return nil, fmt.Errorf("reading values content: %w", err)
 		}
+
+		valuesContents = append(valuesContents, valuesContent)
+	}
+
+	values, err := mergeValues(valuesContents)
+	if err != nil {
+		return nil, fmt.Errorf("merging values content: %w", err)
 	}
 
 	chartPath, err := downloadChart(chart, repo, helmClient, buildDir)
@@ -54,7 +86,7 @@ func handleChart(chart *image.HelmChart, repo *image.HelmRepository, valuesDir,
 		return nil, fmt.Errorf("downloading chart: %w", err)
 	}
 
-	images, err := getChartContainerImages(chart, helmClient, chartPath, valuesPath, kubeVersion)
+	images, err := getChartContainerImages(chart, helmClient, chartPath, valuesPaths, kubeVersion)
 	if err != nil {
 		return nil, fmt.Errorf("getting chart container images: %w", err)
 	}
@@ -65,13 +97,55 @@ func handleChart(chart *image.HelmChart, repo *image.HelmRepository, valuesDir,
 	}
 
 	helmChart := HelmChart{
-		CRD:             NewHelmCRD(chart, chartContent, string(valuesContent), repo.URL),
+		CRD:             NewHelmCRD(chart, chartContent, values, repo.URL),
 		ContainerImages: images,
 	}
 
 	return &helmChart, nil
 }
 
+// mergeValues combines the given values files the same way Helm does when specified through multiple '-f' flags:
+// maps are merged recursively, while any other value is overridden by the latter files.
+func mergeValues(valuesContents [][]byte) (string, error) {
+	switch len(valuesContents) {
+	case 0:
+		return "", nil
+	case 1:
+		return string(valuesContents[0]), nil
+	}
+
+	merged := map[string]any{}
+	for _, content := range valuesContents {
+		values := map[string]any{}
+		if err := yaml.Unmarshal(content, &values); err != nil {
+			return "", fmt.Errorf("parsing values: %w", err)
+		}
+
+		mergeMaps(merged, values)
+	}
+
+	data, err := yaml.Marshal(merged)
+	if err != nil {
+		return "", fmt.Errorf("serializing values: %w", err)
+	}
+
+	return string(data), nil
+}
+
+func mergeMaps(dst, src map[string]any) {
+	for key, srcValue := range src {
+		srcMap, srcIsMap := srcValue.(map[string]any)
+		dstMap, dstIsMap := dst[key].(map[string]any)
+
+		if srcIsMap && dstIsMap {
+			mergeMaps(dstMap, srcMap)
+			continue
+		}
+
+		dst[key] = srcValue
+	}
+}
+
 func downloadChart(chart *image.HelmChart, repo *image.HelmRepository, helmClient image.HelmClient, destDir string) (string, error) {
 	if strings.HasPrefix(repo.URL, "http") {
 		if err := helmClient.AddRepo(repo); err != nil {
@@ -100,8 +174,8 @@ func getChartContent(chartPath string) (string, error) {
 	return base64.StdEncoding.EncodeToString(data), nil
 }
 
-func getChartContainerImages(chart *image.HelmChart, helmClient image.HelmClient, chartPath, valuesPath, kubeVersion string) ([]string, error) {
-	chartResources, err := helmClient.Template(chart.Name, chartPath, chart.Version, valuesPath, kubeVersion, chart.TargetNamespace)
+func getChartContainerImages(chart *image.HelmChart, helmClient image.HelmClient, chartPath string, valuesPaths []string, kubeVersion string) ([]string, error) {
+	chartResources, err := helmClient.Template(chart.Name, chartPath, chart.Version, valuesPaths, kubeVersion, chart.TargetNamespace, chart.IncludeCRDs)
 	if err != nil {
 		return nil, fmt.Errorf("templating chart: %w", err)
 	}
@@ -109,6 +183,10 @@ func getChartContainerImages(chart *image.HelmChart, helmClient image.HelmClient
 	containerImages := map[string]bool{}
 	for _, resource := range chartResources {
 		storeManifestImages(resource, containerImages)
+
+		if chart.IncludeCRDs {
+			storeCRDImages(resource, containerImages)
+		}
 	}
 
 	var images []string
@@ -8,6 +8,9 @@ const (
 	helmChartAPIVersion = "helm.cattle.io/v1"
 	helmChartKind       = "HelmChart"
 	helmChartSource     = "edge-image-builder"
+
+	// RepositoryURLAnnotation stores the URL of the repository the chart was pulled from.
+	RepositoryURLAnnotation = "edge.suse.com/repository-url"
 )
 
 type HelmCRD struct {
@@ -39,8 +42,8 @@ func NewHelmCRD(chart *image.HelmChart, chartContent, valuesContent, repositoryU
 			Name:      chart.Name,
 			Namespace: chart.InstallationNamespace,
 			Annotations: map[string]string{
-				"edge.suse.com/source":         helmChartSource,
-				"edge.suse.com/repository-url": repositoryURL,
+				"edge.suse.com/source":  helmChartSource,
+				RepositoryURLAnnotation: repositoryURL,
 			},
 		},
 		Spec: struct {
@@ -16,7 +16,7 @@ type mockHelmClient struct {
 	addRepoFunc       func(repository *image.HelmRepository) error
 	registryLoginFunc func(repository *image.HelmRepository) error
 	pullFunc          func(chart string, repository *image.HelmRepository, version, destDir string) (string, error)
-	templateFunc      func(chart, repository, version, valuesFilePath, kubeVersion, targetNamespace string) ([]map[string]any, error)
+	templateFunc      func(chart, repository, version string, valuesFilePaths []string, kubeVersion, targetNamespace string, includeCRDs bool) ([]map[string]any, error)
 }
 
 func (m mockHelmClient) AddRepo(repository *image.HelmRepository) error {
@@ -40,9 +40,9 @@ func (m mockHelmClient) Pull(chart string, repository *image.HelmRepository, ver
 	panic("not implemented")
 }
 
-func (m mockHelmClient) Template(chart, repository, version, valuesFilePath, kubeVersion, targetNamespace string) ([]map[string]any, error) {
+func (m mockHelmClient) Template(chart, repository, version string, valuesFilePaths []string, kubeVersion, targetNamespace string, includeCRDs bool) ([]map[string]any, error) {
 	if m.templateFunc != nil {
-		return m.templateFunc(chart, repository, version, valuesFilePath, kubeVersion, targetNamespace)
+		return m.templateFunc(chart, repository, version, valuesFilePaths, kubeVersion, targetNamespace, includeCRDs)
 	}
 	panic("not implemented")
 }
@@ -132,7 +132,7 @@ func TestHandleChart_FailedTemplate(t *testing.T) {
 		pullFunc: func(chart string, repository *image.HelmRepository, version, destDir string) (string, error) {
 			return "", nil
 		},
-		templateFunc: func(chart, repository, version, valuesFilePath, kubeVersion, targetNamespace string) ([]map[string]any, error) {
+		templateFunc: func(chart, repository, version string, valuesFilePaths []string, kubeVersion, targetNamespace string, includeCRDs bool) ([]map[string]any, error) {
 			return nil, fmt.Errorf("failed templating")
 		},
 	}
@@ -164,7 +164,7 @@ func TestHandleChart_FailedGetChartContent(t *testing.T) {
 		pullFunc: func(chart string, repository *image.HelmRepository, version, destDir string) (string, error) {
 			return "does-not-exist.tgz", nil
 		},
-		templateFunc: func(chart, repository, version, valuesFilePath, kubeVersion, targetNamespace string) ([]map[string]any, error) {
+		templateFunc: func(chart, repository, version string, valuesFilePaths []string, kubeVersion, targetNamespace string, includeCRDs bool) ([]map[string]any, error) {
 			return nil, nil
 		},
 	}
@@ -175,6 +175,91 @@ func TestHandleChart_FailedGetChartContent(t *testing.T) {
 	assert.Nil(t, charts)
 }
 
+func TestHandleChart_MultipleValuesFiles(t *testing.T) {
+	valuesDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(valuesDir, "base.yaml"), []byte("replicaCount: 1\nservice:\n  type: ClusterIP\n  port: 80\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(valuesDir, "edge.yaml"), []byte("service:\n  type: NodePort\n"), 0o600))
+
+	chartPath := filepath.Join(t.TempDir(), "apache-10.7.0.tgz")
+	require.NoError(t, os.WriteFile(chartPath, []byte("chart"), 0o600))
+
+	helmChart := &image.HelmChart{
+		Name:           "apache",
+		RepositoryName: "apache-repo",
+		Version:        "10.7.0",
+		ValuesFiles:    []string{"base.yaml", "edge.yaml"},
+	}
+	helmRepo := &image.HelmRepository{
+		Name: "apache-repo",
+		URL:  "oci://registry-1.docker.io/bitnamicharts",
+	}
+
+	var templatedValuesPaths []string
+	helmClient := mockHelmClient{
+		pullFunc: func(chart string, repository *image.HelmRepository, version, destDir string) (string, error) {
+			return chartPath, nil
+		},
+		templateFunc: func(chart, repository, version string, valuesFilePaths []string, kubeVersion, targetNamespace string, includeCRDs bool) ([]map[string]any, error) {
+			templatedValuesPaths = valuesFilePaths
+			return nil, nil
+		},
+	}
+
+	chart, err := handleChart(helmChart, helmRepo, valuesDir, "", "", helmClient)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{filepath.Join(valuesDir, "base.yaml"), filepath.Join(valuesDir, "edge.yaml")}, templatedValuesPaths)
+	assert.Equal(t, "replicaCount: 1\nservice:\n    port: 80\n    type: NodePort\n", chart.CRD.Spec.ValuesContent)
+}
+
+func TestMergeValues(t *testing.T) {
+	tests := []struct {
+		name           string
+		valuesContents [][]byte
+		expectedValues string
+		expectedErr    string
+	}{
+		{
+			name:           "No values",
+			expectedValues: "",
+		},
+		{
+			name:           "Single values file is kept as is",
+			valuesContents: [][]byte{[]byte("# Base values\nreplicaCount: 1\n")},
+			expectedValues: "# Base values\nreplicaCount: 1\n",
+		},
+		{
+			name: "Multiple values files",
+			valuesContents: [][]byte{
+				[]byte("replicaCount: 1\nimage:\n  tag: 1.0.0\nargs:\n  - --verbose\n"),
+				[]byte("image:\n  tag: 1.1.0\nargs:\n  - --quiet\n"),
+			},
+			expectedValues: "args:\n    - --quiet\nimage:\n    tag: 1.1.0\nreplicaCount: 1\n",
+		},
+		{
+			name: "Invalid values file",
+			valuesContents: [][]byte{
+				[]byte("replicaCount: 1\n"),
+				[]byte("- invalid\n"),
+			},
+			expectedErr: "parsing values: yaml: unmarshal errors:\n  line 1: cannot unmarshal !!seq into map[string]interface {}",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			values, err := mergeValues(test.valuesContents)
+
+			if test.expectedErr != "" {
+				require.EqualError(t, err, test.expectedErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, test.expectedValues, values)
+			}
+		})
+	}
+}
+
 func TestDownloadChart_FailedAddingRepo(t *testing.T) {
 	helmChart := &image.HelmChart{}
 	helmRepo := &image.HelmRepository{
@@ -330,7 +415,7 @@ func TestHelmCharts(t *testing.T) {
 		pullFunc: func(chart string, repository *image.HelmRepository, version, destDir string) (string, error) {
 			return file, nil
 		},
-		templateFunc: func(chart, repository, version, valuesFilePath, kubeVersion, targetNamespace string) ([]map[string]any, error) {
+		templateFunc: func(chart, repository, version string, valuesFilePaths []string, kubeVersion, targetNamespace string, includeCRDs bool) ([]map[string]any, error) {
 			chartResource := []map[string]any{
 				{
 					"apiVersion": "v1",
@@ -369,6 +454,70 @@ func TestHelmCharts(t *testing.T) {
 	assert.Equal(t, true, charts[0].CRD.Spec.CreateNamespace)
 }
 
+func TestGetChartContainerImages_IncludeCRDs(t *testing.T) {
+	crd := map[string]any{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"spec": map[string]any{
+			"versions": []any{
+				map[string]any{
+					"name": "v1",
+					"schema": map[string]any{
+						"openAPIV3Schema": map[string]any{
+							"properties": map[string]any{
+								"spec": map[string]any{
+									"properties": map[string]any{
+										"image": map[string]any{
+											"type":    "string",
+											"default": "registry.suse.com/edge/operand:1.2.0",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	deployment := map[string]any{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"spec": map[string]any{
+			"image": "registry.suse.com/edge/operator:1.2.0",
+		},
+	}
+
+	var includedCRDs []bool
+	helmClient := mockHelmClient{
+		templateFunc: func(chart, repository, version string, valuesFilePaths []string, kubeVersion, targetNamespace string, includeCRDs bool) ([]map[string]any, error) {
+			includedCRDs = append(includedCRDs, includeCRDs)
+
+			if includeCRDs {
+				return []map[string]any{crd, deployment}, nil
+			}
+			return []map[string]any{deployment}, nil
+		},
+	}
+
+	chart := &image.HelmChart{
+		Name:    "operator",
+		Version: "1.2.0",
+	}
+
+	images, err := getChartContainerImages(chart, helmClient, "operator-1.2.0.tgz", nil, "v1.30.3+k3s1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"registry.suse.com/edge/operator:1.2.0"}, images)
+
+	chart.IncludeCRDs = true
+
+	images, err = getChartContainerImages(chart, helmClient, "operator-1.2.0.tgz", nil, "v1.30.3+k3s1")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"registry.suse.com/edge/operator:1.2.0", "registry.suse.com/edge/operand:1.2.0"}, images)
+
+	assert.Equal(t, []bool{false, true}, includedCRDs)
+}
+
 func TestMapChartRepos(t *testing.T) {
 	helm := &image.Helm{
 		Charts: []image.HelmChart{
@@ -2,6 +2,7 @@ package registry
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"io"
@@ -11,15 +12,17 @@ import (
 	"strings"
 
 	"github.com/suse-edge/edge-image-builder/pkg/http"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
 	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
 	"gopkg.in/yaml.v3"
 )
 
-func ManifestImages(manifestURLs []string, manifestsDir string) ([]string, error) {
+func ManifestImages(ctx context.Context, client *http.Client, manifests *image.Manifests, manifestsDir string, parallelDownloads int) ([]string, error) {
 	var manifestPaths []string
 
-	if len(manifestURLs) != 0 {
-		paths, err := DownloadManifests(manifestURLs, os.TempDir())
+	if len(manifests.URLs) != 0 {
+		paths, err := DownloadManifests(ctx, client, manifests, os.TempDir(), parallelDownloads)
 		if err != nil {
 			return nil, fmt.Errorf("downloading manifests: %w", err)
 		}
@@ -124,6 +127,39 @@ func storeManifestImages(resource map[string]any, images map[string]bool) {
 	findImages(resource)
 }
 
+// storeCRDImages collects the images referenced as the default value of the 'image'
+// properties in the schema of a CustomResourceDefinition.
+func storeCRDImages(resource map[string]any, images map[string]bool) {
+	if kind, _ := resource["kind"].(string); kind != "CustomResourceDefinition" {
+		return
+	}
+
+	var findImages func(data any)
+
+	findImages = func(data any) {
+		switch t := data.(type) {
+		case map[string]any:
+			if properties, ok := t["properties"].(map[string]any); ok {
+				if property, ok := properties["image"].(map[string]any); ok {
+					if imageName, ok := property["default"].(string); ok && imageName != "" {
+						images[imageName] = true
+					}
+				}
+			}
+
+			for _, v := range t {
+				findImages(v)
+			}
+		case []any:
+			for _, v := range t {
+				findImages(v)
+			}
+		}
+	}
+
+	findImages(resource)
+}
+
 func getManifestPaths(src string) ([]string, error) {
 	if src == "" {
 		return nil, fmt.Errorf("manifest source directory not defined")
@@ -150,17 +186,77 @@ func getManifestPaths(src string) ([]string, error) {
 	return manifestPaths, nil
 }
 
-func DownloadManifests(manifestURLs []string, destPath string) ([]string, error) {
-	var manifestPaths []string
+// DownloadManifests downloads the given manifests into destPath, running at most parallelDownloads
+// downloads at a time. The resulting file names are derived from the position of the URL in the list.
+func DownloadManifests(ctx context.Context, client *http.Client, manifests *image.Manifests, destPath string, parallelDownloads int) ([]string, error) {
+	manifestPaths := make([]string, len(manifests.URLs))
+
+	errGroup, ctx := errgroup.WithContext(ctx)
+	errGroup.SetLimit(max(parallelDownloads, 1))
 
-	for index, manifestURL := range manifestURLs {
+	for index, manifestURL := range manifests.URLs {
 		filePath := filepath.Join(destPath, fmt.Sprintf("dl-manifest-%d.yaml", index+1))
-		manifestPaths = append(manifestPaths, filePath)
+		manifestPaths[index] = filePath
 
-		if err := http.DownloadFile(context.Background(), manifestURL, filePath, nil); err != nil {
-			return nil, fmt.Errorf("downloading manifest '%s': %w", manifestURL, err)
-		}
+		errGroup.Go(func() error {
+			headers, err := manifestHeaders(manifestURL, manifests.Credentials)
+			if err != nil {
+				return fmt.Errorf("resolving credentials for manifest '%s': %w", manifestURL, err)
+			}
+
+			if err = client.DownloadFile(ctx, manifestURL, filePath, headers, nil); err != nil {
+				return fmt.Errorf("downloading manifest '%s': %w", manifestURL, err)
+			}
+
+			return nil
+		})
+	}
+
+	if err := errGroup.Wait(); err != nil {
+		return nil, err
 	}
 
 	return manifestPaths, nil
 }
+
+// manifestHeaders returns the request headers for the given manifest URL based on the
+// credentials with the longest matching URL prefix. Secrets are resolved from the environment.
+func manifestHeaders(manifestURL string, credentials []image.ManifestCredentials) (map[string]string, error) {
+	var match *image.ManifestCredentials
+
+	for i := range credentials {
+		c := &credentials[i]
+		if strings.HasPrefix(manifestURL, c.URLPrefix) && (match == nil || len(c.URLPrefix) > len(match.URLPrefix)) {
+			match = c
+		}
+	}
+
+	if match == nil {
+		return nil, nil
+	}
+
+	headers := make(map[string]string, len(match.Headers)+1)
+	for key, value := range match.Headers {
+		headers[key] = value
+	}
+
+	switch {
+	case match.TokenEnv != "":
+		token := os.Getenv(match.TokenEnv)
+		if token == "" {
+			return nil, fmt.Errorf("environment variable '%s' is not set", match.TokenEnv)
+		}
+
+		headers["Authorization"] = "Bearer " + token
+	case match.Username != "":
+		password := os.Getenv(match.PasswordEnv)
+		if password == "" {
+			return nil, fmt.Errorf("environment variable '%s' is not set", match.PasswordEnv)
+		}
+
+		encoded := base64.StdEncoding.EncodeToString([]byte(match.Username + ":" + password))
+		headers["Authorization"] = "Basic " + encoded
+	}
+
+	return headers, nil
+}
@@ -3,6 +3,7 @@
 package registry
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
@@ -10,6 +11,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
 )
 
 func TestDownloadManifests(t *testing.T) {
@@ -27,7 +29,7 @@ func TestDownloadManifests(t *testing.T) {
 	}
 
 	// Test
-	manifestPaths, err := DownloadManifests(manifestURLs, manifestDownloadDest)
+	manifestPaths, err := DownloadManifests(context.Background(), newClient(t), &image.Manifests{URLs: manifestURLs}, manifestDownloadDest, 1)
 
 	// Verify
 	require.NoError(t, err)
@@ -66,7 +68,7 @@ func TestManifestImages(t *testing.T) {
 	manifestURLs := []string{"https://k8s.io/examples/application/nginx-app.yaml"}
 
 	// Test
-	containerImages, err := ManifestImages(manifestURLs, manifestSrcDir)
+	containerImages, err := ManifestImages(context.Background(), newClient(t), &image.Manifests{URLs: manifestURLs}, manifestSrcDir, 1)
 
 	// Verify
 	require.NoError(t, err)
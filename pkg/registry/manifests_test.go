@@ -1,6 +1,10 @@
 package registry
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
@@ -8,12 +12,21 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/suse-edge/edge-image-builder/pkg/fileio"
+	eibhttp "github.com/suse-edge/edge-image-builder/pkg/http"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
 )
 
 const (
 	localManifestsSrcDir = "local-manifests"
 )
 
+func newClient(t *testing.T) *eibhttp.Client {
+	client, err := eibhttp.NewClient(eibhttp.ClientConfig{})
+	require.NoError(t, err)
+
+	return client
+}
+
 func TestReadManifest(t *testing.T) {
 	// Setup
 	manifestPath := filepath.Join("testdata", "sample-crd.yaml")
@@ -189,7 +202,7 @@ func TestManifestImages_InvalidURL(t *testing.T) {
 	}
 
 	// Test
-	_, err := ManifestImages(manifestURLs, "")
+	_, err := ManifestImages(context.Background(), newClient(t), &image.Manifests{URLs: manifestURLs}, "", 1)
 
 	// Verify
 	require.ErrorContains(t, err, "downloading manifests: downloading manifest 'k8s.io/examples/application/nginx-app.yaml': executing request: Get \"k8s.io/examples/application/nginx-app.yaml\": unsupported protocol scheme \"\"")
@@ -197,7 +210,7 @@ func TestManifestImages_InvalidURL(t *testing.T) {
 
 func TestManifestImages_LocalManifestDirNotDefined(t *testing.T) {
 	// Test
-	containerImages, err := ManifestImages(nil, "")
+	containerImages, err := ManifestImages(context.Background(), newClient(t), &image.Manifests{}, "", 1)
 
 	// Verify
 	require.NoError(t, err)
@@ -209,7 +222,7 @@ func TestManifestImages_InvalidLocalManifestsDir(t *testing.T) {
 	localManifestsDir := "does-not-exist"
 
 	// Test
-	_, err := ManifestImages(nil, localManifestsDir)
+	_, err := ManifestImages(context.Background(), newClient(t), &image.Manifests{}, localManifestsDir, 1)
 
 	// Verify
 	require.ErrorContains(t, err, "getting local manifest paths: reading manifest source dir 'does-not-exist': open does-not-exist: no such file or directory")
@@ -220,7 +233,7 @@ func TestDownloadManifests_NoManifest(t *testing.T) {
 	manifestDownloadDest := ""
 
 	// Test
-	manifestPaths, err := DownloadManifests(nil, manifestDownloadDest)
+	manifestPaths, err := DownloadManifests(context.Background(), newClient(t), &image.Manifests{}, manifestDownloadDest, 1)
 
 	// Verify
 	require.NoError(t, err)
@@ -233,7 +246,7 @@ func TestDownloadManifests_InvalidURL(t *testing.T) {
 	manifestDownloadDest := ""
 
 	// Test
-	manifestPaths, err := DownloadManifests(manifestURLs, manifestDownloadDest)
+	manifestPaths, err := DownloadManifests(context.Background(), newClient(t), &image.Manifests{URLs: manifestURLs}, manifestDownloadDest, 1)
 
 	// Verify
 	require.ErrorContains(t, err, "downloading manifest 'k8s.io/examples/application/nginx-app.yaml': executing request: Get \"k8s.io/examples/application/nginx-app.yaml\": unsupported protocol scheme \"")
@@ -252,8 +265,159 @@ func TestManifestImages_InvalidLocalManifest(t *testing.T) {
 	require.NoError(t, err)
 
 	// Test
-	_, err = ManifestImages(nil, localManifestsSrcDir)
+	_, err = ManifestImages(context.Background(), newClient(t), &image.Manifests{}, localManifestsSrcDir, 1)
 
 	// Verify
 	require.ErrorContains(t, err, "reading manifest: error unmarshalling manifest yaml")
 }
+
+func TestDownloadManifests_Parallel(t *testing.T) {
+	// Setup
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "path: %s", r.URL.Path)
+	}))
+	defer server.Close()
+
+	destDir, err := os.MkdirTemp("", "eib-manifests-")
+	require.NoError(t, err)
+	defer os.RemoveAll(destDir)
+
+	var manifestURLs []string
+	for i := 1; i <= 5; i++ {
+		manifestURLs = append(manifestURLs, fmt.Sprintf("%s/manifest-%d", server.URL, i))
+	}
+
+	// Test
+	manifestPaths, err := DownloadManifests(context.Background(), newClient(t), &image.Manifests{URLs: manifestURLs}, destDir, 3)
+
+	// Verify
+	require.NoError(t, err)
+	require.Len(t, manifestPaths, len(manifestURLs))
+
+	for i, path := range manifestPaths {
+		assert.Equal(t, filepath.Join(destDir, fmt.Sprintf("dl-manifest-%d.yaml", i+1)), path)
+
+		contents, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("path: /manifest-%d", i+1), string(contents))
+	}
+}
+
+func TestDownloadManifests_Offline(t *testing.T) {
+	// Setup
+	var requested bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = true
+	}))
+	defer server.Close()
+
+	manifestURL := server.URL + "/manifest.yaml"
+
+	client, err := eibhttp.NewClient(eibhttp.ClientConfig{Offline: true})
+	require.NoError(t, err)
+
+	// Test
+	manifestPaths, err := DownloadManifests(context.Background(), client, &image.Manifests{URLs: []string{manifestURL}}, t.TempDir(), 1)
+
+	// Verify
+	require.ErrorIs(t, err, eibhttp.ErrOffline)
+	assert.ErrorContains(t, err, fmt.Sprintf("'%s' is not available locally", manifestURL))
+	assert.Nil(t, manifestPaths)
+	assert.False(t, requested)
+}
+
+func TestDownloadManifests_FailureCancelsRemaining(t *testing.T) {
+	// Setup
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		// Block until the download is cancelled by the client
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	destDir, err := os.MkdirTemp("", "eib-manifests-")
+	require.NoError(t, err)
+	defer os.RemoveAll(destDir)
+
+	manifestURLs := []string{server.URL + "/slow", server.URL + "/fail", server.URL + "/slow"}
+
+	// Test
+	manifestPaths, err := DownloadManifests(context.Background(), newClient(t), &image.Manifests{URLs: manifestURLs}, destDir, 2)
+
+	// Verify
+	require.ErrorContains(t, err, fmt.Sprintf("downloading manifest '%s/fail': unexpected status code: 404", server.URL))
+	assert.Empty(t, manifestPaths)
+}
+
+func TestDownloadManifests_Credentials(t *testing.T) {
+	// Setup
+	t.Setenv("EIB_TEST_MANIFEST_TOKEN", "s3cr3t")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%s|%s", r.Header.Get("Authorization"), r.Header.Get("X-Tenant"))
+	}))
+	defer server.Close()
+
+	destDir, err := os.MkdirTemp("", "eib-manifests-")
+	require.NoError(t, err)
+	defer os.RemoveAll(destDir)
+
+	manifests := &image.Manifests{
+		URLs: []string{
+			server.URL + "/secured/manifest.yaml",
+			server.URL + "/public/manifest.yaml",
+		},
+		Credentials: []image.ManifestCredentials{
+			{
+				URLPrefix: server.URL + "/secured/",
+				TokenEnv:  "EIB_TEST_MANIFEST_TOKEN",
+				Headers: map[string]string{
+					"X-Tenant": "edge",
+				},
+			},
+		},
+	}
+
+	// Test
+	manifestPaths, err := DownloadManifests(context.Background(), newClient(t), manifests, destDir, 1)
+
+	// Verify
+	require.NoError(t, err)
+	require.Len(t, manifestPaths, 2)
+
+	contents, err := os.ReadFile(manifestPaths[0])
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer s3cr3t|edge", string(contents))
+
+	contents, err = os.ReadFile(manifestPaths[1])
+	require.NoError(t, err)
+	assert.Equal(t, "|", string(contents))
+}
+
+func TestManifestHeaders_BasicAuth(t *testing.T) {
+	t.Setenv("EIB_TEST_MANIFEST_PASSWORD", "pass")
+
+	credentials := []image.ManifestCredentials{
+		{
+			URLPrefix:   "https://example.com/",
+			Username:    "user",
+			PasswordEnv: "EIB_TEST_MANIFEST_PASSWORD",
+		},
+		{
+			URLPrefix: "https://example.com/other/",
+			TokenEnv:  "EIB_TEST_UNSET_VARIABLE",
+		},
+	}
+
+	headers, err := manifestHeaders("https://example.com/manifest.yaml", credentials)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"Authorization": "Basic dXNlcjpwYXNz"}, headers)
+
+	// The longest matching prefix takes precedence
+	_, err = manifestHeaders("https://example.com/other/manifest.yaml", credentials)
+	require.EqualError(t, err, "environment variable 'EIB_TEST_UNSET_VARIABLE' is not set")
+}
@@ -0,0 +1,277 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+const (
+	defaultRegistryHost = "docker.io"
+	dockerHubAPIHost    = "registry-1.docker.io"
+	defaultImageTag     = "latest"
+
+	sizeLookupTimeout = 30 * time.Second
+)
+
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// ImageSizeResolver estimates the size of container images by querying
+// the manifests served by their registries.
+type ImageSizeResolver struct {
+	// Client is used to query the registries. Defaults to http.DefaultClient.
+	Client *http.Client
+}
+
+type imageReference struct {
+	host       string
+	repository string
+	reference  string
+}
+
+type imageManifest struct {
+	Manifests []struct {
+		Digest   string `json:"digest"`
+		Platform struct {
+			OS           string `json:"os"`
+			Architecture string `json:"architecture"`
+		} `json:"platform"`
+	} `json:"manifests"`
+	Config struct {
+		Size int64 `json:"size"`
+	} `json:"config"`
+	Layers []struct {
+		Size int64 `json:"size"`
+	} `json:"layers"`
+}
+
+// ImageSize returns the combined (compressed) size in bytes of the config and layers
+// of the given image for the specified architecture.
+func (r ImageSizeResolver) ImageSize(containerImage string, arch image.Arch) (int64, error) {
+	ref := parseImageReference(containerImage)
+
+	ctx, cancel := context.WithTimeout(context.Background(), sizeLookupTimeout)
+	defer cancel()
+
+	manifest, err := r.fetchManifest(ctx, ref, ref.reference)
+	if err != nil {
+		return 0, fmt.Errorf("fetching manifest: %w", err)
+	}
+
+	if len(manifest.Manifests) != 0 {
+		platformArch, err := arch.ShortName()
+		if err != nil {
+			return 0, err
+		}
+
+		digest := ""
+		for _, m := range manifest.Manifests {
+			if m.Platform.OS == "linux" && m.Platform.Architecture == platformArch {
+				digest = m.Digest
+				break
+			}
+		}
+
+		if digest == "" {
+			return 0, fmt.Errorf("no manifest found for platform linux/%s", platformArch)
+		}
+
+		if manifest, err = r.fetchManifest(ctx, ref, digest); err != nil {
+			return 0, fmt.Errorf("fetching platform manifest: %w", err)
+		}
+	}
+
+	size := manifest.Config.Size
+	for _, layer := range manifest.Layers {
+		size += layer.Size
+	}
+
+	return size, nil
+}
+
+func (r ImageSizeResolver) fetchManifest(ctx context.Context, ref *imageReference, reference string) (*imageManifest, error) {
+	manifestURL := fmt.Sprintf("https://%s/v2/%s/manifests/%s", ref.host, ref.repository, reference)
+
+	resp, err := r.get(ctx, manifestURL, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		_ = resp.Body.Close()
+
+		var token string
+		if token, err = r.anonymousToken(ctx, challenge); err != nil {
+			return nil, fmt.Errorf("authenticating: %w", err)
+		}
+
+		if resp, err = r.get(ctx, manifestURL, token); err != nil {
+			return nil, err
+		}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var manifest imageManifest
+	if err = json.NewDecoder(resp.Body).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("decoding manifest: %w", err)
+	}
+
+	return &manifest, nil
+}
+
+func (r ImageSizeResolver) get(ctx context.Context, requestURL, token string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	client := r.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("executing request: %w", err)
+	}
+
+	return resp, nil
+}
+
+// anonymousToken requests a pull token from the authorization service
+// advertised in a 'WWW-Authenticate: Bearer ...' challenge.
+func (r ImageSizeResolver) anonymousToken(ctx context.Context, challenge string) (string, error) {
+	params := parseBearerChallenge(challenge)
+
+	realm := params["realm"]
+	if realm == "" {
+		return "", fmt.Errorf("unsupported authentication challenge: %q", challenge)
+	}
+
+	query := url.Values{}
+	for _, key := range []string{"service", "scope"} {
+		if value := params[key]; value != "" {
+			query.Set(key, value)
+		}
+	}
+
+	tokenURL := realm
+	if len(query) != 0 {
+		tokenURL = fmt.Sprintf("%s?%s", realm, query.Encode())
+	}
+
+	resp, err := r.get(ctx, tokenURL, "")
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var tokenResponse struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+
+	if err = json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&tokenResponse); err != nil {
+		return "", fmt.Errorf("decoding token response: %w", err)
+	}
+
+	if tokenResponse.Token != "" {
+		return tokenResponse.Token, nil
+	}
+
+	return tokenResponse.AccessToken, nil
+}
+
+func parseBearerChallenge(challenge string) map[string]string {
+	params := map[string]string{}
+
+	scheme, rest, ok := strings.Cut(challenge, " ")
+	if !ok || !strings.EqualFold(scheme, "bearer") {
+		return params
+	}
+
+	for _, param := range strings.Split(rest, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(param), "=")
+		if !found {
+			continue
+		}
+
+		params[strings.ToLower(key)] = strings.Trim(value, `"`)
+	}
+
+	return params
+}
+
+// NormalizeImageName splits a container image into its fully qualified repository (e.g. 'docker.io/library/nginx')
+// and its tag or digest, so that differently spelled references to the same image can be compared.
+func NormalizeImageName(containerImage string) (repository, reference string) {
+	ref := parseImageReference(containerImage)
+
+	host := ref.host
+	if host == dockerHubAPIHost {
+		host = defaultRegistryHost
+	}
+
+	return host + "/" + ref.repository, ref.reference
+}
+
+// parseImageReference splits a container image into its registry host, repository and
+// tag (or digest) following the same defaults as the container tooling.
+func parseImageReference(containerImage string) *imageReference {
+	ref := &imageReference{
+		host:      defaultRegistryHost,
+		reference: defaultImageTag,
+	}
+
+	name := containerImage
+	if i := strings.Index(name, "@"); i != -1 {
+		ref.reference = name[i+1:]
+		name = name[:i]
+	} else if i = strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		ref.reference = name[i+1:]
+		name = name[:i]
+	}
+
+	if first, rest, found := strings.Cut(name, "/"); found &&
+		(strings.ContainsAny(first, ".:") || first == "localhost") {
+		ref.host = first
+		name = rest
+	}
+
+	if ref.host == defaultRegistryHost {
+		ref.host = dockerHubAPIHost
+
+		if !strings.Contains(name, "/") {
+			name = "library/" + name
+		}
+	}
+
+	ref.repository = name
+	return ref
+}
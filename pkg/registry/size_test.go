@@ -0,0 +1,122 @@
+package registry
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/suse-edge/edge-image-builder/pkg/image"
+)
+
+func TestParseImageReference(t *testing.T) {
+	tests := map[string]struct {
+		image    string
+		expected imageReference
+	}{
+		"Docker Hub official image": {
+			image:    "nginx",
+			expected: imageReference{host: "registry-1.docker.io", repository: "library/nginx", reference: "latest"},
+		},
+		"Docker Hub with tag": {
+			image:    "docker.io/rancher/hello-world:v0.1",
+			expected: imageReference{host: "registry-1.docker.io", repository: "rancher/hello-world", reference: "v0.1"},
+		},
+		"Custom registry with port": {
+			image:    "localhost:5000/foo/bar:1.0",
+			expected: imageReference{host: "localhost:5000", repository: "foo/bar", reference: "1.0"},
+		},
+		"Digest": {
+			image:    "ghcr.io/fluxcd/flux-cli@sha256:02aa",
+			expected: imageReference{host: "ghcr.io", repository: "fluxcd/flux-cli", reference: "sha256:02aa"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, *parseImageReference(test.image))
+		})
+	}
+}
+
+func TestNormalizeImageName(t *testing.T) {
+	tests := map[string]struct {
+		image              string
+		expectedRepository string
+		expectedReference  string
+	}{
+		"Docker Hub official image": {
+			image:              "nginx",
+			expectedRepository: "docker.io/library/nginx",
+			expectedReference:  "latest",
+		},
+		"Docker Hub fully qualified": {
+			image:              "docker.io/library/nginx:1.25",
+			expectedRepository: "docker.io/library/nginx",
+			expectedReference:  "1.25",
+		},
+		"Custom registry with port": {
+			image:              "localhost:5000/foo/bar:1.0",
+			expectedRepository: "localhost:5000/foo/bar",
+			expectedReference:  "1.0",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			repository, reference := NormalizeImageName(test.image)
+			assert.Equal(t, test.expectedRepository, repository)
+			assert.Equal(t, test.expectedReference, reference)
+		})
+	}
+}
+
+func TestImageSize(t *testing.T) {
+	const token = "secret"
+
+	index := `{"manifests": [
+		{"digest": "sha256:arm", "platform": {"os": "linux", "architecture": "arm64"}},
+		{"digest": "sha256:amd", "platform": {"os": "linux", "architecture": "amd64"}}
+	]}`
+	manifest := `{"config": {"size": 100}, "layers": [{"size": 1000}, {"size": 2000}]}`
+
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			assert.Equal(t, "repository:foo/bar:pull", r.URL.Query().Get("scope"))
+			fmt.Fprintf(w, `{"token": "%s"}`, token)
+			return
+		}
+
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			w.Header().Set("WWW-Authenticate",
+				fmt.Sprintf(`Bearer realm="%s/token",service="test",scope="repository:foo/bar:pull"`, server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/v2/foo/bar/manifests/1.0":
+			fmt.Fprint(w, index)
+		case "/v2/foo/bar/manifests/sha256:amd":
+			fmt.Fprint(w, manifest)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	host := strings.TrimPrefix(server.URL, "https://")
+	resolver := ImageSizeResolver{Client: server.Client()}
+
+	size, err := resolver.ImageSize(host+"/foo/bar:1.0", image.ArchTypeX86)
+	require.NoError(t, err)
+	assert.EqualValues(t, 3100, size)
+
+	_, err = resolver.ImageSize(host+"/foo/bar:missing", image.ArchTypeX86)
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "unexpected status code: 404")
+}
@@ -0,0 +1,72 @@
+package rpm
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	packageNameRegex    = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._+-]*$`)
+	packageVersionRegex = regexp.MustCompile(`^([0-9]+:)?[A-Za-z0-9._+~^]+(-[A-Za-z0-9._+~^]+)?$`)
+
+	// Longer operators must be matched first so that '>=' is not mistaken for '>'.
+	versionOperators = []string{">=", "<=", "=", ">", "<"}
+)
+
+// PackageSpec holds a package name with an optional version constraint, e.g. 'foo', 'foo=1.2.3' or 'foo>=1.2'.
+type PackageSpec struct {
+	Name     string
+	Operator string
+	Version  string
+}
+
+// ParsePackageSpec splits a package list entry into the package name and its optional version constraint.
+func ParsePackageSpec(spec string) (*PackageSpec, error) {
+	p := &PackageSpec{Name: spec}
+
+	if index := strings.IndexAny(spec, "<>="); index != -1 {
+		p.Name = spec[:index]
+
+		constraint := spec[index:]
+		for _, operator := range versionOperators {
+			if version, found := strings.CutPrefix(constraint, operator); found {
+				p.Operator = operator
+				p.Version = version
+				break
+			}
+		}
+
+		if !packageVersionRegex.MatchString(p.Version) {
+			return nil, fmt.Errorf("invalid version constraint '%s' in '%s'", constraint, spec)
+		}
+	}
+
+	if !packageNameRegex.MatchString(p.Name) {
+		return nil, fmt.Errorf("invalid package name '%s' in '%s'", p.Name, spec)
+	}
+
+	return p, nil
+}
+
+// String returns the package spec in the format understood by zypper.
+func (p *PackageSpec) String() string {
+	return p.Name + p.Operator + p.Version
+}
+
+// FormatPackageList joins the given package list entries into a single string of shell arguments.
+// Entries containing a version constraint are quoted, so that the operators are not interpreted
+// as redirections by the shell.
+func FormatPackageList(packages []string) string {
+	formatted := make([]string, 0, len(packages))
+
+	for _, pkg := range packages {
+		if strings.ContainsAny(pkg, "<>=") {
+			pkg = fmt.Sprintf("'%s'", pkg)
+		}
+
+		formatted = append(formatted, pkg)
+	}
+
+	return strings.Join(formatted, " ")
+}
@@ -0,0 +1,69 @@
+package rpm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePackageSpec(t *testing.T) {
+	tests := map[string]struct {
+		spec          string
+		expected      *PackageSpec
+		expectedError string
+	}{
+		`name only`: {
+			spec:     "cowsay",
+			expected: &PackageSpec{Name: "cowsay"},
+		},
+		`exact version`: {
+			spec:     "cowsay=3.03",
+			expected: &PackageSpec{Name: "cowsay", Operator: "=", Version: "3.03"},
+		},
+		`minimum version`: {
+			spec:     "rke2-selinux>=0.18",
+			expected: &PackageSpec{Name: "rke2-selinux", Operator: ">=", Version: "0.18"},
+		},
+		`version with epoch and release`: {
+			spec:     "cowsay<1:3.03-150500.1.1",
+			expected: &PackageSpec{Name: "cowsay", Operator: "<", Version: "1:3.03-150500.1.1"},
+		},
+		`missing version`: {
+			spec:          "cowsay>=",
+			expectedError: "invalid version constraint '>=' in 'cowsay>='",
+		},
+		`invalid operator`: {
+			spec:          "cowsay=>3.03",
+			expectedError: "invalid version constraint '=>3.03' in 'cowsay=>3.03'",
+		},
+		`missing name`: {
+			spec:          "=3.03",
+			expectedError: "invalid package name '' in '=3.03'",
+		},
+		`whitespace`: {
+			spec:          "cowsay 3.03",
+			expectedError: "invalid package name 'cowsay 3.03' in 'cowsay 3.03'",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			p, err := ParsePackageSpec(test.spec)
+
+			if test.expectedError != "" {
+				require.EqualError(t, err, test.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, p)
+			assert.Equal(t, test.spec, p.String())
+		})
+	}
+}
+
+func TestFormatPackageList(t *testing.T) {
+	formatted := FormatPackageList([]string{"foo", "bar=1.2.3", "baz>=2"})
+	assert.Equal(t, "foo 'bar=1.2.3' 'baz>=2'", formatted)
+}
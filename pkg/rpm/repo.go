@@ -1,6 +1,7 @@
 package rpm
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -16,12 +17,15 @@ const (
 )
 
 type RepoCreator struct {
-	logOut string
+	// context terminates the running createrepo command once it is cancelled
+	context context.Context
+	logOut  string
 }
 
-func NewRepoCreator(logOut string) *RepoCreator {
+func NewRepoCreator(ctx context.Context, logOut string) *RepoCreator {
 	return &RepoCreator{
-		logOut: logOut,
+		context: ctx,
+		logOut:  logOut,
 	}
 }
 
@@ -34,7 +38,7 @@ func (r *RepoCreator) Create(path string) error {
 	}
 	defer logFile.Close()
 
-	cmd := prepareRepoCommand(path, logFile)
+	cmd := prepareRepoCommand(r.context, path, logFile)
 	err = cmd.Run()
 	if err != nil {
 		return fmt.Errorf("error running createrepo: %w", err)
@@ -44,8 +48,8 @@ func (r *RepoCreator) Create(path string) error {
 	return nil
 }
 
-func prepareRepoCommand(path string, w io.Writer) *exec.Cmd {
-	cmd := exec.Command(createRepoExec, path)
+func prepareRepoCommand(ctx context.Context, path string, w io.Writer) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, createRepoExec, path)
 	cmd.Stdout = w
 	cmd.Stderr = w
 
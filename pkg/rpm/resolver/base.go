@@ -1,6 +1,7 @@
 package resolver
 
 import (
+	"context"
 	_ "embed"
 	"fmt"
 	"io"
@@ -28,6 +29,8 @@ type ImageImporter interface {
 }
 
 type TarballImageBuilder struct {
+	// context terminates the running tarball image script once it is cancelled
+	context context.Context
 	// dir from where the image builder will work
 	dir string
 	// path to the ISO/RAW file from which a tarball will be created
@@ -38,8 +41,9 @@ type TarballImageBuilder struct {
 	imgImporter ImageImporter
 }
 
-func NewTarballBuilder(workDir, imgPath, imgType string, importer ImageImporter) *TarballImageBuilder {
+func NewTarballBuilder(ctx context.Context, workDir, imgPath, imgType string, importer ImageImporter) *TarballImageBuilder {
 	return &TarballImageBuilder{
+		context:     ctx,
 		dir:         workDir,
 		imgPath:     imgPath,
 		imgType:     imgType,
@@ -131,7 +135,7 @@ func (t *TarballImageBuilder) runTarballImageScript() error {
 
 func (t *TarballImageBuilder) prepareTarballImageCmd(log io.Writer) *exec.Cmd {
 	scriptPath := filepath.Join(t.dir, prepareTarballScriptName)
-	cmd := exec.Command(scriptPath)
+	cmd := exec.CommandContext(t.context, scriptPath)
 	cmd.Stdout = log
 	cmd.Stderr = log
 	return cmd
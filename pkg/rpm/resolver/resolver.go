@@ -10,6 +10,7 @@ import (
 	"github.com/suse-edge/edge-image-builder/pkg/fileio"
 	"github.com/suse-edge/edge-image-builder/pkg/image"
 	"github.com/suse-edge/edge-image-builder/pkg/mount"
+	"github.com/suse-edge/edge-image-builder/pkg/rpm"
 	"github.com/suse-edge/edge-image-builder/pkg/template"
 	"go.uber.org/zap"
 )
@@ -151,6 +152,10 @@ func (r *Resolver) prepareLocalRPMs(localRPMConfig *image.LocalRPMConfig) error
 		return fmt.Errorf("copying local rpms to %s: %w", rpmDest, err)
 	}
 
+	if err := removeSupersededRPMs(rpmDest); err != nil {
+		return fmt.Errorf("removing superseded rpms: %w", err)
+	}
+
 	rpmPaths, err := r.generateResolverImgRPMPaths()
 	if err != nil {
 		return fmt.Errorf("constructing list of rpm paths that need to be installed: %w", err)
@@ -178,22 +183,24 @@ func (r *Resolver) prepareLocalRPMs(localRPMConfig *image.LocalRPMConfig) error
 
 func (r *Resolver) writeRPMResolutionScript(localRPMConfig *image.LocalRPMConfig, packages *image.Packages) error {
 	values := struct {
-		RegCode      string
-		AddRepo      []image.AddRepo
-		CacheDir     string
-		PKGList      string
-		LocalRPMList string
-		LocalGPGList string
-		NoGPGCheck   bool
+		RegCode        string
+		AddRepo        []image.AddRepo
+		CacheDir       string
+		PKGList        string
+		LocalRPMList   string
+		LocalGPGList   string
+		NoGPGCheck     bool
+		InstallOptions string
 	}{
-		RegCode:    packages.RegCode,
-		AddRepo:    packages.AdditionalRepos,
-		CacheDir:   r.generateResolverImgRPMRepoPath(),
-		NoGPGCheck: packages.NoGPGCheck,
+		RegCode:        packages.RegCode,
+		AddRepo:        packages.AdditionalRepos,
+		CacheDir:       r.generateResolverImgRPMRepoPath(),
+		NoGPGCheck:     packages.NoGPGCheck,
+		InstallOptions: strings.Join(packages.InstallOptions, " "),
 	}
 
 	if len(packages.PKGList) > 0 {
-		values.PKGList = strings.Join(packages.PKGList, " ")
+		values.PKGList = rpm.FormatPackageList(packages.PKGList)
 	}
 
 	if localRPMConfig != nil {
@@ -262,6 +269,30 @@ func (r *Resolver) generatePKGInstallList(packages *image.Packages) []string {
 	return list
 }
 
+// removeSupersededRPMs removes older versions of packages which are provided multiple times,
+// passing several versions of the same package to zypper results in a conflict.
+func removeSupersededRPMs(rpmDir string) error {
+	entries, err := os.ReadDir(rpmDir)
+	if err != nil {
+		return fmt.Errorf("reading RPM dir: %w", err)
+	}
+
+	var filenames []string
+	for _, entry := range entries {
+		filenames = append(filenames, entry.Name())
+	}
+
+	for superseded, kept := range rpm.Deduplicate(filenames) {
+		zap.S().Warnf("Skipping RPM '%s' in favour of the higher version '%s'", superseded, kept)
+
+		if err = os.Remove(filepath.Join(rpmDir, superseded)); err != nil {
+			return fmt.Errorf("removing '%s': %w", superseded, err)
+		}
+	}
+
+	return nil
+}
+
 // path to the build dir, as seen in the EIB image
 func (r *Resolver) generateBuildContextPath() string {
 	return filepath.Join(r.dir, "resolver-image-build")
@@ -296,8 +327,8 @@ func (r *Resolver) generateResolverImgRPMPaths() (rpmPathList []string, err erro
 		return nil, fmt.Errorf("reading RPM source dir: %w", err)
 	}
 
-	for _, rpm := range rpms {
-		rpmPathList = append(rpmPathList, filepath.Join(r.generateResolverImgLocalRPMDirPath(), rpm.Name()))
+	for _, rpmFile := range rpms {
+		rpmPathList = append(rpmPathList, filepath.Join(r.generateResolverImgLocalRPMDirPath(), rpmFile.Name()))
 	}
 
 	return rpmPathList, nil
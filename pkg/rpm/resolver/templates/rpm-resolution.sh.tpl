@@ -9,6 +9,7 @@ set -euo pipefail
 #  LocalRPMList - list of local RPMs for which dependency resolution has to be done
 #  LocalGPGList - list of local GPG keys that will be imported in the resolver image
 #  NoGPGCheck   - when set to true skips the GPG validation for all third-party repositories and local RPMs
+#  InstallOptions - additional options passed to the zypper install command
 
 {{ if ne .RegCode "" }}
 suseconnect -r {{ .RegCode }}
@@ -49,6 +50,9 @@ zypper \
   --force-resolution \
   --auto-agree-with-licenses \
   --allow-vendor-change \
+  {{ if .InstallOptions -}}
+  {{ .InstallOptions }} \
+  {{ end -}}
   -n {{.PKGList}} {{.LocalRPMList}}
 
 touch {{.CacheDir}}/zypper-success
\ No newline at end of file
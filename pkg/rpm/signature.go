@@ -0,0 +1,318 @@
+package rpm
+
+import (
+	"bytes"
+	"crypto"
+	_ "crypto/md5"  //nolint:gosec // legacy payload digest algorithm supported by rpm
+	_ "crypto/sha1" //nolint:gosec // legacy payload digest algorithm supported by rpm
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/crypto/openpgp" //nolint:staticcheck // matches the implementation used by containers/image
+)
+
+const (
+	leadSize        = 96
+	headerIntroSize = 16
+	indexEntrySize  = 16
+
+	// Signatures covering only the main header
+	sigTagDSA = 267
+	sigTagRSA = 268
+	// Signatures covering both the main header and the payload
+	sigTagPGP = 1002
+	sigTagGPG = 1005
+
+	// Digest of the compressed payload stored in the main header
+	tagPayloadDigest     = 5092
+	tagPayloadDigestAlgo = 5093
+
+	typeInt32       = 4
+	typeString      = 6
+	typeBin         = 7
+	typeStringArray = 8
+)
+
+// payloadDigestAlgos maps the OpenPGP hash algorithm identifiers used by rpm to their implementations.
+var payloadDigestAlgos = map[uint32]crypto.Hash{
+	1:  crypto.MD5,
+	2:  crypto.SHA1,
+	8:  crypto.SHA256,
+	9:  crypto.SHA384,
+	10: crypto.SHA512,
+	11: crypto.SHA224,
+}
+
+var (
+	leadMagic   = []byte{0xed, 0xab, 0xee, 0xdb}
+	headerMagic = []byte{0x8e, 0xad, 0xe8}
+)
+
+// ErrUnsigned is returned when an RPM package does not carry an OpenPGP signature.
+var ErrUnsigned = errors.New("package is not signed")
+
+// ErrUnsignedPayload is returned when an RPM package only carries a signature of its main header
+// which does not contain a digest of the payload, so the payload cannot be verified.
+var ErrUnsignedPayload = errors.New("package payload is not covered by the signature")
+
+// indexEntry describes the location of a tag value in the data store of a header.
+type indexEntry struct {
+	typ    uint32
+	offset uint32
+	count  uint32
+}
+
+// ReadKeyRing loads the armored or binary OpenPGP public keys stored in the given directory.
+func ReadKeyRing(keysDir string) (openpgp.EntityList, error) {
+	entries, err := os.ReadDir(keysDir)
+	if err != nil {
+		return nil, fmt.Errorf("reading keys directory: %w", err)
+	}
+
+	var keyRing openpgp.EntityList
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(keysDir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("reading key '%s': %w", entry.Name(), err)
+		}
+
+		keys, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(data))
+		if err != nil {
+			if keys, err = openpgp.ReadKeyRing(bytes.NewReader(data)); err != nil {
+				return nil, fmt.Errorf("parsing key '%s': %w", entry.Name(), err)
+			}
+		}
+
+		keyRing = append(keyRing, keys...)
+	}
+
+	return keyRing, nil
+}
+
+// VerifySignature checks that the RPM package at the given path is signed by one of the keys in the key ring.
+// Signatures covering only the main header are accepted if the payload matches the digest stored in the signed header.
+func VerifySignature(path string, keyRing openpgp.KeyRing) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("opening package: %w", err)
+	}
+	defer file.Close()
+
+	lead := make([]byte, leadSize)
+	if _, err = io.ReadFull(file, lead); err != nil {
+		return fmt.Errorf("reading lead: %w", err)
+	}
+
+	if !bytes.HasPrefix(lead, leadMagic) {
+		return fmt.Errorf("invalid lead magic")
+	}
+
+	sigHeader, err := readHeader(file)
+	if err != nil {
+		return fmt.Errorf("reading signature header: %w", err)
+	}
+
+	// The signature header is padded to an 8 byte boundary
+	if padding := (8 - len(sigHeader)%8) % 8; padding != 0 {
+		if _, err = io.CopyN(io.Discard, file, int64(padding)); err != nil {
+			return fmt.Errorf("reading signature header padding: %w", err)
+		}
+	}
+
+	signatures, err := parseSignatures(sigHeader)
+	if err != nil {
+		return fmt.Errorf("parsing signature header: %w", err)
+	}
+
+	header, err := readHeader(file)
+	if err != nil {
+		return fmt.Errorf("reading main header: %w", err)
+	}
+
+	headerSignature, headerSigned := signatures[sigTagRSA]
+	if !headerSigned {
+		headerSignature, headerSigned = signatures[sigTagDSA]
+	}
+
+	if headerSigned {
+		var digest []byte
+		var hash crypto.Hash
+
+		if digest, hash, err = parsePayloadDigest(header); err != nil {
+			return fmt.Errorf("parsing payload digest: %w", err)
+		}
+
+		// Older packages without a payload digest additionally sign the header together with the payload
+		if digest != nil {
+			if err = checkSignature(keyRing, bytes.NewReader(header), headerSignature); err != nil {
+				return err
+			}
+
+			return checkPayloadDigest(file, digest, hash)
+		}
+	}
+
+	for _, tag := range []uint32{sigTagPGP, sigTagGPG} {
+		if signature, ok := signatures[tag]; ok {
+			return checkSignature(keyRing, io.MultiReader(bytes.NewReader(header), file), signature)
+		}
+	}
+
+	if headerSigned {
+		return ErrUnsignedPayload
+	}
+
+	return ErrUnsigned
+}
+
+func checkSignature(keyRing openpgp.KeyRing, signed io.Reader, signature []byte) error {
+	if _, err := openpgp.CheckDetachedSignature(keyRing, signed, bytes.NewReader(signature)); err != nil {
+		return fmt.Errorf("verifying signature: %w", err)
+	}
+
+	return nil
+}
+
+// checkPayloadDigest compares the digest of the remaining payload with the one stored in the signed main header.
+func checkPayloadDigest(payload io.Reader, digest []byte, hash crypto.Hash) error {
+	h := hash.New()
+	if _, err := io.Copy(h, payload); err != nil {
+		return fmt.Errorf("reading payload: %w", err)
+	}
+
+	if !bytes.Equal(h.Sum(nil), digest) {
+		return fmt.Errorf("verifying payload: digest does not match the signed header")
+	}
+
+	return nil
+}
+
+// parsePayloadDigest returns the payload digest stored in the main header and the algorithm it was calculated with.
+// A nil digest is returned if the header does not contain one.
+func parsePayloadDigest(header []byte) ([]byte, crypto.Hash, error) {
+	entries, store, err := parseIndex(header)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	digestEntry, ok := entries[tagPayloadDigest]
+	if !ok {
+		return nil, 0, nil
+	}
+
+	algoEntry, ok := entries[tagPayloadDigestAlgo]
+	if !ok || algoEntry.typ != typeInt32 || uint64(algoEntry.offset)+4 > uint64(len(store)) {
+		return nil, 0, fmt.Errorf("invalid payload digest algorithm tag")
+	}
+
+	algo := binary.BigEndian.Uint32(store[algoEntry.offset:])
+	hash, ok := payloadDigestAlgos[algo]
+	if !ok {
+		return nil, 0, fmt.Errorf("unsupported payload digest algorithm %d", algo)
+	}
+
+	if (digestEntry.typ != typeString && digestEntry.typ != typeStringArray) || digestEntry.offset >= uint32(len(store)) {
+		return nil, 0, fmt.Errorf("invalid payload digest tag")
+	}
+
+	value, _, found := bytes.Cut(store[digestEntry.offset:], []byte{0})
+	if !found {
+		return nil, 0, fmt.Errorf("unterminated payload digest tag")
+	}
+
+	digest, err := hex.DecodeString(string(value))
+	if err != nil || len(digest) != hash.Size() {
+		return nil, 0, fmt.Errorf("malformed payload digest '%s'", value)
+	}
+
+	return digest, hash, nil
+}
+
+// readHeader reads a complete header structure, including its intro, index entries and data store.
+func readHeader(r io.Reader) ([]byte, error) {
+	intro := make([]byte, headerIntroSize)
+	if _, err := io.ReadFull(r, intro); err != nil {
+		return nil, fmt.Errorf("reading header intro: %w", err)
+	}
+
+	if !bytes.HasPrefix(intro, headerMagic) {
+		return nil, fmt.Errorf("invalid header magic")
+	}
+
+	entries := binary.BigEndian.Uint32(intro[8:12])
+	storeSize := binary.BigEndian.Uint32(intro[12:16])
+
+	size := uint64(entries)*indexEntrySize + uint64(storeSize)
+	if size > 256<<20 {
+		return nil, fmt.Errorf("header size %d exceeds the maximum allowed size", size)
+	}
+
+	header := make([]byte, headerIntroSize+size)
+	copy(header, intro)
+
+	if _, err := io.ReadFull(r, header[headerIntroSize:]); err != nil {
+		return nil, fmt.Errorf("reading header data: %w", err)
+	}
+
+	return header, nil
+}
+
+// parseIndex returns the index entries of a header by tag along with its data store.
+func parseIndex(header []byte) (map[uint32]indexEntry, []byte, error) {
+	count := int(binary.BigEndian.Uint32(header[8:12]))
+	if headerIntroSize+count*indexEntrySize > len(header) {
+		return nil, nil, fmt.Errorf("header index exceeds the header size")
+	}
+
+	store := header[headerIntroSize+count*indexEntrySize:]
+	entries := map[uint32]indexEntry{}
+
+	for i := 0; i < count; i++ {
+		entry := header[headerIntroSize+i*indexEntrySize:]
+
+		entries[binary.BigEndian.Uint32(entry[0:4])] = indexEntry{
+			typ:    binary.BigEndian.Uint32(entry[4:8]),
+			offset: binary.BigEndian.Uint32(entry[8:12]),
+			count:  binary.BigEndian.Uint32(entry[12:16]),
+		}
+	}
+
+	return entries, store, nil
+}
+
+// parseSignatures returns the binary values of the signature tags found in the signature header.
+func parseSignatures(header []byte) (map[uint32][]byte, error) {
+	entries, store, err := parseIndex(header)
+	if err != nil {
+		return nil, err
+	}
+
+	signatures := map[uint32][]byte{}
+
+	for _, tag := range []uint32{sigTagDSA, sigTagRSA, sigTagPGP, sigTagGPG} {
+		entry, ok := entries[tag]
+		if !ok {
+			continue
+		}
+
+		if entry.typ != typeBin || uint64(entry.offset)+uint64(entry.count) > uint64(len(store)) {
+			return nil, fmt.Errorf("signature tag %d exceeds the header data store", tag)
+		}
+
+		signatures[tag] = store[entry.offset : entry.offset+entry.count]
+	}
+
+	return signatures, nil
+}
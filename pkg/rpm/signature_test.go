@@ -0,0 +1,247 @@
+package rpm
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/openpgp"        //nolint:staticcheck
+	"golang.org/x/crypto/openpgp/armor"  //nolint:staticcheck
+	"golang.org/x/crypto/openpgp/errors" //nolint:staticcheck
+)
+
+type headerEntry struct {
+	tag     uint32
+	dataTyp uint32
+	data    []byte
+}
+
+func buildHeader(entries []headerEntry) []byte {
+	var index, store bytes.Buffer
+
+	for _, e := range entries {
+		count := uint32(1)
+		if e.dataTyp == 7 {
+			count = uint32(len(e.data))
+		}
+
+		_ = binary.Write(&index, binary.BigEndian, []uint32{e.tag, e.dataTyp, uint32(store.Len()), count})
+		store.Write(e.data)
+	}
+
+	var header bytes.Buffer
+	header.Write([]byte{0x8e, 0xad, 0xe8, 0x01, 0, 0, 0, 0})
+	_ = binary.Write(&header, binary.BigEndian, []uint32{uint32(len(entries)), uint32(store.Len())})
+	header.Write(index.Bytes())
+	header.Write(store.Bytes())
+
+	return header.Bytes()
+}
+
+type signMode int
+
+const (
+	// signHeader signs the main header which contains the digest of the payload
+	signHeader signMode = iota
+	// signHeaderWithoutDigest signs a main header which does not contain a payload digest
+	signHeaderWithoutDigest
+	// signHeaderAndPayload signs the main header together with the payload like older packages
+	signHeaderAndPayload
+)
+
+const testPayload = "payload"
+
+// buildRPM assembles a minimal RPM package, signed with the given entity if provided.
+func buildRPM(t *testing.T, signer *openpgp.Entity, mode signMode) []byte {
+	mainEntries := []headerEntry{
+		{tag: 1000, dataTyp: 6, data: []byte("test\x00")},
+	}
+
+	if mode == signHeader {
+		digest := sha256.Sum256([]byte(testPayload))
+		mainEntries = append(mainEntries,
+			headerEntry{tag: tagPayloadDigestAlgo, dataTyp: 4, data: []byte{0, 0, 0, 8}},
+			headerEntry{tag: tagPayloadDigest, dataTyp: 8, data: []byte(hex.EncodeToString(digest[:]) + "\x00")},
+		)
+	}
+	mainHeader := buildHeader(mainEntries)
+
+	var sigEntries []headerEntry
+	if signer != nil {
+		tag := uint32(sigTagRSA)
+		signed := mainHeader
+		if mode == signHeaderAndPayload {
+			tag = sigTagPGP
+			signed = append(bytes.Clone(mainHeader), testPayload...)
+		}
+
+		var signature bytes.Buffer
+		require.NoError(t, openpgp.DetachSign(&signature, signer, bytes.NewReader(signed), nil))
+
+		sigEntries = append(sigEntries, headerEntry{tag: tag, dataTyp: 7, data: signature.Bytes()})
+	}
+	sigHeader := buildHeader(sigEntries)
+
+	var rpm bytes.Buffer
+	rpm.Write(append([]byte{0xed, 0xab, 0xee, 0xdb, 0x03, 0x00}, make([]byte, leadSize-6)...))
+	rpm.Write(sigHeader)
+	rpm.Write(make([]byte, (8-len(sigHeader)%8)%8))
+	rpm.Write(mainHeader)
+	rpm.WriteString(testPayload)
+
+	return rpm.Bytes()
+}
+
+func writePublicKey(t *testing.T, path string, entity *openpgp.Entity) {
+	var key bytes.Buffer
+
+	w, err := armor.Encode(&key, openpgp.PublicKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.Serialize(w))
+	require.NoError(t, w.Close())
+
+	require.NoError(t, os.WriteFile(path, key.Bytes(), 0o600))
+}
+
+func TestVerifySignature(t *testing.T) {
+	signer, err := openpgp.NewEntity("signer", "", "signer@example.com", nil)
+	require.NoError(t, err)
+
+	other, err := openpgp.NewEntity("other", "", "other@example.com", nil)
+	require.NoError(t, err)
+
+	tempDir := t.TempDir()
+
+	signedRPM := filepath.Join(tempDir, "signed.rpm")
+	require.NoError(t, os.WriteFile(signedRPM, buildRPM(t, signer, signHeader), 0o600))
+
+	legacySignedRPM := filepath.Join(tempDir, "legacy-signed.rpm")
+	require.NoError(t, os.WriteFile(legacySignedRPM, buildRPM(t, signer, signHeaderAndPayload), 0o600))
+
+	noDigestRPM := filepath.Join(tempDir, "no-digest.rpm")
+	require.NoError(t, os.WriteFile(noDigestRPM, buildRPM(t, signer, signHeaderWithoutDigest), 0o600))
+
+	unsignedRPM := filepath.Join(tempDir, "unsigned.rpm")
+	require.NoError(t, os.WriteFile(unsignedRPM, buildRPM(t, nil, signHeader), 0o600))
+
+	tamperedData := buildRPM(t, signer, signHeader)
+	tamperedData[bytes.Index(tamperedData, []byte("test\x00"))] = 'T'
+	tamperedRPM := filepath.Join(tempDir, "tampered.rpm")
+	require.NoError(t, os.WriteFile(tamperedRPM, tamperedData, 0o600))
+
+	tamperedPayloadData := buildRPM(t, signer, signHeader)
+	tamperedPayloadData[len(tamperedPayloadData)-1] = 'X'
+	tamperedPayloadRPM := filepath.Join(tempDir, "tampered-payload.rpm")
+	require.NoError(t, os.WriteFile(tamperedPayloadRPM, tamperedPayloadData, 0o600))
+
+	tamperedLegacyData := buildRPM(t, signer, signHeaderAndPayload)
+	tamperedLegacyData[len(tamperedLegacyData)-1] = 'X'
+	tamperedLegacyRPM := filepath.Join(tempDir, "tampered-legacy.rpm")
+	require.NoError(t, os.WriteFile(tamperedLegacyRPM, tamperedLegacyData, 0o600))
+
+	textRPM := filepath.Join(tempDir, "text.rpm")
+	require.NoError(t, os.WriteFile(textRPM, []byte("not an RPM"), 0o600))
+
+	tests := map[string]struct {
+		path          string
+		keyRing       openpgp.EntityList
+		expectedError string
+		expectedIs    error
+	}{
+		`signed with matching key`: {
+			path:    signedRPM,
+			keyRing: openpgp.EntityList{other, signer},
+		},
+		`signed with unknown key`: {
+			path:       signedRPM,
+			keyRing:    openpgp.EntityList{other},
+			expectedIs: errors.ErrUnknownIssuer,
+		},
+		`header and payload signed with matching key`: {
+			path:    legacySignedRPM,
+			keyRing: openpgp.EntityList{signer},
+		},
+		`header signed without payload digest`: {
+			path:       noDigestRPM,
+			keyRing:    openpgp.EntityList{signer},
+			expectedIs: ErrUnsignedPayload,
+		},
+		`unsigned`: {
+			path:       unsignedRPM,
+			keyRing:    openpgp.EntityList{signer},
+			expectedIs: ErrUnsigned,
+		},
+		`tampered header`: {
+			path:          tamperedRPM,
+			keyRing:       openpgp.EntityList{signer},
+			expectedError: "verifying signature: openpgp: invalid signature: hash tag doesn't match",
+		},
+		`tampered payload`: {
+			path:          tamperedPayloadRPM,
+			keyRing:       openpgp.EntityList{signer},
+			expectedError: "verifying payload: digest does not match the signed header",
+		},
+		`tampered payload of header and payload signature`: {
+			path:          tamperedLegacyRPM,
+			keyRing:       openpgp.EntityList{signer},
+			expectedError: "verifying signature: openpgp: invalid signature: hash tag doesn't match",
+		},
+		`not an RPM`: {
+			path:          textRPM,
+			keyRing:       openpgp.EntityList{signer},
+			expectedError: "reading lead: unexpected EOF",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := VerifySignature(test.path, test.keyRing)
+
+			switch {
+			case test.expectedIs != nil:
+				assert.ErrorIs(t, err, test.expectedIs)
+			case test.expectedError != "":
+				assert.EqualError(t, err, test.expectedError)
+			default:
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestReadKeyRing(t *testing.T) {
+	// Setup
+	entity, err := openpgp.NewEntity("signer", "", "signer@example.com", nil)
+	require.NoError(t, err)
+
+	keysDir := t.TempDir()
+	writePublicKey(t, filepath.Join(keysDir, "armored.key"), entity)
+
+	var binaryKey bytes.Buffer
+	require.NoError(t, entity.Serialize(&binaryKey))
+	require.NoError(t, os.WriteFile(filepath.Join(keysDir, "binary.gpg"), binaryKey.Bytes(), 0o600))
+
+	// Test
+	keyRing, err := ReadKeyRing(keysDir)
+
+	// Verify
+	require.NoError(t, err)
+	require.Len(t, keyRing, 2)
+	assert.Equal(t, entity.PrimaryKey.KeyId, keyRing[0].PrimaryKey.KeyId)
+	assert.Equal(t, entity.PrimaryKey.KeyId, keyRing[1].PrimaryKey.KeyId)
+}
+
+func TestReadKeyRing_InvalidKey(t *testing.T) {
+	keysDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(keysDir, "invalid.key"), []byte("not a key"), 0o600))
+
+	_, err := ReadKeyRing(keysDir)
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "parsing key 'invalid.key'")
+}
@@ -0,0 +1,210 @@
+package rpm
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Filename holds the components of an RPM file name in the '<name>-<version>-<release>.<arch>.rpm' format.
+type Filename struct {
+	Name    string
+	Version string
+	Release string
+	Arch    string
+}
+
+// ParseFilename splits an RPM file name in the '<name>-<version>-<release>.<arch>.rpm' format into its components.
+func ParseFilename(filename string) (*Filename, error) {
+	nevra, found := strings.CutSuffix(filename, ".rpm")
+	if !found {
+		return nil, fmt.Errorf("missing '.rpm' extension in '%s'", filename)
+	}
+
+	archIndex := strings.LastIndex(nevra, ".")
+	if archIndex == -1 {
+		return nil, fmt.Errorf("missing architecture in '%s'", filename)
+	}
+
+	nevr := nevra[:archIndex]
+
+	releaseIndex := strings.LastIndex(nevr, "-")
+	if releaseIndex == -1 {
+		return nil, fmt.Errorf("missing release in '%s'", filename)
+	}
+
+	versionIndex := strings.LastIndex(nevr[:releaseIndex], "-")
+	if versionIndex <= 0 {
+		return nil, fmt.Errorf("missing name or version in '%s'", filename)
+	}
+
+	return &Filename{
+		Name:    nevr[:versionIndex],
+		Version: nevr[versionIndex+1 : releaseIndex],
+		Release: nevr[releaseIndex+1:],
+		Arch:    nevra[archIndex+1:],
+	}, nil
+}
+
+// Compare compares the version and release of two RPMs, returning a negative number when f is older than other,
+// a positive number when f is newer than other and zero if both are the same.
+func (f *Filename) Compare(other *Filename) int {
+	if c := CompareVersions(f.Version, other.Version); c != 0 {
+		return c
+	}
+
+	return CompareVersions(f.Release, other.Release)
+}
+
+// Deduplicate finds RPM files providing a different version of the same package (and architecture).
+// The returned map contains every superseded file name as a key with the file name of the highest
+// available version as its value. File names which cannot be parsed are never considered duplicates.
+func Deduplicate(filenames []string) map[string]string {
+	latest := map[string]string{}
+	parsed := map[string]*Filename{}
+
+	sorted := slices.Clone(filenames)
+	slices.Sort(sorted)
+
+	for _, filename := range sorted {
+		f, err := ParseFilename(filename)
+		if err != nil {
+			continue
+		}
+		parsed[filename] = f
+
+		key := f.Name + "." + f.Arch
+		if current, ok := latest[key]; !ok || f.Compare(parsed[current]) > 0 {
+			latest[key] = filename
+		}
+	}
+
+	superseded := map[string]string{}
+
+	for filename, f := range parsed {
+		if kept := latest[f.Name+"."+f.Arch]; kept != filename {
+			superseded[filename] = kept
+		}
+	}
+
+	return superseded
+}
+
+// CompareVersions compares two version (or release) strings following the same rules as rpmvercmp.
+// It returns -1 when a is older than b, 1 when a is newer than b and 0 if both are equal.
+func CompareVersions(a, b string) int {
+	if a == b {
+		return 0
+	}
+
+	for a != "" || b != "" {
+		a = strings.TrimLeftFunc(a, isVersionSeparator)
+		b = strings.TrimLeftFunc(b, isVersionSeparator)
+
+		// A tilde sorts before anything, even the end of the version
+		if strings.HasPrefix(a, "~") || strings.HasPrefix(b, "~") {
+			if !strings.HasPrefix(a, "~") {
+				return 1
+			}
+			if !strings.HasPrefix(b, "~") {
+				return -1
+			}
+
+			a, b = a[1:], b[1:]
+			continue
+		}
+
+		// A caret sorts after the end of the version but before anything else
+		if strings.HasPrefix(a, "^") || strings.HasPrefix(b, "^") {
+			switch {
+			case a == "":
+				return -1
+			case b == "":
+				return 1
+			case !strings.HasPrefix(a, "^"):
+				return 1
+			case !strings.HasPrefix(b, "^"):
+				return -1
+			}
+
+			a, b = a[1:], b[1:]
+			continue
+		}
+
+		if a == "" || b == "" {
+			break
+		}
+
+		numeric := isDigit(rune(a[0]))
+		segmentFunc := isLetter
+		if numeric {
+			segmentFunc = isDigit
+		}
+
+		var segmentA, segmentB string
+		segmentA, a = splitSegment(a, segmentFunc)
+		segmentB, b = splitSegment(b, segmentFunc)
+
+		// Numeric segments are always newer than alphabetic ones
+		if segmentB == "" {
+			if numeric {
+				return 1
+			}
+			return -1
+		}
+
+		if numeric {
+			segmentA = strings.TrimLeft(segmentA, "0")
+			segmentB = strings.TrimLeft(segmentB, "0")
+
+			if c := len(segmentA) - len(segmentB); c != 0 {
+				return sign(c)
+			}
+		}
+
+		if c := strings.Compare(segmentA, segmentB); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case a == "" && b == "":
+		return 0
+	case a == "":
+		return -1
+	default:
+		return 1
+	}
+}
+
+func splitSegment(s string, f func(rune) bool) (segment, rest string) {
+	end := strings.IndexFunc(s, func(r rune) bool { return !f(r) })
+	if end == -1 {
+		return s, ""
+	}
+
+	return s[:end], s[end:]
+}
+
+func isVersionSeparator(r rune) bool {
+	return !isDigit(r) && !isLetter(r) && r != '~' && r != '^'
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+func isLetter(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,127 @@
+package rpm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFilename(t *testing.T) {
+	tests := map[string]struct {
+		filename      string
+		expected      *Filename
+		expectedError string
+	}{
+		`full name`: {
+			filename: "cowsay-3.03-150500.1.1.noarch.rpm",
+			expected: &Filename{Name: "cowsay", Version: "3.03", Release: "150500.1.1", Arch: "noarch"},
+		},
+		`name containing dashes`: {
+			filename: "rke2-selinux-0.18-1.sle.noarch.rpm",
+			expected: &Filename{Name: "rke2-selinux", Version: "0.18", Release: "1.sle", Arch: "noarch"},
+		},
+		`missing extension`: {
+			filename:      "cowsay-3.03-1.noarch",
+			expectedError: "missing '.rpm' extension in 'cowsay-3.03-1.noarch'",
+		},
+		`missing release`: {
+			filename:      "cowsay.noarch.rpm",
+			expectedError: "missing release in 'cowsay.noarch.rpm'",
+		},
+		`missing version`: {
+			filename:      "cowsay-1.noarch.rpm",
+			expectedError: "missing name or version in 'cowsay-1.noarch.rpm'",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			f, err := ParseFilename(test.filename)
+
+			if test.expectedError != "" {
+				require.EqualError(t, err, test.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, f)
+		})
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a        string
+		b        string
+		expected int
+	}{
+		{a: "1.0", b: "1.0", expected: 0},
+		{a: "1.0", b: "2.0", expected: -1},
+		{a: "2.0", b: "1.0", expected: 1},
+		{a: "1.10", b: "1.9", expected: 1},
+		{a: "1.01", b: "1.1", expected: 0},
+		{a: "1.0", b: "1.0.1", expected: -1},
+		{a: "1.0a", b: "1.0", expected: 1},
+		{a: "1.0", b: "1.0a", expected: -1},
+		{a: "1.a", b: "1.1", expected: -1},
+		{a: "alpha", b: "beta", expected: -1},
+		{a: "1.0~rc1", b: "1.0", expected: -1},
+		{a: "1.0~rc1", b: "1.0~rc2", expected: -1},
+		{a: "1.0^git1", b: "1.0", expected: 1},
+		{a: "1.0^git1", b: "1.0.1", expected: -1},
+		{a: "1_0", b: "1.0", expected: 0},
+		{a: "150500.1.2", b: "150500.1.10", expected: -1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.a+" vs "+test.b, func(t *testing.T) {
+			assert.Equal(t, test.expected, CompareVersions(test.a, test.b))
+		})
+	}
+}
+
+func TestDeduplicate(t *testing.T) {
+	tests := map[string]struct {
+		filenames []string
+		expected  map[string]string
+	}{
+		`no duplicates`: {
+			filenames: []string{"cowsay-3.03-1.1.noarch.rpm", "fortune-1.0-1.x86_64.rpm"},
+			expected:  map[string]string{},
+		},
+		`two versions of the same package`: {
+			filenames: []string{"cowsay-3.10-1.1.noarch.rpm", "cowsay-3.9-1.1.noarch.rpm"},
+			expected: map[string]string{
+				"cowsay-3.9-1.1.noarch.rpm": "cowsay-3.10-1.1.noarch.rpm",
+			},
+		},
+		`two releases of the same version`: {
+			filenames: []string{"cowsay-3.03-2.1.noarch.rpm", "cowsay-3.03-10.1.noarch.rpm", "fortune-1.0-1.x86_64.rpm"},
+			expected: map[string]string{
+				"cowsay-3.03-2.1.noarch.rpm": "cowsay-3.03-10.1.noarch.rpm",
+			},
+		},
+		`three versions of the same package`: {
+			filenames: []string{"cowsay-1.0-1.noarch.rpm", "cowsay-2.0-1.noarch.rpm", "cowsay-1.5-1.noarch.rpm"},
+			expected: map[string]string{
+				"cowsay-1.0-1.noarch.rpm": "cowsay-2.0-1.noarch.rpm",
+				"cowsay-1.5-1.noarch.rpm": "cowsay-2.0-1.noarch.rpm",
+			},
+		},
+		`same package for different architectures`: {
+			filenames: []string{"cowsay-1.0-1.x86_64.rpm", "cowsay-2.0-1.aarch64.rpm"},
+			expected:  map[string]string{},
+		},
+		`unparsable file names`: {
+			filenames: []string{"my-policy.rpm", "policy.rpm"},
+			expected:  map[string]string{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, Deduplicate(test.filenames))
+		})
+	}
+}